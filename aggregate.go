@@ -0,0 +1,120 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AggregatedRequirement is one distinct constraint on a package together
+// with every package version that declares it.
+type AggregatedRequirement struct {
+	Requirement Term
+	Declarers   []NameVersion
+}
+
+// String renders the requirement and its declarers, for example
+// "rubyzip <3.0.0 (rubyXL 3.4.25, rubyXL 3.4.26)".
+func (r AggregatedRequirement) String() string {
+	declarers := make([]string, len(r.Declarers))
+	for i, nv := range r.Declarers {
+		declarers[i] = fmt.Sprintf("%s %s", FormatName(nv.Name), nv.Version)
+	}
+	return fmt.Sprintf("%s (%s)", r.Requirement, strings.Join(declarers, ", "))
+}
+
+// AggregateRequirements walks every published version of every package
+// reachable from root and collects the distinct constraints declared on
+// target, answering "who constrains rubyzip and how" without a solve.
+// Constraints are listed in the order they are first found, each with its
+// declarers. source must answer for the root package, as the solver's
+// sources do; missing packages are skipped and the walk is bounded like
+// DependencyComponents.
+//
+// Example:
+//
+//	reqs, err := AggregateRequirements(CombinedSource{root, registry}, root.Term(), MakeName("rubyzip"))
+//	for _, req := range reqs {
+//	    fmt.Println(req)
+//	}
+func AggregateRequirements(source Source, root Term, target Name) ([]AggregatedRequirement, error) {
+	rootVersion, err := extractDecisionVersion(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []AggregatedRequirement
+	index := make(map[string]int)
+	collect := func(name Name, version Version, deps []Term) {
+		for _, dep := range deps {
+			if dep.Name != target {
+				continue
+			}
+			key := dep.String()
+			i, ok := index[key]
+			if !ok {
+				i = len(result)
+				index[key] = i
+				result = append(result, AggregatedRequirement{Requirement: dep})
+			}
+			result[i].Declarers = append(result[i].Declarers, NameVersion{Name: name, Version: version})
+		}
+	}
+
+	deps, err := source.GetDependencies(root.Name, rootVersion)
+	if err != nil {
+		return nil, err
+	}
+	collect(root.Name, rootVersion, deps)
+
+	seen := map[Name]bool{root.Name: true}
+	queue := make([]Name, 0, len(deps))
+	for _, dep := range deps {
+		queue = append(queue, dep.Name)
+	}
+	for len(queue) > 0 && len(seen) <= maxComponentScan {
+		name := queue[0]
+		queue = queue[1:]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		versions, err := source.GetVersions(name)
+		if err != nil {
+			if isMissingPackage(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, ver := range versions {
+			deps, err := source.GetDependencies(name, ver)
+			if err != nil {
+				if isMissingPackage(err) {
+					continue
+				}
+				return nil, err
+			}
+			collect(name, ver, deps)
+			for _, dep := range deps {
+				if dep.Positive {
+					queue = append(queue, dep.Name)
+				}
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,34 @@
+package pubgrub
+
+import "testing"
+
+func TestAggregateRequirements(t *testing.T) {
+	below3 := NewTerm(MakeName("rubyzip"), NewVersionSetCondition(mustParseVersionRange(t, "<3.0.0")))
+	atLeast2 := NewTerm(MakeName("rubyzip"), NewVersionSetCondition(mustParseVersionRange(t, ">=2.0.0")))
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("rubyXL"), SimpleVersion("3.4.25"), []Term{below3})
+	source.AddPackage(MakeName("rubyXL"), SimpleVersion("3.4.26"), []Term{below3})
+	source.AddPackage(MakeName("caxlsx"), SimpleVersion("4.0.0"), []Term{
+		atLeast2,
+		NewTerm(MakeName("rubyXL"), NewVersionSetCondition(FullVersionSet())),
+	})
+	source.AddPackage(MakeName("unrelated"), SimpleVersion("1.0.0"), []Term{below3})
+	source.AddPackage(MakeName("rubyzip"), SimpleVersion("2.4.1"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("caxlsx"), NewVersionSetCondition(FullVersionSet()))
+
+	reqs, err := AggregateRequirements(CombinedSource{root, source}, root.Term(), MakeName("rubyzip"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reqs) != 2 {
+		t.Fatalf("expected two distinct requirements, got %v", reqs)
+	}
+	if got := reqs[0].String(); got != "rubyzip >=2.0.0 (caxlsx 4.0.0)" {
+		t.Fatalf("unexpected first requirement: %s", got)
+	}
+	if got := reqs[1].String(); got != "rubyzip <3.0.0 (rubyXL 3.4.25, rubyXL 3.4.26)" {
+		t.Fatalf("unexpected second requirement: %s", got)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 The University of Queensland
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+// AnyCondition matches every version of a package, the "*" constraint. It
+// is what a Term with a nil Condition means; NewTerm and NewNegativeTerm
+// substitute it for nil so every term converts to a VersionSet. Being an
+// empty struct, all AnyCondition values are the same singleton.
+//
+// Example:
+//
+//	term := NewTerm(MakeName("rack"), AnyCondition{})
+//	fmt.Println(term) // rack
+type AnyCondition struct{}
+
+// String returns "*".
+func (AnyCondition) String() string {
+	return "*"
+}
+
+// Satisfies returns true for every version.
+func (AnyCondition) Satisfies(Version) bool {
+	return true
+}
+
+// ToVersionSet implements VersionSetConverter with the full set.
+func (AnyCondition) ToVersionSet() VersionSet {
+	return fullVersionSet
+}
+
+var (
+	_ Condition           = AnyCondition{}
+	_ VersionSetConverter = AnyCondition{}
+)
@@ -0,0 +1,70 @@
+package pubgrub
+
+import "testing"
+
+func TestNewTermUsesAnyConditionForNil(t *testing.T) {
+	term := NewTerm(MakeName("rack"), nil)
+	if _, ok := term.Condition.(AnyCondition); !ok {
+		t.Fatalf("expected AnyCondition, got %T", term.Condition)
+	}
+	if set, ok := termAllowedSet(term); !ok || !set.IsSubset(fullVersionSet) || !fullVersionSet.IsSubset(set) {
+		t.Fatalf("expected the full set, got %v", set)
+	}
+	if got := term.String(); got != "rack" {
+		t.Fatalf("unexpected rendering %q", got)
+	}
+	if negative := NewNegativeTerm(MakeName("rack"), nil); negative.SatisfiedBy(SimpleVersion("1.0.0")) {
+		t.Fatalf("expected not rack to exclude every version")
+	}
+}
+
+func TestSolveOverUnconditionedDependencies(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("lib"), nil),
+		{Name: MakeName("json"), Positive: true}, // Raw nil condition
+	})
+	source.AddPackage(MakeName("lib"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("lib"), SimpleVersion("2.0.0"), nil)
+	source.AddPackage(MakeName("json"), SimpleVersion("1.0.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), nil)
+	solution, err := NewSolver(root, source).Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v, ok := solution.GetVersion(MakeName("lib")); !ok || v.String() != "2.0.0" {
+		t.Fatalf("expected the newest lib, got %v", v)
+	}
+	if _, ok := solution.GetVersion(MakeName("json")); !ok {
+		t.Fatalf("expected json in the solution, got %v", solution)
+	}
+}
+
+func TestPropagateOverUnconditionedDependencies(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("lib"), SimpleVersion("1.0.0"), nil)
+
+	allowed, conflict, err := Propagate([]Term{
+		NewTerm(MakeName("lib"), nil),
+		NewTerm(MakeName("lib"), EqualsCondition{Version: SimpleVersion("1.0.0")}),
+	}, source)
+	if err != nil || conflict != nil {
+		t.Fatalf("unexpected failure: %v, %v", err, conflict)
+	}
+	if got := allowed[MakeName("lib")]; got == nil || got.String() != "==1.0.0" {
+		t.Fatalf("expected lib to be narrowed to 1.0.0, got %v", got)
+	}
+
+	_, conflict, err = Propagate([]Term{
+		NewTerm(MakeName("lib"), nil),
+		NewNegativeTerm(MakeName("lib"), nil),
+	}, source)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conflict == nil {
+		t.Fatal("expected requiring and forbidding lib to conflict")
+	}
+}
@@ -0,0 +1,77 @@
+package pubgrub_test
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/contriboss/pubgrub-go"
+)
+
+// The declarations below pin the signatures of the stable core API. A change
+// that breaks downstream code using these identifiers fails to compile here;
+// such changes need a new major version. Experimental packages under x/ and
+// root APIs documented as experimental are deliberately not listed.
+var (
+	_ func(...pubgrub.Source) *pubgrub.Solver                                                                 = pubgrub.NewSolver
+	_ func([]pubgrub.Source, ...pubgrub.SolverOption) *pubgrub.Solver                                         = pubgrub.NewSolverWithOptions
+	_ func(*pubgrub.Solver, ...pubgrub.SolverOption) *pubgrub.Solver                                          = (*pubgrub.Solver).Configure
+	_ func(*pubgrub.Solver) *pubgrub.Solver                                                                   = (*pubgrub.Solver).EnableIncompatibilityTracking
+	_ func(*pubgrub.Solver) []*pubgrub.Incompatibility                                                        = (*pubgrub.Solver).GetIncompatibilities
+	_ func(*pubgrub.Solver) pubgrub.SolveStats                                                                = (*pubgrub.Solver).Stats
+	_ func(*pubgrub.Solver, pubgrub.Term, ...pubgrub.SolverOption) (pubgrub.Solution, error)                  = (*pubgrub.Solver).Solve
+	_ func(*pubgrub.Solver, context.Context, pubgrub.Term, ...pubgrub.SolverOption) (pubgrub.Solution, error) = (*pubgrub.Solver).SolveContext
+
+	_ func(bool) pubgrub.SolverOption         = pubgrub.WithIncompatibilityTracking
+	_ func(int) pubgrub.SolverOption          = pubgrub.WithMaxSteps
+	_ func(*slog.Logger) pubgrub.SolverOption = pubgrub.WithLogger
+
+	_ func(string) pubgrub.Name                                    = pubgrub.MakeName
+	_ func(pubgrub.Name, pubgrub.Condition) pubgrub.Term           = pubgrub.NewTerm
+	_ func(pubgrub.Name, pubgrub.Condition) pubgrub.Term           = pubgrub.NewNegativeTerm
+	_ func(string) (pubgrub.VersionSet, error)                     = pubgrub.ParseVersionRange
+	_ func(string) (*pubgrub.SemanticVersion, error)               = pubgrub.ParseSemanticVersion
+	_ func(pubgrub.VersionSet) *pubgrub.VersionSetCondition        = pubgrub.NewVersionSetCondition
+	_ func() *pubgrub.RootSource                                   = pubgrub.NewRootSource
+	_ func(*pubgrub.RootSource, pubgrub.Name, pubgrub.Condition)   = (*pubgrub.RootSource).AddPackage
+	_ func(*pubgrub.RootSource) pubgrub.Term                       = (*pubgrub.RootSource).Term
+	_ func(pubgrub.Source) *pubgrub.CachedSource                   = pubgrub.NewCachedSource
+	_ func(pubgrub.Solution, pubgrub.Name) (pubgrub.Version, bool) = pubgrub.Solution.GetVersion
+
+	_ = pubgrub.Term{Name: pubgrub.Name{}, Condition: nil, Positive: true}
+	_ = pubgrub.NameVersion{Name: pubgrub.Name{}, Version: nil}
+	_ = pubgrub.EqualsCondition{Version: nil}
+
+	_ pubgrub.Source    = (*pubgrub.InMemorySource)(nil)
+	_ pubgrub.Source    = (*pubgrub.CachedSource)(nil)
+	_ pubgrub.Source    = pubgrub.CombinedSource{}
+	_ pubgrub.Source    = (*pubgrub.RootSource)(nil)
+	_ pubgrub.Condition = pubgrub.EqualsCondition{}
+	_ pubgrub.Condition = (*pubgrub.VersionSetCondition)(nil)
+	_ pubgrub.Version   = pubgrub.SimpleVersion("")
+	_ pubgrub.Version   = (*pubgrub.SemanticVersion)(nil)
+
+	_ error = pubgrub.ErrNoSolutionFound{}
+	_ error = (*pubgrub.NoSolutionError)(nil)
+	_ error = pubgrub.ErrIterationLimit{}
+)
+
+// Interfaces users implement must not gain methods: every implementation
+// outside this module would stop compiling. Each literal below implements
+// the named interface only while its method set is unchanged.
+var (
+	_ pubgrub.Source = (interface {
+		GetVersions(pubgrub.Name) ([]pubgrub.Version, error)
+		GetDependencies(pubgrub.Name, pubgrub.Version) ([]pubgrub.Term, error)
+	})(nil)
+	_ pubgrub.Version = (interface {
+		String() string
+		Sort(pubgrub.Version) int
+	})(nil)
+	_ pubgrub.Condition = (interface {
+		String() string
+		Satisfies(pubgrub.Version) bool
+	})(nil)
+	_ pubgrub.VersionSetConverter = (interface {
+		ToVersionSet() pubgrub.VersionSet
+	})(nil)
+)
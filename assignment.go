@@ -44,6 +44,18 @@ type assignment struct {
 	cause         *Incompatibility // Incompatibility that caused this (for derivations)
 	decisionLevel int              // Decision level for backtracking
 	index         int              // Assignment index for satisfier ordering
+	fingerprint   string           // Memoized package state, see packageFingerprint
+
+	// Cumulative package state after this assignment, filled in by
+	// partialSolution.append so queries never re-fold the stack.
+	state    VersionSet // Allowed versions of the package
+	required bool       // Whether any positive assignment exists
+	decided  bool       // Whether a decision exists
+
+	// Stack positions of the latest positive and negative assignment at or
+	// below this one, -1 if none; satisfier lookups follow them.
+	lastPositive int
+	lastNegative int
 }
 
 // isDecision returns true if this assignment is an explicit version selection
@@ -70,7 +82,7 @@ func (a *assignment) describe() string {
 	}
 
 	desc := fmt.Sprintf("%s idx=%d lvl=%d kind=%s term=%s version=%s",
-		a.name.Value(), a.index, a.decisionLevel, kind, a.term.String(), version)
+		FormatName(a.name), a.index, a.decisionLevel, kind, a.term.String(), version)
 
 	if a.allowed != nil {
 		desc += fmt.Sprintf(" allowed=%s", a.allowed.String())
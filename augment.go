@@ -0,0 +1,87 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+// DependencyAugmenter rewrites the dependencies of a package version after
+// they are fetched from the Source. It lets embedders inject policy
+// dependencies or strip terms without wrapping every Source.
+//
+// Augment is called for every package version the solver inspects, except
+// the virtual root package, and must be deterministic: the solver may ask for
+// the same version several times. The returned slice may be deps itself,
+// modified or not; it must not be retained by the augmenter.
+type DependencyAugmenter interface {
+	Augment(name Name, version Version, deps []Term) []Term
+}
+
+// DependencyAugmenterFunc adapts a function to the DependencyAugmenter interface.
+//
+// Example:
+//
+//	runtime, _ := ParseVersionRange(">=3.2.0")
+//	augmenter := DependencyAugmenterFunc(func(name Name, _ Version, deps []Term) []Term {
+//	    if name == MakeName("runtime") {
+//	        return deps
+//	    }
+//	    return append(slices.Clone(deps), NewTerm(MakeName("runtime"), NewVersionSetCondition(runtime)))
+//	})
+type DependencyAugmenterFunc func(name Name, version Version, deps []Term) []Term
+
+// Augment implements DependencyAugmenter.
+func (f DependencyAugmenterFunc) Augment(name Name, version Version, deps []Term) []Term {
+	return f(name, version, deps)
+}
+
+// getDependencies fetches the dependencies of name@version from the source
+// and applies the configured augmenter, policy overrides and replacements.
+// All solver code paths that read dependencies go through here so they agree
+// on what a version requires.
+func (st *solverState) getDependencies(name Name, version Version) ([]Term, error) {
+	deps, err := st.fetchDependencies(name, version)
+	if err != nil {
+		return nil, err
+	}
+	if deps, err = st.resolveTags(deps); err != nil {
+		return nil, err
+	}
+	if deps, err = st.resolveBestEffort(name, version, deps); err != nil {
+		return nil, err
+	}
+	deps = st.resolveOptional(name, version, deps)
+	if name != st.partial.root {
+		deps = rewriteDependencies(st.options, name, version, deps)
+	}
+	deps = st.redirect(name, version, deps)
+	if err := st.admitDependencies(deps); err != nil {
+		return nil, err
+	}
+	return deps, nil
+}
+
+// rewriteDependencies applies the augmenter and policy overrides in options
+// to the dependencies of a non-root package version.
+func rewriteDependencies(options SolverOptions, name Name, version Version, deps []Term) []Term {
+	if augmenter := options.Augmenter; augmenter != nil {
+		deps = augmenter.Augment(name, version, deps)
+	}
+	if len(options.Policies) > 0 {
+		deps = applyOverrides(options.Policies, deps)
+	}
+	return deps
+}
+
+var (
+	_ DependencyAugmenter = DependencyAugmenterFunc(nil)
+)
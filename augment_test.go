@@ -0,0 +1,47 @@
+package pubgrub
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDependencyAugmenterInjectsAndStrips(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("testkit"), EqualsCondition{Version: SimpleVersion("9.9.9")}),
+	})
+	source.AddPackage(MakeName("runtime"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("runtime"), SimpleVersion("2.0.0"), nil)
+
+	runtime := NewTerm(MakeName("runtime"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+	var seen []string
+	augmenter := DependencyAugmenterFunc(func(name Name, version Version, deps []Term) []Term {
+		seen = append(seen, name.Value())
+		if name == MakeName("runtime") {
+			return deps
+		}
+		// Strip the dev-only testkit requirement and inject the runtime.
+		kept := slices.DeleteFunc(slices.Clone(deps), func(term Term) bool {
+			return term.Name == MakeName("testkit")
+		})
+		return append(kept, runtime)
+	})
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	solver := NewSolverWithOptions([]Source{root, source}, WithDependencyAugmenter(augmenter))
+	solution, err := solver.Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, ok := solution.GetVersion(MakeName("runtime")); !ok || ver.String() != "1.0.0" {
+		t.Fatalf("expected injected runtime 1.0.0, got %v", ver)
+	}
+	if _, ok := solution.GetVersion(MakeName("testkit")); ok {
+		t.Fatalf("expected testkit to be stripped")
+	}
+	if slices.Contains(seen, "$$root") {
+		t.Fatalf("expected root dependencies not to be augmented")
+	}
+}
@@ -518,3 +518,268 @@ func BenchmarkCacheReuse(b *testing.B) {
 		}
 	})
 }
+
+// propagationWorkload builds a layered graph where every package has several
+// releases depending on a range of the next layer, so solving is dominated by
+// unit propagation over ranged and exact-version terms.
+func propagationWorkload(tb testing.TB) (*RootSource, Source) {
+	tb.Helper()
+	source := &InMemorySource{}
+	layers, width, releases := 6, 5, 4
+
+	for layer := 0; layer < layers; layer++ {
+		for w := 0; w < width; w++ {
+			name := MakeName(fmt.Sprintf("l%d_p%d", layer, w))
+			for r := 0; r < releases; r++ {
+				ver, _ := ParseSemanticVersion(fmt.Sprintf("1.%d.0", r))
+				var deps []Term
+				if layer < layers-1 {
+					set, err := ParseVersionRange(fmt.Sprintf(">=1.%d.0", r/2))
+					if err != nil {
+						tb.Fatalf("parse range: %v", err)
+					}
+					for next := 0; next < width; next += 2 {
+						deps = append(deps, NewTerm(MakeName(fmt.Sprintf("l%d_p%d", layer+1, (w+next)%width)), NewVersionSetCondition(set)))
+					}
+				}
+				source.AddPackage(name, ver, deps)
+			}
+		}
+	}
+
+	root := NewRootSource()
+	for w := 0; w < width; w++ {
+		root.AddPackage(MakeName(fmt.Sprintf("l0_p%d", w)), NewVersionSetCondition(FullVersionSet()))
+	}
+	return root, source
+}
+
+// BenchmarkPropagationAllocs reports allocations for a propagation-heavy
+// solve; see TestPropagationAllocationBudget for the enforced ceiling.
+func BenchmarkPropagationAllocs(b *testing.B) {
+	root, source := propagationWorkload(b)
+	solver := NewSolver(root, source)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for b.Loop() {
+		if _, err := solver.Solve(root.Term()); err != nil {
+			b.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+// propagationAllocBudget is the allocation ceiling for one solve of
+// propagationWorkload. Raise it only with a justification; regressions here
+// usually mean the propagation hot path started cloning sets or terms again.
+// The final solution retains its dependency edges for Prune, which costs a
+// lookup key per resolved package.
+const propagationAllocBudget = 3550
+
+func TestPropagationAllocationBudget(t *testing.T) {
+	if testing.Short() {
+		t.Skip("allocation budget is not checked in short mode")
+	}
+	if raceEnabled {
+		t.Skip("allocation budget is not checked under the race detector")
+	}
+	root, source := propagationWorkload(t)
+	solver := NewSolver(root, source)
+
+	allocs := testing.AllocsPerRun(20, func() {
+		if _, err := solver.Solve(root.Term()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if allocs > propagationAllocBudget {
+		t.Fatalf("solve allocated %.0f times, budget is %d", allocs, propagationAllocBudget)
+	}
+}
+
+// conflictingWideWorkload builds a wide graph whose newest releases disagree
+// on a shared dependency, so the solver has to learn its way down to older
+// releases. It is small enough to solve quickly but conflict-rich enough for
+// queue discipline to matter.
+func conflictingWideWorkload(tb testing.TB, width int) (*RootSource, Source) {
+	tb.Helper()
+	source := &InMemorySource{}
+	shared := MakeName("shared")
+	for v := 1; v <= 3; v++ {
+		ver, _ := ParseSemanticVersion(fmt.Sprintf("%d.0.0", v))
+		source.AddPackage(shared, ver, nil)
+	}
+
+	root := NewRootSource()
+	for i := 0; i < width; i++ {
+		name := MakeName(fmt.Sprintf("pkg%d", i))
+		for r := 0; r < 4; r++ {
+			ver, _ := ParseSemanticVersion(fmt.Sprintf("1.%d.0", r))
+			// Newer releases pin different shared majors; 1.0.0 accepts any.
+			want := ">=1.0.0"
+			if r > 0 {
+				want = fmt.Sprintf("==%d.0.0", (i+r)%3+1)
+			}
+			set, err := ParseVersionRange(want)
+			if err != nil {
+				tb.Fatalf("parse range: %v", err)
+			}
+			source.AddPackage(name, ver, []Term{NewTerm(shared, NewVersionSetCondition(set))})
+		}
+		root.AddPackage(name, NewVersionSetCondition(FullVersionSet()))
+	}
+	return root, source
+}
+
+// BenchmarkPropagationOrder compares queue disciplines on a conflict-heavy
+// wide graph. Lookahead is disabled so version selection cannot sidestep the
+// conflicts; the conflicts/op metric shows how much learning each order needs.
+func BenchmarkPropagationOrder(b *testing.B) {
+	orders := []struct {
+		name  string
+		order PropagationOrder
+	}{
+		{"FIFO", PropagationFIFO},
+		{"LIFO", PropagationLIFO},
+		{"MostConstrained", PropagationMostConstrained},
+	}
+
+	for _, tc := range orders {
+		b.Run(tc.name, func(b *testing.B) {
+			root, source := conflictingWideWorkload(b, 12)
+			solver := NewSolverWithOptions([]Source{root, source},
+				WithPropagationOrder(tc.order),
+				WithVersionStrategy(VersionNewest),
+			)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for b.Loop() {
+				if _, err := solver.Solve(root.Term()); err != nil {
+					b.Fatalf("unexpected error: %v", err)
+				}
+			}
+			b.ReportMetric(float64(solver.Stats().Conflicts), "conflicts/op")
+		})
+	}
+}
+
+// BenchmarkTieBreak compares tie-breaking strategies; packages/op shows the
+// size of the resulting solution.
+func BenchmarkTieBreak(b *testing.B) {
+	strategies := []struct {
+		name     string
+		tieBreak TieBreak
+	}{
+		{"Newest", TieBreakNewest},
+		{"FewerDependencies", TieBreakFewerDependencies},
+	}
+
+	// Forty libraries whose versions each depend on one of ten pool
+	// packages. All versions score equally under lookahead; half of the pool
+	// is required by the root, so tie-breaking decides how many extra pool
+	// packages end up in the solution.
+	source := &InMemorySource{}
+	root := NewRootSource()
+	pinned := EqualsCondition{Version: SimpleVersion("1.0.0")}
+	for i := range 10 {
+		pool := MakeName(fmt.Sprintf("pool%d", i))
+		source.AddPackage(pool, SimpleVersion("1.0.0"), nil)
+		if i < 5 {
+			root.AddPackage(pool, pinned)
+		}
+	}
+	for i := range 40 {
+		lib := MakeName(fmt.Sprintf("lib%d", i))
+		for v := range 4 {
+			pool := MakeName(fmt.Sprintf("pool%d", (i+v*3)%10))
+			source.AddPackage(lib, SimpleVersion(fmt.Sprintf("1.%d.0", v)), []Term{NewTerm(pool, pinned)})
+		}
+		root.AddPackage(lib, NewVersionSetCondition(FullVersionSet()))
+	}
+
+	for _, tc := range strategies {
+		b.Run(tc.name, func(b *testing.B) {
+			solver := NewSolverWithOptions([]Source{root, source}, WithTieBreak(tc.tieBreak))
+
+			var solution Solution
+			b.ReportAllocs()
+			b.ResetTimer()
+			for b.Loop() {
+				var err error
+				if solution, err = solver.Solve(root.Term()); err != nil {
+					b.Fatalf("unexpected error: %v", err)
+				}
+			}
+			b.ReportMetric(float64(len(solution)), "packages/op")
+		})
+	}
+}
+
+// BenchmarkExactPinAllowedSet measures converting exact-pin terms, which
+// propagation does for every evaluation of an EqualsCondition dependency.
+func BenchmarkExactPinAllowedSet(b *testing.B) {
+	terms := make([]Term, 64)
+	for i := range terms {
+		terms[i] = NewTerm(MakeName(fmt.Sprintf("pkg%d", i)), EqualsCondition{Version: SimpleVersion(fmt.Sprintf("1.0.%d", i))})
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		for _, term := range terms {
+			if _, ok := termAllowedSet(term); !ok {
+				b.Fatal("expected an allowed set")
+			}
+		}
+	}
+}
+
+// unsolvableWideWorkload extends conflictingWideWorkload with root pins on
+// releases that require different shared majors, so it has no solution.
+func unsolvableWideWorkload(tb testing.TB, width int) (*RootSource, Source) {
+	tb.Helper()
+	root, source := conflictingWideWorkload(tb, width)
+	pin, _ := ParseSemanticVersion("1.1.0")
+	root.AddPackage(MakeName("pkg0"), EqualsCondition{Version: pin})
+	root.AddPackage(MakeName("pkg1"), EqualsCondition{Version: pin})
+	return root, source
+}
+
+// BenchmarkTrackingOverhead compares incompatibility tracking off, on, and
+// enabled only on failure, on a solvable and an unsolvable workload. Tracking
+// on failure should match "Off" when solvable and cost roughly "Off" plus
+// "On" when not.
+func BenchmarkTrackingOverhead(b *testing.B) {
+	workloads := []struct {
+		name     string
+		build    func(testing.TB, int) (*RootSource, Source)
+		solvable bool
+	}{
+		{"Solvable", conflictingWideWorkload, true},
+		{"Unsolvable", unsolvableWideWorkload, false},
+	}
+	modes := []struct {
+		name string
+		opt  SolverOption
+	}{
+		{"Off", WithIncompatibilityTracking(false)},
+		{"On", WithIncompatibilityTracking(true)},
+		{"OnFailure", WithTrackingOnFailure(true)},
+	}
+
+	for _, workload := range workloads {
+		root, source := workload.build(b, 8)
+		for _, mode := range modes {
+			b.Run(workload.name+"/"+mode.name, func(b *testing.B) {
+				solver := NewSolverWithOptions([]Source{root, source}, mode.opt)
+				b.ReportAllocs()
+				for b.Loop() {
+					_, err := solver.Solve(root.Term())
+					if (err == nil) != workload.solvable {
+						b.Fatalf("unexpected result: %v", err)
+					}
+				}
+				b.ReportMetric(float64(solver.Stats().Steps), "steps/op")
+			})
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+// BestEffortCondition marks a dependency as best effort, for optional
+// integrations that may not exist in every environment or registry mirror.
+// When the package is not published, or none of its versions satisfies
+// Condition, the solver drops the dependency with a BestEffortWarning
+// instead of failing. Otherwise the dependency is required as usual, so a
+// best-effort dependency that conflicts with other requirements still fails
+// the solve. A nil Condition accepts any version.
+//
+// Example:
+//
+//	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{
+//	    NewTerm(MakeName("sentry"), BestEffortCondition{Condition: NewVersionSetCondition(set)}),
+//	})
+type BestEffortCondition struct {
+	Condition Condition
+}
+
+// String returns the wrapped condition marked as best effort.
+func (c BestEffortCondition) String() string {
+	if c.Condition == nil {
+		return "* (best effort)"
+	}
+	return c.Condition.String() + " (best effort)"
+}
+
+// Satisfies reports whether the wrapped condition accepts ver.
+func (c BestEffortCondition) Satisfies(ver Version) bool {
+	return c.Condition == nil || c.Condition.Satisfies(ver)
+}
+
+// resolveBestEffort replaces best-effort dependencies of name@version with
+// their wrapped conditions, dropping those no published version satisfies
+// with a warning, raised once per dependency. deps is copied before
+// modification.
+func (st *solverState) resolveBestEffort(name Name, version Version, deps []Term) ([]Term, error) {
+	var result []Term
+	for i, dep := range deps {
+		cond, ok := dep.Condition.(BestEffortCondition)
+		if !ok {
+			if result != nil {
+				result = append(result, dep)
+			}
+			continue
+		}
+		if result == nil {
+			result = append(make([]Term, 0, len(deps)), deps[:i]...)
+		}
+		dep.Condition = cond.Condition
+		available, err := st.bestEffortAvailable(dep.Name, cond)
+		if err != nil {
+			return nil, err
+		}
+		if available {
+			result = append(result, dep)
+			continue
+		}
+		key := dependencyScoreKey(name, version) + "->" + dep.Name.Value()
+		if st.dropped[key] {
+			continue
+		}
+		if st.dropped == nil {
+			st.dropped = make(map[string]bool)
+		}
+		st.dropped[key] = true
+		st.warn(BestEffortWarning{Dependent: name, Version: version, Dependency: dep})
+	}
+	if result == nil {
+		return deps, nil
+	}
+	return result, nil
+}
+
+// bestEffortAvailable reports whether a published version of name
+// satisfies cond. A package the source does not know is unavailable.
+func (st *solverState) bestEffortAvailable(name Name, cond BestEffortCondition) (bool, error) {
+	versions, err := st.getVersions(name)
+	if err != nil {
+		if isMissingPackage(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, ver := range versions {
+		if cond.Satisfies(ver) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
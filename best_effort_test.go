@@ -0,0 +1,60 @@
+package pubgrub
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBestEffortDependencyDroppedWhenUnavailable(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("sentry"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("sentry"), BestEffortCondition{Condition: NewVersionSetCondition(mustParseVersionRange(t, ">=2.0.0"))}),
+		NewTerm(MakeName("newrelic"), BestEffortCondition{}),
+	})
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	solver := NewSolver(root, source)
+	solution, err := solver.Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, pkg := range []string{"sentry", "newrelic"} {
+		if ver, ok := solution.GetVersion(MakeName(pkg)); ok {
+			t.Fatalf("expected unavailable %s to be dropped, got %s", pkg, ver)
+		}
+	}
+
+	warnings := solver.Warnings()
+	if len(warnings) != 2 {
+		t.Fatalf("expected a warning per dropped dependency, got %v", warnings)
+	}
+	w, ok := warnings[0].(BestEffortWarning)
+	if !ok || w.Dependent != MakeName("app") || w.Dependency.Name != MakeName("sentry") {
+		t.Fatalf("unexpected warning %#v", warnings[0])
+	}
+	if !strings.Contains(w.Warning(), "dropped best-effort dependency sentry >=2.0.0") {
+		t.Fatalf("unexpected warning text %q", w.Warning())
+	}
+}
+
+func TestBestEffortDependencyRequiredWhenAvailable(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("sentry"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("sentry"), SimpleVersion("2.0.0"), nil)
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("sentry"), BestEffortCondition{Condition: NewVersionSetCondition(mustParseVersionRange(t, "<2.0.0"))}),
+	})
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+	root.AddPackage(MakeName("sentry"), NewVersionSetCondition(mustParseVersionRange(t, ">=2.0.0")))
+
+	solver := NewSolver(root, source)
+	if _, err := solver.Solve(root.Term()); err == nil {
+		t.Fatalf("expected an available best-effort dependency to be enforced")
+	}
+	if len(solver.Warnings()) != 0 {
+		t.Fatalf("expected no warnings, got %v", solver.Warnings())
+	}
+}
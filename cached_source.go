@@ -1,6 +1,10 @@
 package pubgrub
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"sync"
+)
 
 // CachedSource wraps a Source and caches GetVersions and GetDependencies calls
 // to improve performance when the same queries are made repeatedly.
@@ -20,8 +24,11 @@ import "fmt"
 //
 // The cache is maintained for the lifetime of the CachedSource instance and
 // assumes that version lists and dependencies are immutable during solving.
+// It is safe for concurrent use; concurrent misses for the same key may both
+// reach the underlying source.
 type CachedSource struct {
 	source Source
+	mu     sync.Mutex
 
 	// Cache for GetVersions results
 	versionsCache     map[Name][]Version
@@ -32,6 +39,21 @@ type CachedSource struct {
 	depsCache     map[string][]Term
 	depsCalls     int
 	depsCacheHits int
+
+	// lastRead is the count at the previous SnapshotCacheStats.
+	lastRead cacheCounters
+
+	// Observer installed by ObserveCacheStats
+	observeMu    sync.Mutex // Serializes observer calls
+	observe      func(total, delta CacheStats)
+	observeEvery int
+	lastObserved cacheCounters
+}
+
+// cacheCounters is a point-in-time copy of the call and hit counts.
+type cacheCounters struct {
+	versionsCalls, versionsHits int
+	depsCalls, depsHits         int
 }
 
 // NewCachedSource creates a new caching wrapper around the given source.
@@ -45,46 +67,76 @@ func NewCachedSource(source Source) *CachedSource {
 
 // GetVersions returns all available versions for a package, caching the result.
 func (c *CachedSource) GetVersions(name Name) ([]Version, error) {
+	return c.GetVersionsCtx(context.Background(), name)
+}
+
+// GetVersionsCtx implements ContextSource, passing ctx to the underlying
+// source on a cache miss. Errors, including ctx.Err(), are not cached.
+func (c *CachedSource) GetVersionsCtx(ctx context.Context, name Name) ([]Version, error) {
+	c.mu.Lock()
 	c.versionsCalls++
 
 	// Check cache first
 	if versions, ok := c.versionsCache[name]; ok {
 		c.versionsCacheHits++
+		event := c.tick()
+		c.mu.Unlock()
+		c.notify(event)
 		return versions, nil
 	}
+	event := c.tick()
+	c.mu.Unlock()
+	c.notify(event)
 
 	// Cache miss - fetch from underlying source
-	versions, err := c.source.GetVersions(name)
+	versions, err := getVersionsCtx(ctx, c.source, name)
 	if err != nil {
 		return nil, err
 	}
 
 	// Store in cache
+	c.mu.Lock()
 	c.versionsCache[name] = versions
+	c.mu.Unlock()
 	return versions, nil
 }
 
 // GetDependencies returns dependencies for a specific package version, caching the result.
 func (c *CachedSource) GetDependencies(name Name, version Version) ([]Term, error) {
-	c.depsCalls++
+	return c.GetDependenciesCtx(context.Background(), name, version)
+}
 
+// GetDependenciesCtx implements ContextSource, passing ctx to the underlying
+// source on a cache miss.
+func (c *CachedSource) GetDependenciesCtx(ctx context.Context, name Name, version Version) ([]Term, error) {
 	// Create cache key from name and version
 	key := fmt.Sprintf("%s@%s", name.Value(), version)
 
+	c.mu.Lock()
+	c.depsCalls++
+
 	// Check cache first
 	if deps, ok := c.depsCache[key]; ok {
 		c.depsCacheHits++
+		event := c.tick()
+		c.mu.Unlock()
+		c.notify(event)
 		return deps, nil
 	}
+	event := c.tick()
+	c.mu.Unlock()
+	c.notify(event)
 
 	// Cache miss - fetch from underlying source
-	deps, err := c.source.GetDependencies(name, version)
+	deps, err := getDependenciesCtx(ctx, c.source, name, version)
 	if err != nil {
 		return nil, err
 	}
 
 	// Store in cache
+	c.mu.Lock()
 	c.depsCache[key] = deps
+	c.mu.Unlock()
 	return deps, nil
 }
 
@@ -105,13 +157,115 @@ type CacheStats struct {
 
 // GetCacheStats returns cache performance statistics.
 func (c *CachedSource) GetCacheStats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.counters().stats()
+}
+
+// SnapshotCacheStats returns the cumulative statistics together with the
+// change since the previous SnapshotCacheStats, or since the cache was
+// created or cleared. Both are read under one lock, so concurrent readers
+// each see every call exactly once across their deltas.
+//
+// Example:
+//
+//	for range time.Tick(time.Minute) {
+//	    _, delta := cached.SnapshotCacheStats()
+//	    metrics.Gauge("cache.hit_rate", delta.OverallHitRate)
+//	}
+func (c *CachedSource) SnapshotCacheStats() (total, delta CacheStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.counters()
+	total, delta = now.stats(), now.sub(c.lastRead).stats()
+	c.lastRead = now
+	return total, delta
+}
+
+// ObserveCacheStats installs fn to be called after every every-th cache
+// operation with the cumulative statistics and the change since its previous
+// call, so a long-lived resolver can stream cache effectiveness without
+// polling. Calls are serialized but run on the goroutine that performed the
+// operation, so fn should return quickly; it may read the cache's statistics
+// but must not install another observer. A nil fn or every below one removes
+// the observer.
+//
+// Example:
+//
+//	cached.ObserveCacheStats(1000, func(total, delta CacheStats) {
+//	    metrics.Gauge("cache.hit_rate", delta.OverallHitRate)
+//	})
+func (c *CachedSource) ObserveCacheStats(every int, fn func(total, delta CacheStats)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if fn == nil || every < 1 {
+		c.observe, c.observeEvery = nil, 0
+		return
+	}
+	c.observe, c.observeEvery = fn, every
+	c.lastObserved = c.counters()
+}
+
+// cacheStatsEvent is an observer call prepared under c.mu.
+type cacheStatsEvent struct {
+	observe      func(total, delta CacheStats)
+	total, delta CacheStats
+}
+
+// tick prepares an observer call when the operation just counted is due
+// one. c.mu must be held.
+func (c *CachedSource) tick() *cacheStatsEvent {
+	if c.observe == nil {
+		return nil
+	}
+	now := c.counters()
+	if (now.versionsCalls+now.depsCalls)%c.observeEvery != 0 {
+		return nil
+	}
+	event := &cacheStatsEvent{observe: c.observe, total: now.stats(), delta: now.sub(c.lastObserved).stats()}
+	c.lastObserved = now
+	return event
+}
+
+// notify delivers event, if any, to its observer.
+func (c *CachedSource) notify(event *cacheStatsEvent) {
+	if event == nil {
+		return
+	}
+	c.observeMu.Lock()
+	defer c.observeMu.Unlock()
+	event.observe(event.total, event.delta)
+}
+
+// counters copies the current counts. c.mu must be held.
+func (c *CachedSource) counters() cacheCounters {
+	return cacheCounters{
+		versionsCalls: c.versionsCalls,
+		versionsHits:  c.versionsCacheHits,
+		depsCalls:     c.depsCalls,
+		depsHits:      c.depsCacheHits,
+	}
+}
+
+// sub returns the counts accumulated since prev.
+func (k cacheCounters) sub(prev cacheCounters) cacheCounters {
+	return cacheCounters{
+		versionsCalls: k.versionsCalls - prev.versionsCalls,
+		versionsHits:  k.versionsHits - prev.versionsHits,
+		depsCalls:     k.depsCalls - prev.depsCalls,
+		depsHits:      k.depsHits - prev.depsHits,
+	}
+}
+
+// stats converts the counts to CacheStats with hit rates.
+func (k cacheCounters) stats() CacheStats {
 	stats := CacheStats{
-		VersionsCalls:     c.versionsCalls,
-		VersionsCacheHits: c.versionsCacheHits,
-		DepsCalls:         c.depsCalls,
-		DepsCacheHits:     c.depsCacheHits,
-		TotalCalls:        c.versionsCalls + c.depsCalls,
-		TotalCacheHits:    c.versionsCacheHits + c.depsCacheHits,
+		VersionsCalls:     k.versionsCalls,
+		VersionsCacheHits: k.versionsHits,
+		DepsCalls:         k.depsCalls,
+		DepsCacheHits:     k.depsHits,
+		TotalCalls:        k.versionsCalls + k.depsCalls,
+		TotalCacheHits:    k.versionsHits + k.depsHits,
 	}
 
 	if stats.VersionsCalls > 0 {
@@ -131,10 +285,27 @@ func (c *CachedSource) GetCacheStats() CacheStats {
 
 // ClearCache clears all cached data while preserving the underlying source.
 func (c *CachedSource) ClearCache() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.versionsCache = make(map[Name][]Version)
 	c.depsCache = make(map[string][]Term)
 	c.versionsCalls = 0
 	c.versionsCacheHits = 0
 	c.depsCalls = 0
 	c.depsCacheHits = 0
+	c.lastRead = cacheCounters{}
+	c.lastObserved = cacheCounters{}
 }
+
+// VersionMetadata implements MetadataProvider, passing the call through
+// uncached; providers usually have the metadata at hand from listing the
+// versions.
+func (c *CachedSource) VersionMetadata(name Name, version Version) any {
+	return versionMetadata(c.source, name, version)
+}
+
+var (
+	_ ContextSource    = (*CachedSource)(nil)
+	_ MetadataProvider = (*CachedSource)(nil)
+)
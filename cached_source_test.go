@@ -2,6 +2,7 @@ package pubgrub
 
 import (
 	"fmt"
+	"sync"
 	"testing"
 )
 
@@ -209,3 +210,97 @@ func TestCachedSource_Integration(t *testing.T) {
 		t.Error("expected some calls to be made")
 	}
 }
+
+func TestCachedSource_SnapshotCacheStats(t *testing.T) {
+	inner := &InMemorySource{}
+	inner.AddPackage(MakeName("A"), SimpleVersion("1.0.0"), nil)
+	cached := NewCachedSource(inner)
+
+	cached.GetVersions(MakeName("A"))
+	cached.GetVersions(MakeName("A"))
+	total, delta := cached.SnapshotCacheStats()
+	if total.TotalCalls != 2 || delta.TotalCalls != 2 || delta.TotalCacheHits != 1 {
+		t.Fatalf("unexpected first snapshot: total %+v, delta %+v", total, delta)
+	}
+
+	cached.GetVersions(MakeName("A"))
+	total, delta = cached.SnapshotCacheStats()
+	if total.TotalCalls != 3 || delta.TotalCalls != 1 || delta.OverallHitRate != 1 {
+		t.Fatalf("unexpected second snapshot: total %+v, delta %+v", total, delta)
+	}
+
+	cached.ClearCache()
+	cached.GetVersions(MakeName("A"))
+	if _, delta := cached.SnapshotCacheStats(); delta.TotalCalls != 1 || delta.TotalCacheHits != 0 {
+		t.Fatalf("expected the delta to restart after ClearCache, got %+v", delta)
+	}
+}
+
+func TestCachedSource_ObserveCacheStats(t *testing.T) {
+	inner := &InMemorySource{}
+	inner.AddPackage(MakeName("A"), SimpleVersion("1.0.0"), nil)
+	cached := NewCachedSource(inner)
+
+	var deltas []CacheStats
+	cached.ObserveCacheStats(2, func(total, delta CacheStats) {
+		deltas = append(deltas, delta)
+		// Reading the stats from the observer must not deadlock.
+		if got := cached.GetCacheStats(); got.TotalCalls != total.TotalCalls {
+			t.Errorf("expected %d calls, got %d", total.TotalCalls, got.TotalCalls)
+		}
+	})
+	for range 5 {
+		cached.GetVersions(MakeName("A"))
+	}
+	cached.GetDependencies(MakeName("A"), SimpleVersion("1.0.0"))
+
+	if len(deltas) != 3 {
+		t.Fatalf("expected an observation every 2 operations, got %d", len(deltas))
+	}
+	if deltas[0].TotalCalls != 2 || deltas[0].TotalCacheHits != 1 {
+		t.Fatalf("unexpected first delta: %+v", deltas[0])
+	}
+	if deltas[2].VersionsCalls != 1 || deltas[2].DepsCalls != 1 {
+		t.Fatalf("unexpected last delta: %+v", deltas[2])
+	}
+
+	cached.ObserveCacheStats(0, nil)
+	cached.GetVersions(MakeName("A"))
+	cached.GetVersions(MakeName("A"))
+	if len(deltas) != 3 {
+		t.Fatalf("expected no observations after removal, got %d", len(deltas))
+	}
+}
+
+func TestCachedSource_ConcurrentStats(t *testing.T) {
+	inner := &InMemorySource{}
+	inner.AddPackage(MakeName("A"), SimpleVersion("1.0.0"), nil)
+	cached := NewCachedSource(inner)
+	var observed int
+	cached.ObserveCacheStats(1, func(_, delta CacheStats) {
+		observed += delta.TotalCalls
+	})
+
+	var wg sync.WaitGroup
+	reads := make([]int, 4)
+	for i := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				cached.GetVersions(MakeName("A"))
+				_, delta := cached.SnapshotCacheStats()
+				reads[i] += delta.TotalCalls
+			}
+		}()
+	}
+	wg.Wait()
+
+	sum := 0
+	for _, n := range reads {
+		sum += n
+	}
+	if sum != 400 || observed != 400 {
+		t.Fatalf("expected every call counted once, got %d snapshot and %d observed", sum, observed)
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// maxComplexityPackages bounds how many packages EstimateComplexity
+	// lists before it stops and marks the estimate truncated.
+	maxComplexityPackages = 2000
+	// maxComplexitySampleVersions is how many of the newest versions of a
+	// package EstimateComplexity reads dependencies for.
+	maxComplexitySampleVersions = 8
+	// complexityStepCost is a conservative cost of one solver step, used to
+	// turn the recommended step limit into a timeout.
+	complexityStepCost = 50 * time.Microsecond
+)
+
+// ComplexityEstimate summarizes the dependency graph reachable from a set of
+// root requirements, as sampled by EstimateComplexity.
+type ComplexityEstimate struct {
+	// Packages is the number of reachable packages listed.
+	Packages int
+	// Versions is the total number of versions those packages publish.
+	Versions int
+	// AvgVersions is Versions per package.
+	AvgVersions float64
+	// AvgDependencies is the mean number of dependencies of a sampled
+	// version.
+	AvgDependencies float64
+	// Tightness is the mean share of a dependency's published versions that
+	// a sampled requirement excludes: 0 when every requirement accepts
+	// everything, approaching 1 for exact pins.
+	Tightness float64
+	// Truncated reports that sampling stopped at its package limit, so the
+	// graph is larger than measured and the recommendations are lower bounds.
+	Truncated bool
+	// SourceLatency is the mean duration of the Source calls made while
+	// sampling.
+	SourceLatency time.Duration
+
+	// Score is the heuristic difficulty: packages times the binary choices
+	// per package, weighted by constraint tightness. Compare scores across
+	// problems rather than reading them in isolation.
+	Score float64
+	// MaxSteps is a step limit that leaves room for backtracking.
+	MaxSteps int
+	// Timeout is a deadline covering MaxSteps steps and the expected Source
+	// calls at the sampled latency.
+	Timeout time.Duration
+}
+
+// String returns a one-line summary.
+func (e ComplexityEstimate) String() string {
+	truncated := ""
+	if e.Truncated {
+		truncated = ", truncated"
+	}
+	return fmt.Sprintf("score %.0f: %d packages, %.1f versions each, %.1f dependencies per version, tightness %.2f%s",
+		e.Score, e.Packages, e.AvgVersions, e.AvgDependencies, e.Tightness, truncated)
+}
+
+// EstimateComplexity samples the graph reachable from root in source and
+// estimates how hard it is to solve, so a service can route hard problems
+// to bigger workers or configure WithMaxSteps and SolveContext accordingly.
+//
+// Every reachable package is listed, up to a limit, and the dependencies of
+// its newest few versions are read, so the cost is a fraction of a solve's
+// Source calls. Wrap source in a CachedSource to reuse them for the solve.
+// Missing packages are skipped; other source errors are returned.
+//
+// Example:
+//
+//	estimate, err := EstimateComplexity(cached, *root)
+//	if err == nil && estimate.Score > hardThreshold {
+//	    return queue.Submit(job, "large-worker")
+//	}
+//	ctx, cancel := context.WithTimeout(ctx, estimate.Timeout)
+//	defer cancel()
+//	solution, err := NewSolverWithOptions([]Source{root, cached}, WithMaxSteps(estimate.MaxSteps)).SolveContext(ctx, root.Term())
+func EstimateComplexity(source Source, root RootSource) (ComplexityEstimate, error) {
+	var estimate ComplexityEstimate
+	var calls int
+	var elapsed time.Duration
+	timed := func(call func() error) error {
+		start := time.Now()
+		err := call()
+		elapsed += time.Since(start)
+		calls++
+		return err
+	}
+
+	published := make(map[Name][]Version)
+	var sampled []Term
+	var sampledVersions int
+	queue := make([]Name, 0, len(root))
+	for _, req := range root {
+		queue = append(queue, req.Name)
+		sampled = append(sampled, req)
+	}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if _, ok := published[name]; ok {
+			continue
+		}
+		if len(published) >= maxComplexityPackages {
+			estimate.Truncated = true
+			break
+		}
+
+		var versions []Version
+		err := timed(func() (err error) {
+			versions, err = source.GetVersions(name)
+			return err
+		})
+		published[name] = versions
+		if err != nil {
+			if isMissingPackage(err) {
+				continue
+			}
+			return ComplexityEstimate{}, err
+		}
+
+		first := len(versions) - maxComplexitySampleVersions
+		if first < 0 {
+			first = 0
+		}
+		for _, ver := range versions[first:] {
+			var deps []Term
+			err := timed(func() (err error) {
+				deps, err = source.GetDependencies(name, ver)
+				return err
+			})
+			if err != nil {
+				if isMissingPackage(err) {
+					continue
+				}
+				return ComplexityEstimate{}, err
+			}
+			sampledVersions++
+			estimate.AvgDependencies += float64(len(deps))
+			for _, dep := range deps {
+				sampled = append(sampled, dep)
+				queue = append(queue, dep.Name)
+			}
+		}
+	}
+
+	for _, versions := range published {
+		estimate.Packages++
+		estimate.Versions += len(versions)
+	}
+	if estimate.Packages > 0 {
+		estimate.AvgVersions = float64(estimate.Versions) / float64(estimate.Packages)
+	}
+	if sampledVersions > 0 {
+		estimate.AvgDependencies /= float64(sampledVersions)
+	}
+	if calls > 0 {
+		estimate.SourceLatency = elapsed / time.Duration(calls)
+	}
+	estimate.Tightness = requirementTightness(sampled, published)
+
+	estimate.Score = float64(estimate.Packages) * math.Log2(1+estimate.AvgVersions) * (1 + 2*estimate.Tightness)
+	steps := 20 * float64(estimate.Versions) * (1 + 2*estimate.Tightness)
+	estimate.MaxSteps = int(math.Min(math.Max(steps, 1000), 100*defaultMaxSteps))
+	// Listing every package and fetching dependencies for about two
+	// versions of each is the common case; double it for backtracking.
+	expectedCalls := time.Duration(3 * estimate.Packages)
+	timeout := 2 * (expectedCalls*estimate.SourceLatency + time.Duration(estimate.MaxSteps)*complexityStepCost)
+	estimate.Timeout = timeout.Truncate(time.Second) + time.Second
+	return estimate, nil
+}
+
+// requirementTightness returns the mean share of published versions the
+// positive requirements exclude, over requirements on listed packages.
+func requirementTightness(requirements []Term, published map[Name][]Version) float64 {
+	var total float64
+	var counted int
+	for _, req := range requirements {
+		versions := published[req.Name]
+		allowed, ok := termAllowedSet(req)
+		if !ok || len(versions) == 0 {
+			continue
+		}
+		excluded := 0
+		for _, ver := range versions {
+			if !allowed.Contains(ver) {
+				excluded++
+			}
+		}
+		total += float64(excluded) / float64(len(versions))
+		counted++
+	}
+	if counted == 0 {
+		return 0
+	}
+	return total / float64(counted)
+}
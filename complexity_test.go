@@ -0,0 +1,89 @@
+package pubgrub
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEstimateComplexityMeasuresGraph(t *testing.T) {
+	source := &InMemorySource{}
+	for _, ver := range []string{"1.0.0", "2.0.0", "3.0.0", "4.0.0"} {
+		source.AddPackage(MakeName("lib"), SimpleVersion(ver), nil)
+	}
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("lib"), EqualsCondition{Version: SimpleVersion("1.0.0")}),
+		NewTerm(MakeName("missing"), NewVersionSetCondition(FullVersionSet())),
+	})
+	source.AddPackage(MakeName("app"), SimpleVersion("2.0.0"), []Term{
+		NewTerm(MakeName("lib"), NewVersionSetCondition(FullVersionSet())),
+	})
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), NewVersionSetCondition(FullVersionSet()))
+
+	estimate, err := EstimateComplexity(source, *root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// app, lib and the unpublished missing package.
+	if estimate.Packages != 3 || estimate.Versions != 6 || estimate.Truncated {
+		t.Fatalf("unexpected graph size: %+v", estimate)
+	}
+	// Three dependencies over app's 2 and lib's 4 sampled versions.
+	if estimate.AvgDependencies != 0.5 {
+		t.Fatalf("expected 0.5 dependencies per version, got %v", estimate.AvgDependencies)
+	}
+	// The pin on lib excludes 3 of 4 versions; the other requirements none.
+	if want := 0.75 / 3; estimate.Tightness != want {
+		t.Fatalf("expected tightness %v, got %v", want, estimate.Tightness)
+	}
+	if estimate.Score <= 0 || estimate.MaxSteps < 1000 || estimate.Timeout < time.Second {
+		t.Fatalf("unexpected recommendations: %+v", estimate)
+	}
+	if !strings.Contains(estimate.String(), "3 packages") {
+		t.Fatalf("unexpected summary %q", estimate)
+	}
+}
+
+func TestEstimateComplexityRanksLargerGraphsHarder(t *testing.T) {
+	root := RootSource{NewTerm(MakeName("pkg0"), NewVersionSetCondition(FullVersionSet()))}
+	small, _ := EstimateComplexity(chainSource(10), root)
+	large, _ := EstimateComplexity(chainSource(100), root)
+	if large.Score <= small.Score || large.MaxSteps < small.MaxSteps {
+		t.Fatalf("expected the larger graph to score higher: small %v, large %v", small, large)
+	}
+}
+
+func TestEstimateComplexityTruncatesAndReportsErrors(t *testing.T) {
+	root := RootSource{NewTerm(MakeName("pkg0"), NewVersionSetCondition(FullVersionSet()))}
+	source := chainSource(maxComplexityPackages + 10)
+	estimate, err := EstimateComplexity(source, root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !estimate.Truncated || estimate.Packages != maxComplexityPackages {
+		t.Fatalf("expected a truncated estimate, got %v", estimate)
+	}
+
+	broken := &brokenSource{Source: chainSource(3), versions: map[string]bool{"pkg1": true}}
+	if _, err := EstimateComplexity(broken, root); err == nil {
+		t.Fatal("expected the listing error to be returned")
+	}
+}
+
+// chainSource publishes a chain pkg0 -> pkg1 -> ... of n packages with
+// three versions each.
+func chainSource(n int) *InMemorySource {
+	source := &InMemorySource{}
+	for i := range n {
+		var deps []Term
+		if i+1 < n {
+			deps = []Term{NewTerm(MakeName(fmt.Sprintf("pkg%d", i+1)), NewVersionSetCondition(FullVersionSet()))}
+		}
+		for _, ver := range []string{"1.0.0", "2.0.0", "3.0.0"} {
+			source.AddPackage(MakeName(fmt.Sprintf("pkg%d", i)), SimpleVersion(ver), deps)
+		}
+	}
+	return source
+}
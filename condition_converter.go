@@ -0,0 +1,72 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package pubgrub
+
+import (
+	"reflect"
+	"sync"
+)
+
+// conditionConverters holds the converters registered with
+// RegisterConditionConverter, keyed by the dynamic type of the condition.
+var conditionConverters struct {
+	mu      sync.RWMutex
+	convert map[reflect.Type]func(Condition) VersionSet
+}
+
+// RegisterConditionConverter makes conditions of type C take part in the
+// solver's set algebra by converting them with convert, as if C
+// implemented VersionSetConverter. It is meant for condition types from
+// other packages that cannot be given a ToVersionSet method. C must be the
+// concrete type stored in Term.Condition, so a value type and its pointer
+// type are registered separately. Registering a type again replaces its
+// converter and a nil convert removes it. Conditions the solver knows
+// natively, and those implementing VersionSetConverter, ignore the
+// registry. Register converters during initialization, before solving.
+//
+// Example:
+//
+//	pubgrub.RegisterConditionConverter(func(c constraints.Caret) pubgrub.VersionSet {
+//	    lower := pubgrub.NewSemanticVersion(c.Major, c.Minor, c.Patch)
+//	    upper := pubgrub.NewSemanticVersion(c.Major+1, 0, 0)
+//	    return pubgrub.NewVersionRangeSet(lower, true, upper, false)
+//	})
+func RegisterConditionConverter[C Condition](convert func(C) VersionSet) {
+	typ := reflect.TypeFor[C]()
+	conditionConverters.mu.Lock()
+	defer conditionConverters.mu.Unlock()
+	if convert == nil {
+		delete(conditionConverters.convert, typ)
+		return
+	}
+	if conditionConverters.convert == nil {
+		conditionConverters.convert = make(map[reflect.Type]func(Condition) VersionSet)
+	}
+	conditionConverters.convert[typ] = func(cond Condition) VersionSet {
+		return convert(cond.(C))
+	}
+}
+
+// registeredVersionSet converts cond with the converter registered for its
+// type, if any.
+func registeredVersionSet(cond Condition) (VersionSet, bool) {
+	conditionConverters.mu.RLock()
+	convert, ok := conditionConverters.convert[reflect.TypeOf(cond)]
+	conditionConverters.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	set := convert(cond)
+	return set, set != nil
+}
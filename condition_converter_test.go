@@ -0,0 +1,53 @@
+package pubgrub
+
+import (
+	"fmt"
+	"testing"
+)
+
+// foreignTilde stands in for a condition type from another package that
+// has no ToVersionSet method.
+type foreignTilde struct {
+	Major, Minor int
+}
+
+func (c foreignTilde) String() string { return fmt.Sprintf("~%d.%d", c.Major, c.Minor) }
+
+func (c foreignTilde) Satisfies(ver Version) bool {
+	sv, ok := ver.(*SemanticVersion)
+	return ok && sv.Major == c.Major && sv.Minor == c.Minor
+}
+
+func TestRegisterConditionConverter(t *testing.T) {
+	source := &InMemorySource{}
+	for _, raw := range []string{"1.2.0", "1.2.5", "1.3.0"} {
+		source.AddPackage(MakeName("rack"), mustSemver(t, raw), nil)
+	}
+	root := NewRootSource()
+	root.AddPackage(MakeName("rack"), foreignTilde{Major: 1, Minor: 2})
+
+	if _, err := NewSolver(root, source).Solve(root.Term()); err == nil {
+		t.Fatalf("expected an unconvertible condition to fail the solve")
+	}
+
+	RegisterConditionConverter(func(c foreignTilde) VersionSet {
+		return NewVersionRangeSet(NewSemanticVersion(c.Major, c.Minor, 0), true, NewSemanticVersion(c.Major, c.Minor+1, 0), false)
+	})
+	t.Cleanup(func() { RegisterConditionConverter[foreignTilde](nil) })
+
+	solution, err := NewSolver(root, source).Solve(root.Term())
+	if err != nil {
+		t.Fatalf("solve: %v", err)
+	}
+	if v, ok := solution.GetVersion(MakeName("rack")); !ok || v.String() != "1.2.5" {
+		t.Fatalf("expected rack 1.2.5, got %v", v)
+	}
+
+	forbidden, ok := termForbiddenSet(NewNegativeTerm(MakeName("rack"), foreignTilde{Major: 1, Minor: 3}))
+	if !ok || !forbidden.Contains(mustSemver(t, "1.3.0")) || forbidden.Contains(mustSemver(t, "1.2.5")) {
+		t.Fatalf("expected negative terms to convert too, got %v", forbidden)
+	}
+	if _, ok := termAllowedSet(NewTerm(MakeName("rack"), &foreignTilde{Major: 1, Minor: 2})); ok {
+		t.Fatalf("the pointer type was not registered")
+	}
+}
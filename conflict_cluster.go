@@ -0,0 +1,212 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package pubgrub
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ConflictCluster is a recurring conflict pattern seen by a
+// ConflictAnalyzer.
+type ConflictCluster struct {
+	// Fingerprint identifies the pattern, see ConflictFingerprint.
+	Fingerprint string
+	// Count is the number of failures with this fingerprint.
+	Count int
+	// Shapes are the normalized external facts the failures share, sorted.
+	Shapes []string
+	// Packages lists the packages the shapes mention, sorted.
+	Packages []string
+	// Example is the message of the first failure in the cluster.
+	Example string
+}
+
+// ConflictAnalyzer clusters the failures of many solves by the conflict
+// that caused them, so a resolver service can tell which ecosystem-wide
+// constraint causes most failures. It is safe for concurrent use.
+//
+// Example:
+//
+//	analyzer := NewConflictAnalyzer()
+//	for _, job := range jobs {
+//	    _, err := job.Solver.Solve(job.Root)
+//	    analyzer.Add(err)
+//	}
+//	for _, cluster := range analyzer.Clusters()[:3] {
+//	    fmt.Printf("%d failures: %s\n", cluster.Count, strings.Join(cluster.Shapes, "; "))
+//	}
+type ConflictAnalyzer struct {
+	mu       sync.Mutex
+	clusters map[string]*ConflictCluster
+}
+
+// NewConflictAnalyzer returns an empty ConflictAnalyzer.
+func NewConflictAnalyzer() *ConflictAnalyzer {
+	return &ConflictAnalyzer{clusters: make(map[string]*ConflictCluster)}
+}
+
+// Add records a failed solve and returns its fingerprint. It reports false,
+// recording nothing, when err carries no derivation, that is when it is not
+// a NoSolutionError from a solve with incompatibility tracking.
+func (a *ConflictAnalyzer) Add(err error) (string, bool) {
+	var nsErr *NoSolutionError
+	if !errors.As(err, &nsErr) || nsErr.Incompatibility == nil {
+		return "", false
+	}
+	shapes := conflictShapes(nsErr.Incompatibility)
+	fingerprint := fingerprintShapes(shapes)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.clusters == nil {
+		a.clusters = make(map[string]*ConflictCluster)
+	}
+	cluster, ok := a.clusters[fingerprint]
+	if !ok {
+		cluster = &ConflictCluster{
+			Fingerprint: fingerprint,
+			Shapes:      shapes,
+			Packages:    shapePackages(nsErr.Incompatibility),
+			Example:     err.Error(),
+		}
+		a.clusters[fingerprint] = cluster
+	}
+	cluster.Count++
+	return fingerprint, true
+}
+
+// Clusters returns the clusters seen so far, most frequent first.
+func (a *ConflictAnalyzer) Clusters() []ConflictCluster {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	result := make([]ConflictCluster, 0, len(a.clusters))
+	for _, cluster := range a.clusters {
+		result = append(result, *cluster)
+	}
+	slices.SortFunc(result, func(x, y ConflictCluster) int {
+		if c := cmp.Compare(y.Count, x.Count); c != 0 {
+			return c
+		}
+		return strings.Compare(x.Fingerprint, y.Fingerprint)
+	})
+	return result
+}
+
+// ConflictFingerprint returns an identifier shared by derivations that fail
+// for the same reason. It hashes the external facts at the leaves of the
+// derivation, so the order in which the solver found them does not matter,
+// and it normalizes the facts that differ between projects: facts about the
+// root package and "no versions" facts keep only the package names they
+// mention, not their ranges. Two projects that require different versions of a framework
+// whose dependencies conflict therefore share a fingerprint.
+func ConflictFingerprint(incomp *Incompatibility) string {
+	return fingerprintShapes(conflictShapes(incomp))
+}
+
+// conflictShapes returns the sorted, distinct normalized leaves of incomp.
+func conflictShapes(incomp *Incompatibility) []string {
+	roots := rootNames(incomp)
+	var shapes []string
+	for _, leaf := range conflictLeaves(incomp) {
+		shapes = append(shapes, leafShape(leaf, roots))
+	}
+	slices.Sort(shapes)
+	return slices.Compact(shapes)
+}
+
+// conflictLeaves returns the distinct external incompatibilities of a
+// derivation, visiting shared subtrees once.
+func conflictLeaves(incomp *Incompatibility) []*Incompatibility {
+	var leaves []*Incompatibility
+	seen := make(map[*Incompatibility]bool)
+	var walk func(*Incompatibility)
+	walk = func(inc *Incompatibility) {
+		if inc == nil || seen[inc] {
+			return
+		}
+		seen[inc] = true
+		if inc.Cause1 != nil && inc.Cause2 != nil {
+			walk(inc.Cause1)
+			walk(inc.Cause2)
+			return
+		}
+		leaves = append(leaves, inc)
+	}
+	walk(incomp)
+	return leaves
+}
+
+// rootNames returns the root package names: the synthetic "$$root" and
+// the package of a final incompatibility with a single term, which for a
+// failed solve is the root.
+func rootNames(incomp *Incompatibility) map[Name]bool {
+	roots := map[Name]bool{MakeName("$$root"): true}
+	if incomp != nil && len(incomp.Terms) == 1 {
+		roots[incomp.Terms[0].Name] = true
+	}
+	return roots
+}
+
+// leafShape renders an external incompatibility with root terms reduced
+// to "root". Ranges are dropped from facts involving the root and from
+// "no versions" facts, whose ranges are what is left of some requirement
+// and vary with it; the requirement itself is a leaf of its own.
+func leafShape(inc *Incompatibility, roots map[Name]bool) string {
+	dropRanges := inc.Kind == KindNoVersions || slices.ContainsFunc(inc.Terms, func(term Term) bool { return roots[term.Name] })
+	terms := make([]string, 0, len(inc.Terms))
+	for _, term := range inc.Terms {
+		switch {
+		case roots[term.Name]:
+			terms = append(terms, "root")
+		case dropRanges:
+			terms = append(terms, term.Name.Value())
+		default:
+			terms = append(terms, canonicalTerm(term))
+		}
+	}
+	slices.Sort(terms)
+	return inc.Kind.String() + "(" + strings.Join(terms, ", ") + ")"
+}
+
+// shapePackages returns the non-root packages named by the leaves of
+// incomp, sorted.
+func shapePackages(incomp *Incompatibility) []string {
+	roots := rootNames(incomp)
+	var names []string
+	for _, leaf := range conflictLeaves(incomp) {
+		for _, term := range leaf.Terms {
+			if !roots[term.Name] {
+				names = append(names, term.Name.Value())
+			}
+		}
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// fingerprintShapes hashes sorted shapes into a short identifier.
+func fingerprintShapes(shapes []string) string {
+	hash := sha256.New()
+	for _, shape := range shapes {
+		hash.Write([]byte(shape))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil)[:8])
+}
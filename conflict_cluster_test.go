@@ -0,0 +1,68 @@
+package pubgrub
+
+import (
+	"errors"
+	"testing"
+)
+
+func solveForCluster(t *testing.T, source Source, requirements map[string]string) error {
+	t.Helper()
+	root := NewRootSource()
+	for name, constraint := range requirements {
+		root.AddPackage(MakeName(name), NewVersionSetCondition(mustParseVersionRange(t, constraint)))
+	}
+	_, err := NewSolverWithOptions([]Source{root, source}, WithIncompatibilityTracking(true)).Solve(root.Term())
+	if err == nil {
+		t.Fatalf("expected %v to fail", requirements)
+	}
+	return err
+}
+
+func TestConflictAnalyzerClustersAcrossProjects(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("framework"), mustSemver(t, "1.1.0"), []Term{
+		NewTerm(MakeName("lib"), NewVersionSetCondition(mustParseVersionRange(t, ">=2.0.0"))),
+	})
+	source.AddPackage(MakeName("lib"), mustSemver(t, "1.0.0"), nil)
+	source.AddPackage(MakeName("tool"), mustSemver(t, "1.0.0"), []Term{
+		NewTerm(MakeName("lib"), NewVersionSetCondition(mustParseVersionRange(t, "<1.0.0"))),
+	})
+	analyzer := NewConflictAnalyzer()
+
+	first, ok := analyzer.Add(solveForCluster(t, source, map[string]string{"framework": ">=1.0.0"}))
+	if !ok {
+		t.Fatalf("expected a NoSolutionError to be recorded")
+	}
+	second, _ := analyzer.Add(solveForCluster(t, source, map[string]string{"framework": ">=1.1.0, <2.0.0"}))
+	if first != second {
+		t.Fatalf("projects with different root ranges should share a fingerprint")
+	}
+	third, _ := analyzer.Add(solveForCluster(t, source, map[string]string{"tool": ">=1.0.0"}))
+	if third == first {
+		t.Fatalf("a different conflict should get its own fingerprint")
+	}
+
+	clusters := analyzer.Clusters()
+	if len(clusters) != 2 || clusters[0].Fingerprint != first || clusters[0].Count != 2 || clusters[1].Count != 1 {
+		t.Fatalf("unexpected clusters %+v", clusters)
+	}
+	if got := clusters[0].Packages; len(got) != 2 || got[0] != "framework" || got[1] != "lib" {
+		t.Fatalf("expected framework and lib, got %v", got)
+	}
+	if clusters[0].Example == "" || len(clusters[0].Shapes) == 0 {
+		t.Fatalf("expected an example and shapes, got %+v", clusters[0])
+	}
+}
+
+func TestConflictAnalyzerIgnoresOtherErrors(t *testing.T) {
+	analyzer := NewConflictAnalyzer()
+	if _, ok := analyzer.Add(errors.New("timeout")); ok {
+		t.Fatalf("plain errors carry no derivation")
+	}
+	if _, ok := analyzer.Add(ErrNoSolutionFound{}); ok {
+		t.Fatalf("untracked failures carry no derivation")
+	}
+	if len(analyzer.Clusters()) != 0 {
+		t.Fatalf("expected no clusters")
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import "context"
+
+// ContextSource is a Source that accepts the context of the solve, so
+// registry implementations can honor deadlines, carry request-scoped values
+// such as auth tokens, and join traces. SolveContext passes its ctx to every
+// ContextSource call; the solver's own wrappers (CombinedSource,
+// CachedSource, MirrorSource, RecordingSource, ValidatingSource and the
+// consistency guard) forward it to the sources they wrap. The plain Source
+// methods remain for callers without a context.
+//
+// Example:
+//
+//	func (rs *RegistrySource) GetVersionsCtx(ctx context.Context, name Name) ([]Version, error) {
+//	    req, _ := http.NewRequestWithContext(ctx, "GET", rs.BaseURL+"/packages/"+name.Value(), nil)
+//	    req.Header.Set("Authorization", "Bearer "+TokenFrom(ctx))
+//	    // ... send and parse ...
+//	}
+type ContextSource interface {
+	Source
+	// GetVersionsCtx is GetVersions with the context of the solve.
+	GetVersionsCtx(ctx context.Context, name Name) ([]Version, error)
+	// GetDependenciesCtx is GetDependencies with the context of the solve.
+	GetDependenciesCtx(ctx context.Context, name Name, version Version) ([]Term, error)
+}
+
+// SourceWithContext is an alias of ContextSource.
+type SourceWithContext = ContextSource
+
+// AdaptSource returns source as a ContextSource. A source that already
+// implements ContextSource is returned unchanged; any other is wrapped so
+// that calls fail with ctx.Err() once ctx is done and otherwise run the
+// legacy method, which cannot itself be interrupted.
+//
+// Example:
+//
+//	source := AdaptSource(legacy)
+//	versions, err := source.GetVersionsCtx(ctx, MakeName("rails"))
+func AdaptSource(source Source) ContextSource {
+	if cs, ok := source.(ContextSource); ok {
+		return cs
+	}
+	return legacySource{source}
+}
+
+// legacySource adapts a Source without context support.
+type legacySource struct {
+	Source
+}
+
+// GetVersionsCtx implements ContextSource.
+func (s legacySource) GetVersionsCtx(ctx context.Context, name Name) ([]Version, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.GetVersions(name)
+}
+
+// GetDependenciesCtx implements ContextSource.
+func (s legacySource) GetDependenciesCtx(ctx context.Context, name Name, version Version) ([]Term, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.GetDependencies(name, version)
+}
+
+// getVersionsCtx lists the versions of name, passing ctx along when source
+// accepts it.
+func getVersionsCtx(ctx context.Context, source Source, name Name) ([]Version, error) {
+	if cs, ok := source.(ContextSource); ok {
+		return cs.GetVersionsCtx(ctx, name)
+	}
+	return source.GetVersions(name)
+}
+
+// getDependenciesCtx fetches the dependencies of name@version, passing ctx
+// along when source accepts it.
+func getDependenciesCtx(ctx context.Context, source Source, name Name, version Version) ([]Term, error) {
+	if cs, ok := source.(ContextSource); ok {
+		return cs.GetDependenciesCtx(ctx, name, version)
+	}
+	return source.GetDependencies(name, version)
+}
+
+var (
+	_ ContextSource = legacySource{}
+	_ ContextSource = rootedSource{}
+	_ ContextSource = (*MirrorSource)(nil)
+	_ ContextSource = (*ValidatingSource)(nil)
+)
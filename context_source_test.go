@@ -0,0 +1,159 @@
+package pubgrub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type tokenKey struct{}
+
+// tokenSource is a ContextSource that requires an auth token in its context.
+type tokenSource struct {
+	InMemorySource
+	tokens []string
+}
+
+func (s *tokenSource) GetVersionsCtx(ctx context.Context, name Name) ([]Version, error) {
+	s.tokens = append(s.tokens, ctx.Value(tokenKey{}).(string))
+	return s.GetVersions(name)
+}
+
+func (s *tokenSource) GetDependenciesCtx(ctx context.Context, name Name, version Version) ([]Term, error) {
+	s.tokens = append(s.tokens, ctx.Value(tokenKey{}).(string))
+	return s.GetDependencies(name, version)
+}
+
+func TestSolveContextPassesContextToSources(t *testing.T) {
+	source := &tokenSource{}
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("lib"), NewVersionSetCondition(FullVersionSet())),
+	})
+	source.AddPackage(MakeName("lib"), SimpleVersion("1.0.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), NewVersionSetCondition(FullVersionSet()))
+
+	for _, mode := range []ConsistencyMode{ConsistencySnapshot, ConsistencyDetect, ConsistencyOff} {
+		source.tokens = nil
+		ctx := context.WithValue(context.Background(), tokenKey{}, "secret")
+		if _, err := NewSolver(root, source).SolveContext(ctx, root.Term(), WithConsistencyCheck(mode)); err != nil {
+			t.Fatalf("mode %d: unexpected error: %v", mode, err)
+		}
+		if len(source.tokens) == 0 {
+			t.Fatalf("mode %d: expected the source to be called with the solve context", mode)
+		}
+		for _, token := range source.tokens {
+			if token != "secret" {
+				t.Fatalf("mode %d: expected every call to carry the token, got %q", mode, token)
+			}
+		}
+	}
+}
+
+func TestAdaptSourceHonorsCancellation(t *testing.T) {
+	inner := &InMemorySource{}
+	inner.AddPackage(MakeName("lib"), SimpleVersion("1.0.0"), nil)
+	source := AdaptSource(inner)
+
+	if versions, err := source.GetVersionsCtx(context.Background(), MakeName("lib")); err != nil || len(versions) != 1 {
+		t.Fatalf("expected the legacy answer, got %v, %v", versions, err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := source.GetDependenciesCtx(ctx, MakeName("lib"), SimpleVersion("1.0.0")); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if AdaptSource(source) != source {
+		t.Fatalf("expected a ContextSource to be returned unchanged")
+	}
+}
+
+// hangingSource blocks dependency fetches of hang until the context ends,
+// like a registry that stopped responding.
+type hangingSource struct {
+	InMemorySource
+	hang Name
+}
+
+func (s *hangingSource) GetVersionsCtx(ctx context.Context, name Name) ([]Version, error) {
+	return s.GetVersions(name)
+}
+
+func (s *hangingSource) GetDependenciesCtx(ctx context.Context, name Name, version Version) ([]Term, error) {
+	if name == s.hang {
+		<-ctx.Done()
+		return nil, fmt.Errorf("fetch %s: %w", FormatName(name), ctx.Err())
+	}
+	return s.GetDependencies(name, version)
+}
+
+func TestSolveContextTimeoutInterruptsSourceCalls(t *testing.T) {
+	source := &hangingSource{hang: MakeName("lib")}
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("lib"), NewVersionSetCondition(FullVersionSet())),
+	})
+	source.AddPackage(MakeName("lib"), SimpleVersion("1.0.0"), nil)
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), NewVersionSetCondition(FullVersionSet()))
+
+	for _, aggregate := range []bool{false, true} {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		_, err := NewSolverWithOptions([]Source{root, source}, WithSourceErrorAggregation(aggregate)).SolveContext(ctx, root.Term())
+		cancel()
+		if err != context.DeadlineExceeded {
+			t.Fatalf("aggregate %v: expected ctx.Err() itself, got %v", aggregate, err)
+		}
+	}
+}
+
+func TestSourceWrappersForwardContext(t *testing.T) {
+	source := &tokenSource{}
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("lib"), NewVersionSetCondition(FullVersionSet())),
+	})
+	source.AddPackage(MakeName("lib"), SimpleVersion("1.0.0"), nil)
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), NewVersionSetCondition(FullVersionSet()))
+
+	wrappers := map[string]Source{
+		"mirror":     NewMirrorSource(Mirror{Name: "primary", Source: source}),
+		"recording":  NewRecordingSource(source),
+		"validating": NewValidatingSource(source, ValidationReject),
+	}
+	for name, wrapped := range wrappers {
+		source.tokens = nil
+		ctx := context.WithValue(context.Background(), tokenKey{}, "secret")
+		if _, err := NewSolver(root, wrapped).SolveContext(ctx, root.Term()); err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if len(source.tokens) == 0 {
+			t.Fatalf("%s: expected the wrapped source to see the solve context", name)
+		}
+	}
+}
+
+func TestMirrorSourceStopsOnCancellation(t *testing.T) {
+	primary := &hangingSource{hang: MakeName("lib")}
+	primary.AddPackage(MakeName("lib"), SimpleVersion("1.0.0"), nil)
+	fallback := &flakySource{Source: &primary.InMemorySource}
+	mirrors := NewMirrorSource(
+		Mirror{Name: "primary", Source: primary},
+		Mirror{Name: "fallback", Source: fallback},
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := mirrors.GetDependenciesCtx(ctx, MakeName("lib"), SimpleVersion("1.0.0"))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline error, got %v", err)
+	}
+	if fallback.calls != 0 {
+		t.Fatalf("expected no fallback after cancellation, got %d calls", fallback.calls)
+	}
+	if health := mirrors.Health(); health[0].Failures != 0 {
+		t.Fatalf("expected cancellation not to count against the mirror: %+v", health[0])
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package pubgrub
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CoverageReport describes how a VersionSet partitions a list of published
+// versions, see Coverage.
+type CoverageReport struct {
+	// Inside and Outside list the published versions the set contains and
+	// excludes, in published order.
+	Inside  []Version
+	Outside []Version
+	// Boundaries lists the published versions that sit exactly on a bound
+	// of the set, in bound order. A version on both bounds of a singleton
+	// is listed twice.
+	Boundaries []BoundaryHit
+}
+
+// BoundaryHit is a published version equal to a finite bound of a set.
+type BoundaryHit struct {
+	Version Version
+	// Lower reports whether the bound is a lower bound.
+	Lower bool
+	// Inclusive reports whether the bound includes Version.
+	Inclusive bool
+}
+
+// String renders the hit as, for example, "2.0.0 (upper, exclusive)".
+func (h BoundaryHit) String() string {
+	side, kind := "upper", "exclusive"
+	if h.Lower {
+		side = "lower"
+	}
+	if h.Inclusive {
+		kind = "inclusive"
+	}
+	return fmt.Sprintf("%s (%s, %s)", h.Version, side, kind)
+}
+
+// Coverage reports which published versions fall inside and outside set,
+// and which sit exactly on one of its bounds. Adapter authors use it to
+// check a range translation against the upstream ecosystem's semantics:
+// the inside/outside split catches wrong ranges, and boundary hits catch
+// off-by-one mistakes such as an inclusive bound that should be exclusive.
+// Bounds are only known for sets built from intervals, such as those
+// returned by ParseVersionRange; for other VersionSet implementations
+// Boundaries is empty.
+//
+// Example:
+//
+//	set, _ := ParseNpmRange("^1.2.0")
+//	report := Coverage(set, published)
+//	fmt.Println(report)
+//	// inside: 1.2.0, 1.9.1
+//	// outside: 1.1.0, 2.0.0
+//	// boundaries: 1.2.0 (lower, inclusive), 2.0.0 (upper, exclusive)
+func Coverage(set VersionSet, published []Version) CoverageReport {
+	var report CoverageReport
+	for _, version := range published {
+		if set.Contains(version) {
+			report.Inside = append(report.Inside, version)
+		} else {
+			report.Outside = append(report.Outside, version)
+		}
+	}
+
+	intervals, ok := set.(*VersionIntervalSet)
+	if !ok {
+		return report
+	}
+	for interval := range intervals.Intervals() {
+		report.Boundaries = appendBoundaryHits(report.Boundaries, interval.lower, true, published)
+		report.Boundaries = appendBoundaryHits(report.Boundaries, interval.upper, false, published)
+	}
+	return report
+}
+
+// appendBoundaryHits appends a hit for each published version on bound.
+func appendBoundaryHits(hits []BoundaryHit, bound versionBound, lower bool, published []Version) []BoundaryHit {
+	if !bound.isFinite() {
+		return hits
+	}
+	for _, version := range published {
+		if version.Sort(bound.version) == 0 {
+			hits = append(hits, BoundaryHit{Version: version, Lower: lower, Inclusive: bound.inclusive})
+		}
+	}
+	return hits
+}
+
+// String renders the report one line per field, for comparing against a
+// golden file.
+func (r CoverageReport) String() string {
+	var b strings.Builder
+	b.WriteString("inside: ")
+	b.WriteString(joinVersions(r.Inside))
+	b.WriteString("\noutside: ")
+	b.WriteString(joinVersions(r.Outside))
+	b.WriteString("\nboundaries: ")
+	hits := make([]string, len(r.Boundaries))
+	for i, hit := range r.Boundaries {
+		hits[i] = hit.String()
+	}
+	b.WriteString(strings.Join(hits, ", "))
+	return b.String()
+}
+
+// joinVersions renders versions as a comma-separated list.
+func joinVersions(versions []Version) string {
+	parts := make([]string, len(versions))
+	for i, version := range versions {
+		parts[i] = version.String()
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,43 @@
+package pubgrub
+
+import "testing"
+
+func TestCoverageSplitsPublishedVersions(t *testing.T) {
+	var published []Version
+	for _, raw := range []string{"1.1.0", "1.2.0", "1.9.1", "2.0.0"} {
+		published = append(published, mustSemver(t, raw))
+	}
+	set, err := ParseNpmRange("^1.2.0")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	report := Coverage(set, published)
+	want := "inside: 1.2.0, 1.9.1\n" +
+		"outside: 1.1.0, 2.0.0\n" +
+		"boundaries: 1.2.0 (lower, inclusive), 2.0.0 (upper, exclusive)"
+	if got := report.String(); got != want {
+		t.Fatalf("unexpected report:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCoverageReportsBothBoundsOfExclusion(t *testing.T) {
+	published := []Version{mustSemver(t, "1.0.0"), mustSemver(t, "1.5.0")}
+	set, err := ParseVersionRange("!=1.5.0")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	report := Coverage(set, published)
+	if len(report.Inside) != 1 || len(report.Outside) != 1 {
+		t.Fatalf("expected one version on each side, got %s", report)
+	}
+	if len(report.Boundaries) != 2 {
+		t.Fatalf("expected 1.5.0 on two exclusive bounds, got %v", report.Boundaries)
+	}
+	for _, hit := range report.Boundaries {
+		if hit.Inclusive || hit.Version.String() != "1.5.0" {
+			t.Fatalf("unexpected boundary hit %s", hit)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package pubgrub
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Decision journal event kinds, see JournalEntry.Event.
+const (
+	JournalDecision   = "decision"
+	JournalDerivation = "derivation"
+	JournalConflict   = "conflict"
+	JournalBacktrack  = "backtrack"
+)
+
+// JournalEntry is one line written by WithDecisionJournal. Fields that do
+// not apply to an event are omitted.
+type JournalEntry struct {
+	Time time.Time `json:"time"`
+	// Step is the solver main loop iteration, starting at 1; zero for the
+	// root decision made before the loop.
+	Step  int    `json:"step"`
+	Event string `json:"event"`
+	// Package is the package assigned, or the package whose decision was
+	// undone by a backtrack.
+	Package string `json:"package,omitempty"`
+	// Version is the version chosen by a decision.
+	Version string `json:"version,omitempty"`
+	// Term is the constraint a derivation adds.
+	Term string `json:"term,omitempty"`
+	// Level is the decision level of an assignment, or the level a
+	// backtrack returns to.
+	Level int `json:"level"`
+	// Incompatibility is the cause of a derivation, the conflict found, or
+	// the clause learned by a backtrack, with its stable ID.
+	Incompatibility   string `json:"incompatibility,omitempty"`
+	IncompatibilityID string `json:"incompatibility_id,omitempty"`
+}
+
+// journalAssignment writes a decision or derivation to the decision journal.
+func (st *solverState) journalAssignment(assign *assignment) {
+	if st.options.DecisionJournal == nil || assign == nil {
+		return
+	}
+	entry := JournalEntry{Event: JournalDerivation, Package: assign.name.Value(), Level: assign.decisionLevel}
+	if assign.isDecision() {
+		entry.Event = JournalDecision
+		entry.Version = assign.version.String()
+	} else {
+		entry.Term = assign.term.String()
+	}
+	st.writeJournal(entry, assign.cause)
+}
+
+// journalIncompatibility writes a conflict, or a backtrack to level with
+// the clause it learned, to the decision journal.
+func (st *solverState) journalIncompatibility(event string, pkg Name, level int, incomp *Incompatibility) {
+	if st.options.DecisionJournal == nil {
+		return
+	}
+	entry := JournalEntry{Event: event, Level: level}
+	if pkg != (Name{}) {
+		entry.Package = pkg.Value()
+	}
+	st.writeJournal(entry, incomp)
+}
+
+// writeJournal stamps entry and writes it as one JSON line. The first
+// write error is logged and ends the journal for the rest of the solve;
+// the solve itself carries on.
+func (st *solverState) writeJournal(entry JournalEntry, incomp *Incompatibility) {
+	if st.journalErr != nil {
+		return
+	}
+	if st.journalOut == nil {
+		st.journalOut = json.NewEncoder(st.options.DecisionJournal)
+	}
+	entry.Time = time.Now()
+	entry.Step = st.steps
+	if incomp != nil {
+		entry.Incompatibility = incomp.String()
+		entry.IncompatibilityID = incomp.ID()
+	}
+	if err := st.journalOut.Encode(entry); err != nil {
+		st.journalErr = err
+		st.debug("decision journal write failed", "error", err)
+	}
+}
@@ -0,0 +1,70 @@
+package pubgrub
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestDecisionJournalWritesOneLinePerEvent(t *testing.T) {
+	root, source := conflictingWideWorkload(t, 4)
+	var buf bytes.Buffer
+	solver := NewSolverWithOptions([]Source{root, source}, WithVersionStrategy(VersionNewest), WithDecisionJournal(&buf))
+	if _, err := solver.Solve(root.Term()); err != nil {
+		t.Fatalf("solve: %v", err)
+	}
+
+	counts := make(map[string]int)
+	lastStep := 0
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var entry JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("line %q is not a JournalEntry: %v", scanner.Text(), err)
+		}
+		if entry.Time.IsZero() {
+			t.Fatalf("entry without timestamp: %s", scanner.Text())
+		}
+		if entry.Step < lastStep {
+			t.Fatalf("steps went backwards at %s", scanner.Text())
+		}
+		lastStep = entry.Step
+		counts[entry.Event]++
+		if entry.Event == JournalBacktrack && (entry.Package == "" || entry.IncompatibilityID == "") {
+			t.Fatalf("backtrack without pivot or learned clause: %s", scanner.Text())
+		}
+	}
+
+	stats := solver.Stats()
+	if counts[JournalDecision] != stats.Decisions+1 {
+		t.Fatalf("expected %d decisions including the root, got %d", stats.Decisions+1, counts[JournalDecision])
+	}
+	for _, event := range []string{JournalDerivation, JournalConflict, JournalBacktrack} {
+		if counts[event] == 0 {
+			t.Fatalf("expected %s events, got %v", event, counts)
+		}
+	}
+	if counts[JournalBacktrack] != stats.Backtracks {
+		t.Fatalf("expected %d backtracks, got %d", stats.Backtracks, counts[JournalBacktrack])
+	}
+}
+
+type failingWriter struct{ writes int }
+
+func (w *failingWriter) Write([]byte) (int, error) {
+	w.writes++
+	return 0, errors.New("disk full")
+}
+
+func TestDecisionJournalWriteErrorDoesNotFailSolve(t *testing.T) {
+	root, source := conflictingWideWorkload(t, 2)
+	w := &failingWriter{}
+	if _, err := NewSolverWithOptions([]Source{root, source}, WithDecisionJournal(w)).Solve(root.Term()); err != nil {
+		t.Fatalf("solve: %v", err)
+	}
+	if w.writes != 1 {
+		t.Fatalf("expected the journal to stop after the first failed write, got %d writes", w.writes)
+	}
+}
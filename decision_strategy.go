@@ -0,0 +1,172 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"slices"
+	"strings"
+)
+
+// DecisionStrategy chooses which package the solver decides next and which
+// version it picks, for heuristics such as registry popularity or lockfile
+// proximity that the built-in options do not cover. Either method may defer
+// to the built-in behaviour; embed DefaultDecisionStrategy to override only
+// one of them.
+//
+// Whatever the strategy returns, the solver stays complete: a version that
+// leads to a conflict is ruled out and another is picked.
+//
+// Experimental: DecisionStrategy and DefaultDecisionStrategy may change in a
+// minor release while the set of decision heuristics settles.
+type DecisionStrategy interface {
+	// NextPackage chooses the package to decide next from candidates, the
+	// required packages without a decision, sorted by name and never empty.
+	// Returning a name outside candidates, such as EmptyName(), selects the
+	// most constrained package as usual.
+	NextPackage(candidates []Name, view SolverView) Name
+
+	// PickVersion chooses the version of name to try from versions, the
+	// published versions allowed by the current constraints, oldest first
+	// and never empty. Returning nil, or a version outside versions, defers
+	// to the VersionStrategy. Installed and preferred versions are kept
+	// before the strategy is asked.
+	PickVersion(name Name, allowed VersionSet, versions []Version) Version
+}
+
+// SolverView is a read-only view of a solve in progress, see
+// DecisionStrategy. It is only valid during the call it is passed to.
+type SolverView interface {
+	// Root returns the name of the root package.
+	Root() Name
+	// Allowed returns the versions of name the current assignments allow.
+	Allowed(name Name) VersionSet
+	// Decided returns the version decided for name, if any.
+	Decided(name Name) (Version, bool)
+	// DecisionLevel returns the number of decisions in force.
+	DecisionLevel() int
+}
+
+// DefaultDecisionStrategy defers both choices to the built-in behaviour.
+// Embed it in a strategy that only overrides one method.
+//
+// Example:
+//
+//	type popularFirst struct {
+//	    DefaultDecisionStrategy
+//	    downloads map[Name]int
+//	}
+//
+//	func (p popularFirst) NextPackage(candidates []Name, _ SolverView) Name {
+//	    return slices.MaxFunc(candidates, func(a, b Name) int {
+//	        return cmp.Compare(p.downloads[a], p.downloads[b])
+//	    })
+//	}
+type DefaultDecisionStrategy struct{}
+
+// NextPackage implements DecisionStrategy, deferring to the solver.
+func (DefaultDecisionStrategy) NextPackage([]Name, SolverView) Name {
+	return EmptyName()
+}
+
+// PickVersion implements DecisionStrategy, deferring to the solver.
+func (DefaultDecisionStrategy) PickVersion(Name, VersionSet, []Version) Version {
+	return nil
+}
+
+// solverView implements SolverView over the partial solution.
+type solverView struct {
+	partial *partialSolution
+}
+
+// Root implements SolverView.
+func (v solverView) Root() Name {
+	return v.partial.root
+}
+
+// Allowed implements SolverView.
+func (v solverView) Allowed(name Name) VersionSet {
+	return v.partial.allowedSet(name)
+}
+
+// Decided implements SolverView.
+func (v solverView) Decided(name Name) (Version, bool) {
+	for _, assign := range v.partial.perPackage[name] {
+		if assign.kind == assignmentDecision {
+			return assign.version, true
+		}
+	}
+	return nil, false
+}
+
+// DecisionLevel implements SolverView.
+func (v solverView) DecisionLevel() int {
+	return v.partial.decisionLvl
+}
+
+// nextPackage returns the package to decide next, asking the configured
+// DecisionStrategy when there is one.
+func (st *solverState) nextPackage() (Name, bool) {
+	strategy := st.options.DecisionStrategy
+	if strategy == nil {
+		return st.partial.nextDecisionCandidate()
+	}
+
+	var candidates []Name
+	for name := range st.partial.perPackage {
+		if name != st.partial.root && !st.partial.hasDecision(name) && st.partial.isRequired(name) {
+			candidates = append(candidates, name)
+		}
+	}
+	if len(candidates) == 0 {
+		return EmptyName(), false
+	}
+	slices.SortFunc(candidates, func(a, b Name) int { return strings.Compare(a.Value(), b.Value()) })
+
+	if chosen := strategy.NextPackage(slices.Clone(candidates), solverView{st.partial}); slices.Contains(candidates, chosen) {
+		return chosen, true
+	}
+	return st.partial.nextDecisionCandidate()
+}
+
+// strategyPick asks the configured DecisionStrategy for a version of name
+// among the published versions allowed.
+func (st *solverState) strategyPick(name Name, versions []Version, allowed VersionSet) (Version, bool) {
+	strategy := st.options.DecisionStrategy
+	if strategy == nil {
+		return nil, false
+	}
+	candidates := make([]Version, 0, len(versions))
+	for _, ver := range versions {
+		if allowed.Contains(ver) {
+			candidates = append(candidates, ver)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, false
+	}
+
+	chosen := strategy.PickVersion(name, allowed, slices.Clone(candidates))
+	if chosen == nil {
+		return nil, false
+	}
+	for _, ver := range candidates {
+		if ver.Sort(chosen) == 0 {
+			return ver, true
+		}
+	}
+	return nil, false
+}
+
+var _ DecisionStrategy = DefaultDecisionStrategy{}
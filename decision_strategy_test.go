@@ -0,0 +1,133 @@
+package pubgrub
+
+import (
+	"slices"
+	"testing"
+)
+
+// reverseStrategy decides packages in reverse name order and picks the
+// oldest version, recording what the view showed.
+type reverseStrategy struct {
+	DefaultDecisionStrategy
+	decidedBefore map[string]int
+	root          Name
+	levelMismatch bool
+}
+
+func (r *reverseStrategy) NextPackage(candidates []Name, view SolverView) Name {
+	r.root = view.Root()
+	chosen := candidates[len(candidates)-1]
+	decided := 0
+	for _, name := range []string{"a", "b", "c"} {
+		if _, ok := view.Decided(MakeName(name)); ok {
+			decided++
+		}
+	}
+	if decided != view.DecisionLevel() {
+		r.levelMismatch = true
+	}
+	r.decidedBefore[chosen.Value()] = decided
+	return chosen
+}
+
+func (r *reverseStrategy) PickVersion(_ Name, _ VersionSet, versions []Version) Version {
+	return versions[0]
+}
+
+func TestDecisionStrategyChoosesPackagesAndVersions(t *testing.T) {
+	source := &InMemorySource{}
+	for _, pkg := range []string{"a", "b", "c"} {
+		for _, ver := range []string{"1.0.0", "2.0.0"} {
+			source.AddPackage(MakeName(pkg), SimpleVersion(ver), nil)
+		}
+	}
+	root := NewRootSource()
+	for _, pkg := range []string{"a", "b", "c"} {
+		root.AddPackage(MakeName(pkg), NewVersionSetCondition(FullVersionSet()))
+	}
+
+	strategy := &reverseStrategy{decidedBefore: make(map[string]int)}
+	var order []string
+	solver := NewSolverWithOptions([]Source{root, source},
+		WithDecisionStrategy(strategy),
+		WithHooks(SolverHooks{AfterDecision: func(name Name, _ Version) {
+			order = append(order, name.Value())
+		}}),
+	)
+
+	solution, err := solver.Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"c", "b", "a"}; !slices.Equal(order, want) {
+		t.Fatalf("expected decisions in order %v, got %v", want, order)
+	}
+	if strategy.levelMismatch {
+		t.Fatal("expected the decision level to count the decided packages")
+	}
+	if strategy.decidedBefore["a"] != 2 || strategy.root != MakeName("$$root") {
+		t.Fatalf("unexpected view: %v, root %s", strategy.decidedBefore, strategy.root.Value())
+	}
+	for _, pkg := range []string{"a", "b", "c"} {
+		if ver, _ := solution.GetVersion(MakeName(pkg)); ver.String() != "1.0.0" {
+			t.Fatalf("expected the strategy's oldest %s, got %v", pkg, ver)
+		}
+	}
+}
+
+func TestDefaultDecisionStrategyDefers(t *testing.T) {
+	source := &InMemorySource{}
+	for _, pkg := range []string{"a", "b", "c"} {
+		for _, ver := range []string{"1.0.0", "2.0.0"} {
+			source.AddPackage(MakeName(pkg), SimpleVersion(ver), nil)
+		}
+	}
+	root := NewRootSource()
+	for _, pkg := range []string{"a", "b", "c"} {
+		root.AddPackage(MakeName(pkg), NewVersionSetCondition(FullVersionSet()))
+	}
+
+	solver := NewSolverWithOptions([]Source{root, source}, WithDecisionStrategy(DefaultDecisionStrategy{}))
+	solution, err := solver.Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, pkg := range []string{"a", "b", "c"} {
+		if ver, _ := solution.GetVersion(MakeName(pkg)); ver.String() != "2.0.0" {
+			t.Fatalf("expected the default newest %s, got %v", pkg, ver)
+		}
+	}
+}
+
+// conflictingPick always picks the newest version, which conflicts below.
+type conflictingPick struct{ DefaultDecisionStrategy }
+
+func (conflictingPick) PickVersion(_ Name, _ VersionSet, versions []Version) Version {
+	return versions[len(versions)-1]
+}
+
+func TestDecisionStrategyPickGivesWayOnConflict(t *testing.T) {
+	source := &InMemorySource{}
+	for _, pkg := range []string{"a", "b", "c"} {
+		for _, ver := range []string{"1.0.0", "2.0.0"} {
+			source.AddPackage(MakeName(pkg), SimpleVersion(ver), nil)
+		}
+	}
+	root := NewRootSource()
+	for _, pkg := range []string{"a", "b", "c"} {
+		root.AddPackage(MakeName(pkg), NewVersionSetCondition(FullVersionSet()))
+	}
+
+	source.AddPackage(MakeName("a"), SimpleVersion("3.0.0"), []Term{
+		NewTerm(MakeName("missing"), NewVersionSetCondition(FullVersionSet())),
+	})
+
+	solver := NewSolverWithOptions([]Source{root, source}, WithDecisionStrategy(conflictingPick{}))
+	solution, err := solver.Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, _ := solution.GetVersion(MakeName("a")); ver.String() != "2.0.0" {
+		t.Fatalf("expected a to fall back to 2.0.0, got %v", ver)
+	}
+}
@@ -0,0 +1,334 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// maxComponentScan bounds how many packages DependencyComponents explores
+// before giving up on decomposition.
+const maxComponentScan = 10000
+
+// DependencyComponents partitions root requirements into groups that can
+// never interact: no package is reachable, through any version's
+// dependencies, from requirements in two different groups. Each group can be
+// solved on its own and the solutions concatenated. Policy bundles count as
+// dependencies, and the members of a policy exclusive group always share a
+// group.
+//
+// The exploration visits every published version of every reachable package,
+// which is conservative but exact. It returns a single group holding all
+// requirements when more than maxComponentScan packages are reachable.
+// Missing packages are treated as leaves; they make their group unsolvable
+// but do not connect groups.
+//
+// Experimental: the grouping rules and the scan limit may change in a minor
+// release.
+func DependencyComponents(root RootSource, source Source, opts ...SolverOption) ([][]Term, error) {
+	options := defaultSolverOptions()
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+	components, err := dependencyComponents(root, source, options)
+	if err != nil {
+		return nil, err
+	}
+	groups := make([][]Term, 0, len(components))
+	for _, c := range components {
+		if len(c.requirements) > 0 {
+			groups = append(groups, c.requirements)
+		}
+	}
+	return groups, nil
+}
+
+// component is a group of root requirements that can be solved on its own,
+// together with the installed packages (see WithInstalled) it reaches.
+type component struct {
+	requirements []Term
+	installed    []Name
+}
+
+func dependencyComponents(root RootSource, source Source, options SolverOptions) ([]component, error) {
+	// Installed packages are required by the root like requirements, so
+	// they seed the exploration after them, in name order.
+	installed := make([]Name, 0, len(options.Installed))
+	for name := range options.Installed {
+		if name != root.Term().Name {
+			installed = append(installed, name)
+		}
+	}
+	slices.SortFunc(installed, func(a, b Name) int { return strings.Compare(a.Value(), b.Value()) })
+	seeds := make([]Name, 0, len(root)+len(installed))
+	for _, req := range root {
+		seeds = append(seeds, req.Name)
+	}
+	seeds = append(seeds, installed...)
+
+	// owner maps each reached package to the seed index that first reached
+	// it; parent is a union-find forest over seed indices.
+	owner := make(map[Name]int)
+	parent := make([]int, len(seeds))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(a, b int) {
+		if ra, rb := find(a), find(b); ra != rb {
+			parent[rb] = ra
+		}
+	}
+
+	for i, seed := range seeds {
+		queue := []Name{seed}
+		for len(queue) > 0 {
+			name := queue[0]
+			queue = queue[1:]
+
+			if other, ok := owner[name]; ok {
+				union(i, other)
+				continue
+			}
+			owner[name] = i
+			if len(owner) > maxComponentScan {
+				return []component{{requirements: root, installed: installed}}, nil
+			}
+
+			versions, err := source.GetVersions(name)
+			if err != nil {
+				if isMissingPackage(err) {
+					continue
+				}
+				return nil, err
+			}
+			for _, ver := range versions {
+				deps, err := source.GetDependencies(name, ver)
+				if err != nil {
+					if isMissingPackage(err) {
+						continue
+					}
+					return nil, err
+				}
+				deps = replaceDependencies(options.Replacements, rewriteDependencies(options, name, ver, deps), nil)
+				for _, dep := range deps {
+					queue = append(queue, dep.Name)
+				}
+			}
+			for _, policy := range options.Policies {
+				for _, bundle := range policy.Bundles {
+					if bundle.Package == name && bundle.Requires.Positive {
+						queue = append(queue, bundle.Requires.Name)
+					}
+				}
+			}
+		}
+	}
+
+	// At most one member of an exclusive group may be installed, so reached
+	// members constrain each other like a shared dependency.
+	for _, policy := range options.Policies {
+		for _, group := range policy.Groups {
+			first := -1
+			for _, name := range group.Packages {
+				i, ok := owner[name]
+				if !ok {
+					continue
+				}
+				if first < 0 {
+					first = i
+					continue
+				}
+				union(first, i)
+			}
+		}
+	}
+
+	groups := make(map[int]*component)
+	var order []int
+	for i := range seeds {
+		r := find(i)
+		c, ok := groups[r]
+		if !ok {
+			c = &component{}
+			groups[r] = c
+			order = append(order, r)
+		}
+		if i < len(root) {
+			c.requirements = append(c.requirements, root[i])
+		} else {
+			c.installed = append(c.installed, seeds[i])
+		}
+	}
+
+	result := make([]component, 0, len(order))
+	for _, r := range order {
+		result = append(result, *groups[r])
+	}
+	return result, nil
+}
+
+func isMissingPackage(err error) bool {
+	var pkgErr *PackageNotFoundError
+	var verErr *PackageVersionNotFoundError
+	return errors.As(err, &pkgErr) || errors.As(err, &verErr)
+}
+
+// SolveDecomposed solves root by splitting it into independent components
+// (see DependencyComponents) and solving them concurrently, which avoids
+// paying the full sequential cost for large manifests with mostly disjoint
+// dependency trees. The merged solution lists the root first, followed by each
+// component's packages in requirement order.
+//
+// The solver's Source supplies every package except the root; a RootSource it
+// already contains is shadowed by root. It is wrapped in a shared
+// CachedSource so the exploration and the component solves fetch each answer
+// once. If any component has no solution, its error is returned: the whole
+// problem is then unsolvable. Installed packages (see WithInstalled) are
+// required by the one component that reaches them. Statistics are summed over
+// components.
+//
+// Experimental: how components are solved and merged may change in a minor
+// release.
+//
+// Example:
+//
+//	solver := NewSolver(root, registry)
+//	solution, err := solver.SolveDecomposed(ctx, *root)
+func (s *Solver) SolveDecomposed(ctx context.Context, root RootSource, opts ...SolverOption) (Solution, error) {
+	base := s.With(opts...)
+	source := NewCachedSource(base.Source)
+
+	components, err := dependencyComponents(root, source, base.options)
+	if err != nil {
+		return nil, err
+	}
+	if len(components) <= 1 {
+		base.Source = rootedSource{root: root, source: source}
+		solution, err := base.SolveContext(ctx, root.Term())
+		s.stats, s.learned = base.stats, base.learned
+		return solution, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type outcome struct {
+		solution Solution
+		stats    SolveStats
+		learned  []*Incompatibility
+		err      error
+	}
+	outcomes := make([]outcome, len(components))
+
+	var wg sync.WaitGroup
+	for i, c := range components {
+		subRoot := RootSource(c.requirements)
+		// Post-processors run once, on the merged solution, and each
+		// installed package is required by the one component reaching it.
+		installed := make(map[Name]Version, len(c.installed))
+		for _, name := range c.installed {
+			installed[name] = base.options.Installed[name]
+		}
+		solver := base.With(func(opts *SolverOptions) {
+			opts.PostProcessors = nil
+			opts.Installed = installed
+		})
+		solver.Source = rootedSource{root: subRoot, source: source}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			solution, err := solver.SolveContext(ctx, subRoot.Term())
+			if err != nil {
+				cancel()
+			}
+			outcomes[i] = outcome{solution: solution, stats: solver.stats, learned: solver.learned, err: err}
+		}()
+	}
+	wg.Wait()
+
+	var total SolveStats
+	for _, out := range outcomes {
+		total = total.add(out.stats)
+	}
+	s.stats = total
+
+	// Report a definitive failure in preference to cancellations it caused.
+	for _, out := range outcomes {
+		if out.err != nil && !errors.Is(out.err, context.Canceled) {
+			s.learned = out.learned
+			return nil, out.err
+		}
+	}
+	for _, out := range outcomes {
+		if out.err != nil {
+			return nil, out.err
+		}
+	}
+
+	s.learned = nil
+	rootName := root.Term().Name
+	var merged Solution
+	for i, out := range outcomes {
+		for _, nv := range out.solution {
+			if nv.Name == rootName && i > 0 {
+				continue
+			}
+			merged = append(merged, nv)
+		}
+	}
+	return base.postProcess(rootName, merged)
+}
+
+// rootedSource answers queries for the root package from root and every other
+// query from source, shadowing any root package source may also provide.
+type rootedSource struct {
+	root   RootSource
+	source Source
+}
+
+func (s rootedSource) GetVersions(name Name) ([]Version, error) {
+	return s.GetVersionsCtx(context.Background(), name)
+}
+
+func (s rootedSource) GetDependencies(name Name, version Version) ([]Term, error) {
+	return s.GetDependenciesCtx(context.Background(), name, version)
+}
+
+func (s rootedSource) GetVersionsCtx(ctx context.Context, name Name) ([]Version, error) {
+	if name == MakeName("$$root") {
+		return s.root.GetVersions(name)
+	}
+	return getVersionsCtx(ctx, s.source, name)
+}
+
+func (s rootedSource) GetDependenciesCtx(ctx context.Context, name Name, version Version) ([]Term, error) {
+	if name == MakeName("$$root") {
+		return s.root.GetDependencies(name, version)
+	}
+	return getDependenciesCtx(ctx, s.source, name, version)
+}
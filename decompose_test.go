@@ -0,0 +1,198 @@
+package pubgrub
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDependencyComponentsGroupsSharedPackages(t *testing.T) {
+	httpRange := mustParseVersionRange(t, ">=1.0.0, <2.0.0")
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("web"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("http"), NewVersionSetCondition(httpRange)),
+	})
+	source.AddPackage(MakeName("api"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("http"), NewVersionSetCondition(httpRange)),
+	})
+	source.AddPackage(MakeName("http"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("http"), SimpleVersion("1.2.0"), nil)
+	source.AddPackage(MakeName("cli"), SimpleVersion("2.0.0"), []Term{
+		NewTerm(MakeName("flags"), EqualsCondition{Version: SimpleVersion("1.0.0")}),
+	})
+	source.AddPackage(MakeName("flags"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("log"), SimpleVersion("0.1.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("web"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+	root.AddPackage(MakeName("cli"), EqualsCondition{Version: SimpleVersion("2.0.0")})
+	root.AddPackage(MakeName("api"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+	root.AddPackage(MakeName("log"), EqualsCondition{Version: SimpleVersion("0.1.0")})
+
+	components, err := DependencyComponents(*root, source)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(components) != 3 {
+		t.Fatalf("expected 3 components, got %d: %v", len(components), components)
+	}
+	if len(components[0]) != 2 || components[0][0].Name != MakeName("web") || components[0][1].Name != MakeName("api") {
+		t.Fatalf("expected web and api to share a component, got %v", components[0])
+	}
+}
+
+func TestSolveDecomposedMergesComponents(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("web"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("http"), NewVersionSetCondition(mustParseVersionRange(t, ">=1.0.0, <2.0.0"))),
+	})
+	source.AddPackage(MakeName("http"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("http"), SimpleVersion("1.2.0"), nil)
+	source.AddPackage(MakeName("cli"), SimpleVersion("2.0.0"), []Term{
+		NewTerm(MakeName("flags"), EqualsCondition{Version: SimpleVersion("1.0.0")}),
+	})
+	source.AddPackage(MakeName("flags"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("log"), SimpleVersion("0.1.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("web"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+	root.AddPackage(MakeName("cli"), EqualsCondition{Version: SimpleVersion("2.0.0")})
+	root.AddPackage(MakeName("log"), EqualsCondition{Version: SimpleVersion("0.1.0")})
+
+	solver := NewSolver(root, source)
+	decomposed, err := solver.SolveDecomposed(context.Background(), *root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	whole, err := NewSolver(root, source).Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(decomposed) != len(whole) {
+		t.Fatalf("expected %d packages, got %v", len(whole), decomposed)
+	}
+	for _, nv := range whole {
+		ver, ok := decomposed.GetVersion(nv.Name)
+		if !ok || ver.Sort(nv.Version) != 0 {
+			t.Fatalf("expected %s %s, got %v", nv.Name.Value(), nv.Version, ver)
+		}
+	}
+	if solver.Stats().Decisions == 0 {
+		t.Fatalf("expected stats to be summed over components")
+	}
+}
+
+func TestSolveDecomposedReportsFailingComponent(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("web"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("http"), nil),
+	})
+	source.AddPackage(MakeName("http"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("cli"), SimpleVersion("2.0.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("web"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+	root.AddPackage(MakeName("cli"), EqualsCondition{Version: SimpleVersion("9.0.0")})
+
+	solver := NewSolver(root, source)
+	_, err := solver.SolveDecomposed(context.Background(), *root)
+	if !errors.Is(err, ErrNoSolution) {
+		t.Fatalf("expected no solution, got %v", err)
+	}
+}
+
+func TestSolveDecomposedKeepsExclusiveGroupTogether(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("a"), SimpleVersion("1"), nil)
+	source.AddPackage(MakeName("b"), SimpleVersion("1"), nil)
+	root := NewRootSource()
+	root.AddPackage(MakeName("a"), nil)
+	root.AddPackage(MakeName("b"), nil)
+	policy := NewPolicySet("drivers").ExclusiveGroup([]Name{MakeName("a"), MakeName("b")}, "one driver")
+
+	components, err := DependencyComponents(*root, source, WithPolicy(policy))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(components) != 1 {
+		t.Fatalf("expected group members to share a component, got %v", components)
+	}
+
+	solver := NewSolverWithOptions([]Source{root, source}, WithPolicy(policy))
+	if _, err := solver.Solve(root.Term()); !errors.Is(err, ErrNoSolution) {
+		t.Fatalf("expected Solve to fail, got %v", err)
+	}
+	if solution, err := solver.SolveDecomposed(context.Background(), *root); !errors.Is(err, ErrNoSolution) {
+		t.Fatalf("expected SolveDecomposed to fail like Solve, got %v, %v", solution, err)
+	}
+}
+
+func TestSolveDecomposedFollowsBundles(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("plugin"), SimpleVersion("1"), nil)
+	source.AddPackage(MakeName("core"), SimpleVersion("1"), nil)
+	source.AddPackage(MakeName("core"), SimpleVersion("2"), nil)
+	root := NewRootSource()
+	root.AddPackage(MakeName("plugin"), nil)
+	root.AddPackage(MakeName("core"), EqualsCondition{Version: SimpleVersion("2")})
+	policy := NewPolicySet("packs").Bundle(MakeName("plugin"), FullVersionSet(),
+		NewTerm(MakeName("core"), EqualsCondition{Version: SimpleVersion("1")}), "plugin pack")
+
+	components, err := DependencyComponents(*root, source, WithPolicy(policy))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(components) != 1 {
+		t.Fatalf("expected the bundle to join plugin and core, got %v", components)
+	}
+
+	solver := NewSolverWithOptions([]Source{root, source}, WithPolicy(policy))
+	if _, err := solver.Solve(root.Term()); !errors.Is(err, ErrNoSolution) {
+		t.Fatalf("expected Solve to fail, got %v", err)
+	}
+	if solution, err := solver.SolveDecomposed(context.Background(), *root); !errors.Is(err, ErrNoSolution) {
+		t.Fatalf("expected SolveDecomposed to fail like Solve, got %v, %v", solution, err)
+	}
+}
+
+func TestSolveDecomposedRequiresInstalledOnce(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("a"), SimpleVersion("1"), nil)
+	source.AddPackage(MakeName("c"), SimpleVersion("1"), nil)
+	source.AddPackage(MakeName("b"), SimpleVersion("1"), []Term{
+		NewTerm(MakeName("libc"), EqualsCondition{Version: SimpleVersion("1")}),
+	})
+	source.AddPackage(MakeName("libc"), SimpleVersion("1"), nil)
+	source.AddPackage(MakeName("libc"), SimpleVersion("2"), nil)
+	root := NewRootSource()
+	root.AddPackage(MakeName("a"), nil)
+	root.AddPackage(MakeName("c"), nil)
+
+	installed := WithInstalled(map[Name]Version{MakeName("libc"): SimpleVersion("2")})
+	solver := NewSolverWithOptions([]Source{root, source}, installed)
+	whole, err := solver.Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	decomposed, err := solver.SolveDecomposed(context.Background(), *root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(decomposed) != len(whole) {
+		t.Fatalf("expected %v, got %v", whole, decomposed)
+	}
+	if ver, ok := decomposed.GetVersion(MakeName("libc")); !ok || ver.String() != "2" {
+		t.Fatalf("expected installed libc 2, got %v", decomposed)
+	}
+
+	// The held libc 2 conflicts with b, which must fail the decomposed
+	// solve as it fails the whole one.
+	root.AddPackage(MakeName("b"), nil)
+	if _, err := solver.Solve(root.Term()); !errors.Is(err, ErrNoSolution) {
+		t.Fatalf("expected Solve to fail, got %v", err)
+	}
+	if solution, err := solver.SolveDecomposed(context.Background(), *root); !errors.Is(err, ErrNoSolution) {
+		t.Fatalf("expected SolveDecomposed to fail like Solve, got %v, %v", solution, err)
+	}
+}
@@ -76,7 +76,7 @@ func main() {
 		fmt.Println("Solution found:")
 		for _, nv := range solution {
 			if nv.Name != pubgrub.MakeName("$$root") {
-				fmt.Printf("  - %s %s\n", nv.Name.Value(), nv.Version)
+				fmt.Printf("  - %s %s\n", FormatName(nv.Name), nv.Version)
 			}
 		}
 	} else {
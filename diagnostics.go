@@ -0,0 +1,53 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// SolveWithDiagnostics is like Solve but escalates failures: it solves
+// without incompatibility tracking first and, when no solution exists,
+// solves again with tracking and a debug logger writing to a buffer. The
+// returned error is then the detailed *NoSolutionError, with the debug log
+// of the second solve in its Log field. The buffer replaces any configured
+// logger for that solve. Successful solves cost the same as Solve without
+// tracking. Options apply to this call only.
+//
+// Example:
+//
+//	solution, err := solver.SolveWithDiagnostics(root.Term())
+//	var noSolution *NoSolutionError
+//	if errors.As(err, &noSolution) {
+//	    fmt.Println(noSolution.Error())
+//	    os.WriteFile("solve.log", []byte(noSolution.Log), 0o644)
+//	}
+func (s *Solver) SolveWithDiagnostics(root Term, opts ...SolverOption) (Solution, error) {
+	var log bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&log, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	derived := s.With(opts...).With(WithIncompatibilityTracking(false))
+	solution, err := derived.solveTrackingOnFailure(context.Background(), root, WithLogger(logger))
+	s.adopt(derived)
+
+	var noSolution *NoSolutionError
+	if errors.As(err, &noSolution) {
+		noSolution.Log = log.String()
+	}
+	return solution, err
+}
@@ -0,0 +1,144 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package pubgrub
+
+// Ecosystem bundles the conventions of one package ecosystem: how its
+// versions and constraint strings parse and the solver options that give its
+// resolution semantics. NpmEcosystem, GemEcosystem and CargoEcosystem cover
+// the common registries; NewSolver builds a Solver with the bundle applied.
+// Options appended to a preset take precedence over its own.
+//
+// Example:
+//
+//	eco := CargoEcosystem()
+//	set, _ := eco.ParseRange("^1.2")
+//	root := NewRootSource()
+//	root.AddPackage(MakeName("serde"), NewVersionSetCondition(set))
+//	solution, err := eco.NewSolver(root, registry).Solve(root.Term())
+type Ecosystem struct {
+	// Name identifies the ecosystem, such as "npm".
+	Name string
+	// ParseVersion parses a published version.
+	ParseVersion VersionParser
+	// ParseRange parses a constraint string in the ecosystem's syntax.
+	ParseRange func(string) (VersionSet, error)
+	// Options configure the solver for the ecosystem.
+	Options []SolverOption
+}
+
+// NewSolver returns a Solver over sources configured for the ecosystem.
+func (e Ecosystem) NewSolver(sources ...Source) *Solver {
+	return NewSolverWithOptions(sources, e.Options...)
+}
+
+// ecosystemOptions are the options shared by every preset: registries
+// install the newest allowed version, and failures are explained with the
+// root package named after the ecosystem's manifest.
+func ecosystemOptions(manifest string, policy PrereleasePolicy) []SolverOption {
+	return []SolverOption{
+		WithVersionStrategy(VersionNewest),
+		WithPrereleasePolicy(policy),
+		WithTrackingOnFailure(true),
+		WithReporter(&RootLabelReporter{Label: manifest}),
+	}
+}
+
+// NpmEcosystem returns the npm preset: node-semver ranges (see
+// ParseNpmRange), SemanticVersions, and prereleases only when a range
+// mentions them.
+func NpmEcosystem() Ecosystem {
+	return Ecosystem{
+		Name:         "npm",
+		ParseVersion: parseNpmVersion,
+		ParseRange:   ParseNpmRange,
+		Options:      ecosystemOptions("package.json", PrereleaseMentioned),
+	}
+}
+
+// GemEcosystem returns the RubyGems preset: Gem::Requirement strings (see
+// ParseGemRequirement), GemVersions, and prereleases only when a
+// requirement mentions one.
+func GemEcosystem() Ecosystem {
+	return Ecosystem{
+		Name:         "rubygems",
+		ParseVersion: parseGemVersion,
+		ParseRange:   ParseGemRequirement,
+		Options:      ecosystemOptions("Gemfile", PrereleaseMentioned),
+	}
+}
+
+// CargoEcosystem returns the Cargo preset: Cargo version requirements (see
+// ParseCargoRequirement), SemanticVersions, and prereleases only when a
+// requirement mentions them.
+func CargoEcosystem() Ecosystem {
+	return Ecosystem{
+		Name:         "cargo",
+		ParseVersion: parseSemanticVersion,
+		ParseRange:   ParseCargoRequirement,
+		Options:      ecosystemOptions("Cargo.toml", PrereleaseMentioned),
+	}
+}
+
+// NewNpmSolver returns a Solver over sources with npm semantics.
+//
+// Example:
+//
+//	solver := NewNpmSolver(root, registry)
+func NewNpmSolver(sources ...Source) *Solver {
+	return NpmEcosystem().NewSolver(sources...)
+}
+
+// NewGemSolver returns a Solver over sources with RubyGems semantics.
+//
+// Example:
+//
+//	solver := NewGemSolver(root, registry)
+func NewGemSolver(sources ...Source) *Solver {
+	return GemEcosystem().NewSolver(sources...)
+}
+
+// NewCargoSolver returns a Solver over sources with Cargo semantics.
+//
+// Example:
+//
+//	solver := NewCargoSolver(root, registry)
+func NewCargoSolver(sources ...Source) *Solver {
+	return CargoEcosystem().NewSolver(sources...)
+}
+
+// parseSemanticVersion adapts ParseSemanticVersion to VersionParser.
+func parseSemanticVersion(s string) (Version, error) {
+	v, err := ParseSemanticVersion(s)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// parseGemVersion adapts ParseGemVersion to VersionParser.
+func parseGemVersion(s string) (Version, error) {
+	v, err := ParseGemVersion(s)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// parseNpmVersion parses a version as npm does, ignoring a leading "v".
+func parseNpmVersion(s string) (Version, error) {
+	if len(s) > 1 && (s[0] == 'v' || s[0] == 'V') {
+		s = s[1:]
+	}
+	return parseSemanticVersion(s)
+}
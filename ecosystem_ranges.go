@@ -0,0 +1,353 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package pubgrub
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// partialVersion is a version in a constraint that may leave components
+// out or wildcard them, as in "1.2", "1.x" or "*".
+type partialVersion struct {
+	parts []int // Given components, at most three
+	pre   string
+	wild  bool // Written with a wildcard, as in "1.x" or "*"
+}
+
+// parsePartialVersion parses a possibly partial semantic version. Build
+// metadata is ignored, and a prerelease needs all three components.
+func parsePartialVersion(s string) (partialVersion, error) {
+	var p partialVersion
+	if len(s) > 1 && (s[0] == 'v' || s[0] == 'V') {
+		s = s[1:]
+	}
+	s, _, _ = strings.Cut(s, "+")
+	core, pre, hasPre := strings.Cut(s, "-")
+	if core == "" || isWildcard(core) {
+		if hasPre {
+			return p, fmt.Errorf("invalid version %q", s)
+		}
+		p.wild = core != ""
+		return p, nil
+	}
+	fields := strings.Split(core, ".")
+	if len(fields) > 3 {
+		return p, fmt.Errorf("invalid version %q", s)
+	}
+	for i, field := range fields {
+		if isWildcard(field) {
+			for _, rest := range fields[i+1:] {
+				if !isWildcard(rest) {
+					return p, fmt.Errorf("invalid version %q", s)
+				}
+			}
+			p.wild = true
+			break
+		}
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return p, fmt.Errorf("invalid version %q", s)
+		}
+		p.parts = append(p.parts, n)
+	}
+	if hasPre && len(p.parts) < 3 {
+		return p, fmt.Errorf("prerelease needs a full version in %q", s)
+	}
+	p.pre = pre
+	return p, nil
+}
+
+func isWildcard(s string) bool {
+	return s == "*" || s == "x" || s == "X"
+}
+
+// floor returns the lowest version p matches, filling missing components
+// with zeros.
+func (p partialVersion) floor() Version {
+	var c [3]int
+	copy(c[:], p.parts)
+	if p.pre != "" {
+		return NewSemanticVersionWithPrerelease(c[0], c[1], c[2], p.pre)
+	}
+	return NewSemanticVersion(c[0], c[1], c[2])
+}
+
+// bump returns the release after p with the component at level
+// incremented and the later ones zeroed, as in 1.2.3 -> 1.3.0 for level 1.
+func (p partialVersion) bump(level int) Version {
+	var c [3]int
+	copy(c[:], p.parts)
+	c[level]++
+	for i := level + 1; i < 3; i++ {
+		c[i] = 0
+	}
+	return NewSemanticVersion(c[0], c[1], c[2])
+}
+
+// between returns [lower, upper), with nil meaning unbounded.
+func between(lower, upper Version) VersionSet {
+	return intervalSetFromBounds(newLowerBound(lower, true), newUpperBound(upper, false))
+}
+
+// semverComparator returns the versions matching op applied to p, using the
+// node-semver meaning shared by npm and Cargo.
+func semverComparator(op string, p partialVersion) (VersionSet, error) {
+	n := len(p.parts)
+	if n == 0 {
+		switch op {
+		case "<", ">":
+			return EmptyVersionSet(), nil
+		default:
+			return FullVersionSet(), nil
+		}
+	}
+	lo := p.floor()
+	switch op {
+	case "=", "":
+		if n == 3 {
+			return singletonSet(lo), nil
+		}
+		return between(lo, p.bump(n-1)), nil
+	case ">=":
+		return between(lo, nil), nil
+	case ">":
+		if n == 3 {
+			return intervalSetFromBounds(newLowerBound(lo, false), positiveInfinityBound()), nil
+		}
+		return between(p.bump(n-1), nil), nil
+	case "<":
+		return between(nil, lo), nil
+	case "<=":
+		if n == 3 {
+			return intervalSetFromBounds(negativeInfinityBound(), newUpperBound(lo, true)), nil
+		}
+		return between(nil, p.bump(n-1)), nil
+	case "~":
+		if n == 1 {
+			return between(lo, p.bump(0)), nil
+		}
+		return between(lo, p.bump(1)), nil
+	case "^":
+		// The first non-zero given component may not change; when all are
+		// zero, the last given one may not.
+		level := n - 1
+		for i, c := range p.parts {
+			if c != 0 {
+				level = i
+				break
+			}
+		}
+		return between(lo, p.bump(level)), nil
+	}
+	return nil, fmt.Errorf("unknown operator %q", op)
+}
+
+// cutOperator splits a leading comparison operator from expr. ops lists
+// the recognized operators, longest first.
+func cutOperator(expr string, ops []string) (string, string) {
+	for _, op := range ops {
+		if rest, ok := strings.CutPrefix(expr, op); ok {
+			return op, strings.TrimSpace(rest)
+		}
+	}
+	return "", expr
+}
+
+var npmOperators = []string{">=", "<=", ">", "<", "=", "^", "~"}
+
+// ParseNpmRange parses an npm (node-semver) range: comparators separated by
+// spaces must all match, alternatives are joined by "||", and "^", "~",
+// x-ranges such as "1.2.x" and hyphen ranges such as "1.2 - 2.3.4" are
+// supported. A bare version matches exactly, and "" or "*" matches any
+// version. Combine with PrereleaseMentioned, as the npm preset does, for
+// npm's prerelease rules.
+//
+// Example:
+//
+//	set, err := ParseNpmRange("^1.2.0 || >=3.0.0-rc.1 <3.1")
+func ParseNpmRange(s string) (VersionSet, error) {
+	result := EmptyVersionSet()
+	for _, alt := range strings.Split(s, "||") {
+		set, err := parseNpmComparatorSet(strings.TrimSpace(alt))
+		if err != nil {
+			return nil, fmt.Errorf("invalid npm range %q: %w", s, err)
+		}
+		result = result.Union(set)
+	}
+	return result, nil
+}
+
+// parseNpmComparatorSet parses one "||" alternative of an npm range.
+func parseNpmComparatorSet(alt string) (VersionSet, error) {
+	if lo, hi, ok := strings.Cut(alt, " - "); ok {
+		return parseNpmHyphen(strings.TrimSpace(lo), strings.TrimSpace(hi))
+	}
+
+	set := FullVersionSet()
+	fields := strings.Fields(alt)
+	for i := 0; i < len(fields); i++ {
+		token := fields[i]
+		op, rest := cutOperator(token, npmOperators)
+		if op != "" && rest == "" && i+1 < len(fields) {
+			// Operator separated from its version, as in ">= 1.2.3".
+			i++
+			rest = fields[i]
+		}
+		p, err := parsePartialVersion(rest)
+		if err != nil {
+			return nil, err
+		}
+		comparator, err := semverComparator(op, p)
+		if err != nil {
+			return nil, err
+		}
+		set = set.Intersection(comparator)
+	}
+	return set, nil
+}
+
+// parseNpmHyphen parses the hyphen range "lo - hi", which includes both
+// ends; a partial hi includes every version it matches.
+func parseNpmHyphen(lo, hi string) (VersionSet, error) {
+	from, err := parsePartialVersion(lo)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parsePartialVersion(hi)
+	if err != nil {
+		return nil, err
+	}
+	lower, err := semverComparator(">=", from)
+	if err != nil {
+		return nil, err
+	}
+	upper, err := semverComparator("<=", to)
+	if err != nil {
+		return nil, err
+	}
+	return lower.Intersection(upper), nil
+}
+
+var cargoOperators = []string{">=", "<=", ">", "<", "=", "^", "~"}
+
+// ParseCargoRequirement parses a Cargo version requirement: comparators
+// separated by commas must all match. A bare version is a caret
+// requirement, so "1.2" means ">=1.2.0, <2.0.0", and "~", "*" and
+// wildcards such as "1.2.*" are supported.
+//
+// Example:
+//
+//	set, err := ParseCargoRequirement(">=1.2, <1.5")
+func ParseCargoRequirement(s string) (VersionSet, error) {
+	set := FullVersionSet()
+	for _, expr := range strings.Split(s, ",") {
+		expr = strings.TrimSpace(expr)
+		if expr == "" {
+			return nil, fmt.Errorf("invalid cargo requirement %q: empty comparator", s)
+		}
+		op, rest := cutOperator(expr, cargoOperators)
+		if strings.HasPrefix(rest, "v") {
+			return nil, fmt.Errorf("invalid cargo requirement %q: invalid version %q", s, rest)
+		}
+		p, err := parsePartialVersion(rest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cargo requirement %q: %w", s, err)
+		}
+		if op == "" && !p.wild {
+			op = "^"
+		}
+		comparator, err := semverComparator(op, p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cargo requirement %q: %w", s, err)
+		}
+		set = set.Intersection(comparator)
+	}
+	return set, nil
+}
+
+var gemOperators = []string{"~>", ">=", "<=", "!=", ">", "<", "="}
+
+// ParseGemRequirement parses a RubyGems requirement: comparators separated
+// by commas must all match, and "~> 1.2" is the pessimistic operator,
+// meaning ">= 1.2, < 2". A bare version matches exactly. Versions parse as
+// GemVersions, so ones with more than three segments keep their numeric
+// order and prereleases such as "2.1.0.pre1" sort below their release.
+//
+// Example:
+//
+//	set, err := ParseGemRequirement("~> 7.1, >= 7.1.2")
+func ParseGemRequirement(s string) (VersionSet, error) {
+	set := FullVersionSet()
+	for _, expr := range strings.Split(s, ",") {
+		expr = strings.TrimSpace(expr)
+		op, rest := cutOperator(expr, gemOperators)
+		if rest == "" {
+			return nil, fmt.Errorf("invalid gem requirement %q: missing version", s)
+		}
+		var (
+			comparator VersionSet
+			err        error
+		)
+		switch op {
+		case "~>":
+			comparator, err = pessimisticSet(rest)
+		case "", "=":
+			comparator, err = parseRangeExpression("=="+rest, parseGemVersion, false)
+		default:
+			comparator, err = parseRangeExpression(op+rest, parseGemVersion, false)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid gem requirement %q: %w", s, err)
+		}
+		set = set.Intersection(comparator)
+	}
+	return set, nil
+}
+
+// pessimisticSet returns the versions matching "~> v": at least v, and
+// below the release that increments v's second-to-last segment, so
+// "~> 1.2.3" is ">= 1.2.3, < 1.3" and "~> 1" is ">= 1, < 2".
+func pessimisticSet(v string) (VersionSet, error) {
+	segments := strings.Split(v, ".")
+	for len(segments) > 1 {
+		if _, err := strconv.Atoi(segments[len(segments)-1]); err == nil {
+			break
+		}
+		// Prerelease segments such as "rc1" are dropped before bumping.
+		segments = segments[:len(segments)-1]
+	}
+	if len(segments) > 1 {
+		segments = segments[:len(segments)-1]
+	}
+	last, err := strconv.Atoi(segments[len(segments)-1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid version %q", v)
+	}
+	segments[len(segments)-1] = strconv.Itoa(last + 1)
+	for len(segments) < 3 {
+		segments = append(segments, "0")
+	}
+
+	lower, err := parseGemVersion(v)
+	if err != nil {
+		return nil, err
+	}
+	upper, err := parseGemVersion(strings.Join(segments, "."))
+	if err != nil {
+		return nil, err
+	}
+	return between(lower, upper), nil
+}
@@ -0,0 +1,192 @@
+package pubgrub
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEcosystemRangeParsers(t *testing.T) {
+	tests := []struct {
+		parse   func(string) (VersionSet, error)
+		rng     string
+		allowed []string
+		denied  []string
+	}{
+		{ParseNpmRange, "^1.2.3", []string{"1.2.3", "1.9.0"}, []string{"1.2.2", "2.0.0"}},
+		{ParseNpmRange, "^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0"}},
+		{ParseNpmRange, "^0.0.3", []string{"0.0.3"}, []string{"0.0.4"}},
+		{ParseNpmRange, "~1.2.3", []string{"1.2.9"}, []string{"1.3.0"}},
+		{ParseNpmRange, "1.2.x", []string{"1.2.0", "1.2.7"}, []string{"1.3.0"}},
+		{ParseNpmRange, "1.2.3", []string{"1.2.3"}, []string{"1.2.4"}},
+		{ParseNpmRange, "1.2 - 2.3", []string{"1.2.0", "2.3.9"}, []string{"1.1.9", "2.4.0"}},
+		{ParseNpmRange, ">= 1.0.0 <1.5 || >=3", []string{"1.4.9", "3.1.0"}, []string{"1.5.0", "2.0.0"}},
+		{ParseNpmRange, "<=1.2", []string{"1.2.9"}, []string{"1.3.0"}},
+		{ParseNpmRange, "*", []string{"0.0.1", "9.0.0"}, nil},
+		{ParseCargoRequirement, "1.2", []string{"1.2.0", "1.9.0"}, []string{"1.1.0", "2.0.0"}},
+		{ParseCargoRequirement, "0.3.1", []string{"0.3.5"}, []string{"0.4.0"}},
+		{ParseCargoRequirement, "=1.2.3", []string{"1.2.3"}, []string{"1.2.4"}},
+		{ParseCargoRequirement, "1.2.*", []string{"1.2.9"}, []string{"1.3.0"}},
+		{ParseCargoRequirement, "~1", []string{"1.9.0"}, []string{"2.0.0"}},
+		{ParseCargoRequirement, ">=1.2, <1.5", []string{"1.4.0"}, []string{"1.5.0"}},
+		{ParseGemRequirement, "~> 1.2", []string{"1.2.0", "1.9.9"}, []string{"1.1.0", "2.0.0"}},
+		{ParseGemRequirement, "~> 1.2.3", []string{"1.2.3", "1.2.9"}, []string{"1.3.0"}},
+		{ParseGemRequirement, "~> 1.2.3.4", []string{"1.2.3.4", "1.2.3.9"}, []string{"1.2.4"}},
+		{ParseGemRequirement, "~> 7.1, >= 7.1.2", []string{"7.1.2", "7.9.0"}, []string{"7.1.1", "8.0.0"}},
+		{ParseGemRequirement, "!= 1.5.0", []string{"1.4.0"}, []string{"1.5.0"}},
+		{ParseGemRequirement, "2.0.1", []string{"2.0.1"}, []string{"2.0.2"}},
+	}
+	for _, tt := range tests {
+		set, err := tt.parse(tt.rng)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tt.rng, err)
+		}
+		for _, raw := range tt.allowed {
+			v, _ := parseRangeVersion(raw)
+			if !set.Contains(v) {
+				t.Fatalf("%q should allow %s, got %s", tt.rng, raw, set)
+			}
+		}
+		for _, raw := range tt.denied {
+			v, _ := parseRangeVersion(raw)
+			if set.Contains(v) {
+				t.Fatalf("%q should deny %s, got %s", tt.rng, raw, set)
+			}
+		}
+	}
+}
+
+func TestEcosystemRangeParsersRejectMalformed(t *testing.T) {
+	for _, rng := range []string{"^1.2.3.4", "1.x.3", "~a", "1.2-beta"} {
+		if _, err := ParseNpmRange(rng); err == nil {
+			t.Fatalf("npm range %q should not parse", rng)
+		}
+	}
+	for _, rng := range []string{"", "1.2,", "v1.2.3"} {
+		if _, err := ParseCargoRequirement(rng); err == nil {
+			t.Fatalf("cargo requirement %q should not parse", rng)
+		}
+	}
+	for _, rng := range []string{"", "~>", "~> a"} {
+		if _, err := ParseGemRequirement(rng); err == nil {
+			t.Fatalf("gem requirement %q should not parse", rng)
+		}
+	}
+}
+
+func TestNpmSolverSkipsUnmentionedPrereleases(t *testing.T) {
+	eco := NpmEcosystem()
+	source := &InMemorySource{}
+	for _, raw := range []string{"1.0.0", "1.1.0", "2.0.0-beta.1"} {
+		v, err := eco.ParseVersion(raw)
+		if err != nil {
+			t.Fatalf("parse %s: %v", raw, err)
+		}
+		source.AddPackage(MakeName("lodash"), v, nil)
+	}
+	set, err := eco.ParseRange(">=1.0.0")
+	if err != nil {
+		t.Fatalf("parse range: %v", err)
+	}
+	root := NewRootSource()
+	root.AddPackage(MakeName("lodash"), NewVersionSetCondition(set))
+
+	solution, err := NewNpmSolver(root, source).Solve(root.Term())
+	if err != nil {
+		t.Fatalf("solve: %v", err)
+	}
+	if v, ok := solution.GetVersion(MakeName("lodash")); !ok || v.String() != "1.1.0" {
+		t.Fatalf("expected lodash 1.1.0, got %v", v)
+	}
+}
+
+func TestGemSolverReportsAgainstGemfile(t *testing.T) {
+	eco := GemEcosystem()
+	source := &InMemorySource{}
+	v, _ := eco.ParseVersion("6.1.0")
+	source.AddPackage(MakeName("rails"), v, nil)
+	set, err := eco.ParseRange("~> 7.1")
+	if err != nil {
+		t.Fatalf("parse range: %v", err)
+	}
+	root := NewRootSource()
+	root.AddPackage(MakeName("rails"), NewVersionSetCondition(set))
+
+	_, err = NewGemSolver(root, source).Solve(root.Term())
+	var nsErr *NoSolutionError
+	if !errors.As(err, &nsErr) {
+		t.Fatalf("expected NoSolutionError, got %v", err)
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, "Gemfile") || strings.Contains(msg, "$$root") {
+		t.Fatalf("expected the root to read as Gemfile, got:\n%s", msg)
+	}
+}
+
+func TestGemSolverSkipsUnrequestedPrereleases(t *testing.T) {
+	eco := GemEcosystem()
+	source := &InMemorySource{}
+	for _, raw := range []string{"1.0.0", "2.0.0", "2.1.0.pre1"} {
+		v, err := eco.ParseVersion(raw)
+		if err != nil {
+			t.Fatalf("parse %s: %v", raw, err)
+		}
+		source.AddPackage(MakeName("lib"), v, nil)
+	}
+
+	for rng, want := range map[string]string{
+		">= 1.0":        "2.0.0",
+		">= 2.1.0.pre1": "2.1.0.pre1",
+	} {
+		set, err := eco.ParseRange(rng)
+		if err != nil {
+			t.Fatalf("parse range %q: %v", rng, err)
+		}
+		root := NewRootSource()
+		root.AddPackage(MakeName("lib"), NewVersionSetCondition(set))
+
+		solution, err := NewGemSolver(root, source).Solve(root.Term())
+		if err != nil {
+			t.Fatalf("%s: solve: %v", rng, err)
+		}
+		if v, ok := solution.GetVersion(MakeName("lib")); !ok || v.String() != want {
+			t.Fatalf("%s: expected lib %s, got %v", rng, want, v)
+		}
+	}
+}
+
+func TestCargoSolverPicksNewestCompatible(t *testing.T) {
+	eco := CargoEcosystem()
+	source := &InMemorySource{}
+	for _, raw := range []string{"1.0.100", "1.0.200", "2.0.0"} {
+		v, _ := eco.ParseVersion(raw)
+		source.AddPackage(MakeName("serde"), v, nil)
+	}
+	set, err := eco.ParseRange("1.0")
+	if err != nil {
+		t.Fatalf("parse range: %v", err)
+	}
+	root := NewRootSource()
+	root.AddPackage(MakeName("serde"), NewVersionSetCondition(set))
+
+	solution, err := NewCargoSolver(root, source).Solve(root.Term())
+	if err != nil {
+		t.Fatalf("solve: %v", err)
+	}
+	if v, ok := solution.GetVersion(MakeName("serde")); !ok || v.String() != "1.0.200" {
+		t.Fatalf("expected serde 1.0.200, got %v", v)
+	}
+}
+
+func TestWithReporterFormatsNoSolutionError(t *testing.T) {
+	root := NewRootSource()
+	root.AddPackage(MakeName("missing"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+	solver := NewSolverWithOptions([]Source{root, &InMemorySource{}},
+		WithIncompatibilityTracking(true),
+		WithReporter(&RootLabelReporter{Label: "the app"}),
+	)
+	_, err := solver.Solve(root.Term())
+	if err == nil || !strings.Contains(err.Error(), "the app") {
+		t.Fatalf("expected the configured reporter, got %v", err)
+	}
+}
@@ -16,15 +16,57 @@
 package pubgrub
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 )
 
+// ErrNoSolution matches every "no solution" failure with errors.Is,
+// regardless of whether incompatibility tracking was enabled.
+//
+// Example:
+//
+//	_, err := solver.Solve(root.Term())
+//	if errors.Is(err, ErrNoSolution) {
+//	    // Handle no solution case
+//	}
+var ErrNoSolution = errors.New("no solution found")
+
+// AsNoSolution extracts a NoSolutionError from err. When the solve ran
+// without incompatibility tracking, the ErrNoSolutionFound is converted to a
+// NoSolutionError with a single-term incompatibility, so callers can handle
+// both modes with one code path.
+//
+// Example:
+//
+//	if nsErr, ok := AsNoSolution(err); ok {
+//	    fmt.Println(nsErr.Incompatibility)
+//	}
+func AsNoSolution(err error) (*NoSolutionError, bool) {
+	var nsErr *NoSolutionError
+	if errors.As(err, &nsErr) {
+		return nsErr, true
+	}
+	var simpleErr ErrNoSolutionFound
+	if errors.As(err, &simpleErr) {
+		return NewNoSolutionError(NewIncompatibilityNoVersions(simpleErr.Term)), true
+	}
+	return nil, false
+}
+
 // NoSolutionError is returned when version solving fails with detailed explanation
 type NoSolutionError struct {
 	// Incompatibility is the root cause of the failure
 	Incompatibility *Incompatibility
 	// Reporter is used to format the error message (defaults to DefaultReporter)
 	Reporter Reporter
+	// Missing lists every package the source reported as not found during
+	// the solve, sorted by name
+	Missing []Name
+	// Log holds the solver's debug log when the error was produced by
+	// SolveWithDiagnostics
+	Log string
 }
 
 // Error implements the error interface
@@ -38,7 +80,7 @@ func (e *NoSolutionError) Error() string {
 		reporter = &DefaultReporter{}
 	}
 
-	return reporter.Report(e.Incompatibility)
+	return reporter.Report(e.Incompatibility) + missingSuffix(e.Missing)
 }
 
 // WithReporter returns a new error with a custom reporter
@@ -46,6 +88,8 @@ func (e *NoSolutionError) WithReporter(reporter Reporter) *NoSolutionError {
 	return &NoSolutionError{
 		Incompatibility: e.Incompatibility,
 		Reporter:        reporter,
+		Missing:         e.Missing,
+		Log:             e.Log,
 	}
 }
 
@@ -54,6 +98,11 @@ func (e *NoSolutionError) Unwrap() error {
 	return nil
 }
 
+// Is reports whether target is ErrNoSolution.
+func (e *NoSolutionError) Is(target error) bool {
+	return target == ErrNoSolution
+}
+
 // NewNoSolutionError creates a new NoSolutionError from an incompatibility
 func NewNoSolutionError(incomp *Incompatibility) *NoSolutionError {
 	return &NoSolutionError{
@@ -71,21 +120,24 @@ type VersionError struct {
 // Error implements the error interface
 func (e *VersionError) Error() string {
 	if e.Message != "" {
-		return fmt.Sprintf("%s: %s", e.Package.Value(), e.Message)
+		return fmt.Sprintf("%s: %s", FormatName(e.Package), e.Message)
 	}
-	return fmt.Sprintf("version error for package %s", e.Package.Value())
+	return fmt.Sprintf("version error for package %s", FormatName(e.Package))
 }
 
 // DependencyError represents an error while fetching dependencies
 type DependencyError struct {
 	Package Name
 	Version Version
-	Err     error
+	// Chain lists the packages that led to Package being required, starting
+	// from a root requirement and ending with Package. Empty for the root.
+	Chain []Name
+	Err   error
 }
 
 // Error implements the error interface
 func (e *DependencyError) Error() string {
-	return fmt.Sprintf("failed to get dependencies for %s %s: %v", e.Package.Value(), e.Version, e.Err)
+	return fmt.Sprintf("failed to get dependencies for %s %s%s: %v", FormatName(e.Package), e.Version, chainSuffix(e.Chain), e.Err)
 }
 
 // Unwrap returns the underlying error
@@ -93,6 +145,37 @@ func (e *DependencyError) Unwrap() error {
 	return e.Err
 }
 
+// VersionsError represents an error while listing the versions of a package.
+type VersionsError struct {
+	Package Name
+	// Chain lists the packages that led to Package being required, starting
+	// from a root requirement and ending with Package.
+	Chain []Name
+	Err   error
+}
+
+// Error implements the error interface
+func (e *VersionsError) Error() string {
+	return fmt.Sprintf("failed to get versions of %s%s: %v", FormatName(e.Package), chainSuffix(e.Chain), e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *VersionsError) Unwrap() error {
+	return e.Err
+}
+
+// chainSuffix renders a requirement chain as " (required by a -> b)".
+func chainSuffix(chain []Name) string {
+	if len(chain) < 2 {
+		return ""
+	}
+	parts := make([]string, len(chain)-1)
+	for i, name := range chain[:len(chain)-1] {
+		parts[i] = FormatName(name)
+	}
+	return fmt.Sprintf(" (required by %s)", strings.Join(parts, " -> "))
+}
+
 // PackageNotFoundError indicates that a package is absent from the source.
 type PackageNotFoundError struct {
 	Package Name
@@ -100,7 +183,7 @@ type PackageNotFoundError struct {
 
 // Error implements the error interface.
 func (e *PackageNotFoundError) Error() string {
-	return fmt.Sprintf("package %s not found", e.Package.Value())
+	return fmt.Sprintf("package %s not found", FormatName(e.Package))
 }
 
 // PackageVersionNotFoundError indicates a specific version is unavailable.
@@ -111,7 +194,7 @@ type PackageVersionNotFoundError struct {
 
 // Error implements the error interface.
 func (e *PackageVersionNotFoundError) Error() string {
-	return fmt.Sprintf("package %s version %s not found", e.Package.Value(), e.Version)
+	return fmt.Sprintf("package %s version %s not found", FormatName(e.Package), e.Version)
 }
 
 // ErrNoSolutionFound is a simple error returned when solving fails
@@ -123,17 +206,37 @@ func (e *PackageVersionNotFoundError) Error() string {
 //	solver := NewSolver(root, source) // Tracking disabled by default
 //	_, err := solver.Solve(root.Term())
 //	if err != nil {
-//	    if errors.Is(err, ErrNoSolutionFound{}) {
+//	    if errors.Is(err, ErrNoSolution) {
 //	        // Handle no solution case
 //	    }
 //	}
 type ErrNoSolutionFound struct {
 	Term Term
+	// Missing lists every package the source reported as not found during
+	// the solve, sorted by name.
+	Missing []Name
 }
 
 // Error implements the error interface.
 func (e ErrNoSolutionFound) Error() string {
-	return fmt.Sprintf("no solution found for %s", e.Term)
+	return fmt.Sprintf("no solution found for %s", e.Term) + missingSuffix(e.Missing)
+}
+
+// missingSuffix renders the packages a solve could not find, if any.
+func missingSuffix(missing []Name) string {
+	if len(missing) == 0 {
+		return ""
+	}
+	names := make([]string, len(missing))
+	for i, name := range missing {
+		names[i] = FormatName(name)
+	}
+	return "\n\nPackages not found: " + strings.Join(names, ", ")
+}
+
+// Is reports whether target is ErrNoSolution.
+func (e ErrNoSolutionFound) Is(target error) bool {
+	return target == ErrNoSolution
 }
 
 // ErrIterationLimit is returned when the solver exceeds its maximum iteration count.
@@ -150,8 +253,14 @@ func (e ErrNoSolutionFound) Error() string {
 //	if iterErr, ok := err.(ErrIterationLimit); ok {
 //	    log.Printf("Solver exceeded %d steps", iterErr.Steps)
 //	}
+//
+// When the limit stopped a solve in progress, Handle can be passed to
+// Solver.ResumeSolve to continue it with a fresh budget.
 type ErrIterationLimit struct {
+	// Steps is the total number of steps consumed when the limit was hit.
 	Steps int
+	// Handle holds the interrupted solve; nil if there is nothing to resume.
+	Handle *SolveHandle
 }
 
 // Error implements the error interface.
@@ -162,12 +271,71 @@ func (e ErrIterationLimit) Error() string {
 	return fmt.Sprintf("solver exceeded iteration limit after %d steps", e.Steps)
 }
 
+// ErrInconsistentSource is returned when a Source answers the same query
+// differently within a single solve, which would otherwise lead to silently
+// wrong solutions. Enable detection with WithConsistencyCheck(ConsistencyDetect).
+//
+// Example:
+//
+//	_, err := solver.Solve(root.Term())
+//	var inconsistent ErrInconsistentSource
+//	if errors.As(err, &inconsistent) {
+//	    log.Printf("registry changed during resolution of %s", inconsistent.Package.Value())
+//	}
+type ErrInconsistentSource struct {
+	Package Name
+	// Version is set when dependencies changed, nil when the version list did.
+	Version Version
+	// Previous and Current render the two differing answers.
+	Previous string
+	Current  string
+}
+
+// Error implements the error interface.
+func (e ErrInconsistentSource) Error() string {
+	if e.Version != nil {
+		return fmt.Sprintf("source returned inconsistent dependencies for %s %s: [%s] then [%s]",
+			FormatName(e.Package), e.Version, e.Previous, e.Current)
+	}
+	return fmt.Sprintf("source returned inconsistent versions for %s: [%s] then [%s]",
+		FormatName(e.Package), e.Previous, e.Current)
+}
+
+// ErrPackageTimeout is returned when the time spent on a single package
+// exceeds the budget set with WithPackageTimeout, naming the package that
+// makes the solve slow.
+//
+// Example:
+//
+//	_, err := solver.Solve(root.Term(), WithPackageTimeout(10*time.Second))
+//	var timeout ErrPackageTimeout
+//	if errors.As(err, &timeout) {
+//	    log.Printf("%s is too slow to resolve (%s waiting on the registry)", FormatName(timeout.Package), timeout.SourceTime)
+//	}
+type ErrPackageTimeout struct {
+	Package Name
+	// Elapsed is the time spent on Package, SourceTime the share of it
+	// spent in Source calls.
+	Elapsed    time.Duration
+	SourceTime time.Duration
+	Budget     time.Duration
+}
+
+// Error implements the error interface.
+func (e ErrPackageTimeout) Error() string {
+	return fmt.Sprintf("resolving %s exceeded its %s budget: %s spent, %s of it in source calls",
+		FormatName(e.Package), e.Budget, e.Elapsed, e.SourceTime)
+}
+
 var (
 	_ error = (*NoSolutionError)(nil)
 	_ error = (*VersionError)(nil)
 	_ error = (*DependencyError)(nil)
+	_ error = (*VersionsError)(nil)
 	_ error = (*PackageNotFoundError)(nil)
 	_ error = (*PackageVersionNotFoundError)(nil)
 	_ error = ErrNoSolutionFound{}
 	_ error = ErrIterationLimit{}
+	_ error = ErrInconsistentSource{}
+	_ error = ErrPackageTimeout{}
 )
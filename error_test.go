@@ -0,0 +1,145 @@
+package pubgrub
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestErrNoSolutionMatchesBothModes(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("a"), SimpleVersion("1.0.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("a"), EqualsCondition{Version: SimpleVersion("2.0.0")})
+
+	for _, tracking := range []bool{false, true} {
+		solver := NewSolverWithOptions([]Source{root, source}, WithIncompatibilityTracking(tracking))
+		_, err := solver.Solve(root.Term())
+		if !errors.Is(err, ErrNoSolution) {
+			t.Fatalf("tracking=%v: expected errors.Is(err, ErrNoSolution), got %T", tracking, err)
+		}
+		nsErr, ok := AsNoSolution(err)
+		if !ok || nsErr.Incompatibility == nil {
+			t.Fatalf("tracking=%v: expected AsNoSolution to succeed, got %v", tracking, err)
+		}
+	}
+
+	if _, ok := AsNoSolution(errors.New("boom")); ok {
+		t.Fatalf("expected unrelated errors not to convert")
+	}
+}
+
+type failingDepsSource struct {
+	InMemorySource
+	fail Name
+}
+
+func (f *failingDepsSource) GetDependencies(name Name, version Version) ([]Term, error) {
+	if name == f.fail {
+		return nil, errors.New("registry unavailable")
+	}
+	return f.InMemorySource.GetDependencies(name, version)
+}
+
+func TestDependencyErrorIncludesRequirementChain(t *testing.T) {
+	source := &failingDepsSource{fail: MakeName("c")}
+	source.AddPackage(MakeName("a"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("b"), EqualsCondition{Version: SimpleVersion("1.0.0")}),
+	})
+	source.AddPackage(MakeName("b"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("c"), EqualsCondition{Version: SimpleVersion("1.0.0")}),
+	})
+	source.AddPackage(MakeName("c"), SimpleVersion("1.0.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("a"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	_, err := NewSolver(root, source).Solve(root.Term())
+	var depErr *DependencyError
+	if !errors.As(err, &depErr) {
+		t.Fatalf("expected DependencyError, got %v", err)
+	}
+	if got := joinNameValues(depErr.Chain); got != "a,b,c" {
+		t.Fatalf("unexpected chain %q", got)
+	}
+	if !strings.Contains(err.Error(), "(required by a -> b)") {
+		t.Fatalf("expected chain in message, got %q", err.Error())
+	}
+}
+
+// emptyListingSource lists no versions for one package instead of failing.
+type emptyListingSource struct {
+	Source
+	empty Name
+}
+
+func (s emptyListingSource) GetVersions(name Name) ([]Version, error) {
+	if name == s.empty {
+		return []Version{}, nil
+	}
+	return s.Source.GetVersions(name)
+}
+
+func TestUnpublishedPackageNamedInError(t *testing.T) {
+	inner := &InMemorySource{}
+	inner.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("lib"), EqualsCondition{Version: SimpleVersion("1.0.0")}),
+	})
+	inner.AddPackage(MakeName("lib"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("ghost"), NewVersionSetCondition(mustParseVersionRange(t, ">=1.0.0"))),
+	})
+	source := emptyListingSource{Source: inner, empty: MakeName("ghost")}
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	_, err := NewSolver(root, source).EnableIncompatibilityTracking().Solve(root.Term())
+	nsErr, ok := AsNoSolution(err)
+	if !ok {
+		t.Fatalf("expected no solution, got %v", err)
+	}
+	if !strings.Contains(nsErr.Error(), "No versions of ghost are published (required by app -> lib)") {
+		t.Fatalf("expected the unpublished package and its chain in the report, got:\n%s", nsErr)
+	}
+}
+
+// countingSource records how often each package's versions are listed.
+type countingSource struct {
+	Source
+	calls map[Name]int
+}
+
+func (s *countingSource) GetVersions(name Name) ([]Version, error) {
+	s.calls[name]++
+	return s.Source.GetVersions(name)
+}
+
+func TestMissingPackagesQueriedOnceAndListed(t *testing.T) {
+	inner := &InMemorySource{}
+	ghost := NewTerm(MakeName("ghost"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+	phantom := NewTerm(MakeName("phantom"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+	inner.AddPackage(MakeName("app"), SimpleVersion("1.2.0"), []Term{ghost})
+	inner.AddPackage(MakeName("app"), SimpleVersion("1.1.0"), []Term{ghost})
+	inner.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{phantom})
+	source := &countingSource{Source: inner, calls: make(map[Name]int)}
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), NewVersionSetCondition(FullVersionSet()))
+
+	for _, tracking := range []bool{true, false} {
+		clear(source.calls)
+		_, err := NewSolver(root, source).Configure(WithIncompatibilityTracking(tracking)).Solve(root.Term())
+		if err == nil {
+			t.Fatalf("expected resolution to fail")
+		}
+		for _, name := range []string{"ghost", "phantom"} {
+			if calls := source.calls[MakeName(name)]; calls != 1 {
+				t.Fatalf("expected %s to be looked up once, got %d", name, calls)
+			}
+		}
+		if !strings.Contains(err.Error(), "Packages not found: ghost, phantom") {
+			t.Fatalf("expected all missing packages in the error, got:\n%v", err)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import "strings"
+
+// maxEvalCacheEntries bounds the evaluation cache; it is cleared when full.
+const maxEvalCacheEntries = 1 << 16
+
+// evalCacheKey identifies an incompatibility evaluated against a particular
+// combination of package states.
+type evalCacheKey struct {
+	inc   *Incompatibility
+	state string
+}
+
+// evalCacheEntry is a memoized evaluateIncompatibility result. unsatisfied is
+// the index of the single unsatisfied term, or -1.
+type evalCacheEntry struct {
+	relation    incompatibilityRelation
+	unsatisfied int
+}
+
+// packageFingerprint returns a string identifying the allowed set of name and
+// whether it is required. Equal fingerprints give equal relations for every
+// term on name, so it is memoized on the latest assignment: assignments below
+// it never change while it is on the stack.
+func (ps *partialSolution) packageFingerprint(name Name) string {
+	latest := ps.latest(name)
+	if latest == nil {
+		return ""
+	}
+	if latest.fingerprint == "" {
+		fp := ps.allowedSet(name).String()
+		if ps.isRequired(name) {
+			fp += "!"
+		}
+		latest.fingerprint = "=" + fp
+	}
+	return latest.fingerprint
+}
+
+// cachedEvaluation evaluates inc, reusing the result of an earlier evaluation
+// against identical package states. Backtracking typically restores states
+// seen before, so re-propagation after a shallow backjump mostly hits.
+func (st *solverState) cachedEvaluation(inc *Incompatibility) (incompatibilityRelation, *Term, error) {
+	var b strings.Builder
+	for _, term := range inc.Terms {
+		b.WriteString(st.partial.packageFingerprint(term.Name))
+		b.WriteByte(0)
+	}
+	key := evalCacheKey{inc: inc, state: b.String()}
+
+	if entry, ok := st.evalCache[key]; ok {
+		st.evalCacheHits++
+		if entry.unsatisfied < 0 {
+			return entry.relation, nil, nil
+		}
+		term := inc.Terms[entry.unsatisfied]
+		return entry.relation, &term, nil
+	}
+	st.evalCacheMisses++
+
+	relation, unsatisfied, err := st.evaluateTerms(inc)
+	if err != nil {
+		return relation, nil, err
+	}
+
+	entry := evalCacheEntry{relation: relation, unsatisfied: -1}
+	if unsatisfied != nil {
+		for i := range inc.Terms {
+			if inc.Terms[i].Name == unsatisfied.Name {
+				entry.unsatisfied = i
+				break
+			}
+		}
+	}
+	if st.evalCache == nil || len(st.evalCache) >= maxEvalCacheEntries {
+		st.evalCache = make(map[evalCacheKey]evalCacheEntry)
+	}
+	st.evalCache[key] = entry
+	return relation, unsatisfied, nil
+}
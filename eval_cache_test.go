@@ -0,0 +1,71 @@
+package pubgrub
+
+import (
+	"testing"
+)
+
+func TestEvaluationCacheMatchesUncachedSolve(t *testing.T) {
+	source := patchReleaseSource(t, 12, func(i int) string {
+		if i == 0 {
+			return ">=1.0.0"
+		}
+		return ">=2.0.0"
+	})
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("foo"), NewVersionSetCondition(FullVersionSet()))
+
+	plain := NewSolver(root, source)
+	want, err := plain.Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cached := NewSolverWithOptions([]Source{root, source}, WithEvaluationCache(true))
+	got, err := cached.Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for _, nv := range want {
+		if ver, ok := got.GetVersion(nv.Name); !ok || ver.Sort(nv.Version) != 0 {
+			t.Fatalf("expected %s %s, got %v", nv.Name.Value(), nv.Version, ver)
+		}
+	}
+
+	stats := cached.Stats()
+	if stats.EvalCacheHits == 0 {
+		t.Fatalf("expected cache hits after backtracking, got %+v", stats)
+	}
+	if rate := stats.EvalCacheHitRate(); rate <= 0 || rate >= 1 {
+		t.Fatalf("expected hit rate in (0, 1), got %f", rate)
+	}
+	if plain.Stats().EvalCacheHits != 0 || plain.Stats().EvalCacheMisses != 0 {
+		t.Fatalf("expected no cache activity when disabled, got %+v", plain.Stats())
+	}
+}
+
+func TestEvaluationCacheReportsSameFailure(t *testing.T) {
+	source := patchReleaseSource(t, 6, func(int) string { return ">=2.0.0" })
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("foo"), NewVersionSetCondition(FullVersionSet()))
+
+	plain := NewSolver(root, source).EnableIncompatibilityTracking()
+	_, plainErr := plain.Solve(root.Term())
+	cached := NewSolverWithOptions([]Source{root, source},
+		WithIncompatibilityTracking(true),
+		WithEvaluationCache(true),
+	)
+	_, cachedErr := cached.Solve(root.Term())
+
+	if plainErr == nil || cachedErr == nil {
+		t.Fatalf("expected both solves to fail: plain=%v cached=%v", plainErr, cachedErr)
+	}
+	if plainErr.Error() != cachedErr.Error() {
+		t.Fatalf("expected identical explanations:\n%s\nvs\n%s", plainErr, cachedErr)
+	}
+}
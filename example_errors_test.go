@@ -90,11 +90,8 @@ func ExampleNoSolutionError_collapsedReporter() {
 
 	// Output:
 	// Error:
-	// no versions of icons == 2.0.0 satisfy the constraint
-	// And because dropdown 2.0.0 depends on icons == 2.0.0
-	// And because dropdown == 2.0.0 is forbidden
-	// And because $$root 1 depends on dropdown == 2.0.0
-	// And because $$root == 1 is forbidden
+	// Because $$root 1 depends on dropdown == 2.0.0 which depends on icons == 2.0.0
+	// And because no versions of icons == 2.0.0 satisfy the constraint, $$root == 1 is forbidden
 }
 
 // Example demonstrating incompatibility tracking
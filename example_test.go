@@ -15,6 +15,7 @@
 package pubgrub_test
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/contriboss/pubgrub-go"
@@ -111,3 +112,24 @@ func ExampleSemanticVersion() {
 	// v2 > v1: true
 	// v3 (prerelease) < 2.0.0: true
 }
+
+// ExampleResolve resolves a set of requirements in one call.
+func ExampleResolve() {
+	source := &pubgrub.InMemorySource{}
+	for _, v := range []string{"1.0.0", "1.4.0", "2.0.0"} {
+		ver, _ := pubgrub.ParseSemanticVersion(v)
+		source.AddPackage(pubgrub.MakeName("lodash"), ver, nil)
+	}
+
+	solution, err := pubgrub.Resolve(context.Background(), map[string]string{
+		"lodash": ">=1.0.0, <2.0.0",
+	}, source)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	for nv := range solution.All() {
+		fmt.Println(nv)
+	}
+	// Output: lodash 1.4.0
+}
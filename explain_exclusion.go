@@ -0,0 +1,153 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExplainExclusion describes why version is outside set by naming the bound
+// that excludes it. Reporters use it to answer "why wasn't 2.4.1 chosen?"
+// follow-ups once a derivation has narrowed a package to set.
+//
+// Example:
+//
+//	set, _ := ParseVersionRange(">=1.0.0, <2.4.0")
+//	fmt.Println(ExplainExclusion(set, version))
+//	// 2.4.1 is excluded by the upper bound <2.4.0 of >=1.0.0, <2.4.0
+func ExplainExclusion(set VersionSet, version Version) string {
+	if version == nil {
+		return "no version given"
+	}
+	if set == nil || set.IsEmpty() {
+		return fmt.Sprintf("%s is excluded because no versions are allowed", version)
+	}
+	if set.Contains(version) {
+		return fmt.Sprintf("%s is allowed by %s", version, set)
+	}
+
+	iv, ok := set.(*VersionIntervalSet)
+	if !ok {
+		return fmt.Sprintf("%s is not in %s", version, set)
+	}
+
+	// Find the intervals immediately below and above version. Intervals are
+	// normalized, so at most one of each exists and they are adjacent.
+	var below, above *versionInterval
+	for i := range iv.intervals {
+		interval := &iv.intervals[i]
+		if boundExcludesFromBelow(interval.lower, version) {
+			above = interval
+			break
+		}
+		below = interval
+	}
+
+	switch {
+	case below != nil && above != nil:
+		if below.upper.isFinite() && above.lower.isFinite() &&
+			below.upper.version.Sort(version) == 0 && above.lower.version.Sort(version) == 0 {
+			return fmt.Sprintf("%s is explicitly excluded from %s", version, set)
+		}
+		return fmt.Sprintf("%s falls in the gap between %s and %s of %s",
+			version, upperBoundString(below.upper), lowerBoundString(above.lower), set)
+	case below != nil:
+		return fmt.Sprintf("%s is excluded by the upper bound %s of %s",
+			version, upperBoundString(below.upper), set)
+	case above != nil:
+		return fmt.Sprintf("%s is excluded by the lower bound %s of %s",
+			version, lowerBoundString(above.lower), set)
+	default:
+		return fmt.Sprintf("%s is not in %s", version, set)
+	}
+}
+
+// boundExcludesFromBelow reports whether lower starts above version.
+func boundExcludesFromBelow(lower versionBound, version Version) bool {
+	if !lower.isFinite() {
+		return lower.isPosInfinity()
+	}
+	cmp := lower.version.Sort(version)
+	return cmp > 0 || (cmp == 0 && !lower.inclusive)
+}
+
+func lowerBoundString(b versionBound) string {
+	if b.inclusive {
+		return fmt.Sprintf(">=%s", b.version)
+	}
+	return fmt.Sprintf(">%s", b.version)
+}
+
+func upperBoundString(b versionBound) string {
+	if b.inclusive {
+		return fmt.Sprintf("<=%s", b.version)
+	}
+	return fmt.Sprintf("<%s", b.version)
+}
+
+// ExplainVersion answers "why wasn't version of name chosen?" for a failed
+// solve. Every dependency in the derivation tree whose requirement on name
+// excludes version contributes one line, naming the depender and the bound
+// responsible. Returns an empty string when no dependency in the tree rules
+// the version out.
+//
+// Example:
+//
+//	var nsErr *NoSolutionError
+//	if errors.As(err, &nsErr) {
+//	    fmt.Println(nsErr.ExplainVersion(MakeName("rack"), version))
+//	}
+func (e *NoSolutionError) ExplainVersion(name Name, version Version) string {
+	if e == nil || e.Incompatibility == nil {
+		return ""
+	}
+
+	var lines []string
+	seen := make(map[string]bool)
+	visited := make(map[*Incompatibility]bool)
+
+	var walk func(inc *Incompatibility)
+	walk = func(inc *Incompatibility) {
+		if inc == nil || visited[inc] {
+			return
+		}
+		visited[inc] = true
+
+		if inc.Kind == KindFromDependency {
+			for _, term := range inc.Terms {
+				if term.Name != name || term.Positive {
+					continue
+				}
+				required, ok := termAllowedSet(term.Negate())
+				if !ok || required.Contains(version) {
+					continue
+				}
+				line := fmt.Sprintf("%s requires %s %s: %s",
+					inc.depender(), FormatName(name), required, ExplainExclusion(required, version))
+				if !seen[line] {
+					seen[line] = true
+					lines = append(lines, line)
+				}
+			}
+		}
+
+		walk(inc.Cause1)
+		walk(inc.Cause2)
+	}
+	walk(e.Incompatibility)
+
+	return strings.Join(lines, "\n")
+}
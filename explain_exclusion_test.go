@@ -0,0 +1,70 @@
+package pubgrub
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestExplainExclusion(t *testing.T) {
+	v := func(s string) Version {
+		ver, err := ParseSemanticVersion(s)
+		if err != nil {
+			t.Fatalf("parse %s: %v", s, err)
+		}
+		return ver
+	}
+	r := func(s string) VersionSet {
+		set, err := ParseVersionRange(s)
+		if err != nil {
+			t.Fatalf("parse %s: %v", s, err)
+		}
+		return set
+	}
+
+	cases := []struct {
+		set     VersionSet
+		version string
+		want    string
+	}{
+		{r(">=1.0.0, <2.4.0"), "2.4.1", "2.4.1 is excluded by the upper bound <2.4.0 of >=1.0.0, <2.4.0"},
+		{r(">=1.0.0, <2.4.0"), "0.9.0", "0.9.0 is excluded by the lower bound >=1.0.0 of >=1.0.0, <2.4.0"},
+		{r("<1.0.0 || >=2.0.0"), "1.5.0", "1.5.0 falls in the gap between <1.0.0 and >=2.0.0 of <1.0.0 || >=2.0.0"},
+		{r("!=2.4.1"), "2.4.1", "2.4.1 is explicitly excluded from <2.4.1 || >2.4.1"},
+		{r(">=1.0.0"), "1.2.0", "1.2.0 is allowed by >=1.0.0"},
+		{EmptyVersionSet(), "1.0.0", "1.0.0 is excluded because no versions are allowed"},
+	}
+
+	for _, tc := range cases {
+		if got := ExplainExclusion(tc.set, v(tc.version)); got != tc.want {
+			t.Fatalf("ExplainExclusion(%s, %s) = %q, want %q", tc.set, tc.version, got, tc.want)
+		}
+	}
+}
+
+func TestNoSolutionErrorExplainVersion(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("rack"), SimpleVersion("2.0.0"), nil)
+	source.AddPackage(MakeName("rack"), SimpleVersion("3.0.0"), nil)
+	old, _ := ParseVersionRange("<3.0.0")
+	source.AddPackage(MakeName("sinatra"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("rack"), NewVersionSetCondition(old)),
+	})
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("sinatra"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+	root.AddPackage(MakeName("rack"), EqualsCondition{Version: SimpleVersion("3.0.0")})
+
+	solver := NewSolverWithOptions([]Source{root, source}, WithIncompatibilityTracking(true))
+	_, err := solver.Solve(root.Term())
+
+	var nsErr *NoSolutionError
+	if !errors.As(err, &nsErr) {
+		t.Fatalf("expected NoSolutionError, got %v", err)
+	}
+	got := nsErr.ExplainVersion(MakeName("rack"), SimpleVersion("3.0.0"))
+	if !strings.Contains(got, "sinatra 1.0.0 requires rack <3.0.0") ||
+		!strings.Contains(got, "upper bound <3.0.0") {
+		t.Fatalf("unexpected explanation:\n%s", got)
+	}
+}
@@ -0,0 +1,292 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package faketestsource provides a fake package registry for testing code
+// that integrates with the pubgrub solver. A Registry is a pubgrub.Source
+// whose answers can be slowed down, failed, paginated and changed while a
+// solve is running, so timeouts, retries and inconsistency handling can be
+// exercised deterministically without a network.
+//
+// Example:
+//
+//	registry := faketestsource.New(1)
+//	registry.Add(pubgrub.MakeName("rails"), pubgrub.SimpleVersion("7.1.0"))
+//	registry.ErrorRate = 0.2 // One call in five fails with ErrInjected
+//	registry.Latency = func(faketestsource.Call) time.Duration { return 50 * time.Millisecond }
+//	registry.After(3, func(r *faketestsource.Registry) {
+//	    r.Remove(pubgrub.MakeName("rails"), pubgrub.SimpleVersion("7.1.0")) // Yanked mid-solve
+//	})
+//	_, err := pubgrub.NewSolver(root, myRetryingSource(registry)).Solve(root.Term())
+package faketestsource
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/contriboss/pubgrub-go"
+)
+
+// ErrInjected is wrapped by every failure injected through ErrorRate.
+var ErrInjected = errors.New("injected fault")
+
+// Op identifies the kind of registry call.
+type Op int
+
+const (
+	// OpVersions lists one page of a package's versions.
+	OpVersions Op = iota
+	// OpDependencies fetches the dependencies of a package version.
+	OpDependencies
+)
+
+// String returns "versions" or "dependencies".
+func (op Op) String() string {
+	if op == OpDependencies {
+		return "dependencies"
+	}
+	return "versions"
+}
+
+// Call describes one registry call, as passed to Latency and Fault and
+// recorded in Calls.
+type Call struct {
+	// Seq numbers calls from 1 in the order they were made.
+	Seq  int
+	Op   Op
+	Name pubgrub.Name
+	// Version is set for OpDependencies.
+	Version pubgrub.Version
+	// Page is the page index for OpVersions.
+	Page int
+}
+
+// String renders the call, for example "#3 dependencies rails 7.1.0".
+func (c Call) String() string {
+	if c.Op == OpDependencies {
+		return fmt.Sprintf("#%d %s %s %s", c.Seq, c.Op, pubgrub.FormatName(c.Name), c.Version)
+	}
+	return fmt.Sprintf("#%d %s %s page %d", c.Seq, c.Op, pubgrub.FormatName(c.Name), c.Page)
+}
+
+// Registry is a fake package registry. Configure its fields before use; the
+// package data may be changed at any time, including from After hooks while
+// a solve is running. It is safe for concurrent use, but injected errors and
+// mutations are only reproducible when calls arrive in a deterministic order.
+type Registry struct {
+	// Latency, when set, returns how long each call takes. The delay is
+	// spent in Sleep before the call is answered.
+	Latency func(Call) time.Duration
+	// Sleep waits out latencies. Default: time.Sleep. Tests can replace it
+	// with a fake clock.
+	Sleep func(time.Duration)
+	// ErrorRate is the probability, between 0 and 1, that a call fails with
+	// an error wrapping ErrInjected. Failures are drawn from a generator
+	// seeded by New. Default: 0
+	ErrorRate float64
+	// Fault, when set, is consulted for every call; a non-nil error fails
+	// the call with it. It is applied before ErrorRate.
+	Fault func(Call) error
+	// PageSize splits version listings into pages of at most PageSize
+	// versions, each fetched by its own call. Default: 0 (one page)
+	PageSize int
+
+	mu        sync.Mutex
+	rand      *rand.Rand
+	packages  map[pubgrub.Name][]release
+	calls     []Call
+	mutations []mutation
+}
+
+// release is one published version and its dependencies.
+type release struct {
+	version pubgrub.Version
+	deps    []pubgrub.Term
+}
+
+// mutation is a change scheduled by After.
+type mutation struct {
+	after  int
+	mutate func(*Registry)
+}
+
+// New returns an empty registry whose injected errors are drawn from a
+// generator seeded with seed.
+func New(seed uint64) *Registry {
+	return &Registry{
+		rand:     rand.New(rand.NewPCG(seed, seed)),
+		packages: make(map[pubgrub.Name][]release),
+	}
+}
+
+// Add publishes version of name with deps, replacing an existing release of
+// the same version. Versions are listed in ascending order.
+func (r *Registry) Add(name pubgrub.Name, version pubgrub.Version, deps ...pubgrub.Term) *Registry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.packages == nil {
+		r.packages = make(map[pubgrub.Name][]release)
+	}
+	releases := slices.DeleteFunc(r.packages[name], func(rel release) bool { return rel.version.Sort(version) == 0 })
+	releases = append(releases, release{version: version, deps: deps})
+	slices.SortFunc(releases, func(a, b release) int { return a.version.Sort(b.version) })
+	r.packages[name] = releases
+	return r
+}
+
+// Remove unpublishes version of name, as when a release is yanked. The
+// package stays known, possibly with no versions.
+func (r *Registry) Remove(name pubgrub.Name, version pubgrub.Version) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if releases, ok := r.packages[name]; ok {
+		r.packages[name] = slices.DeleteFunc(releases, func(rel release) bool { return rel.version.Sort(version) == 0 })
+	}
+}
+
+// After schedules mutate to run once calls calls have been answered, before
+// the next call is served. It simulates a registry changing mid-solve.
+func (r *Registry) After(calls int, mutate func(*Registry)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mutations = append(r.mutations, mutation{after: calls, mutate: mutate})
+}
+
+// Calls returns the calls made so far, in order.
+func (r *Registry) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.calls)
+}
+
+// ListPage returns page (from 0) of the versions of name and whether more
+// pages follow. It is the paginated endpoint GetVersions is built on, for
+// testing clients that page through listings themselves.
+func (r *Registry) ListPage(name pubgrub.Name, page int) ([]pubgrub.Version, bool, error) {
+	if err := r.begin(Call{Op: OpVersions, Name: name, Page: page}); err != nil {
+		return nil, false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	releases, ok := r.packages[name]
+	if !ok {
+		return nil, false, &pubgrub.PackageNotFoundError{Package: name}
+	}
+	start, end := 0, len(releases)
+	if r.PageSize > 0 {
+		start = min(page*r.PageSize, len(releases))
+		end = min(start+r.PageSize, len(releases))
+	} else if page > 0 {
+		start = end
+	}
+	versions := make([]pubgrub.Version, 0, end-start)
+	for _, rel := range releases[start:end] {
+		versions = append(versions, rel.version)
+	}
+	return versions, end < len(releases), nil
+}
+
+// GetVersions implements pubgrub.Source by fetching every page of the
+// listing. A mutation between pages shows up in the result, as it would
+// against a live paginated registry.
+func (r *Registry) GetVersions(name pubgrub.Name) ([]pubgrub.Version, error) {
+	var versions []pubgrub.Version
+	for page := 0; ; page++ {
+		chunk, more, err := r.ListPage(name, page)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, chunk...)
+		if !more {
+			return versions, nil
+		}
+	}
+}
+
+// GetDependencies implements pubgrub.Source.
+func (r *Registry) GetDependencies(name pubgrub.Name, version pubgrub.Version) ([]pubgrub.Term, error) {
+	if err := r.begin(Call{Op: OpDependencies, Name: name, Version: version}); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	releases, ok := r.packages[name]
+	if !ok {
+		return nil, &pubgrub.PackageNotFoundError{Package: name}
+	}
+	for _, rel := range releases {
+		if rel.version.Sort(version) == 0 {
+			return slices.Clone(rel.deps), nil
+		}
+	}
+	return nil, &pubgrub.PackageVersionNotFoundError{Package: name, Version: version}
+}
+
+// begin runs due mutations, records call, waits out its latency and decides
+// whether it fails.
+func (r *Registry) begin(call Call) error {
+	r.mu.Lock()
+	var due []func(*Registry)
+	r.mutations = slices.DeleteFunc(r.mutations, func(m mutation) bool {
+		if m.after <= len(r.calls) {
+			due = append(due, m.mutate)
+			return true
+		}
+		return false
+	})
+	r.mu.Unlock()
+	for _, mutate := range due {
+		mutate(r)
+	}
+
+	r.mu.Lock()
+	call.Seq = len(r.calls) + 1
+	r.calls = append(r.calls, call)
+	failed := r.ErrorRate > 0 && r.random() < r.ErrorRate
+	r.mu.Unlock()
+
+	if r.Latency != nil {
+		if delay := r.Latency(call); delay > 0 {
+			sleep := r.Sleep
+			if sleep == nil {
+				sleep = time.Sleep
+			}
+			sleep(delay)
+		}
+	}
+	if r.Fault != nil {
+		if err := r.Fault(call); err != nil {
+			return err
+		}
+	}
+	if failed {
+		return fmt.Errorf("%s: %w", call, ErrInjected)
+	}
+	return nil
+}
+
+// random draws from the seeded generator. r.mu must be held.
+func (r *Registry) random() float64 {
+	if r.rand == nil {
+		r.rand = rand.New(rand.NewPCG(0, 0))
+	}
+	return r.rand.Float64()
+}
+
+var _ pubgrub.Source = (*Registry)(nil)
@@ -0,0 +1,122 @@
+package faketestsource
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/contriboss/pubgrub-go"
+)
+
+func TestRegistryPaginatesListings(t *testing.T) {
+	registry := New(1)
+	for _, v := range []string{"1.0.0", "1.1.0", "1.2.0"} {
+		registry.Add(pubgrub.MakeName("lib"), pubgrub.SimpleVersion(v))
+	}
+	registry.PageSize = 2
+
+	versions, err := registry.GetVersions(pubgrub.MakeName("lib"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(versions) != 3 || versions[2].String() != "1.2.0" {
+		t.Fatalf("unexpected versions %v", versions)
+	}
+	if calls := registry.Calls(); len(calls) != 2 || calls[1].Page != 1 {
+		t.Fatalf("expected two page calls, got %v", calls)
+	}
+}
+
+func TestRegistryInjectsFaultsDeterministically(t *testing.T) {
+	run := func() []bool {
+		registry := New(1)
+		registry.Add(pubgrub.MakeName("lib"), pubgrub.SimpleVersion("1.0.0"))
+		registry.ErrorRate = 0.5
+		var failed []bool
+		for range 20 {
+			_, err := registry.GetVersions(pubgrub.MakeName("lib"))
+			if err != nil && !errors.Is(err, ErrInjected) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			failed = append(failed, err != nil)
+		}
+		return failed
+	}
+
+	first, second := run(), run()
+	failures := 0
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("expected the same failures for the same seed, call %d differs", i+1)
+		}
+		if first[i] {
+			failures++
+		}
+	}
+	if failures == 0 || failures == len(first) {
+		t.Fatalf("expected some calls to fail, got %d of %d", failures, len(first))
+	}
+}
+
+func TestRegistryLatencyAndFaultHooks(t *testing.T) {
+	anyVersion := pubgrub.NewVersionSetCondition(pubgrub.FullVersionSet())
+	registry := New(1)
+	registry.Add(pubgrub.MakeName("app"), pubgrub.SimpleVersion("1.0.0"), pubgrub.NewTerm(pubgrub.MakeName("lib"), anyVersion))
+	for _, v := range []string{"1.0.0", "1.1.0", "1.2.0"} {
+		registry.Add(pubgrub.MakeName("lib"), pubgrub.SimpleVersion(v))
+	}
+	root := pubgrub.NewRootSource()
+	root.AddPackage(pubgrub.MakeName("app"), anyVersion)
+
+	var slept time.Duration
+	registry.Sleep = func(d time.Duration) { slept += d }
+	registry.Latency = func(call Call) time.Duration {
+		if call.Op == OpDependencies {
+			return time.Second
+		}
+		return 0
+	}
+	broken := errors.New("registry down")
+	registry.Fault = func(call Call) error {
+		if call.Op == OpDependencies && call.Name == pubgrub.MakeName("lib") {
+			return broken
+		}
+		return nil
+	}
+
+	_, err := pubgrub.NewSolver(root, registry).Solve(root.Term())
+	if !errors.Is(err, broken) {
+		t.Fatalf("expected the injected fault, got %v", err)
+	}
+	var want time.Duration
+	for _, call := range registry.Calls() {
+		if call.Op == OpDependencies {
+			want += time.Second
+		}
+	}
+	if want == 0 || slept != want {
+		t.Fatalf("expected every dependency call to be delayed by a second, slept %v for %v", slept, registry.Calls())
+	}
+}
+
+func TestRegistryMutatesMidSolve(t *testing.T) {
+	anyVersion := pubgrub.NewVersionSetCondition(pubgrub.FullVersionSet())
+	registry := New(1)
+	registry.Add(pubgrub.MakeName("app"), pubgrub.SimpleVersion("1.0.0"), pubgrub.NewTerm(pubgrub.MakeName("lib"), anyVersion))
+	for _, v := range []string{"1.0.0", "1.1.0", "1.2.0"} {
+		registry.Add(pubgrub.MakeName("lib"), pubgrub.SimpleVersion(v))
+	}
+	root := pubgrub.NewRootSource()
+	root.AddPackage(pubgrub.MakeName("app"), anyVersion)
+
+	// Yank lib 1.2.0 once the solver has listed lib.
+	registry.After(3, func(r *Registry) {
+		r.Remove(pubgrub.MakeName("lib"), pubgrub.SimpleVersion("1.2.0"))
+	})
+
+	_, err := pubgrub.NewSolver(root, registry).Solve(root.Term())
+	var notFound *pubgrub.PackageVersionNotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("expected the yanked version to be reported, got %v (calls %v)", err, registry.Calls())
+	}
+}
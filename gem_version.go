@@ -0,0 +1,168 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GemVersion is a RubyGems version such as "7.1.2" or "2.1.0.pre1", ordered
+// like Gem::Version. The version splits into runs of digits and runs of
+// letters; numeric runs compare as numbers, letter runs compare as strings
+// and sort below any number. A version with letters is a prerelease, so
+// "2.1.0.pre1" sorts below "2.1.0". Trailing zeros are ignored, so "1.2" and
+// "1.2.0" sort equal, and a "-" reads as ".pre." as it does in RubyGems.
+//
+// Other Version types are compared through their String form with the same
+// rules.
+//
+// Example:
+//
+//	pre, _ := ParseGemVersion("2.1.0.pre1")
+//	release, _ := ParseGemVersion("2.1.0")
+//	fmt.Println(pre.Sort(release) < 0) // true
+type GemVersion struct {
+	raw      string
+	segments []string
+}
+
+// ParseGemVersion parses s as a RubyGems version: a leading number followed
+// by letters, digits, dots and dashes, with no empty dot-separated parts.
+func ParseGemVersion(s string) (*GemVersion, error) {
+	if s == "" || s[0] < '0' || s[0] > '9' {
+		return nil, fmt.Errorf("invalid gem version: %q", s)
+	}
+	for part := range strings.SplitSeq(s, ".") {
+		if part == "" || strings.Trim(part, "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ-") != "" {
+			return nil, fmt.Errorf("invalid gem version: %q", s)
+		}
+	}
+	return &GemVersion{raw: s, segments: gemSegments(s)}, nil
+}
+
+// String returns the version as written.
+func (v *GemVersion) String() string {
+	return v.raw
+}
+
+// Sort implements Version.
+func (v *GemVersion) Sort(other Version) int {
+	if o, ok := other.(*GemVersion); ok {
+		return compareGemSegments(v.segments, o.segments)
+	}
+	return compareGemSegments(v.segments, gemSegments(other.String()))
+}
+
+// prerelease reports whether v contains letters, as Gem::Version#prerelease?
+// does.
+func (v *GemVersion) prerelease() bool {
+	return strings.ContainsFunc(v.raw, isGemLetter)
+}
+
+// gemSegments splits s into runs of digits and runs of letters, then drops
+// the trailing zeros of its release and prerelease parts, matching
+// Gem::Version#canonical_segments.
+func gemSegments(s string) []string {
+	var segments []string
+	for i := 0; i < len(s); {
+		if s[i] == '-' {
+			segments = append(segments, "pre")
+			i++
+			continue
+		}
+		j := i
+		switch {
+		case s[i] >= '0' && s[i] <= '9':
+			for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+				j++
+			}
+		case isGemLetter(rune(s[i])):
+			for j < len(s) && isGemLetter(rune(s[j])) {
+				j++
+			}
+		default:
+			i++
+			continue
+		}
+		segments = append(segments, s[i:j])
+		i = j
+	}
+
+	split := len(segments)
+	for i, segment := range segments {
+		if isGemLetterSegment(segment) {
+			split = i
+			break
+		}
+	}
+	release := trimZeroSegments(segments[:split])
+	pre := trimZeroSegments(segments[split:])
+	return append(release[:len(release):len(release)], pre...)
+}
+
+// trimZeroSegments drops trailing numeric segments equal to zero.
+func trimZeroSegments(segments []string) []string {
+	for len(segments) > 0 {
+		last := segments[len(segments)-1]
+		if isGemLetterSegment(last) || strings.TrimLeft(last, "0") != "" {
+			break
+		}
+		segments = segments[:len(segments)-1]
+	}
+	return segments
+}
+
+// compareGemSegments compares canonical segments as Gem::Version#<=> does,
+// treating missing segments as zero.
+func compareGemSegments(a, b []string) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		sa, sb := "0", "0"
+		if i < len(a) {
+			sa = a[i]
+		}
+		if i < len(b) {
+			sb = b[i]
+		}
+		la, lb := isGemLetterSegment(sa), isGemLetterSegment(sb)
+		var c int
+		switch {
+		case la && lb:
+			c = strings.Compare(sa, sb)
+		case la:
+			c = -1
+		case lb:
+			c = 1
+		default:
+			c = compareSegment(sa, sb)
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func isGemLetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+func isGemLetterSegment(segment string) bool {
+	return segment != "" && isGemLetter(rune(segment[0]))
+}
+
+var (
+	_ Version = (*GemVersion)(nil)
+)
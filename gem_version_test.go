@@ -0,0 +1,53 @@
+package pubgrub
+
+import "testing"
+
+func TestGemVersionOrdering(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"1.10.0", "1.9.0", 1},
+		{"2.0.0.pre1", "2.0.0", -1},
+		{"2.1.0.pre1", "2.0.0", 1},
+		{"2.1.0.pre1", "2.1.0.pre2", -1},
+		{"2.1.0.beta", "2.1.0.rc1", -1},
+		{"1.0.a", "1.a", 0},
+		{"1.2", "1.2.0", 0},
+		{"1.0.0-rc1", "1.0.0.pre.rc1", 0},
+		{"7.1.2.1", "7.1.2", 1},
+	}
+	for _, tc := range cases {
+		a, err := ParseGemVersion(tc.a)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tc.a, err)
+		}
+		b, err := ParseGemVersion(tc.b)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tc.b, err)
+		}
+		if got := a.Sort(b); got != tc.want {
+			t.Fatalf("%s vs %s: got %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+		if got := b.Sort(a); got != -tc.want {
+			t.Fatalf("%s vs %s: got %d, want %d", tc.b, tc.a, got, -tc.want)
+		}
+	}
+}
+
+func TestGemVersionAgainstOtherVersions(t *testing.T) {
+	pre, _ := ParseGemVersion("2.0.0.pre1")
+	for _, other := range []Version{mustSemver(t, "2.0.0"), NumericDottedVersion("2.0")} {
+		if pre.Sort(other) >= 0 || other.Sort(pre) <= 0 {
+			t.Fatalf("expected %s to sort below %s in both directions", pre, other)
+		}
+	}
+}
+
+func TestParseGemVersionRejectsMalformed(t *testing.T) {
+	for _, raw := range []string{"", "v1.0", "1..2", "1.0.", "1.0+build"} {
+		if _, err := ParseGemVersion(raw); err == nil {
+			t.Fatalf("expected %q to be rejected", raw)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+// SolverHooks lets ecosystem adapters implement selection quirks, such as
+// Ruby's "prefer non-prerelease unless required" or npm's "latest dist-tag"
+// preference, without forking version selection. Nil hooks are skipped.
+type SolverHooks struct {
+	// BeforePick receives the versions of name allowed by the current
+	// constraints, oldest first, and returns the ones the VersionStrategy
+	// may choose from. The result only expresses a preference: versions
+	// outside the allowed set are ignored, and when nothing allowed is left
+	// the original candidates are used, so a hook cannot make a solvable
+	// problem unsolvable.
+	BeforePick func(name Name, candidates []Version) []Version
+
+	// AfterDecision is called after version is chosen for name. Decisions
+	// may later be undone by backtracking, in which case the hook runs
+	// again for the replacement.
+	AfterDecision func(name Name, version Version)
+}
+
+// beforePick applies the BeforePick hook to the published versions of name,
+// returning the candidates in ascending order.
+func (st *solverState) beforePick(name Name, versions []Version, allowed VersionSet) []Version {
+	candidates := make([]Version, 0, len(versions))
+	for _, ver := range versions {
+		if allowed.Contains(ver) {
+			candidates = append(candidates, ver)
+		}
+	}
+	if len(candidates) == 0 {
+		return candidates
+	}
+
+	preferred := st.options.Hooks.BeforePick(name, append([]Version(nil), candidates...))
+	kept := make([]Version, 0, len(preferred))
+	for _, ver := range preferred {
+		if ver != nil && allowed.Contains(ver) {
+			kept = append(kept, ver)
+		}
+	}
+	if len(kept) == 0 {
+		return candidates
+	}
+	return kept
+}
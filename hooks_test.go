@@ -0,0 +1,54 @@
+package pubgrub
+
+import (
+	"slices"
+	"testing"
+)
+
+func preferStable(name Name, candidates []Version) []Version {
+	return slices.DeleteFunc(candidates, func(v Version) bool {
+		sv, ok := v.(*SemanticVersion)
+		return ok && sv.Prerelease != ""
+	})
+}
+
+func TestHooksPreferStableUnlessRequired(t *testing.T) {
+	stable := NewSemanticVersion(1, 0, 0)
+	beta := NewSemanticVersionWithPrerelease(1, 1, 0, "beta")
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("lib"), stable, nil)
+	source.AddPackage(MakeName("lib"), beta, nil)
+
+	var decided []string
+	hooks := SolverHooks{
+		BeforePick: preferStable,
+		AfterDecision: func(name Name, version Version) {
+			decided = append(decided, name.Value()+" "+version.String())
+		},
+	}
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("lib"), NewVersionSetCondition(FullVersionSet()))
+	solution, err := NewSolverWithOptions([]Source{root, source}, WithHooks(hooks)).Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, _ := solution.GetVersion(MakeName("lib")); ver == nil || ver.Sort(stable) != 0 {
+		t.Fatalf("expected the stable release, got %v", ver)
+	}
+	if !slices.Equal(decided, []string{"lib 1.0.0"}) {
+		t.Fatalf("unexpected decisions %v", decided)
+	}
+
+	// Requiring the prerelease leaves nothing stable; the hook cannot make
+	// the problem unsolvable.
+	pinned := NewRootSource()
+	pinned.AddPackage(MakeName("lib"), EqualsCondition{Version: beta})
+	solution, err = NewSolverWithOptions([]Source{pinned, source}, WithHooks(hooks)).Solve(pinned.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, _ := solution.GetVersion(MakeName("lib")); ver == nil || ver.Sort(beta) != 0 {
+		t.Fatalf("expected the required prerelease, got %v", ver)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"slices"
+	"strings"
+)
+
+// ImpactReport estimates the blast radius of changing one requirement.
+type ImpactReport struct {
+	// Package is the package the changed requirement targets.
+	Package Name
+	// Current is its version in the current solution, nil if absent.
+	Current Version
+	// Satisfied reports whether Current already meets the changed
+	// requirement, in which case nothing has to move.
+	Satisfied bool
+	// Dependents lists the packages that transitively depend on Package;
+	// their requirements on it may conflict with a new version.
+	Dependents []Name
+	// Dependencies lists the packages Package transitively depends on;
+	// their versions may change along with it.
+	Dependencies []Name
+	// Shared lists the Dependencies that other packages of the solution
+	// also depend on, where a change would ripple beyond Package.
+	Shared []Name
+	// Affected is the sorted union of Package, Dependents and Dependencies.
+	Affected []Name
+}
+
+// Impact estimates which packages of current could be affected by changing
+// the requirement on change.Name to change, without solving. It walks the
+// dependency graph of current: a new version of the package may need new
+// versions of its transitive dependencies and must still satisfy its
+// transitive dependents. The estimate is conservative and cheap enough for
+// UIs to show before running the solver.
+//
+// When the current version already satisfies change the report is empty
+// apart from Current and Satisfied. A package that is not in current has no
+// known graph; its report only has Package set. Packages whose dependencies
+// cannot be fetched are treated as leaves.
+//
+// Example:
+//
+//	report := Impact(solution, NewTerm(MakeName("rails"), NewVersionSetCondition(mustRange(">=8.0.0"))), source)
+//	fmt.Printf("%d packages may change\n", len(report.Affected))
+func Impact(current Solution, change Term, source Source) ImpactReport {
+	report := ImpactReport{Package: change.Name}
+	version, ok := current.GetVersion(change.Name)
+	if !ok {
+		return report
+	}
+	report.Current = version
+	if change.SatisfiedBy(version) {
+		report.Satisfied = true
+		return report
+	}
+
+	// Dependency edges between the packages of the solution.
+	edges := make(map[Name][]Name, len(current))
+	reverse := make(map[Name][]Name, len(current))
+	for _, nv := range current {
+		deps, err := source.GetDependencies(nv.Name, nv.Version)
+		if err != nil {
+			continue
+		}
+		for _, dep := range deps {
+			if !dep.Positive {
+				continue
+			}
+			if _, ok := current.GetVersion(dep.Name); !ok {
+				continue
+			}
+			edges[nv.Name] = append(edges[nv.Name], dep.Name)
+			reverse[dep.Name] = append(reverse[dep.Name], nv.Name)
+		}
+	}
+
+	dependencies := reachable(change.Name, edges)
+	dependents := reachable(change.Name, reverse)
+
+	// A dependency is shared when something outside the changed package's
+	// own subtree requires it.
+	for name := range dependencies {
+		for _, dependent := range reverse[name] {
+			if dependent != change.Name && !dependencies[dependent] {
+				report.Shared = append(report.Shared, name)
+				break
+			}
+		}
+	}
+
+	affected := map[Name]bool{change.Name: true}
+	for name := range dependencies {
+		report.Dependencies = append(report.Dependencies, name)
+		affected[name] = true
+	}
+	for name := range dependents {
+		if name == MakeName("$$root") {
+			continue
+		}
+		report.Dependents = append(report.Dependents, name)
+		affected[name] = true
+	}
+	for name := range affected {
+		report.Affected = append(report.Affected, name)
+	}
+
+	for _, names := range [][]Name{report.Dependents, report.Dependencies, report.Shared, report.Affected} {
+		slices.SortFunc(names, func(a, b Name) int { return strings.Compare(a.Value(), b.Value()) })
+	}
+	return report
+}
+
+// reachable returns the packages reachable from start along edges, excluding
+// start itself.
+func reachable(start Name, edges map[Name][]Name) map[Name]bool {
+	seen := make(map[Name]bool)
+	stack := []Name{start}
+	for len(stack) > 0 {
+		name := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, next := range edges[name] {
+			if next != start && !seen[next] {
+				seen[next] = true
+				stack = append(stack, next)
+			}
+		}
+	}
+	return seen
+}
@@ -0,0 +1,61 @@
+package pubgrub
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestImpactReportsBlastRadius(t *testing.T) {
+	anyVersion := NewVersionSetCondition(FullVersionSet())
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("rails"), anyVersion),
+		NewTerm(MakeName("sidekiq"), anyVersion),
+		NewTerm(MakeName("json"), anyVersion),
+	})
+	source.AddPackage(MakeName("rails"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("rack"), anyVersion),
+		NewTerm(MakeName("activesupport"), anyVersion),
+	})
+	source.AddPackage(MakeName("sidekiq"), SimpleVersion("1.0.0"), []Term{NewTerm(MakeName("rack"), anyVersion)})
+	for _, pkg := range []string{"rack", "activesupport", "json"} {
+		source.AddPackage(MakeName(pkg), SimpleVersion("1.0.0"), nil)
+	}
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+	solution, err := NewSolver(root, source).Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	combined := CombinedSource{root, source}
+
+	names := func(values ...string) []Name {
+		out := make([]Name, len(values))
+		for i, v := range values {
+			out[i] = MakeName(v)
+		}
+		return out
+	}
+
+	report := Impact(solution, NewTerm(MakeName("rails"), NewVersionSetCondition(mustParseVersionRange(t, ">=2.0.0"))), combined)
+	if report.Satisfied || report.Current == nil {
+		t.Fatalf("expected rails 1.0.0 not to satisfy the change, got %+v", report)
+	}
+	if !slices.Equal(report.Dependencies, names("activesupport", "rack")) {
+		t.Fatalf("unexpected dependencies %v", report.Dependencies)
+	}
+	if !slices.Equal(report.Dependents, names("app")) {
+		t.Fatalf("unexpected dependents %v", report.Dependents)
+	}
+	if !slices.Equal(report.Shared, names("rack")) {
+		t.Fatalf("expected rack to be shared with sidekiq, got %v", report.Shared)
+	}
+	if !slices.Equal(report.Affected, names("activesupport", "app", "rack", "rails")) {
+		t.Fatalf("unexpected affected set %v", report.Affected)
+	}
+
+	if report := Impact(solution, NewTerm(MakeName("rails"), anyVersion), combined); !report.Satisfied || len(report.Affected) != 0 {
+		t.Fatalf("expected a satisfied change to affect nothing, got %+v", report)
+	}
+}
@@ -30,8 +30,32 @@ const (
 	KindFromDependency
 	// KindConflict means derived from conflict resolution
 	KindConflict
+	// KindSelfDependency means a package version depends on itself at a
+	// version it does not satisfy, making that version unusable
+	KindSelfDependency
+	// KindPolicy means the versions are forbidden by a policy rule, such as
+	// an organization-wide exclusion; Reason describes the rule
+	KindPolicy
 )
 
+// String returns the stable name of the kind used in serialized output.
+func (k IncompatibilityKind) String() string {
+	switch k {
+	case KindNoVersions:
+		return "no_versions"
+	case KindFromDependency:
+		return "dependency"
+	case KindConflict:
+		return "conflict"
+	case KindSelfDependency:
+		return "self_dependency"
+	case KindPolicy:
+		return "policy"
+	default:
+		return fmt.Sprintf("kind(%d)", int(k))
+	}
+}
+
 // Incompatibility represents a set of package requirements that cannot all be satisfied
 type Incompatibility struct {
 	// Terms that are incompatible
@@ -44,6 +68,12 @@ type Incompatibility struct {
 	// Package and Version for KindFromDependency
 	Package Name
 	Version Version
+	// Versions is set instead of Version when a dependency incompatibility
+	// covers a range of package versions that declare the same dependency.
+	Versions VersionSet
+	// Reason carries extra context for incompatibilities that do not come
+	// from ordinary dependency metadata, such as the offending self-dependency
+	Reason string
 }
 
 // NewIncompatibilityNoVersions creates an incompatibility for when no versions exist
@@ -54,6 +84,18 @@ func NewIncompatibilityNoVersions(term Term) *Incompatibility {
 	}
 }
 
+// NewIncompatibilityUnpublished creates a KindNoVersions incompatibility for
+// a package the source lists no versions of at all. chain is the requirement
+// chain that led to the package, ending with it, and is named in the message.
+func NewIncompatibilityUnpublished(name Name, chain []Name) *Incompatibility {
+	return &Incompatibility{
+		Terms:   []Term{NewTerm(name, nil)},
+		Kind:    KindNoVersions,
+		Package: name,
+		Reason:  fmt.Sprintf("no versions of %s are published%s", FormatName(name), chainSuffix(chain)),
+	}
+}
+
 // NewIncompatibilityFromDependency creates an incompatibility from a dependency
 // Represents: package@version depends on dependency
 // Per PubGrub spec: "foo ^1.0.0 depends on bar ^2.0.0" → {foo ^1.0.0, not bar ^2.0.0}
@@ -69,6 +111,50 @@ func NewIncompatibilityFromDependency(pkg Name, ver Version, dependency Term) *I
 	}
 }
 
+// NewIncompatibilityFromDependencyRange creates a dependency incompatibility
+// covering every version of pkg in versions.
+// Represents: pkg versions depend on dependency → {pkg versions, not dependency}
+//
+// The solver uses this when consecutive releases declare an identical
+// dependency, so a single learned clause can rule out the whole range instead
+// of one clause per release.
+func NewIncompatibilityFromDependencyRange(pkg Name, versions VersionSet, dependency Term) *Incompatibility {
+	if ver, ok := singletonVersionFromSet(versions); ok {
+		return NewIncompatibilityFromDependency(pkg, ver, dependency)
+	}
+	base := NewTerm(pkg, NewVersionSetCondition(versions))
+	return &Incompatibility{
+		Terms:    []Term{base, dependency.Negate()},
+		Kind:     KindFromDependency,
+		Package:  pkg,
+		Versions: versions,
+	}
+}
+
+// NewIncompatibilitySelfDependency creates an incompatibility forbidding
+// pkg@ver because it depends on itself with a constraint ver does not meet.
+func NewIncompatibilitySelfDependency(pkg Name, ver Version, dependency Term) *Incompatibility {
+	return &Incompatibility{
+		Terms:   []Term{NewTerm(pkg, EqualsCondition{Version: ver})},
+		Kind:    KindSelfDependency,
+		Package: pkg,
+		Version: ver,
+		Reason:  dependency.String(),
+	}
+}
+
+// NewIncompatibilityPolicy creates an incompatibility forbidding the versions
+// matched by term for the given reason. The term must be positive: it forbids
+// selecting the package at those versions without requiring the package.
+func NewIncompatibilityPolicy(term Term, reason string) *Incompatibility {
+	return &Incompatibility{
+		Terms:   []Term{term},
+		Kind:    KindPolicy,
+		Package: term.Name,
+		Reason:  reason,
+	}
+}
+
 // NewIncompatibilityConflict creates a derived incompatibility from two causes
 func NewIncompatibilityConflict(terms []Term, cause1, cause2 *Incompatibility) *Incompatibility {
 	// Deduplicate terms by Name
@@ -96,6 +182,37 @@ func (inc *Incompatibility) String() string {
 		return "version solving failed"
 	}
 
+	if inc.Kind == KindSelfDependency {
+		return fmt.Sprintf("%s depends on itself (%s), which it does not satisfy", inc.depender(), inc.Reason)
+	}
+
+	if inc.Kind == KindNoVersions && inc.Reason != "" {
+		return inc.Reason
+	}
+
+	if inc.Kind == KindPolicy && len(inc.Terms) == 1 {
+		if inc.Reason == "" {
+			return fmt.Sprintf("%s is forbidden by policy", inc.Terms[0])
+		}
+		return fmt.Sprintf("%s is forbidden by policy (%s)", inc.Terms[0], inc.Reason)
+	}
+
+	if inc.Kind == KindPolicy && len(inc.Terms) == 2 && inc.Terms[0].Positive && !inc.Terms[1].Positive {
+		statement := fmt.Sprintf("%s requires %s by policy", inc.Terms[0], inc.Terms[1].Negate())
+		if inc.Reason == "" {
+			return statement
+		}
+		return fmt.Sprintf("%s (%s)", statement, inc.Reason)
+	}
+
+	if inc.Kind == KindPolicy && len(inc.Terms) == 2 && inc.Terms[0].Positive && inc.Terms[1].Positive {
+		statement := fmt.Sprintf("%s and %s cannot both be installed by policy", FormatName(inc.Terms[0].Name), FormatName(inc.Terms[1].Name))
+		if inc.Reason == "" {
+			return statement
+		}
+		return fmt.Sprintf("%s (%s)", statement, inc.Reason)
+	}
+
 	if len(inc.Terms) == 1 {
 		return fmt.Sprintf("%s is forbidden", inc.Terms[0])
 	}
@@ -113,9 +230,9 @@ func (inc *Incompatibility) String() string {
 			dep = inc.Terms[1]
 		}
 		if !dep.Positive {
-			dep = dep.Negate()
+			return fmt.Sprintf("%s depends on %s", inc.depender(), dep.Negate())
 		}
-		return fmt.Sprintf("%s %s depends on %s", inc.Package.Value(), inc.Version, dep)
+		return fmt.Sprintf("%s forbids %s", inc.depender(), dep)
 	}
 
 	var parts []string
@@ -124,3 +241,29 @@ func (inc *Incompatibility) String() string {
 	}
 	return fmt.Sprintf("%s are incompatible", strings.Join(parts, " and "))
 }
+
+// noVersionsStatement describes a KindNoVersions incompatibility in lower
+// case, preferring the recorded reason.
+func (inc *Incompatibility) noVersionsStatement() string {
+	if inc.Reason != "" {
+		return inc.Reason
+	}
+	return fmt.Sprintf("no versions of %s satisfy the constraint", inc.Terms[0])
+}
+
+// upperFirst capitalizes the first letter of a sentence.
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// depender describes the package side of a dependency incompatibility,
+// either a single version ("foo 1.0.0") or a range ("foo >=1.0.0, <=1.0.9").
+func (inc *Incompatibility) depender() string {
+	if inc.Versions != nil {
+		return fmt.Sprintf("%s %s", FormatName(inc.Package), inc.Versions)
+	}
+	return fmt.Sprintf("%s %s", FormatName(inc.Package), inc.Version)
+}
@@ -0,0 +1,382 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ID returns a deterministic identifier for the incompatibility, derived
+// from its kind and canonicalized terms. Two incompatibilities that forbid the
+// same combination in the same way share an ID regardless of term order,
+// condition representation or the process that learned them, so IDs can be
+// used as stable references across runs.
+//
+// Example:
+//
+//	for _, inc := range solver.GetIncompatibilities() {
+//	    fmt.Printf("%s  %s\n", inc.ID(), inc)
+//	}
+func (inc *Incompatibility) ID() string {
+	if inc == nil {
+		return ""
+	}
+
+	terms := make([]string, len(inc.Terms))
+	for i, term := range inc.Terms {
+		terms[i] = canonicalTerm(term)
+	}
+	slices.Sort(terms)
+
+	hash := sha256.New()
+	hash.Write([]byte(inc.Kind.String()))
+	for _, term := range terms {
+		hash.Write([]byte{0})
+		hash.Write([]byte(term))
+	}
+	return hex.EncodeToString(hash.Sum(nil)[:8])
+}
+
+// canonicalTerm renders a term independent of its Condition implementation.
+func canonicalTerm(term Term) string {
+	polarity := "+"
+	if !term.Positive {
+		polarity = "-"
+	}
+	return term.Name.Value() + " " + polarity + termConstraint(term)
+}
+
+// termConstraint renders the constraint of a term in its positive form, so
+// "not foo >=1.0.0" yields ">=1.0.0".
+func termConstraint(term Term) string {
+	positive := term
+	if !positive.Positive {
+		positive = positive.Negate()
+	}
+	if set, ok := termAllowedSet(positive); ok && set != nil {
+		return set.String()
+	}
+	if positive.Condition == nil {
+		return "*"
+	}
+	return positive.Condition.String()
+}
+
+// IncompatibilityRecord is the serialized form of an Incompatibility.
+// Causes are referenced by ID; records are written so that causes always
+// precede the incompatibilities derived from them.
+type IncompatibilityRecord struct {
+	ID       string       `json:"id"`
+	Kind     string       `json:"kind"`
+	Terms    []TermRecord `json:"terms"`
+	Package  string       `json:"package,omitempty"`
+	Version  string       `json:"version,omitempty"`
+	Versions string       `json:"versions,omitempty"`
+	Reason   string       `json:"reason,omitempty"`
+	Cause1   string       `json:"cause1,omitempty"`
+	Cause2   string       `json:"cause2,omitempty"`
+}
+
+// TermRecord is the serialized form of a Term. Constraint is always written
+// in positive form and parsed with the range syntax of ParseVersionRange.
+type TermRecord struct {
+	Package    string `json:"package"`
+	Positive   bool   `json:"positive"`
+	Constraint string `json:"constraint"`
+	// BestEffort marks a BestEffortCondition; Constraint is the wrapped one.
+	BestEffort bool `json:"best_effort,omitempty"`
+	// Optional marks an OptionalCondition; Constraint is the wrapped one.
+	Optional bool `json:"optional,omitempty"`
+}
+
+// VersionParser converts a serialized version string back into a Version.
+type VersionParser func(string) (Version, error)
+
+// MarshalIncompatibilities serializes incompatibilities, including every
+// cause reachable from them, to JSON. Incompatibilities sharing an ID are
+// written once.
+//
+// Example:
+//
+//	data, err := MarshalIncompatibilities(solver.GetIncompatibilities())
+//	os.WriteFile("learned.json", data, 0o644)
+func MarshalIncompatibilities(incs []*Incompatibility) ([]byte, error) {
+	return json.MarshalIndent(IncompatibilityRecords(incs), "", "  ")
+}
+
+// IncompatibilityRecords converts incompatibilities and their causes into
+// records, causes first.
+func IncompatibilityRecords(incs []*Incompatibility) []IncompatibilityRecord {
+	var records []IncompatibilityRecord
+	written := make(map[string]bool)
+	visited := make(map[*Incompatibility]bool)
+
+	var visit func(inc *Incompatibility)
+	visit = func(inc *Incompatibility) {
+		if inc == nil || visited[inc] {
+			return
+		}
+		visited[inc] = true
+		visit(inc.Cause1)
+		visit(inc.Cause2)
+
+		id := inc.ID()
+		if written[id] {
+			return
+		}
+		written[id] = true
+		records = append(records, newIncompatibilityRecord(id, inc))
+	}
+
+	for _, inc := range incs {
+		visit(inc)
+	}
+	return records
+}
+
+func newIncompatibilityRecord(id string, inc *Incompatibility) IncompatibilityRecord {
+	record := IncompatibilityRecord{
+		ID:     id,
+		Kind:   inc.Kind.String(),
+		Terms:  make([]TermRecord, len(inc.Terms)),
+		Reason: inc.Reason,
+		Cause1: inc.Cause1.ID(),
+		Cause2: inc.Cause2.ID(),
+	}
+	for i, term := range inc.Terms {
+		record.Terms[i] = termRecord(term)
+	}
+	if inc.Package != (Name{}) {
+		record.Package = inc.Package.Value()
+	}
+	if inc.Version != nil {
+		record.Version = inc.Version.String()
+	}
+	if inc.Versions != nil {
+		record.Versions = inc.Versions.String()
+	}
+	return record
+}
+
+// UnmarshalIncompatibilities restores incompatibilities written by
+// MarshalIncompatibilities. Versions are parsed with parse, or with the
+// SemanticVersion-then-SimpleVersion fallback of ParseVersionRange when parse
+// is nil. Use the parser matching the Version type of your Source so restored
+// terms compare correctly against its versions.
+//
+// The result preserves record order, so causes precede derived
+// incompatibilities and cause links point into the returned slice.
+func UnmarshalIncompatibilities(data []byte, parse VersionParser) ([]*Incompatibility, error) {
+	var records []IncompatibilityRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("decode incompatibilities: %w", err)
+	}
+	return IncompatibilitiesFromRecords(records, parse)
+}
+
+// IncompatibilitiesFromRecords rebuilds incompatibilities from records.
+// See UnmarshalIncompatibilities.
+func IncompatibilitiesFromRecords(records []IncompatibilityRecord, parse VersionParser) ([]*Incompatibility, error) {
+	if parse == nil {
+		parse = parseRangeVersion
+	}
+
+	byID := make(map[string]*Incompatibility, len(records))
+	result := make([]*Incompatibility, 0, len(records))
+	for _, record := range records {
+		inc, err := incompatibilityFromRecord(record, parse, byID)
+		if err != nil {
+			return nil, fmt.Errorf("incompatibility %s: %w", record.ID, err)
+		}
+		byID[record.ID] = inc
+		result = append(result, inc)
+	}
+	return result, nil
+}
+
+func incompatibilityFromRecord(record IncompatibilityRecord, parse VersionParser, byID map[string]*Incompatibility) (*Incompatibility, error) {
+	kind, err := parseIncompatibilityKind(record.Kind)
+	if err != nil {
+		return nil, err
+	}
+
+	inc := &Incompatibility{Kind: kind, Reason: record.Reason}
+	for _, tr := range record.Terms {
+		term, err := termFromRecord(tr, parse)
+		if err != nil {
+			return nil, err
+		}
+		inc.Terms = append(inc.Terms, term)
+	}
+
+	if record.Package != "" {
+		inc.Package = MakeName(record.Package)
+	}
+	if record.Version != "" {
+		if inc.Version, err = parse(record.Version); err != nil {
+			return nil, fmt.Errorf("version: %w", err)
+		}
+	}
+	if record.Versions != "" {
+		if inc.Versions, err = parseSerializedSet(record.Versions, parse); err != nil {
+			return nil, fmt.Errorf("versions: %w", err)
+		}
+	}
+
+	for _, link := range []struct {
+		id     string
+		target **Incompatibility
+	}{{record.Cause1, &inc.Cause1}, {record.Cause2, &inc.Cause2}} {
+		if link.id == "" {
+			continue
+		}
+		cause, ok := byID[link.id]
+		if !ok {
+			return nil, fmt.Errorf("unknown cause %s", link.id)
+		}
+		*link.target = cause
+	}
+
+	if id := inc.ID(); record.ID != "" && id != record.ID {
+		return nil, fmt.Errorf("content does not match id (computed %s)", id)
+	}
+	return inc, nil
+}
+
+// termRecord serializes a term.
+func termRecord(term Term) TermRecord {
+	cond, bestEffort := term.Condition.(BestEffortCondition)
+	if bestEffort {
+		term.Condition = cond.Condition
+	}
+	optional, isOptional := term.Condition.(OptionalCondition)
+	if isOptional {
+		term.Condition = optional.Condition
+	}
+	return TermRecord{
+		Package:    term.Name.Value(),
+		Positive:   term.Positive,
+		Constraint: termConstraint(term),
+		BestEffort: bestEffort,
+		Optional:   isOptional,
+	}
+}
+
+// termFromRecord restores a term serialized by termRecord.
+func termFromRecord(tr TermRecord, parse VersionParser) (Term, error) {
+	set, err := parseSerializedSet(tr.Constraint, parse)
+	if err != nil {
+		return Term{}, fmt.Errorf("term %s: %w", tr.Package, err)
+	}
+	term := NewTerm(MakeName(tr.Package), conditionFromSet(set))
+	if tr.BestEffort {
+		term.Condition = BestEffortCondition{Condition: term.Condition}
+	}
+	if tr.Optional {
+		term.Condition = OptionalCondition{Condition: term.Condition}
+	}
+	if !tr.Positive {
+		term = term.Negate()
+	}
+	return term, nil
+}
+
+// conditionFromSet restores exact pins as EqualsCondition so restored terms
+// render the same way as the originals.
+func conditionFromSet(set VersionSet) Condition {
+	if ver, ok := singletonVersionFromSet(set); ok {
+		return EqualsCondition{Version: ver}
+	}
+	return NewVersionSetCondition(set)
+}
+
+// parseSerializedSet parses a VersionSet string as produced by String.
+func parseSerializedSet(s string, parse VersionParser) (VersionSet, error) {
+	if strings.TrimSpace(s) == "∅" {
+		return EmptyVersionSet(), nil
+	}
+	return parseVersionRangeWith(s, parse)
+}
+
+func parseIncompatibilityKind(s string) (IncompatibilityKind, error) {
+	for _, kind := range []IncompatibilityKind{KindNoVersions, KindFromDependency, KindConflict, KindSelfDependency, KindPolicy} {
+		if kind.String() == s {
+			return kind, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown incompatibility kind %q", s)
+}
+
+// ExplainFromExport formats the failure recorded in data, as written by
+// MarshalIncompatibilities, without the original Source, so failures can be
+// analyzed from production logs. data may hold just the final conflict or
+// the learned clauses followed by it; the final conflict is taken to be the
+// last record no other record derives from. Versions are restored as
+// semantic versions when they parse as such and as dotted or simple versions
+// otherwise, so ranges keep their order and render exactly as recorded. The
+// report uses DefaultReporter; restore the clauses with
+// UnmarshalIncompatibilities to use another Reporter.
+//
+// Example:
+//
+//	// In production:
+//	data, _ := MarshalIncompatibilities([]*Incompatibility{nsErr.Incompatibility})
+//	log.Printf("resolution failed: %s", data)
+//
+//	// Later, offline:
+//	explanation, err := ExplainFromExport(data)
+func ExplainFromExport(data []byte) (string, error) {
+	incs, err := UnmarshalIncompatibilities(data, parseExportedVersion)
+	if err != nil {
+		return "", err
+	}
+	final := finalConflict(incs)
+	if final == nil {
+		return "", errors.New("export contains no incompatibilities")
+	}
+	return (&DefaultReporter{}).Report(final), nil
+}
+
+// parseExportedVersion reads a version like ParseVersionRange does, keeping
+// the semantic form only when it renders as recorded so that restored
+// incompatibilities hash to their exported IDs.
+func parseExportedVersion(raw string) (Version, error) {
+	if sv, err := ParseSemanticVersion(raw); err == nil && sv.String() == raw {
+		return sv, nil
+	}
+	return ParseDottedVersion(raw), nil
+}
+
+// finalConflict returns the last incompatibility that is not a cause of
+// another.
+func finalConflict(incs []*Incompatibility) *Incompatibility {
+	cited := make(map[*Incompatibility]bool, len(incs))
+	for _, inc := range incs {
+		cited[inc.Cause1] = true
+		cited[inc.Cause2] = true
+	}
+	for i := len(incs) - 1; i >= 0; i-- {
+		if !cited[incs[i]] {
+			return incs[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package pubgrub
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIncompatibilityIDIsCanonical(t *testing.T) {
+	foo := NewTerm(MakeName("foo"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+	bar := NewTerm(MakeName("bar"), NewVersionSetCondition(FullVersionSet().Singleton(SimpleVersion("2.0.0"))))
+
+	a := NewIncompatibilityConflict([]Term{foo, bar}, nil, nil)
+	b := NewIncompatibilityConflict([]Term{bar, foo}, nil, nil)
+	if a.ID() != b.ID() {
+		t.Fatalf("expected term order and condition type not to affect ID: %s vs %s", a.ID(), b.ID())
+	}
+
+	c := NewIncompatibilityConflict([]Term{foo, bar.Negate()}, nil, nil)
+	if a.ID() == c.ID() {
+		t.Fatalf("expected polarity to affect ID")
+	}
+
+	d := NewIncompatibilityNoVersions(foo)
+	e := NewIncompatibilityConflict([]Term{foo}, nil, nil)
+	if d.ID() == e.ID() {
+		t.Fatalf("expected kind to affect ID")
+	}
+}
+
+func TestIncompatibilitySerializationRoundTrip(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("a"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("c"), EqualsCondition{Version: SimpleVersion("1.0.0")}),
+	})
+	source.AddPackage(MakeName("b"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("c"), EqualsCondition{Version: SimpleVersion("2.0.0")}),
+	})
+	source.AddPackage(MakeName("c"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("c"), SimpleVersion("2.0.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("a"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+	root.AddPackage(MakeName("b"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	solver := NewSolverWithOptions([]Source{root, source}, WithIncompatibilityTracking(true))
+	_, err := solver.Solve(root.Term())
+	var nsErr *NoSolutionError
+	if !errors.As(err, &nsErr) {
+		t.Fatalf("expected NoSolutionError, got %v", err)
+	}
+
+	data, err := MarshalIncompatibilities([]*Incompatibility{nsErr.Incompatibility})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	parseSimple := func(s string) (Version, error) { return SimpleVersion(s), nil }
+	restored, err := UnmarshalIncompatibilities(data, parseSimple)
+	if err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(restored) == 0 {
+		t.Fatalf("expected restored incompatibilities")
+	}
+
+	last := restored[len(restored)-1]
+	if last.ID() != nsErr.Incompatibility.ID() {
+		t.Fatalf("expected root incompatibility last with ID %s, got %s", nsErr.Incompatibility.ID(), last.ID())
+	}
+
+	want := NewNoSolutionError(nsErr.Incompatibility).Error()
+	if got := NewNoSolutionError(last).Error(); got != want {
+		t.Fatalf("restored derivation differs:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnmarshalIncompatibilitiesRejectsTamperedID(t *testing.T) {
+	data := []byte(`[{"id":"0000000000000000","kind":"no_versions","terms":[{"package":"foo","positive":true,"constraint":"==1.0.0"}]}]`)
+	if _, err := UnmarshalIncompatibilities(data, nil); err == nil {
+		t.Fatalf("expected mismatched id to be rejected")
+	}
+}
+
+func TestExplainFromExport(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("a"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("c"), EqualsCondition{Version: SimpleVersion("1.0.0")}),
+	})
+	source.AddPackage(MakeName("b"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("c"), EqualsCondition{Version: SimpleVersion("2.0.0")}),
+	})
+	source.AddPackage(MakeName("c"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("c"), SimpleVersion("2.0.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("a"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+	root.AddPackage(MakeName("b"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	solver := NewSolverWithOptions([]Source{root, source}, WithIncompatibilityTracking(true))
+	_, err := solver.Solve(root.Term())
+	var nsErr *NoSolutionError
+	if !errors.As(err, &nsErr) {
+		t.Fatalf("expected NoSolutionError, got %v", err)
+	}
+	want := (&DefaultReporter{}).Report(nsErr.Incompatibility)
+
+	for name, incs := range map[string][]*Incompatibility{
+		"final conflict": {nsErr.Incompatibility},
+		"with learned":   append(solver.GetIncompatibilities(), nsErr.Incompatibility),
+	} {
+		data, err := MarshalIncompatibilities(incs)
+		if err != nil {
+			t.Fatalf("%s: marshal: %v", name, err)
+		}
+		got, err := ExplainFromExport(data)
+		if err != nil {
+			t.Fatalf("%s: explain: %v", name, err)
+		}
+		if got != want {
+			t.Fatalf("%s: offline explanation differs:\n%s\nwant:\n%s", name, got, want)
+		}
+	}
+
+	if _, err := ExplainFromExport([]byte("[]")); err == nil {
+		t.Fatalf("expected an error for an empty export")
+	}
+}
+
+func TestExplainFromExportKeepsSemanticOrder(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("a"), mustSemver(t, "1.0.0"), []Term{
+		NewTerm(MakeName("lib"), NewVersionSetCondition(mustParseVersionRange(t, ">=1.9.0, <1.10.0"))),
+	})
+	source.AddPackage(MakeName("b"), mustSemver(t, "1.0.0"), []Term{
+		NewTerm(MakeName("lib"), NewVersionSetCondition(mustParseVersionRange(t, ">=1.10.0"))),
+	})
+	source.AddPackage(MakeName("lib"), mustSemver(t, "1.9.0"), nil)
+	source.AddPackage(MakeName("lib"), mustSemver(t, "1.10.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("a"), EqualsCondition{Version: mustSemver(t, "1.0.0")})
+	root.AddPackage(MakeName("b"), EqualsCondition{Version: mustSemver(t, "1.0.0")})
+
+	_, err := NewSolver(root, source).EnableIncompatibilityTracking().Solve(root.Term())
+	var nsErr *NoSolutionError
+	if !errors.As(err, &nsErr) {
+		t.Fatalf("expected NoSolutionError, got %v", err)
+	}
+
+	data, err := MarshalIncompatibilities([]*Incompatibility{nsErr.Incompatibility})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	got, err := ExplainFromExport(data)
+	if err != nil {
+		t.Fatalf("explain: %v", err)
+	}
+	if want := (&DefaultReporter{}).Report(nsErr.Incompatibility); got != want {
+		t.Fatalf("offline explanation differs:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"slices"
+	"strings"
+)
+
+// installedIncompatibilities requires every installed package from the
+// root. Held packages are required at their installed version, so unit
+// propagation fixes them at decision level 0 before any decision is made;
+// upgradable packages are required at any version and preferred at their
+// installed one by installedPick.
+func installedIncompatibilities(options SolverOptions, root Name) []*Incompatibility {
+	names := make([]Name, 0, len(options.Installed))
+	for name := range options.Installed {
+		if name != root {
+			names = append(names, name)
+		}
+	}
+	slices.SortFunc(names, func(a, b Name) int { return strings.Compare(a.Value(), b.Value()) })
+
+	incs := make([]*Incompatibility, 0, len(names))
+	for _, name := range names {
+		version := options.Installed[name]
+		required := NewTerm(name, EqualsCondition{Version: version})
+		reason := "installed"
+		if slices.Contains(options.Upgradable, name) {
+			required = NewTerm(name, NewVersionSetCondition(FullVersionSet()))
+			reason = "installed at " + version.String() + ", upgradable"
+		}
+		incs = append(incs, &Incompatibility{
+			Terms:   []Term{NewTerm(root, nil), required.Negate()},
+			Kind:    KindPolicy,
+			Package: root,
+			Reason:  reason,
+		})
+	}
+	return incs
+}
+
+// installedPick returns the installed version of an upgradable package when
+// it is published and still allowed, so it only changes when necessary.
+func (st *solverState) installedPick(name Name, versions []Version, allowed VersionSet) (Version, bool) {
+	installed, ok := st.options.Installed[name]
+	if !ok {
+		return nil, false
+	}
+	return publishedPick(installed, versions, allowed)
+}
+
+// preferredPick returns the preferred version of name when it is published
+// and still allowed, see WithPreferredVersions.
+func (st *solverState) preferredPick(name Name, versions []Version, allowed VersionSet) (Version, bool) {
+	preferred, ok := st.options.Preferred[name]
+	if !ok {
+		return nil, false
+	}
+	return publishedPick(preferred, versions, allowed)
+}
+
+// publishedPick returns the published version equal to target when allowed
+// contains it.
+func publishedPick(target Version, versions []Version, allowed VersionSet) (Version, bool) {
+	if !allowed.Contains(target) {
+		return nil, false
+	}
+	for _, ver := range versions {
+		if ver.Sort(target) == 0 {
+			return ver, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,171 @@
+package pubgrub
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInstalledPackagesAreKept(t *testing.T) {
+	source := &InMemorySource{}
+	for _, pkg := range []string{"libssl", "zlib"} {
+		for _, ver := range []string{"1.0.0", "2.0.0", "3.0.0"} {
+			source.AddPackage(MakeName(pkg), SimpleVersion(ver), nil)
+		}
+	}
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("app"), SimpleVersion("2.0.0"), []Term{
+		NewTerm(MakeName("libssl"), NewVersionSetCondition(mustParseVersionRange(t, ">=3.0.0"))),
+	})
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), NewVersionSetCondition(FullVersionSet()))
+
+	solver := NewSolverWithOptions([]Source{root, source},
+		WithInstalled(map[Name]Version{
+			MakeName("libssl"): SimpleVersion("2.0.0"),
+			MakeName("zlib"):   SimpleVersion("1.0.0"),
+		}),
+	)
+	solution, err := solver.Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for pkg, want := range map[string]string{"libssl": "2.0.0", "zlib": "1.0.0", "app": "1.0.0"} {
+		if ver, _ := solution.GetVersion(MakeName(pkg)); ver == nil || ver.String() != want {
+			t.Fatalf("expected %s %s, got %v in %v", pkg, want, ver, solution)
+		}
+	}
+}
+
+func TestUpgradableInstalledPackageMovesOnlyWhenNeeded(t *testing.T) {
+	source := &InMemorySource{}
+	for _, pkg := range []string{"libssl", "zlib"} {
+		for _, ver := range []string{"1.0.0", "2.0.0", "3.0.0"} {
+			source.AddPackage(MakeName(pkg), SimpleVersion(ver), nil)
+		}
+	}
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("app"), SimpleVersion("2.0.0"), []Term{
+		NewTerm(MakeName("libssl"), NewVersionSetCondition(mustParseVersionRange(t, ">=3.0.0"))),
+	})
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), NewVersionSetCondition(mustParseVersionRange(t, ">=2.0.0")))
+
+	solver := NewSolverWithOptions([]Source{root, source},
+		WithInstalled(map[Name]Version{
+			MakeName("libssl"): SimpleVersion("2.0.0"),
+			MakeName("zlib"):   SimpleVersion("1.0.0"),
+		}),
+		WithUpgradable(MakeName("libssl"), MakeName("zlib")),
+	)
+	solution, err := solver.Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, _ := solution.GetVersion(MakeName("libssl")); ver == nil || ver.String() != "3.0.0" {
+		t.Fatalf("expected libssl upgraded to 3.0.0 for app, got %v", ver)
+	}
+	if ver, _ := solution.GetVersion(MakeName("zlib")); ver == nil || ver.String() != "1.0.0" {
+		t.Fatalf("expected zlib left at its installed 1.0.0, got %v", ver)
+	}
+}
+
+func TestHeldInstalledPackageConflict(t *testing.T) {
+	source := &InMemorySource{}
+	for _, pkg := range []string{"libssl", "zlib"} {
+		for _, ver := range []string{"1.0.0", "2.0.0", "3.0.0"} {
+			source.AddPackage(MakeName(pkg), SimpleVersion(ver), nil)
+		}
+	}
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("app"), SimpleVersion("2.0.0"), []Term{
+		NewTerm(MakeName("libssl"), NewVersionSetCondition(mustParseVersionRange(t, ">=3.0.0"))),
+	})
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), NewVersionSetCondition(mustParseVersionRange(t, ">=2.0.0")))
+
+	solver := NewSolverWithOptions([]Source{root, source},
+		WithInstalled(map[Name]Version{MakeName("libssl"): SimpleVersion("2.0.0")}),
+		WithIncompatibilityTracking(true),
+	)
+	_, err := solver.Solve(root.Term())
+	if err == nil {
+		t.Fatalf("expected the held libssl to conflict with app 2.0.0")
+	}
+	if !strings.Contains(err.Error(), "(installed)") {
+		t.Fatalf("expected the installed requirement in the report, got:\n%v", err)
+	}
+}
+
+func TestPreferredVersionsKeptWhileAllowed(t *testing.T) {
+	source := &InMemorySource{}
+	for _, pkg := range []string{"libssl", "zlib"} {
+		for _, ver := range []string{"1.0.0", "2.0.0", "3.0.0"} {
+			source.AddPackage(MakeName(pkg), SimpleVersion(ver), nil)
+		}
+	}
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("app"), SimpleVersion("2.0.0"), []Term{
+		NewTerm(MakeName("libssl"), NewVersionSetCondition(mustParseVersionRange(t, ">=3.0.0"))),
+	})
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), NewVersionSetCondition(mustParseVersionRange(t, ">=2.0.0")))
+	root.AddPackage(MakeName("zlib"), NewVersionSetCondition(FullVersionSet()))
+
+	solver := NewSolverWithOptions([]Source{root, source},
+		WithPreferredVersions(map[Name]Version{
+			MakeName("libssl"): SimpleVersion("2.0.0"),
+			MakeName("zlib"):   SimpleVersion("2.0.0"),
+		}),
+		WithPreferredVersions(map[Name]Version{MakeName("unused"): SimpleVersion("1.0.0")}),
+	)
+	solution, err := solver.Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for pkg, want := range map[string]string{"zlib": "2.0.0", "libssl": "3.0.0", "app": "2.0.0"} {
+		if ver, _ := solution.GetVersion(MakeName(pkg)); ver == nil || ver.String() != want {
+			t.Fatalf("expected %s %s, got %v in %v", pkg, want, ver, solution)
+		}
+	}
+	if _, ok := solution.GetVersion(MakeName("unused")); ok {
+		t.Fatalf("expected a preference not to pull in its package: %v", solution)
+	}
+}
+
+func TestPreferredVersionGivesWayOnConflict(t *testing.T) {
+	source := &InMemorySource{}
+	for _, pkg := range []string{"libssl", "zlib"} {
+		for _, ver := range []string{"1.0.0", "2.0.0", "3.0.0"} {
+			source.AddPackage(MakeName(pkg), SimpleVersion(ver), nil)
+		}
+	}
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("app"), SimpleVersion("2.0.0"), []Term{
+		NewTerm(MakeName("libssl"), NewVersionSetCondition(mustParseVersionRange(t, ">=3.0.0"))),
+	})
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), NewVersionSetCondition(FullVersionSet()))
+	root.AddPackage(MakeName("libssl"), NewVersionSetCondition(mustParseVersionRange(t, "<3.0.0")))
+
+	// app 2.0.0 is allowed when picked but needs libssl >=3.0.0.
+	solver := NewSolverWithOptions([]Source{root, source},
+		WithPreferredVersions(map[Name]Version{MakeName("app"): SimpleVersion("2.0.0")}),
+		WithInstalled(map[Name]Version{MakeName("zlib"): SimpleVersion("1.0.0")}),
+		WithPreferredVersions(map[Name]Version{MakeName("zlib"): SimpleVersion("3.0.0")}),
+	)
+	solution, err := solver.Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, _ := solution.GetVersion(MakeName("app")); ver == nil || ver.String() != "1.0.0" {
+		t.Fatalf("expected app to fall back to 1.0.0, got %v", ver)
+	}
+	if ver, _ := solution.GetVersion(MakeName("zlib")); ver == nil || ver.String() != "1.0.0" {
+		t.Fatalf("expected the installed zlib to win over the preference, got %v", ver)
+	}
+}
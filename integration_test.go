@@ -0,0 +1,157 @@
+//go:build integration
+
+package pubgrub
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// The integration suite resolves corpora of real manifests against snapshots
+// of real registry subsets, catching correctness and performance regressions
+// that small synthetic graphs never reveal. It only runs with the
+// integration build tag:
+//
+//	go test -tags integration -run TestIntegrationSnapshots
+//
+// Snapshots are read from testdata/integration, or from the directory named
+// by PUBGRUB_SNAPSHOT_DIR. PUBGRUB_SNAPSHOT_URLS, a comma-separated list,
+// downloads further snapshots before the run, for datasets too large to
+// vendor such as the top 1000 gems or npm packages.
+
+// integrationSnapshot is a registry subset plus the manifests resolved
+// against it. Packages use the PackageRecord form of the problem-file
+// format, so a RecordingSource run against a live registry can seed one.
+type integrationSnapshot struct {
+	Ecosystem   string               `json:"ecosystem"`
+	Description string               `json:"description,omitempty"`
+	Packages    []PackageRecord      `json:"packages"`
+	Manifests   []integrationExpects `json:"manifests"`
+}
+
+// integrationExpects is one manifest and its recorded outcome.
+type integrationExpects struct {
+	Name string       `json:"name"`
+	Root []TermRecord `json:"root"`
+	// Solution maps package names to their expected versions; packages not
+	// listed must not be resolved. Ignored when Unsolvable is set.
+	Solution   map[string]string `json:"solution,omitempty"`
+	Unsolvable bool              `json:"unsolvable,omitempty"`
+	// MaxSteps fails the manifest when the solve takes more steps, so
+	// heuristic regressions show up as failures.
+	MaxSteps int `json:"max_steps,omitempty"`
+}
+
+func TestIntegrationSnapshots(t *testing.T) {
+	dir := os.Getenv("PUBGRUB_SNAPSHOT_DIR")
+	if dir == "" {
+		dir = filepath.Join("testdata", "integration")
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatalf("list snapshots: %v", err)
+	}
+	if urls := os.Getenv("PUBGRUB_SNAPSHOT_URLS"); urls != "" {
+		files = append(files, downloadSnapshots(t, strings.Split(urls, ","))...)
+	}
+	if len(files) == 0 {
+		t.Skipf("no snapshots in %s", dir)
+	}
+
+	for _, file := range files {
+		t.Run(strings.TrimSuffix(filepath.Base(file), ".json"), func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatalf("read snapshot: %v", err)
+			}
+			var snapshot integrationSnapshot
+			if err := json.Unmarshal(data, &snapshot); err != nil {
+				t.Fatalf("decode snapshot: %v", err)
+			}
+			for _, manifest := range snapshot.Manifests {
+				t.Run(manifest.Name, func(t *testing.T) {
+					t.Parallel()
+					checkManifest(t, snapshot, manifest)
+				})
+			}
+		})
+	}
+}
+
+// checkManifest resolves one manifest and compares the outcome with the
+// recorded expectations.
+func checkManifest(t *testing.T, snapshot integrationSnapshot, manifest integrationExpects) {
+	problem := &Problem{Root: manifest.Root, Packages: snapshot.Packages}
+	root, source, err := problem.Sources(nil)
+	if err != nil {
+		t.Fatalf("load manifest: %v", err)
+	}
+
+	solver := NewSolver(root, source)
+	solution, err := solver.Solve(root.Term())
+	if steps := solver.Stats().Steps; manifest.MaxSteps > 0 && steps > manifest.MaxSteps {
+		t.Errorf("solve took %d steps, recorded maximum is %d", steps, manifest.MaxSteps)
+	}
+	if manifest.Unsolvable {
+		if !errors.Is(err, ErrNoSolution) {
+			t.Fatalf("expected no solution, got %v (err %v)", solution, err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rootName := root.Term().Name
+	resolved := 0
+	for nv := range solution.All() {
+		if nv.Name == rootName {
+			continue
+		}
+		resolved++
+		want, ok := manifest.Solution[nv.Name.Value()]
+		if !ok {
+			t.Errorf("unexpected package %s", nv)
+			continue
+		}
+		if got := nv.Version.String(); got != want {
+			t.Errorf("%s resolved to %s, recorded %s", FormatName(nv.Name), got, want)
+		}
+	}
+	if resolved != len(manifest.Solution) {
+		t.Errorf("resolved %d packages, recorded %d", resolved, len(manifest.Solution))
+	}
+}
+
+// downloadSnapshots fetches snapshot files into a temporary directory.
+func downloadSnapshots(t *testing.T, urls []string) []string {
+	t.Helper()
+	dir := t.TempDir()
+	var files []string
+	for i, url := range urls {
+		url = strings.TrimSpace(url)
+		resp, err := http.Get(url)
+		if err != nil {
+			t.Fatalf("download %s: %v", url, err)
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil || resp.StatusCode != http.StatusOK {
+			t.Fatalf("download %s: status %s, %v", url, resp.Status, err)
+		}
+		name := filepath.Join(dir, fmt.Sprintf("%d-%s", i, path.Base(url)))
+		if err := os.WriteFile(name, data, 0o644); err != nil {
+			t.Fatalf("save %s: %v", url, err)
+		}
+		files = append(files, name)
+	}
+	return files
+}
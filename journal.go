@@ -0,0 +1,56 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import "time"
+
+// DependencyFetch records a GetDependencies call the solver made on its
+// source. The solver calls GetDependencies at most once per package version
+// per solve, whether or not the source is a CachedSource, so the journal
+// lists each pair once, in the order they were first needed.
+type DependencyFetch struct {
+	Package Name
+	Version Version
+	// Err is the error the source returned, if any. Errors are remembered
+	// like results and not retried within the solve.
+	Err error
+}
+
+// fetchedDependencies is a memoized GetDependencies answer.
+type fetchedDependencies struct {
+	deps []Term
+	err  error
+}
+
+// fetchDependencies queries the source for the dependencies of
+// name@version, at most once per solve. Callers must not modify the
+// returned slice.
+func (st *solverState) fetchDependencies(name Name, version Version) ([]Term, error) {
+	key := dependencyScoreKey(name, version)
+	if fetched, ok := st.fetched[key]; ok {
+		st.fetchHits++
+		return fetched.deps, fetched.err
+	}
+
+	start := time.Now()
+	deps, err := getDependenciesCtx(st.ctx, st.source, name, version)
+	st.clock.sourceCall(name, start)
+	if st.fetched == nil {
+		st.fetched = make(map[string]fetchedDependencies)
+	}
+	st.fetched[key] = fetchedDependencies{deps: deps, err: err}
+	st.journal = append(st.journal, DependencyFetch{Package: name, Version: version, Err: err})
+	return deps, err
+}
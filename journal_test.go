@@ -0,0 +1,51 @@
+package pubgrub
+
+import (
+	"context"
+	"testing"
+)
+
+// fetchCountingSource records how often each package version's dependencies
+// are fetched.
+type fetchCountingSource struct {
+	Source
+	calls map[string]int
+}
+
+func (s *fetchCountingSource) GetDependencies(name Name, version Version) ([]Term, error) {
+	s.calls[dependencyScoreKey(name, version)]++
+	return s.Source.GetDependencies(name, version)
+}
+
+func TestDependenciesFetchedOncePerSolve(t *testing.T) {
+	root, inner := conflictingWideWorkload(t, 6)
+	source := &fetchCountingSource{Source: inner, calls: make(map[string]int)}
+
+	solver := NewSolverWithOptions([]Source{root, source}, WithVersionBucketing(true))
+	result, err := solver.SolveResult(context.Background(), root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for key, n := range source.calls {
+		if n != 1 {
+			t.Fatalf("dependencies of %s fetched %d times", key, n)
+		}
+	}
+	if result.Stats.DependencyFetchHits == 0 {
+		t.Fatalf("expected lookahead and decisions to share fetches, stats %+v", result.Stats)
+	}
+
+	// The journal also covers the root, which the counting source wraps.
+	if len(result.Fetches) != len(source.calls)+1 || result.Stats.DependencyFetches != len(result.Fetches) {
+		t.Fatalf("journal has %d entries, source saw %d pairs, stats %+v", len(result.Fetches), len(source.calls), result.Stats)
+	}
+	seen := make(map[string]bool)
+	for _, fetch := range result.Fetches {
+		key := dependencyScoreKey(fetch.Package, fetch.Version)
+		if seen[key] {
+			t.Fatalf("%s journaled twice", key)
+		}
+		seen[key] = true
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// frozenIncompatibilities pins every frozen package to its locked version.
+// Like policy constraints, the pins forbid other versions without requiring
+// the package.
+func frozenIncompatibilities(options SolverOptions) []*Incompatibility {
+	var incs []*Incompatibility
+	for _, name := range options.Frozen {
+		version, ok := options.Locked.GetVersion(name)
+		if !ok {
+			continue
+		}
+		forbidden := EmptyVersionSet().Singleton(version).Complement()
+		incs = append(incs, NewIncompatibilityPolicy(
+			NewTerm(name, NewVersionSetCondition(forbidden)),
+			fmt.Sprintf("frozen at %s by the lockfile", version),
+		))
+	}
+	return incs
+}
+
+// FrozenConflictError is returned instead of a plain no-solution error when
+// packages frozen with WithFrozen take part in the failure, which usually
+// means the lockfile has gone stale. It names the frozen pins involved and
+// the declared requirements they contradict; Err keeps the full derivation.
+//
+// Example:
+//
+//	var frozen *FrozenConflictError
+//	if errors.As(err, &frozen) {
+//	    for _, pin := range frozen.Pins {
+//	        log.Printf("unfreeze %s to resolve", pin.Name.Value())
+//	    }
+//	}
+type FrozenConflictError struct {
+	// Pins lists the frozen packages in the failure at their locked versions.
+	Pins []NameVersion
+	// Requirements lists the declared requirements excluding a locked version.
+	Requirements []FrozenRequirement
+	// Err is the underlying no-solution error.
+	Err error
+}
+
+// FrozenRequirement is a declared requirement that excludes the locked
+// version of a frozen package.
+type FrozenRequirement struct {
+	// Dependent declared the requirement; it is the root package for root
+	// requirements.
+	Dependent Name
+	// Version is the version of Dependent declaring the requirement, nil
+	// when it holds for a range of versions.
+	Version     Version
+	Requirement Term
+}
+
+// Error implements the error interface
+func (e *FrozenConflictError) Error() string {
+	var b strings.Builder
+	b.WriteString("frozen packages conflict with the requirements:")
+	for _, pin := range e.Pins {
+		fmt.Fprintf(&b, "\n  %s is frozen at %s by the lockfile", FormatName(pin.Name), pin.Version)
+		for _, req := range e.Requirements {
+			if req.Requirement.Name != pin.Name {
+				continue
+			}
+			fmt.Fprintf(&b, "\n    excluded by %s", req.describe())
+		}
+	}
+	return b.String()
+}
+
+// Unwrap returns the underlying no-solution error.
+func (e *FrozenConflictError) Unwrap() error {
+	return e.Err
+}
+
+// describe renders who declared the requirement.
+func (r FrozenRequirement) describe() string {
+	switch {
+	case r.Dependent == MakeName("$$root"):
+		return "the root requirement " + r.Requirement.String()
+	case r.Version != nil:
+		return fmt.Sprintf("%s %s's requirement %s", FormatName(r.Dependent), r.Version, r.Requirement)
+	default:
+		return fmt.Sprintf("%s's requirement %s", FormatName(r.Dependent), r.Requirement)
+	}
+}
+
+// frozenConflict wraps err in a FrozenConflictError when the derivation of
+// incomp rests on frozen pins, and returns err unchanged otherwise.
+func (st *solverState) frozenConflict(incomp *Incompatibility, err error) error {
+	if len(st.frozen) == 0 || incomp == nil {
+		return err
+	}
+
+	var leaves []*Incompatibility
+	seen := make(map[*Incompatibility]bool)
+	var walk func(*Incompatibility)
+	walk = func(inc *Incompatibility) {
+		if inc == nil || seen[inc] {
+			return
+		}
+		seen[inc] = true
+		if inc.Cause1 == nil && inc.Cause2 == nil {
+			leaves = append(leaves, inc)
+			return
+		}
+		walk(inc.Cause1)
+		walk(inc.Cause2)
+	}
+	walk(incomp)
+
+	pinned := make(map[Name]Version)
+	conflict := &FrozenConflictError{Err: err}
+	for _, leaf := range leaves {
+		if !st.frozen[leaf] {
+			continue
+		}
+		version, _ := st.options.Locked.GetVersion(leaf.Package)
+		pinned[leaf.Package] = version
+		conflict.Pins = append(conflict.Pins, NameVersion{Name: leaf.Package, Version: version})
+	}
+	if len(conflict.Pins) == 0 {
+		return err
+	}
+	slices.SortFunc(conflict.Pins, func(a, b NameVersion) int { return strings.Compare(a.Name.Value(), b.Name.Value()) })
+
+	for _, leaf := range leaves {
+		if leaf.Kind != KindFromDependency {
+			continue
+		}
+		for _, term := range leaf.Terms {
+			version, ok := pinned[term.Name]
+			if !ok || term.Name == leaf.Package || term.Positive {
+				continue
+			}
+			requirement := term.Negate()
+			if requirement.SatisfiedBy(version) {
+				continue
+			}
+			conflict.Requirements = append(conflict.Requirements, FrozenRequirement{
+				Dependent:   leaf.Package,
+				Version:     leaf.Version,
+				Requirement: requirement,
+			})
+		}
+	}
+	return conflict
+}
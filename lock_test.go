@@ -0,0 +1,146 @@
+package pubgrub
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFrozenPackagesKeepLockedVersions(t *testing.T) {
+	source := &InMemorySource{}
+	for _, pkg := range []string{"rails", "rack"} {
+		source.AddPackage(MakeName(pkg), SimpleVersion("1.0.0"), nil)
+		source.AddPackage(MakeName(pkg), SimpleVersion("2.0.0"), nil)
+	}
+	root := NewRootSource()
+	root.AddPackage(MakeName("rails"), NewVersionSetCondition(FullVersionSet()))
+	root.AddPackage(MakeName("rack"), NewVersionSetCondition(FullVersionSet()))
+
+	locked := Solution{
+		{Name: MakeName("rails"), Version: SimpleVersion("1.0.0")},
+		{Name: MakeName("rack"), Version: SimpleVersion("1.0.0")},
+	}
+
+	solver := NewSolverWithOptions([]Source{root, source},
+		WithLockfile(locked),
+		WithFrozen(MakeName("rails"), MakeName("unlocked")),
+	)
+	solution, err := solver.Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, _ := solution.GetVersion(MakeName("rails")); ver == nil || ver.String() != "1.0.0" {
+		t.Fatalf("expected frozen rails 1.0.0, got %v", ver)
+	}
+	if ver, _ := solution.GetVersion(MakeName("rack")); ver == nil || ver.String() != "2.0.0" {
+		t.Fatalf("expected unfrozen rack to update to 2.0.0, got %v", ver)
+	}
+}
+
+func TestFrozenPackageConflictNamesLock(t *testing.T) {
+	source := &InMemorySource{}
+	for _, pkg := range []string{"rails", "rack"} {
+		source.AddPackage(MakeName(pkg), SimpleVersion("1.0.0"), nil)
+		source.AddPackage(MakeName(pkg), SimpleVersion("2.0.0"), nil)
+	}
+	root := NewRootSource()
+	root.AddPackage(MakeName("rails"), NewVersionSetCondition(FullVersionSet()))
+	root.AddPackage(MakeName("rack"), NewVersionSetCondition(FullVersionSet()))
+
+	locked := Solution{
+		{Name: MakeName("rails"), Version: SimpleVersion("1.0.0")},
+		{Name: MakeName("rack"), Version: SimpleVersion("1.0.0")},
+	}
+	root.AddPackage(MakeName("rails"), NewVersionSetCondition(mustParseVersionRange(t, ">=2.0.0")))
+
+	solver := NewSolverWithOptions([]Source{root, source},
+		WithLockfile(locked),
+		WithFrozen(MakeName("rails")),
+		WithIncompatibilityTracking(true),
+	)
+	_, err := solver.Solve(root.Term())
+	if err == nil {
+		t.Fatalf("expected the frozen pin to conflict with the requirement")
+	}
+	if !strings.Contains(err.Error(), "frozen at 1.0.0 by the lockfile") {
+		t.Fatalf("expected the lockfile pin in the report, got:\n%v", err)
+	}
+}
+
+func TestFrozenConflictError(t *testing.T) {
+	source := &InMemorySource{}
+	for _, pkg := range []string{"rails", "rack"} {
+		source.AddPackage(MakeName(pkg), SimpleVersion("1.0.0"), nil)
+		source.AddPackage(MakeName(pkg), SimpleVersion("2.0.0"), nil)
+	}
+	root := NewRootSource()
+	root.AddPackage(MakeName("rails"), NewVersionSetCondition(FullVersionSet()))
+	root.AddPackage(MakeName("rack"), NewVersionSetCondition(FullVersionSet()))
+
+	locked := Solution{
+		{Name: MakeName("rails"), Version: SimpleVersion("1.0.0")},
+		{Name: MakeName("rack"), Version: SimpleVersion("1.0.0")},
+	}
+	// rails 1.0.0 is frozen but the app now needs a newer one, and the frozen
+	// rack is too new for the locked rails.
+	root.AddPackage(MakeName("rails"), NewVersionSetCondition(mustParseVersionRange(t, ">=2.0.0")))
+	locked[1].Version = SimpleVersion("2.0.0")
+
+	for _, tracking := range []bool{true, false} {
+		solver := NewSolverWithOptions([]Source{root, source},
+			WithLockfile(locked),
+			WithFrozen(MakeName("rails"), MakeName("rack")),
+			WithIncompatibilityTracking(tracking),
+		)
+		_, err := solver.Solve(root.Term())
+
+		var frozen *FrozenConflictError
+		if !errors.As(err, &frozen) {
+			t.Fatalf("expected FrozenConflictError, got %T: %v", err, err)
+		}
+		if !errors.Is(err, ErrNoSolution) {
+			t.Fatalf("expected the no-solution error to stay reachable")
+		}
+		if len(frozen.Pins) != 1 || frozen.Pins[0].Name != MakeName("rails") {
+			t.Fatalf("expected only the rails pin to be blamed, got %v", frozen.Pins)
+		}
+		if len(frozen.Requirements) != 1 || frozen.Requirements[0].Dependent != MakeName("$$root") {
+			t.Fatalf("expected the root requirement to be named, got %+v", frozen.Requirements)
+		}
+		want := "rails is frozen at 1.0.0 by the lockfile\n    excluded by the root requirement rails >=2.0.0"
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in:\n%v", want, err)
+		}
+	}
+}
+
+func TestFrozenPinsConflictingWithEachOther(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("rails"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("rack"), NewVersionSetCondition(mustParseVersionRange(t, "<2.0.0"))),
+	})
+	source.AddPackage(MakeName("rack"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("rack"), SimpleVersion("2.0.0"), nil)
+	root := NewRootSource()
+	root.AddPackage(MakeName("rails"), NewVersionSetCondition(FullVersionSet()))
+
+	locked := Solution{
+		{Name: MakeName("rails"), Version: SimpleVersion("1.0.0")},
+		{Name: MakeName("rack"), Version: SimpleVersion("2.0.0")},
+	}
+	_, err := NewSolverWithOptions([]Source{root, source},
+		WithLockfile(locked),
+		WithFrozen(MakeName("rails"), MakeName("rack")),
+	).Solve(root.Term())
+
+	var frozen *FrozenConflictError
+	if !errors.As(err, &frozen) {
+		t.Fatalf("expected FrozenConflictError, got %T: %v", err, err)
+	}
+	if len(frozen.Requirements) != 1 || frozen.Requirements[0].Dependent != MakeName("rails") {
+		t.Fatalf("expected rails' requirement on rack to be named, got %+v", frozen.Requirements)
+	}
+	if !strings.Contains(err.Error(), "excluded by rails 1.0.0's requirement rack <2.0.0") {
+		t.Fatalf("unexpected message:\n%v", err)
+	}
+}
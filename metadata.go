@@ -0,0 +1,55 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package pubgrub
+
+// MetadataProvider is implemented by sources that know more about a
+// version than its dependencies, such as its publish date, size or download
+// URL. The solver copies the metadata of every resolved version into
+// NameVersion.Metadata, so installers need no second pass over the
+// registry after resolution. The metadata is opaque to the solver.
+// CachedSource, CombinedSource and MirrorSource pass metadata through from
+// the source that supplied the version.
+//
+// Example:
+//
+//	type Artifact struct {
+//	    URL       string
+//	    Size      int64
+//	    Published time.Time
+//	}
+//
+//	func (r *Registry) VersionMetadata(name Name, version Version) any {
+//	    return r.artifacts[name][version.String()] // an Artifact
+//	}
+//
+//	for _, nv := range solution {
+//	    if artifact, ok := nv.Metadata.(Artifact); ok {
+//	        download(artifact.URL)
+//	    }
+//	}
+type MetadataProvider interface {
+	Source
+	// VersionMetadata returns the metadata of version, or nil if there is
+	// none.
+	VersionMetadata(name Name, version Version) any
+}
+
+// versionMetadata asks source for the metadata of version, returning nil
+// when it provides none.
+func versionMetadata(source Source, name Name, version Version) any {
+	if provider, ok := source.(MetadataProvider); ok {
+		return provider.VersionMetadata(name, version)
+	}
+	return nil
+}
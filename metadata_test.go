@@ -0,0 +1,57 @@
+package pubgrub
+
+import "testing"
+
+// artifactSource attaches a download URL to every version it publishes.
+type artifactSource struct {
+	*InMemorySource
+	host string
+}
+
+func (s artifactSource) VersionMetadata(name Name, version Version) any {
+	return s.host + "/" + name.Value() + "-" + version.String() + ".gem"
+}
+
+func TestSolutionCarriesVersionMetadata(t *testing.T) {
+	public := &InMemorySource{}
+	public.AddPackage(MakeName("rails"), SimpleVersion("7.0.0"), []Term{
+		NewTerm(MakeName("rack"), NewVersionSetCondition(FullVersionSet())),
+	})
+	private := &InMemorySource{}
+	private.AddPackage(MakeName("rack"), SimpleVersion("3.0.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("rails"), NewVersionSetCondition(FullVersionSet()))
+	solver := NewSolver(root, artifactSource{public, "https://rubygems.org"}, artifactSource{private, "https://gems.internal"})
+	solution, err := solver.Solve(root.Term())
+	if err != nil {
+		t.Fatalf("solve: %v", err)
+	}
+
+	want := map[string]any{
+		"$$root": nil,
+		"rails":  "https://rubygems.org/rails-7.0.0.gem",
+		"rack":   "https://gems.internal/rack-3.0.0.gem",
+	}
+	for _, nv := range solution {
+		if nv.Metadata != want[nv.Name.Value()] {
+			t.Fatalf("%s: expected metadata %v, got %v", nv, want[nv.Name.Value()], nv.Metadata)
+		}
+	}
+}
+
+func TestMirrorSourcePassesMetadataThrough(t *testing.T) {
+	registry := &InMemorySource{}
+	registry.AddPackage(MakeName("rack"), SimpleVersion("3.0.0"), nil)
+	primary := &flakySource{Source: artifactSource{registry, "https://primary"}, down: true}
+	fallback := artifactSource{registry, "https://fallback"}
+	mirrors := NewMirrorSource(Mirror{Name: "primary", Source: primary}, Mirror{Name: "fallback", Source: fallback})
+
+	if _, err := mirrors.GetVersions(MakeName("rack")); err != nil {
+		t.Fatalf("get versions: %v", err)
+	}
+	got := mirrors.VersionMetadata(MakeName("rack"), SimpleVersion("3.0.0"))
+	if got != "https://fallback/rack-3.0.0.gem" {
+		t.Fatalf("expected the serving mirror's metadata, got %v", got)
+	}
+}
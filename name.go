@@ -15,7 +15,11 @@
 
 package pubgrub
 
-import "unique"
+import (
+	"slices"
+	"strings"
+	"unique"
+)
 
 // Name represents a package name using value interning for memory efficiency.
 // Multiple instances of the same package name share the same underlying memory.
@@ -43,3 +47,83 @@ func MakeName(s string) Name {
 func EmptyName() Name {
 	return unique.Make("")
 }
+
+// qualifiedNameSeparator joins the registry and package of a qualified name.
+// Package names never contain it, so qualified names cannot collide with
+// bare ones.
+const qualifiedNameSeparator = "\x1f"
+
+// MakeQualifiedName creates a Name for a package of the given registry, so
+// adapters mixing ecosystems can keep "lodash" from npm apart from "lodash"
+// from a private mirror. An empty registry gives the bare name. Errors,
+// reporters and logs render qualified names as "registry:name".
+//
+// Example:
+//
+//	npm := MakeQualifiedName("npm", "lodash")
+//	internal := MakeQualifiedName("internal", "lodash")
+//	// npm != internal
+func MakeQualifiedName(registry, name string) Name {
+	if registry == "" {
+		return MakeName(name)
+	}
+	return MakeName(registry + qualifiedNameSeparator + name)
+}
+
+// SplitQualifiedName returns the registry and package of a name made by
+// MakeQualifiedName. The registry is empty for bare names.
+func SplitQualifiedName(name Name) (registry, pkg string) {
+	registry, pkg, ok := strings.Cut(name.Value(), qualifiedNameSeparator)
+	if !ok {
+		return "", name.Value()
+	}
+	return registry, pkg
+}
+
+// FormatName renders name for people: bare names as written and qualified
+// names as "registry:name".
+func FormatName(name Name) string {
+	registry, pkg := SplitQualifiedName(name)
+	if registry == "" {
+		return pkg
+	}
+	return registry + ":" + pkg
+}
+
+// NameCollisions reports package names that appear under more than one
+// registry among names, counting bare names as a registry of their own. The
+// result maps each colliding package name to its distinct Names, sorted; it
+// is nil when there are no collisions. Run it over a solution to catch
+// adapters that resolved the same package from two ecosystems.
+//
+// Example:
+//
+//	var names []Name
+//	for nv := range solution.All() {
+//	    names = append(names, nv.Name)
+//	}
+//	for pkg, group := range NameCollisions(names) {
+//	    log.Printf("%s resolved from %d registries", pkg, len(group))
+//	}
+func NameCollisions(names []Name) map[string][]Name {
+	byPackage := make(map[string][]Name)
+	for _, name := range names {
+		_, pkg := SplitQualifiedName(name)
+		if !slices.Contains(byPackage[pkg], name) {
+			byPackage[pkg] = append(byPackage[pkg], name)
+		}
+	}
+
+	var collisions map[string][]Name
+	for pkg, group := range byPackage {
+		if len(group) < 2 {
+			continue
+		}
+		slices.SortFunc(group, func(a, b Name) int { return strings.Compare(a.Value(), b.Value()) })
+		if collisions == nil {
+			collisions = make(map[string][]Name)
+		}
+		collisions[pkg] = group
+	}
+	return collisions
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package pubgrub
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNameLimit is returned when a NameScope would exceed its limits.
+var ErrNameLimit = errors.New("name limit exceeded")
+
+// NameStats accounts for interned package names.
+type NameStats struct {
+	// Names is the number of distinct names.
+	Names int
+	// Bytes is the total length of their strings.
+	Bytes int
+}
+
+// NameScope interns and accounts for the package names of one unit of work,
+// such as resolving one untrusted manifest. Names are unique.Handles, which
+// the runtime reclaims once nothing refers to them, so what grows without
+// bound in a long-running service is the set of names kept reachable by
+// per-request state. A scope makes that set visible with Stats, caps it
+// with MaxNames and MaxBytes so a hostile manifest cannot make a resolve
+// hold millions of bogus names, and drops its own references on Release.
+// It is safe for concurrent use.
+//
+// Example:
+//
+//	scope := &NameScope{MaxNames: 50_000}
+//	defer scope.Release()
+//	solver := NewSolverWithOptions([]Source{root, registry}, WithNameScope(scope))
+//	_, err := solver.Solve(root.Term())
+//	if errors.Is(err, ErrNameLimit) {
+//	    return fmt.Errorf("manifest references too many packages: %w", err)
+//	}
+type NameScope struct {
+	// MaxNames and MaxBytes bound the distinct names the scope admits and
+	// their total length. Zero means no limit.
+	MaxNames int
+	MaxBytes int
+
+	mu    sync.Mutex
+	names map[Name]struct{}
+	bytes int
+}
+
+// NewNameScope returns a NameScope without limits.
+func NewNameScope() *NameScope {
+	return &NameScope{}
+}
+
+// Make interns str like MakeName, admitting it to the scope.
+func (s *NameScope) Make(str string) (Name, error) {
+	name := MakeName(str)
+	return name, s.Admit(name)
+}
+
+// MakeQualified interns a qualified name like MakeQualifiedName, admitting
+// it to the scope.
+func (s *NameScope) MakeQualified(registry, name string) (Name, error) {
+	qualified := MakeQualifiedName(registry, name)
+	return qualified, s.Admit(qualified)
+}
+
+// Admit adds name to the scope. It returns an error wrapping ErrNameLimit,
+// and leaves the scope unchanged, if name is new and would exceed a limit.
+func (s *NameScope) Admit(name Name) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.names[name]; ok {
+		return nil
+	}
+	size := len(name.Value())
+	if s.MaxNames > 0 && len(s.names)+1 > s.MaxNames {
+		return fmt.Errorf("%w: %s would be name %d of at most %d", ErrNameLimit, FormatName(name), len(s.names)+1, s.MaxNames)
+	}
+	if s.MaxBytes > 0 && s.bytes+size > s.MaxBytes {
+		return fmt.Errorf("%w: %s would bring names to %d of at most %d bytes", ErrNameLimit, FormatName(name), s.bytes+size, s.MaxBytes)
+	}
+	if s.names == nil {
+		s.names = make(map[Name]struct{})
+	}
+	s.names[name] = struct{}{}
+	s.bytes += size
+	return nil
+}
+
+// Stats returns the names admitted so far.
+func (s *NameScope) Stats() NameStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return NameStats{Names: len(s.names), Bytes: s.bytes}
+}
+
+// Release forgets every admitted name, so names no longer referenced
+// elsewhere can be reclaimed, and resets the statistics. The scope can be
+// reused afterwards.
+func (s *NameScope) Release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.names = nil
+	s.bytes = 0
+}
+
+// admitDependencies admits the names of deps to the configured NameScope.
+func (st *solverState) admitDependencies(deps []Term) error {
+	scope := st.options.NameScope
+	if scope == nil {
+		return nil
+	}
+	for _, dep := range deps {
+		if err := scope.Admit(dep.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nameStats accounts for the names the solve holds clauses about.
+func (st *solverState) nameStats() NameStats {
+	var stats NameStats
+	for name := range st.incompatibilities {
+		stats.Names++
+		stats.Bytes += len(name.Value())
+	}
+	return stats
+}
@@ -0,0 +1,73 @@
+package pubgrub
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestNameScopeAccountsAndLimits(t *testing.T) {
+	scope := &NameScope{MaxNames: 2}
+	if _, err := scope.Make("rails"); err != nil {
+		t.Fatalf("make: %v", err)
+	}
+	if _, err := scope.Make("rails"); err != nil {
+		t.Fatalf("re-admitting a name should be free: %v", err)
+	}
+	if _, err := scope.MakeQualified("npm", "rack"); err != nil {
+		t.Fatalf("make qualified: %v", err)
+	}
+	if _, err := scope.Make("puma"); !errors.Is(err, ErrNameLimit) {
+		t.Fatalf("expected ErrNameLimit, got %v", err)
+	}
+	want := NameStats{Names: 2, Bytes: len("rails") + len(MakeQualifiedName("npm", "rack").Value())}
+	if got := scope.Stats(); got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	scope.Release()
+	if got := scope.Stats(); got != (NameStats{}) {
+		t.Fatalf("expected an empty scope after Release, got %+v", got)
+	}
+	if _, err := scope.Make("puma"); err != nil {
+		t.Fatalf("a released scope should admit names again: %v", err)
+	}
+}
+
+func TestNameScopeByteLimit(t *testing.T) {
+	scope := &NameScope{MaxBytes: 8}
+	if _, err := scope.Make("sinatra"); err != nil {
+		t.Fatalf("make: %v", err)
+	}
+	if _, err := scope.Make("rack"); !errors.Is(err, ErrNameLimit) {
+		t.Fatalf("expected ErrNameLimit, got %v", err)
+	}
+}
+
+func TestWithNameScopeBoundsSolve(t *testing.T) {
+	source := &InMemorySource{}
+	var deps []Term
+	for i := range 20 {
+		name := MakeName(fmt.Sprintf("bogus-%d", i))
+		source.AddPackage(name, SimpleVersion("1.0.0"), nil)
+		deps = append(deps, NewTerm(name, NewVersionSetCondition(FullVersionSet())))
+	}
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), deps)
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), NewVersionSetCondition(FullVersionSet()))
+
+	solver := NewSolver(root, source)
+	if _, err := solver.Solve(root.Term()); err != nil {
+		t.Fatalf("solve: %v", err)
+	}
+	if got := solver.Stats().Names.Names; got != 22 {
+		t.Fatalf("expected 22 names including the root and app, got %d", got)
+	}
+
+	scope := &NameScope{MaxNames: 10}
+	_, err := solver.Solve(root.Term(), WithNameScope(scope))
+	var depErr *DependencyError
+	if !errors.Is(err, ErrNameLimit) || !errors.As(err, &depErr) || depErr.Package != MakeName("app") {
+		t.Fatalf("expected app's dependencies to exceed the scope, got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package pubgrub
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestQualifiedNamesStayDistinct(t *testing.T) {
+	npm := MakeQualifiedName("npm", "lodash")
+	mirror := MakeQualifiedName("mirror", "lodash")
+	bare := MakeName("lodash")
+	if npm == mirror || npm == bare || MakeQualifiedName("", "lodash") != bare {
+		t.Fatalf("expected registries to keep names apart")
+	}
+	if registry, pkg := SplitQualifiedName(npm); registry != "npm" || pkg != "lodash" {
+		t.Fatalf("unexpected split %q %q", registry, pkg)
+	}
+	if got := FormatName(npm); got != "npm:lodash" {
+		t.Fatalf("unexpected format %q", got)
+	}
+	if got := FormatName(MakeName("org.apache:commons")); got != "org.apache:commons" {
+		t.Fatalf("expected bare names unchanged, got %q", got)
+	}
+
+	collisions := NameCollisions([]Name{npm, bare, mirror, npm, MakeName("react")})
+	if len(collisions) != 1 || len(collisions["lodash"]) != 3 {
+		t.Fatalf("expected one collision over three names, got %v", collisions)
+	}
+	if NameCollisions([]Name{npm, MakeName("react")}) != nil {
+		t.Fatalf("expected no collisions")
+	}
+}
+
+func TestQualifiedNamesInErrors(t *testing.T) {
+	lodash := MakeQualifiedName("npm", "lodash")
+	source := &InMemorySource{}
+	source.AddPackage(lodash, SimpleVersion("1.0.0"), nil)
+	root := NewRootSource()
+	root.AddPackage(lodash, EqualsCondition{Version: SimpleVersion("2.0.0")})
+
+	solver := NewSolverWithOptions([]Source{root, source}, WithIncompatibilityTracking(true))
+	_, err := solver.Solve(root.Term())
+	if err == nil {
+		t.Fatalf("expected no solution")
+	}
+	if !strings.Contains(err.Error(), "npm:lodash") || strings.Contains(err.Error(), qualifiedNameSeparator) {
+		t.Fatalf("expected the qualified name in display form, got:\n%v", err)
+	}
+}
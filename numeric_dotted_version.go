@@ -0,0 +1,143 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NumericDottedVersion is a version made of any number of dot-separated
+// numeric segments, such as "3.4.14" or "2024.1.0.7", compared segment by
+// segment as numbers. It avoids the lexicographic surprises of SimpleVersion,
+// where "3.4.9" sorts after "3.4.14". Missing trailing segments count as
+// zero and leading zeros are ignored, so "1.2" and "1.2.0" sort equal.
+//
+// Against a SemanticVersion the segments are compared with major, minor and
+// patch, and a prerelease sorts before the matching NumericDottedVersion.
+// Against a GemVersion the RubyGems rules apply. Other Version types are
+// compared as strings.
+//
+// Example:
+//
+//	v, err := ParseNumericDottedVersion("3.4.14")
+//	fmt.Println(v.Sort(NumericDottedVersion("3.4.9")) > 0) // true
+type NumericDottedVersion string
+
+// ParseNumericDottedVersion validates s as dot-separated decimal segments.
+func ParseNumericDottedVersion(s string) (NumericDottedVersion, error) {
+	if s == "" {
+		return "", fmt.Errorf("invalid numeric dotted version: empty")
+	}
+	for segment := range strings.SplitSeq(s, ".") {
+		if segment == "" || strings.TrimLeft(segment, "0123456789") != "" {
+			return "", fmt.Errorf("invalid numeric dotted version: %s", s)
+		}
+	}
+	return NumericDottedVersion(s), nil
+}
+
+// ParseDottedVersion parses s as a NumericDottedVersion when it is one and
+// falls back to SimpleVersion for opaque strings such as "latest" or
+// "r1234-beta". It suits sources that receive versions as plain strings.
+//
+// Example:
+//
+//	source.AddPackage(MakeName("lib"), ParseDottedVersion(raw), deps)
+func ParseDottedVersion(s string) Version {
+	if v, err := ParseNumericDottedVersion(s); err == nil {
+		return v
+	}
+	return SimpleVersion(s)
+}
+
+// Sort implements Version.
+func (v NumericDottedVersion) Sort(other Version) int {
+	switch o := other.(type) {
+	case NumericDottedVersion:
+		return compareDotted(string(v), string(o))
+	case *SemanticVersion:
+		if o == nil {
+			return strings.Compare(string(v), "")
+		}
+		return v.compareSemantic(o)
+	case *GemVersion:
+		return -o.Sort(v)
+	default:
+		return strings.Compare(string(v), other.String())
+	}
+}
+
+// String returns the version as written.
+func (v NumericDottedVersion) String() string {
+	return string(v)
+}
+
+// compareSemantic compares v with the major, minor and patch of sv, ranking
+// a prerelease of equal numbers below v.
+func (v NumericDottedVersion) compareSemantic(sv *SemanticVersion) int {
+	core := [3]int{sv.Major, sv.Minor, sv.Patch}
+	rest := string(v)
+	for i := 0; rest != "" || i < len(core); i++ {
+		var segment string
+		segment, rest, _ = strings.Cut(rest, ".")
+		if segment == "" {
+			segment = "0"
+		}
+		other := "0"
+		if i < len(core) {
+			other = strconv.Itoa(core[i])
+		}
+		if c := compareSegment(segment, other); c != 0 {
+			return c
+		}
+	}
+	if sv.Prerelease != "" {
+		return 1
+	}
+	return 0
+}
+
+// compareDotted compares two dotted numeric strings segment by segment.
+func compareDotted(a, b string) int {
+	for a != "" || b != "" {
+		var sa, sb string
+		sa, a, _ = strings.Cut(a, ".")
+		sb, b, _ = strings.Cut(b, ".")
+		if c := compareSegment(sa, sb); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// compareSegment compares two decimal strings numerically without parsing,
+// so segments of any length compare correctly. Empty segments count as zero.
+func compareSegment(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+var (
+	_ Version = NumericDottedVersion("")
+)
@@ -0,0 +1,82 @@
+package pubgrub
+
+import "testing"
+
+func TestNumericDottedVersionOrdering(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"3.4.14", "3.4.9", 1},
+		{"3.4.9", "3.4.14", -1},
+		{"1.2", "1.2.0", 0},
+		{"1.02.3", "1.2.3", 0},
+		{"2024.1.0.7", "2024.1.0.10", -1},
+		{"1.0.0.1", "1.0.0", 1},
+		{"99999999999999999999", "9", 1},
+	}
+	for _, tc := range cases {
+		a, err := ParseNumericDottedVersion(tc.a)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tc.a, err)
+		}
+		if got := a.Sort(NumericDottedVersion(tc.b)); got != tc.want {
+			t.Fatalf("%s vs %s: got %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestNumericDottedVersionAgainstSemantic(t *testing.T) {
+	cases := []struct {
+		dotted, semantic string
+		want             int
+	}{
+		{"1.2.10", "1.2.9", 1},
+		{"1.2.3", "1.2.3", 0},
+		{"1.2", "1.2.0", 0},
+		{"1.2.3.1", "1.2.3", 1},
+		{"1.2.3", "1.2.3-beta", 1},
+		{"1.2.2", "1.2.3-beta", -1},
+	}
+	for _, tc := range cases {
+		sv, err := ParseSemanticVersion(tc.semantic)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tc.semantic, err)
+		}
+		v := NumericDottedVersion(tc.dotted)
+		if got := v.Sort(sv); got != tc.want {
+			t.Fatalf("%s vs %s: got %d, want %d", tc.dotted, tc.semantic, got, tc.want)
+		}
+		if got := sv.Sort(v); got != -tc.want {
+			t.Fatalf("%s vs %s: got %d, want %d", tc.semantic, tc.dotted, got, -tc.want)
+		}
+	}
+}
+
+func TestParseDottedVersionFallsBackForOpaqueStrings(t *testing.T) {
+	for _, raw := range []string{"", "latest", "1.2-beta", "1..2", ".1"} {
+		if _, ok := ParseDottedVersion(raw).(SimpleVersion); !ok {
+			t.Fatalf("expected %q to fall back to SimpleVersion", raw)
+		}
+	}
+	if _, ok := ParseDottedVersion("3.4.14").(NumericDottedVersion); !ok {
+		t.Fatalf("expected 3.4.14 to parse as NumericDottedVersion")
+	}
+}
+
+func TestDottedVersionsResolveNumerically(t *testing.T) {
+	source := &InMemorySource{}
+	for _, raw := range []string{"3.4.9", "3.4.14", "3.4.2.1"} {
+		source.AddPackage(MakeName("lib"), ParseDottedVersion(raw), nil)
+	}
+	root := NewRootSource()
+	root.AddPackage(MakeName("lib"), NewVersionSetCondition(mustParseVersionRange(t, ">=3.4.0.0, <3.5")))
+
+	solution, err := NewSolver(root, source).Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, _ := solution.GetVersion(MakeName("lib")); ver == nil || ver.String() != "3.4.14" {
+		t.Fatalf("expected lib 3.4.14, got %v", ver)
+	}
+}
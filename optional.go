@@ -0,0 +1,177 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// OptionalCondition marks a dependency as optional, for feature groups such
+// as npm optionalDependencies or optional gem groups. The solver resolves
+// the dependency when it can; when it takes part in a conflict, the solve is
+// retried without it and an OptionalWarning is raised. Unlike
+// BestEffortCondition, an optional dependency is also skipped when its
+// versions exist but clash with other requirements. A nil Condition accepts
+// any version.
+//
+// Optional dependencies are skipped one at a time, per dependent package,
+// until the solve succeeds or the conflict no longer involves one, so a
+// problem with optional dependencies may take several solves.
+//
+// Example:
+//
+//	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{
+//	    NewTerm(MakeName("fsevents"), OptionalCondition{Condition: NewVersionSetCondition(set)}),
+//	})
+type OptionalCondition struct {
+	Condition Condition
+}
+
+// String returns the wrapped condition marked as optional.
+func (c OptionalCondition) String() string {
+	if c.Condition == nil {
+		return "* (optional)"
+	}
+	return c.Condition.String() + " (optional)"
+}
+
+// Satisfies reports whether the wrapped condition accepts ver.
+func (c OptionalCondition) Satisfies(ver Version) bool {
+	return c.Condition == nil || c.Condition.Satisfies(ver)
+}
+
+// optionalKey identifies the optional dependency of dependent on dependency.
+// Skipping applies to every version of dependent that declares it.
+func optionalKey(dependent, dependency Name) string {
+	return dependent.Value() + "->" + dependency.Value()
+}
+
+// resolveOptional replaces optional dependencies of name@version with their
+// wrapped conditions, dropping those a previous attempt decided to skip with
+// a warning, raised once per dependency. deps is copied before modification.
+func (st *solverState) resolveOptional(name Name, version Version, deps []Term) []Term {
+	var result []Term
+	for i, dep := range deps {
+		cond, ok := dep.Condition.(OptionalCondition)
+		if !ok {
+			if result != nil {
+				result = append(result, dep)
+			}
+			continue
+		}
+		if result == nil {
+			result = append(make([]Term, 0, len(deps)), deps[:i]...)
+		}
+		dep.Condition = cond.Condition
+		key := optionalKey(name, dep.Name)
+		if !st.skipOptional[key] {
+			if st.optional == nil {
+				st.optional = make(map[string]Name)
+			}
+			st.optional[key] = dep.Name
+			result = append(result, dep)
+			continue
+		}
+		dropKey := dependencyScoreKey(name, version) + "->" + dep.Name.Value()
+		if st.dropped[dropKey] {
+			continue
+		}
+		if st.dropped == nil {
+			st.dropped = make(map[string]bool)
+		}
+		st.dropped[dropKey] = true
+		st.warn(OptionalWarning{Dependent: name, Version: version, Dependency: dep})
+	}
+	if result == nil {
+		return deps
+	}
+	return result
+}
+
+// solveSkippingOptional retries a solve that failed with err, skipping one
+// more optional dependency from the conflict each time, until a solve
+// succeeds or the conflict involves no optional dependency left to skip, in
+// which case the last failure is returned.
+func (s *Solver) solveSkippingOptional(ctx context.Context, root Term, err error) (Solution, error) {
+	skip := make(map[string]bool)
+	optional := s.optional
+	total := s.stats
+	for {
+		key, found, diagErr := s.optionalConflict(ctx, root, err, skip, optional)
+		if diagErr != nil {
+			return nil, diagErr
+		}
+		if !found {
+			return nil, err
+		}
+		skip[key] = true
+		s.debug("retrying without optional dependency", "dependency", key)
+
+		attempt := s.With()
+		attempt.keepState = s.keepState
+		attempt.skipOptional = skip
+		var solution Solution
+		solution, err = attempt.SolveContext(ctx, root)
+		s.adopt(attempt)
+		total = total.add(s.stats)
+		s.stats = total
+		if err == nil || !errors.Is(err, ErrNoSolution) {
+			return solution, err
+		}
+		optional = attempt.optional
+	}
+}
+
+// optionalConflict returns an optional dependency, not yet skipped, whose
+// package takes part in the conflict that made the solve fail with err. When
+// several do, the first by key is returned. If err carries no derivation
+// because incompatibility tracking was off, root is solved again with
+// tracking to find the conflict.
+func (s *Solver) optionalConflict(ctx context.Context, root Term, err error, skip map[string]bool, optional map[string]Name) (string, bool, error) {
+	var noSolution *NoSolutionError
+	if !errors.As(err, &noSolution) {
+		diag := s.With(WithTrackingOnFailure(false), WithIncompatibilityTracking(true))
+		diag.skipOptional = skip
+		_, err := diag.SolveContext(ctx, root)
+		if !errors.As(err, &noSolution) {
+			if err != nil && !errors.Is(err, ErrNoSolution) {
+				return "", false, err
+			}
+			return "", false, nil
+		}
+		optional = diag.optional
+	}
+	// The derivation of a conflict may not go through the dependency edge
+	// itself, so match on the optional package appearing anywhere in it.
+	involved := make(map[Name]bool)
+	for _, leaf := range conflictLeaves(noSolution.Incompatibility) {
+		for _, term := range leaf.Terms {
+			involved[term.Name] = true
+		}
+	}
+	var keys []string
+	for key, dependency := range optional {
+		if involved[dependency] && !skip[key] {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return "", false, nil
+	}
+	slices.Sort(keys)
+	return keys[0], true, nil
+}
@@ -0,0 +1,104 @@
+package pubgrub
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestOptionalDependencyResolvedWhenPossible(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("libc"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("libc"), SimpleVersion("2.0.0"), nil)
+	source.AddPackage(MakeName("native"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("libc"), EqualsCondition{Version: SimpleVersion("2.0.0")}),
+	})
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("native"), OptionalCondition{}),
+	})
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+	root.AddPackage(MakeName("libc"), NewVersionSetCondition(mustParseVersionRange(t, ">=1.0.0")))
+
+	solver := NewSolver(root, source)
+	solution, err := solver.Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, ok := solution.GetVersion(MakeName("native")); !ok || ver.String() != "1.0.0" {
+		t.Fatalf("expected optional native 1.0.0 to be resolved, got %v", solution)
+	}
+	if ver, _ := solution.GetVersion(MakeName("libc")); ver.String() != "2.0.0" {
+		t.Fatalf("expected native to require libc 2.0.0, got %s", ver)
+	}
+	if len(solver.Warnings()) != 0 {
+		t.Fatalf("expected no warnings, got %v", solver.Warnings())
+	}
+}
+
+func TestOptionalDependencySkippedOnConflict(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("libc"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("libc"), SimpleVersion("2.0.0"), nil)
+	source.AddPackage(MakeName("native"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("libc"), EqualsCondition{Version: SimpleVersion("2.0.0")}),
+	})
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("native"), OptionalCondition{}),
+	})
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+	root.AddPackage(MakeName("libc"), NewVersionSetCondition(mustParseVersionRange(t, "<2.0.0")))
+
+	for _, tracking := range []bool{false, true} {
+		solver := NewSolverWithOptions([]Source{root, source}, WithIncompatibilityTracking(tracking))
+		solution, err := solver.Solve(root.Term())
+		if err != nil {
+			t.Fatalf("tracking=%v: unexpected error: %v", tracking, err)
+		}
+		if ver, ok := solution.GetVersion(MakeName("native")); ok {
+			t.Fatalf("tracking=%v: expected conflicting native to be skipped, got %s", tracking, ver)
+		}
+		if ver, _ := solution.GetVersion(MakeName("libc")); ver.String() != "1.0.0" {
+			t.Fatalf("tracking=%v: expected libc 1.0.0, got %s", tracking, ver)
+		}
+
+		warnings := solver.Warnings()
+		if len(warnings) != 1 {
+			t.Fatalf("tracking=%v: expected one warning, got %v", tracking, warnings)
+		}
+		w, ok := warnings[0].(OptionalWarning)
+		if !ok || w.Dependent != MakeName("app") || w.Dependency.Name != MakeName("native") {
+			t.Fatalf("tracking=%v: unexpected warning %#v", tracking, warnings[0])
+		}
+		if !strings.Contains(w.Warning(), "skipped optional dependency native") {
+			t.Fatalf("unexpected warning text %q", w.Warning())
+		}
+		if solver.Stats().Steps == 0 {
+			t.Fatalf("expected stats to cover the retries")
+		}
+	}
+}
+
+func TestOptionalDependencyDoesNotHideRequiredConflicts(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("libc"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("libc"), SimpleVersion("2.0.0"), nil)
+	source.AddPackage(MakeName("native"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("libc"), EqualsCondition{Version: SimpleVersion("2.0.0")}),
+	})
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("native"), OptionalCondition{}),
+	})
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+	root.AddPackage(MakeName("libc"), NewVersionSetCondition(mustParseVersionRange(t, "<2.0.0")))
+
+	root.AddPackage(MakeName("libc"), NewVersionSetCondition(mustParseVersionRange(t, ">=2.0.0")))
+
+	solver := NewSolver(root, source)
+	_, err := solver.Solve(root.Term())
+	if !errors.Is(err, ErrNoSolution) {
+		t.Fatalf("expected no solution, got %v", err)
+	}
+}
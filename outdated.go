@@ -0,0 +1,147 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OutdatedPackage describes a resolved package for which newer versions are
+// published.
+type OutdatedPackage struct {
+	Name    Name
+	Current Version
+	// Latest is the newest published version.
+	Latest Version
+	// Newest is the newest published version every dependent in the solution
+	// accepts. When it is newer than Current, the package can be updated
+	// without touching anything else; nil when no published version is
+	// acceptable to all dependents.
+	Newest Version
+	// Blockers lists the requirements in the solution that exclude Latest.
+	Blockers []OutdatedBlocker
+}
+
+// OutdatedBlocker is a requirement of a resolved package that keeps an
+// outdated package from its latest version.
+type OutdatedBlocker struct {
+	Dependent Name
+	Version   Version
+	// Requirement is the dependent's term on the outdated package.
+	Requirement Term
+}
+
+// String renders the package in the style of `bundle outdated`.
+func (o OutdatedPackage) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (newest %s, installed %s", FormatName(o.Name), o.Latest, o.Current)
+	for i, blocker := range o.Blockers {
+		if i == 0 {
+			b.WriteString(", requested by ")
+		} else {
+			b.WriteString(", ")
+		}
+		if blocker.Dependent == MakeName("$$root") {
+			fmt.Fprintf(&b, "root %q", conditionString(blocker.Requirement.Condition))
+		} else {
+			fmt.Fprintf(&b, "%s %s %q", FormatName(blocker.Dependent), blocker.Version, conditionString(blocker.Requirement.Condition))
+		}
+	}
+	b.WriteString(")")
+	return b.String()
+}
+
+// Outdated lists the packages of sol for which source publishes a version
+// newer than the selected one, in solution order. For each, it reports which
+// packages of the solution impose the requirements excluding the latest
+// version, which is what a `bundle outdated`-style command shows.
+//
+// Packages whose versions or dependencies cannot be fetched are skipped.
+//
+// Example:
+//
+//	for _, pkg := range Outdated(solution, source) {
+//	    fmt.Println(pkg)
+//	}
+func Outdated(sol Solution, source Source) []OutdatedPackage {
+	requirements := solutionRequirements(sol, source)
+
+	var outdated []OutdatedPackage
+	for _, nv := range sol {
+		if nv.Name == MakeName("$$root") {
+			continue
+		}
+		versions, err := source.GetVersions(nv.Name)
+		if err != nil || len(versions) == 0 {
+			continue
+		}
+		latest := versions[len(versions)-1]
+		if latest.Sort(nv.Version) <= 0 {
+			continue
+		}
+
+		entry := OutdatedPackage{Name: nv.Name, Current: nv.Version, Latest: latest}
+		for _, req := range requirements[nv.Name] {
+			if !req.term.SatisfiedBy(latest) {
+				entry.Blockers = append(entry.Blockers, OutdatedBlocker{
+					Dependent:   req.dependent.Name,
+					Version:     req.dependent.Version,
+					Requirement: req.term,
+				})
+			}
+		}
+		for i := len(versions) - 1; i >= 0; i-- {
+			if acceptedByAll(versions[i], requirements[nv.Name]) {
+				entry.Newest = versions[i]
+				break
+			}
+		}
+		outdated = append(outdated, entry)
+	}
+	return outdated
+}
+
+// solutionRequirements collects the requirements every resolved package
+// places on the others, keyed by the required package. Packages whose
+// dependencies cannot be fetched contribute none.
+func solutionRequirements(sol Solution, source Source) map[Name][]outdatedRequirement {
+	requirements := make(map[Name][]outdatedRequirement)
+	for _, nv := range sol {
+		deps, err := source.GetDependencies(nv.Name, nv.Version)
+		if err != nil {
+			continue
+		}
+		for _, dep := range deps {
+			requirements[dep.Name] = append(requirements[dep.Name], outdatedRequirement{dependent: nv, term: dep})
+		}
+	}
+	return requirements
+}
+
+type outdatedRequirement struct {
+	dependent NameVersion
+	term      Term
+}
+
+// acceptedByAll reports whether ver satisfies every requirement.
+func acceptedByAll(ver Version, reqs []outdatedRequirement) bool {
+	for _, req := range reqs {
+		if !req.term.SatisfiedBy(ver) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package pubgrub
+
+import "testing"
+
+func TestOutdatedReportsBlockers(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("rubyXL"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("rubyzip"), NewVersionSetCondition(mustParseVersionRange(t, "<3.0.0"))),
+	})
+	source.AddPackage(MakeName("rubyXL"), SimpleVersion("1.1.0"), []Term{
+		NewTerm(MakeName("rubyzip"), NewVersionSetCondition(mustParseVersionRange(t, "<3.0.0"))),
+	})
+	for _, v := range []string{"2.3.0", "2.4.1", "3.0.0"} {
+		source.AddPackage(MakeName("rubyzip"), SimpleVersion(v), nil)
+	}
+	source.AddPackage(MakeName("json"), SimpleVersion("2.0.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("rubyXL"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+	root.AddPackage(MakeName("json"), NewVersionSetCondition(FullVersionSet()))
+
+	solution := Solution{
+		{Name: MakeName("$$root"), Version: SimpleVersion("1")},
+		{Name: MakeName("rubyXL"), Version: SimpleVersion("1.0.0")},
+		{Name: MakeName("rubyzip"), Version: SimpleVersion("2.3.0")},
+		{Name: MakeName("json"), Version: SimpleVersion("2.0.0")},
+	}
+	outdated := Outdated(solution, CombinedSource{root, source})
+	if len(outdated) != 2 {
+		t.Fatalf("expected rubyXL and rubyzip to be outdated, got %v", outdated)
+	}
+
+	rubyXL := outdated[0]
+	if rubyXL.Name != MakeName("rubyXL") || rubyXL.Latest.String() != "1.1.0" || rubyXL.Newest.String() != "1.0.0" {
+		t.Fatalf("unexpected rubyXL entry: %+v", rubyXL)
+	}
+	if got := rubyXL.String(); got != `rubyXL (newest 1.1.0, installed 1.0.0, requested by root "== 1.0.0")` {
+		t.Fatalf("unexpected rendering: %s", got)
+	}
+
+	rubyzip := outdated[1]
+	if rubyzip.Latest.String() != "3.0.0" || rubyzip.Newest.String() != "2.4.1" {
+		t.Fatalf("expected latest 3.0.0 and newest acceptable 2.4.1, got %+v", rubyzip)
+	}
+	if len(rubyzip.Blockers) != 1 || rubyzip.Blockers[0].Dependent != MakeName("rubyXL") {
+		t.Fatalf("expected rubyXL to block rubyzip, got %+v", rubyzip.Blockers)
+	}
+}
+
+func TestSlackReport(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("rubyXL"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("rubyzip"), NewVersionSetCondition(mustParseVersionRange(t, "<3.0.0"))),
+	})
+	source.AddPackage(MakeName("rubyXL"), SimpleVersion("1.1.0"), []Term{
+		NewTerm(MakeName("rubyzip"), NewVersionSetCondition(mustParseVersionRange(t, "<3.0.0"))),
+	})
+	for _, v := range []string{"2.3.0", "2.4.1", "3.0.0"} {
+		source.AddPackage(MakeName("rubyzip"), SimpleVersion(v), nil)
+	}
+	source.AddPackage(MakeName("json"), SimpleVersion("2.0.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("rubyXL"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+	root.AddPackage(MakeName("json"), NewVersionSetCondition(FullVersionSet()))
+
+	solution := Solution{
+		{Name: MakeName("$$root"), Version: SimpleVersion("1")},
+		{Name: MakeName("rubyXL"), Version: SimpleVersion("1.0.0")},
+		{Name: MakeName("rubyzip"), Version: SimpleVersion("2.3.0")},
+		{Name: MakeName("json"), Version: SimpleVersion("2.0.0")},
+	}
+	report := SlackReport(solution, CombinedSource{root, source})
+	if len(report) != 3 {
+		t.Fatalf("expected an entry per resolved package, got %v", report)
+	}
+
+	rubyzip := report[1]
+	if rubyzip.Name != MakeName("rubyzip") || rubyzip.Newer != 2 || rubyzip.Slack != 1 {
+		t.Fatalf("expected 1 of 2 newer rubyzip versions allowed, got %+v", rubyzip)
+	}
+	if got, want := rubyzip.String(), "rubyzip 2.3.0: 1 of 2 newer versions allowed, tightest: rubyXL 1.0.0 requires rubyzip <3.0.0"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	rubyXL := report[0]
+	if rubyXL.Newer != 1 || rubyXL.Slack != 0 || rubyXL.Tightest == nil || rubyXL.Tightest.Dependent != MakeName("$$root") {
+		t.Fatalf("expected the root pin to hold rubyXL back, got %+v", rubyXL)
+	}
+	if json := report[2]; json.Newer != 0 || json.Slack != 0 {
+		t.Fatalf("expected json to be current, got %+v", json)
+	}
+}
@@ -39,10 +39,13 @@ const (
 //  3. Backtracks (removes assignments when conflicts occur)
 //
 // Assignments are indexed both globally (for satisfier ordering) and per-package
-// (for fast version set computation).
+// (for fast version set computation). The global list is a trail: each
+// decision level occupies a contiguous segment starting at levelStart[level],
+// so backtracking cuts the trail at a known offset instead of searching for it.
 type partialSolution struct {
 	assignments []*assignment          // All assignments in chronological order
 	perPackage  map[Name][]*assignment // Assignments indexed by package name
+	levelStart  []int                  // Trail offset where each decision level begins
 	decisionLvl int                    // Current decision level
 	nextIndex   int                    // Next assignment index
 	root        Name                   // Root package name
@@ -53,6 +56,7 @@ func newPartialSolution(root Name) *partialSolution {
 	return &partialSolution{
 		assignments: make([]*assignment, 0),
 		perPackage:  make(map[Name][]*assignment),
+		levelStart:  []int{0},
 		decisionLvl: 0,
 		nextIndex:   0,
 		root:        root,
@@ -72,10 +76,41 @@ func (ps *partialSolution) newDecisionAssignment(name Name, version Version, lev
 	}
 }
 
-// append adds an assignment to the partial solution.
+// append adds an assignment to the partial solution, recording the package's
+// cumulative state on it. Callers that already computed the resulting allowed
+// set may store it in assign.state to skip the recomputation.
 func (ps *partialSolution) append(assign *assignment) {
-	ps.assignments = append(ps.assignments, assign)
 	stack := ps.perPackage[assign.name]
+	prev := fullVersionSet
+	assign.lastPositive, assign.lastNegative = -1, -1
+	if len(stack) > 0 {
+		top := stack[len(stack)-1]
+		prev = top.state
+		assign.required = top.required
+		assign.decided = top.decided
+		assign.lastPositive, assign.lastNegative = top.lastPositive, top.lastNegative
+	}
+	if assign.term.Positive {
+		assign.required = true
+		assign.lastPositive = len(stack)
+	} else {
+		assign.lastNegative = len(stack)
+	}
+	if assign.kind == assignmentDecision {
+		assign.decided = true
+	}
+	if assign.state == nil {
+		assign.state = prev
+		if assign.term.Positive {
+			if assign.allowed != nil {
+				assign.state = prev.Intersection(assign.allowed)
+			}
+		} else if assign.forbidden != nil {
+			assign.state = prev.Intersection(assign.forbidden.Complement())
+		}
+	}
+
+	ps.assignments = append(ps.assignments, assign)
 	stack = append(stack, assign)
 	ps.perPackage[assign.name] = stack
 	ps.nextIndex++
@@ -92,24 +127,21 @@ func (ps *partialSolution) latest(name Name) *assignment {
 
 // allowedSet computes the currently allowed version set for a package by
 // intersecting all positive constraints and excluding forbidden sets.
+//
+// The result is shared with the assignment stack and must not be mutated.
 func (ps *partialSolution) allowedSet(name Name) VersionSet {
-	stack := ps.perPackage[name]
-	full := FullVersionSet()
-	if len(stack) == 0 {
-		return full
+	if latest := ps.latest(name); latest != nil {
+		return latest.state
 	}
+	return fullVersionSet
+}
 
-	current := full
-	for _, assign := range stack {
-		if assign.term.Positive {
-			if assign.allowed != nil {
-				current = current.Intersection(assign.allowed)
-			}
-		} else if assign.forbidden != nil {
-			current = current.Intersection(assign.forbidden.Complement())
-		}
-	}
-	return current
+// isRequired reports whether the package must be part of the solution, i.e.
+// whether any positive assignment exists for it. Packages constrained only by
+// negative terms may be left out entirely.
+func (ps *partialSolution) isRequired(name Name) bool {
+	latest := ps.latest(name)
+	return latest != nil && latest.required
 }
 
 // hasAssignments returns true if there are any assignments for the package.
@@ -120,6 +152,7 @@ func (ps *partialSolution) hasAssignments(name Name) bool {
 // addDecision adds a version selection decision, incrementing the decision level.
 func (ps *partialSolution) addDecision(name Name, version Version) *assignment {
 	ps.decisionLvl++
+	ps.levelStart = append(ps.levelStart, len(ps.assignments))
 	assign := ps.newDecisionAssignment(name, version, ps.decisionLvl)
 	ps.append(assign)
 	return assign
@@ -142,7 +175,8 @@ func (ps *partialSolution) addDerivation(term Term, cause *Incompatibility) (*as
 	if err != nil {
 		return nil, false, err
 	}
-	if newAllowed.IsEmpty() {
+	required := ps.isRequired(term.Name)
+	if newAllowed.IsEmpty() && (term.Positive || required) {
 		return nil, false, errNoAllowedVersions
 	}
 
@@ -165,6 +199,7 @@ func (ps *partialSolution) addDerivation(term Term, cause *Incompatibility) (*as
 		assign.forbidden = forbidden
 	}
 
+	assign.state = newAllowed
 	changed := !setsEqual(currentAllowed, newAllowed)
 	ps.append(assign)
 
@@ -172,13 +207,14 @@ func (ps *partialSolution) addDerivation(term Term, cause *Incompatibility) (*as
 		return assign, true, nil
 	}
 
-	if changed && !term.Positive {
+	if changed && !term.Positive && required {
 		// Record tightened allowance as positive assignment
 		tightening := &assignment{
 			name:          term.Name,
 			term:          termFromAllowedSet(term.Name, newAllowed),
 			kind:          assignmentDerivation,
 			allowed:       newAllowed,
+			state:         newAllowed,
 			cause:         cause,
 			decisionLevel: ps.decisionLvl,
 			index:         ps.nextIndex,
@@ -192,47 +228,41 @@ func (ps *partialSolution) addDerivation(term Term, cause *Incompatibility) (*as
 
 // backtrack removes all assignments above the specified decision level.
 // Used when the solver needs to undo decisions during conflict resolution.
+// The cost is proportional to the number of assignments removed.
 func (ps *partialSolution) backtrack(level int) {
 	if level < 0 {
 		level = 0
 	}
+	if level >= ps.decisionLvl {
+		return
+	}
 
-	for len(ps.assignments) > 0 {
-		last := ps.assignments[len(ps.assignments)-1]
-		if last.decisionLevel <= level {
-			break
-		}
-		ps.assignments = ps.assignments[:len(ps.assignments)-1]
+	cut := ps.levelStart[level+1]
+	for i := len(ps.assignments) - 1; i >= cut; i-- {
+		last := ps.assignments[i]
+		ps.assignments[i] = nil
 		stack := ps.perPackage[last.name]
-		if len(stack) > 0 {
-			stack = stack[:len(stack)-1]
-			if len(stack) == 0 {
-				delete(ps.perPackage, last.name)
-			} else {
-				ps.perPackage[last.name] = stack
-			}
+		if len(stack) <= 1 {
+			delete(ps.perPackage, last.name)
+			continue
 		}
+		stack[len(stack)-1] = nil
+		ps.perPackage[last.name] = stack[:len(stack)-1]
 	}
 
+	ps.assignments = ps.assignments[:cut]
+	ps.levelStart = ps.levelStart[:level+1]
 	ps.decisionLvl = level
 }
 
-// isComplete returns true if every package (except root) has a decision assignment.
+// isComplete returns true if every required package (except root) has a decision assignment.
 func (ps *partialSolution) isComplete() bool {
-	for name, stack := range ps.perPackage {
-		// Skip root assignment
-		if name == ps.root {
+	for name := range ps.perPackage {
+		// Skip root assignment and packages that may be absent
+		if name == ps.root || !ps.isRequired(name) {
 			continue
 		}
-
-		hasDecision := false
-		for _, assign := range stack {
-			if assign.kind == assignmentDecision {
-				hasDecision = true
-				break
-			}
-		}
-		if !hasDecision {
+		if !ps.hasDecision(name) {
 			return false
 		}
 	}
@@ -245,23 +275,20 @@ func (ps *partialSolution) isComplete() bool {
 // Heuristic: Prefer packages with tighter constraints (smaller allowed sets)
 // to reduce search space early. This helps avoid exploring dead ends when
 // there are many interdependent packages.
+//
+// Packages are visited in map order; ties are broken by name, so the result
+// is deterministic without tracking which names were already seen.
 func (ps *partialSolution) nextDecisionCandidate() (Name, bool) {
-	seen := make(map[Name]bool)
 	bestScore := maxConstraintPriority
 	bestName := EmptyName()
 	found := false
 
-	for _, assign := range ps.assignments {
-		name := assign.name
+	for name := range ps.perPackage {
 		if name == ps.root {
 			continue
 		}
-		if seen[name] {
-			continue
-		}
-		seen[name] = true
 
-		if ps.hasDecision(name) {
+		if ps.hasDecision(name) || !ps.isRequired(name) {
 			continue
 		}
 
@@ -322,58 +349,107 @@ func constraintScoreForSet(allowed VersionSet) int {
 
 // hasDecision returns true if there's a decision assignment for the package.
 func (ps *partialSolution) hasDecision(name Name) bool {
-	stack := ps.perPackage[name]
-	for _, assign := range stack {
-		if assign.kind == assignmentDecision {
-			return true
-		}
-	}
-	return false
+	latest := ps.latest(name)
+	return latest != nil && latest.decided
 }
 
 // satisfier finds the assignment that most recently satisfied a term in the incompatibility.
 // Used during conflict resolution to identify which assignment to analyze.
 func (ps *partialSolution) satisfier(inc *Incompatibility) *assignment {
-	var selected *assignment
-	maxIndex := -1
-
-	for _, term := range inc.Terms {
-		stack := ps.perPackage[term.Name]
-		for i := len(stack) - 1; i >= 0; i-- {
-			assign := stack[i]
-			if termSatisfiedBy(term, assign) {
-				if assign.index > maxIndex {
-					selected = assign
-					maxIndex = assign.index
-				}
-				break
-			}
-		}
-	}
-
-	return selected
+	satisfier, _ := ps.conflictLevels(inc)
+	return satisfier
 }
 
 // previousDecisionLevel finds the highest decision level among assignments
 // satisfying the incompatibility, excluding the satisfier itself.
 // Used to determine where to backtrack during conflict resolution.
 func (ps *partialSolution) previousDecisionLevel(inc *Incompatibility, satisfier *assignment) int {
+	var buf [4]termMatcher
+	return ps.previousLevel(inc, matchersFor(inc, buf[:0]), satisfier)
+}
+
+// conflictLevels returns the satisfier of inc together with the previous
+// decision level, building each term's version set once. Each term is looked
+// up with latestSatisfying, so the cost does not grow with stack depth for
+// positive terms.
+func (ps *partialSolution) conflictLevels(inc *Incompatibility) (*assignment, int) {
+	var buf [4]termMatcher
+	matchers := matchersFor(inc, buf[:0])
+
+	var selected *assignment
+	maxIndex := -1
+	for i, term := range inc.Terms {
+		if assign := latestSatisfying(ps.perPackage[term.Name], matchers[i], nil, maxIndex, -1); assign != nil {
+			selected = assign
+			maxIndex = assign.index
+		}
+	}
+	if selected == nil {
+		return nil, 0
+	}
+	return selected, ps.previousLevel(inc, matchers, selected)
+}
+
+func (ps *partialSolution) previousLevel(inc *Incompatibility, matchers []termMatcher, satisfier *assignment) int {
 	level := 0
+	for i, term := range inc.Terms {
+		if assign := latestSatisfying(ps.perPackage[term.Name], matchers[i], satisfier, -1, level); assign != nil {
+			level = assign.decisionLevel
+		}
+	}
+	return level
+}
 
-	for _, term := range inc.Terms {
-		stack := ps.perPackage[term.Name]
-		for i := len(stack) - 1; i >= 0; i-- {
-			assign := stack[i]
-			if assign == satisfier {
-				continue
-			}
-			if termSatisfiedBy(term, assign) && assign.decisionLevel > level {
-				level = assign.decisionLevel
+// latestSatisfying returns the most recent assignment in stack that satisfies
+// m, other than skip, whose index exceeds minIndex and whose decision level
+// exceeds minLevel; nil if there is none. Indices and levels only grow along
+// a stack, so the search stops at the first assignment below either bound.
+//
+// Positive assignments are found through the lastPositive marker in constant
+// time: a positive assignment's allowed set is the package's cumulative state
+// at that point, which only shrinks along the stack, so when the latest
+// positive assignment does not satisfy m no earlier one does. Negative
+// assignments, which only negative terms can match, are visited newest first
+// through the lastNegative chain without touching the positive ones.
+func latestSatisfying(stack []*assignment, m termMatcher, skip *assignment, minIndex, minLevel int) *assignment {
+	if len(stack) == 0 || !m.ok {
+		return nil
+	}
+	top := stack[len(stack)-1]
+
+	var found *assignment
+	for p := top.lastPositive; p >= 0; p = stack[p-1].lastPositive {
+		assign := stack[p]
+		if assign.index <= minIndex || assign.decisionLevel <= minLevel {
+			break
+		}
+		if assign != skip {
+			if m.satisfiedBy(assign) {
+				found = assign
 			}
+			break
 		}
+		if p == 0 {
+			break
+		}
+	}
+	if m.positive {
+		return found
 	}
 
-	return level
+	for n := top.lastNegative; n >= 0; n = stack[n-1].lastNegative {
+		assign := stack[n]
+		if assign.index <= minIndex || assign.decisionLevel <= minLevel || (found != nil && assign.index < found.index) {
+			break
+		}
+		if assign != skip && m.satisfiedBy(assign) {
+			return assign
+		}
+		if n == 0 {
+			break
+		}
+	}
+	return found
 }
 
 // buildSolution constructs the final solution from decision assignments.
@@ -420,7 +496,7 @@ func (ps *partialSolution) pendingPackages() []Name {
 		}
 		seen[name] = true
 
-		if !ps.hasDecision(name) {
+		if !ps.hasDecision(name) && ps.isRequired(name) {
 			pending = append(pending, name)
 		}
 	}
@@ -430,41 +506,55 @@ func (ps *partialSolution) pendingPackages() []Name {
 
 // termSatisfiedBy checks if an assignment satisfies a term in an incompatibility.
 func termSatisfiedBy(term Term, assign *assignment) bool {
-	if assign == nil {
-		return false
-	}
+	return newTermMatcher(term).satisfiedBy(assign)
+}
 
+// termMatcher tests assignments against one term, holding the term's version
+// set so repeated tests do not rebuild it.
+type termMatcher struct {
+	positive bool
+	set      VersionSet // allowed set for positive terms, forbidden set otherwise
+	ok       bool
+}
+
+func newTermMatcher(term Term) termMatcher {
 	if term.Positive {
-		required, ok := termAllowedSet(term)
-		if !ok {
-			return false
-		}
-		if assign.term.Positive {
-			if assign.allowed == nil {
-				return false
-			}
-			return assign.allowed.IsSubset(required)
-		}
-		if assign.allowed != nil {
-			return assign.allowed.IsSubset(required)
-		}
-		return false
+		set, ok := termAllowedSet(term)
+		return termMatcher{positive: true, set: set, ok: ok}
 	}
+	set, ok := termForbiddenSet(term)
+	return termMatcher{set: set, ok: ok}
+}
 
-	forbidden, ok := termForbiddenSet(term)
-	if !ok {
+// matchersFor appends a matcher for each term of inc to buf.
+func matchersFor(inc *Incompatibility, buf []termMatcher) []termMatcher {
+	for _, term := range inc.Terms {
+		buf = append(buf, newTermMatcher(term))
+	}
+	return buf
+}
+
+func (m termMatcher) satisfiedBy(assign *assignment) bool {
+	if assign == nil || !m.ok {
 		return false
 	}
 
+	if m.positive {
+		if assign.allowed == nil {
+			return false
+		}
+		return assign.allowed.IsSubset(m.set)
+	}
+
 	if assign.term.Positive {
 		if assign.allowed == nil {
 			return false
 		}
-		return assign.allowed.IsDisjoint(forbidden)
+		return assign.allowed.IsDisjoint(m.set)
 	}
 
 	if assign.forbidden == nil {
 		return false
 	}
-	return forbidden.IsSubset(assign.forbidden)
+	return m.set.IsSubset(assign.forbidden)
 }
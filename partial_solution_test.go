@@ -1,6 +1,9 @@
 package pubgrub
 
-import "testing"
+import (
+	"fmt"
+	"testing"
+)
 
 func TestPartialSolutionPreviousDecisionLevel(t *testing.T) {
 	root := MakeName("root")
@@ -37,3 +40,139 @@ func TestPartialSolutionPreviousDecisionLevel(t *testing.T) {
 		t.Fatalf("expected previous decision level 1, got %d", prev)
 	}
 }
+
+func TestConflictLevelsMatchesFullScan(t *testing.T) {
+	root := MakeName("root")
+	ps := newPartialSolution(root)
+	ps.seedRoot(root, SimpleVersion("1"))
+
+	a, b := MakeName("a"), MakeName("b")
+	mustDerive := func(term Term) {
+		t.Helper()
+		if _, _, err := ps.addDerivation(term, nil); err != nil {
+			t.Fatalf("derive %s: %v", term, err)
+		}
+	}
+
+	// Interleave derivations on a and b across several decision levels so
+	// both stacks are several assignments deep.
+	for i := 1; i <= 4; i++ {
+		mustDerive(NewTerm(a, NewVersionSetCondition(mustParseVersionRange(t, fmt.Sprintf("<%d.0.0", 10-i)))))
+		ps.addDecision(MakeName(fmt.Sprintf("pad%d", i)), SimpleVersion("1"))
+		mustDerive(NewNegativeTerm(b, EqualsCondition{Version: SimpleVersion(fmt.Sprintf("%d.0.0", i))}))
+	}
+	mustDerive(NewTerm(b, NewVersionSetCondition(mustParseVersionRange(t, ">=5.0.0"))))
+
+	incs := []*Incompatibility{
+		{Terms: []Term{
+			NewTerm(a, NewVersionSetCondition(mustParseVersionRange(t, "<9.0.0"))),
+			NewNegativeTerm(b, EqualsCondition{Version: SimpleVersion("2.0.0")}),
+		}},
+		{Terms: []Term{
+			NewTerm(a, NewVersionSetCondition(mustParseVersionRange(t, "<7.0.0"))),
+			NewTerm(b, NewVersionSetCondition(mustParseVersionRange(t, ">=1.0.0"))),
+		}},
+		{Terms: []Term{
+			NewTerm(a, NewVersionSetCondition(mustParseVersionRange(t, "<20.0.0"))),
+		}},
+		// Negative terms satisfied by positive assignments, by negative
+		// ones, and by both.
+		{Terms: []Term{
+			NewNegativeTerm(a, EqualsCondition{Version: SimpleVersion("9.5.0")}),
+			NewNegativeTerm(b, EqualsCondition{Version: SimpleVersion("3.0.0")}),
+		}},
+		{Terms: []Term{
+			NewTerm(a, NewVersionSetCondition(mustParseVersionRange(t, "<8.0.0"))),
+			NewNegativeTerm(b, NewVersionSetCondition(mustParseVersionRange(t, "<2.0.0"))),
+		}},
+		{Terms: []Term{
+			NewNegativeTerm(b, EqualsCondition{Version: SimpleVersion("1.0.0")}),
+		}},
+	}
+
+	for _, inc := range incs {
+		// Reference: scan every assignment of every term.
+		var wantSat *assignment
+		for _, term := range inc.Terms {
+			stack := ps.perPackage[term.Name]
+			for i := len(stack) - 1; i >= 0; i-- {
+				if termSatisfiedBy(term, stack[i]) {
+					if wantSat == nil || stack[i].index > wantSat.index {
+						wantSat = stack[i]
+					}
+					break
+				}
+			}
+		}
+		wantLevel := 0
+		for _, term := range inc.Terms {
+			for _, assign := range ps.perPackage[term.Name] {
+				if assign != wantSat && termSatisfiedBy(term, assign) && assign.decisionLevel > wantLevel {
+					wantLevel = assign.decisionLevel
+				}
+			}
+		}
+
+		gotSat, gotLevel := ps.conflictLevels(inc)
+		if gotSat != wantSat || gotLevel != wantLevel {
+			t.Fatalf("%s: expected satisfier %v at previous level %d, got %v at %d",
+				inc, describeAssignment(wantSat), wantLevel, describeAssignment(gotSat), gotLevel)
+		}
+	}
+}
+
+func describeAssignment(assign *assignment) string {
+	if assign == nil {
+		return "<nil>"
+	}
+	return assign.describe()
+}
+
+func TestPartialSolutionBacktrackTrail(t *testing.T) {
+	root := MakeName("root")
+	ps := newPartialSolution(root)
+	ps.seedRoot(root, SimpleVersion("1"))
+
+	a, b := MakeName("a"), MakeName("b")
+	if _, _, err := ps.addDerivation(NewTerm(a, NewVersionSetCondition(mustParseVersionRange(t, "<3.0.0"))), nil); err != nil {
+		t.Fatalf("derive: %v", err)
+	}
+	ps.addDecision(a, SimpleVersion("2.0.0"))
+	if _, _, err := ps.addDerivation(NewTerm(b, NewVersionSetCondition(mustParseVersionRange(t, ">=1.0.0"))), nil); err != nil {
+		t.Fatalf("derive: %v", err)
+	}
+	ps.addDecision(b, SimpleVersion("1.5.0"))
+	ps.addDecision(MakeName("c"), SimpleVersion("1"))
+
+	ps.backtrack(1)
+
+	if ps.decisionLvl != 1 || len(ps.levelStart) != 2 {
+		t.Fatalf("expected level 1 with 2 trail segments, got level %d, %d segments", ps.decisionLvl, len(ps.levelStart))
+	}
+	if len(ps.assignments) != 4 {
+		t.Fatalf("expected 4 assignments after backtrack, got %d:\n%s", len(ps.assignments), ps.snapshot())
+	}
+	if !ps.hasDecision(a) || ps.hasDecision(b) {
+		t.Fatalf("expected a decided and b undecided")
+	}
+	if ps.hasAssignments(MakeName("c")) {
+		t.Fatalf("expected c to be removed")
+	}
+	if got := ps.allowedSet(b).String(); got != ">=1.0.0" {
+		t.Fatalf("expected b to keep its level-1 derivation, got %s", got)
+	}
+
+	// Satisfier markers are cut with the stacks: the b decision is gone, so
+	// the level-1 derivation satisfies a term on b.
+	inc := &Incompatibility{Terms: []Term{NewTerm(b, NewVersionSetCondition(mustParseVersionRange(t, ">=0.5.0")))}}
+	if sat := ps.satisfier(inc); sat == nil || sat.isDecision() || sat.decisionLevel != 1 {
+		t.Fatalf("expected b's level-1 derivation as satisfier, got %s", describeAssignment(sat))
+	}
+
+	// The trail keeps working after the cut.
+	ps.addDecision(b, SimpleVersion("1.0.0"))
+	ps.backtrack(0)
+	if len(ps.assignments) != 2 || ps.hasAssignments(b) || ps.hasDecision(a) {
+		t.Fatalf("expected only level-0 assignments, got:\n%s", ps.snapshot())
+	}
+}
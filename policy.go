@@ -0,0 +1,225 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// PolicyRule targets a set of versions of one package.
+type PolicyRule struct {
+	Package  Name
+	Versions VersionSet
+	// Reason is shown in error reports when the rule causes a conflict.
+	Reason string
+}
+
+// PolicySet is a reusable collection of version rules that can be applied to
+// any solve with WithPolicy, letting platform teams manage approved versions
+// centrally instead of in every project.
+//
+// Rules come in five flavours:
+//   - Constraints limit a package to the given versions whenever it is used.
+//     They do not pull the package into the solution.
+//   - Exclusions forbid the given versions, e.g. releases with known
+//     vulnerabilities.
+//   - Overrides replace every transitive dependency on the package with the
+//     given versions, regardless of what the depender declared. Root
+//     requirements are left as written.
+//   - Bundles require another package whenever a package is installed,
+//     independent of its own metadata, e.g. for plugin packs.
+//   - Exclusive groups allow at most one of several packages, e.g.
+//     competing database drivers or conflicting forks.
+//
+// Example:
+//
+//	policy := NewPolicySet("org-baseline")
+//	policy.Constrain(MakeName("rails"), mustRange(">=7.0.0, <8.0.0"), "supported major")
+//	policy.Exclude(MakeName("log4j"), mustRange("<2.17.0"), "CVE-2021-44228")
+//	solver := NewSolverWithOptions([]Source{root, source}, WithPolicy(policy))
+type PolicySet struct {
+	Name        string
+	Constraints []PolicyRule
+	Exclusions  []PolicyRule
+	Overrides   []PolicyRule
+	Bundles     []PolicyBundle
+	Groups      []PolicyGroup
+}
+
+// PolicyBundle requires Requires whenever a version of Package in Versions
+// is part of the solution.
+type PolicyBundle struct {
+	Package  Name
+	Versions VersionSet
+	Requires Term
+	// Reason is shown in error reports when the bundle causes a conflict.
+	Reason string
+}
+
+// NewPolicySet creates an empty named policy set.
+func NewPolicySet(name string) *PolicySet {
+	return &PolicySet{Name: name}
+}
+
+// Constrain limits pkg to versions whenever it is part of a solution.
+func (p *PolicySet) Constrain(pkg Name, versions VersionSet, reason string) *PolicySet {
+	p.Constraints = append(p.Constraints, PolicyRule{Package: pkg, Versions: versions, Reason: reason})
+	return p
+}
+
+// Exclude forbids versions of pkg.
+func (p *PolicySet) Exclude(pkg Name, versions VersionSet, reason string) *PolicySet {
+	p.Exclusions = append(p.Exclusions, PolicyRule{Package: pkg, Versions: versions, Reason: reason})
+	return p
+}
+
+// Override replaces transitive dependencies on pkg with versions.
+func (p *PolicySet) Override(pkg Name, versions VersionSet, reason string) *PolicySet {
+	p.Overrides = append(p.Overrides, PolicyRule{Package: pkg, Versions: versions, Reason: reason})
+	return p
+}
+
+// PolicyGroup allows at most one of Packages in a solution.
+type PolicyGroup struct {
+	Packages []Name
+	// Reason is shown in error reports when the group causes a conflict.
+	Reason string
+}
+
+// ExclusiveGroup allows at most one of packages to be installed.
+func (p *PolicySet) ExclusiveGroup(packages []Name, reason string) *PolicySet {
+	p.Groups = append(p.Groups, PolicyGroup{Packages: slices.Clone(packages), Reason: reason})
+	return p
+}
+
+// Bundle requires requirement whenever pkg is installed at one of versions.
+func (p *PolicySet) Bundle(pkg Name, versions VersionSet, requirement Term, reason string) *PolicySet {
+	p.Bundles = append(p.Bundles, PolicyBundle{Package: pkg, Versions: versions, Requires: requirement, Reason: reason})
+	return p
+}
+
+// incompatibilities converts the rules into policy incompatibilities.
+// Constraints and exclusions forbid a version set without requiring the
+// package, bundles take the shape of a dependency and exclusive groups become
+// one incompatibility per pair of members.
+func (p *PolicySet) incompatibilities() []*Incompatibility {
+	var incs []*Incompatibility
+	for _, rule := range p.Constraints {
+		forbidden := rule.Versions.Complement()
+		if forbidden.IsEmpty() {
+			continue
+		}
+		incs = append(incs, NewIncompatibilityPolicy(
+			NewTerm(rule.Package, NewVersionSetCondition(forbidden)),
+			p.describe("constraint", rule),
+		))
+	}
+	for _, rule := range p.Exclusions {
+		if rule.Versions.IsEmpty() {
+			continue
+		}
+		incs = append(incs, NewIncompatibilityPolicy(
+			NewTerm(rule.Package, NewVersionSetCondition(rule.Versions)),
+			p.describe("exclusion", rule),
+		))
+	}
+	for _, bundle := range p.Bundles {
+		if bundle.Versions.IsEmpty() || !bundle.Requires.Positive {
+			continue
+		}
+		incs = append(incs, &Incompatibility{
+			Terms: []Term{
+				NewTerm(bundle.Package, NewVersionSetCondition(bundle.Versions)),
+				bundle.Requires.Negate(),
+			},
+			Kind:    KindPolicy,
+			Package: bundle.Package,
+			Reason:  p.describe("bundle", PolicyRule{Reason: bundle.Reason}),
+		})
+	}
+	for _, group := range p.Groups {
+		members := make([]string, len(group.Packages))
+		for i, name := range group.Packages {
+			members[i] = FormatName(name)
+		}
+		reason := p.describe("exclusive group", PolicyRule{Reason: group.Reason})
+		reason = fmt.Sprintf("%s, at most one of %s", reason, strings.Join(members, ", "))
+		for i, a := range group.Packages {
+			for _, b := range group.Packages[i+1:] {
+				if a == b {
+					continue
+				}
+				incs = append(incs, &Incompatibility{
+					Terms: []Term{
+						NewTerm(a, NewVersionSetCondition(FullVersionSet())),
+						NewTerm(b, NewVersionSetCondition(FullVersionSet())),
+					},
+					Kind:    KindPolicy,
+					Package: a,
+					Reason:  reason,
+				})
+			}
+		}
+	}
+	return incs
+}
+
+// describe renders the reason attached to a policy incompatibility.
+func (p *PolicySet) describe(kind string, rule PolicyRule) string {
+	desc := kind
+	if p.Name != "" {
+		desc = fmt.Sprintf("%s %s", p.Name, kind)
+	}
+	if rule.Reason != "" {
+		desc = fmt.Sprintf("%s: %s", desc, rule.Reason)
+	}
+	return desc
+}
+
+// overrideFor returns the replacement for a dependency on pkg, if any. Later
+// policies win over earlier ones.
+func overrideFor(policies []*PolicySet, pkg Name) (VersionSet, bool) {
+	for i := len(policies) - 1; i >= 0; i-- {
+		rules := policies[i].Overrides
+		for j := len(rules) - 1; j >= 0; j-- {
+			if rules[j].Package == pkg {
+				return rules[j].Versions, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// applyOverrides rewrites positive dependencies targeted by an override.
+// deps is copied before modification.
+func applyOverrides(policies []*PolicySet, deps []Term) []Term {
+	var result []Term
+	for i, dep := range deps {
+		versions, ok := overrideFor(policies, dep.Name)
+		if !ok || !dep.Positive {
+			continue
+		}
+		if result == nil {
+			result = append([]Term(nil), deps...)
+		}
+		result[i] = NewTerm(dep.Name, NewVersionSetCondition(versions))
+	}
+	if result == nil {
+		return deps
+	}
+	return result
+}
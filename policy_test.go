@@ -0,0 +1,240 @@
+package pubgrub
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPolicyExclusionAndConstraint(t *testing.T) {
+	source := &InMemorySource{}
+	for _, v := range []string{"2.16.0", "2.17.0", "2.18.0"} {
+		ver, _ := ParseSemanticVersion(v)
+		source.AddPackage(MakeName("log4j"), ver, nil)
+	}
+	app, _ := ParseSemanticVersion("1.0.0")
+	source.AddPackage(MakeName("app"), app, []Term{
+		NewTerm(MakeName("log4j"), NewVersionSetCondition(FullVersionSet())),
+	})
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), EqualsCondition{Version: app})
+
+	policy := NewPolicySet("org").
+		Exclude(MakeName("log4j"), mustParseVersionRange(t, "<2.17.0"), "CVE-2021-44228").
+		Constrain(MakeName("log4j"), mustParseVersionRange(t, "<2.18.0"), "not yet approved")
+
+	solution, err := NewSolverWithOptions([]Source{root, source}, WithPolicy(policy)).Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, _ := solution.GetVersion(MakeName("log4j")); ver == nil || ver.String() != "2.17.0" {
+		t.Fatalf("expected log4j 2.17.0, got %v", ver)
+	}
+}
+
+func TestPolicyConstraintDoesNotRequirePackage(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("a"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("unused"), SimpleVersion("1.0.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("a"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	policy := NewPolicySet("org").Exclude(MakeName("unused"), FullVersionSet(), "banned")
+	solution, err := NewSolverWithOptions([]Source{root, source}, WithPolicy(policy)).Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := solution.GetVersion(MakeName("unused")); ok {
+		t.Fatalf("expected unused package to stay out of the solution")
+	}
+}
+
+func TestPolicyViolationIsReported(t *testing.T) {
+	source := &InMemorySource{}
+	for _, v := range []string{"2.16.0", "2.17.0", "2.18.0"} {
+		ver, _ := ParseSemanticVersion(v)
+		source.AddPackage(MakeName("log4j"), ver, nil)
+	}
+	app, _ := ParseSemanticVersion("1.0.0")
+	source.AddPackage(MakeName("app"), app, []Term{
+		NewTerm(MakeName("log4j"), NewVersionSetCondition(FullVersionSet())),
+	})
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), EqualsCondition{Version: app})
+
+	policy := NewPolicySet("org").Exclude(MakeName("log4j"), FullVersionSet(), "banned library")
+	solver := NewSolverWithOptions([]Source{root, source}, WithPolicy(policy), WithIncompatibilityTracking(true))
+	_, err := solver.Solve(root.Term())
+
+	var nsErr *NoSolutionError
+	if !errors.As(err, &nsErr) {
+		t.Fatalf("expected NoSolutionError, got %v", err)
+	}
+	if !strings.Contains(nsErr.Error(), "forbidden by policy (org exclusion: banned library)") {
+		t.Fatalf("expected policy reason in report, got:\n%s", nsErr.Error())
+	}
+}
+
+func TestPolicyOverrideReplacesTransitiveRequirement(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("minimist"), EqualsCondition{Version: SimpleVersion("1.2.0")}),
+	})
+	source.AddPackage(MakeName("minimist"), SimpleVersion("1.2.0"), nil)
+	source.AddPackage(MakeName("minimist"), SimpleVersion("1.2.6"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	policy := NewPolicySet("org").Override(MakeName("minimist"), mustParseVersionRange(t, ">=1.2.6"), "")
+	solution, err := NewSolverWithOptions([]Source{root, source}, WithPolicy(policy)).Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, _ := solution.GetVersion(MakeName("minimist")); ver == nil || ver.String() != "1.2.6" {
+		t.Fatalf("expected overridden minimist 1.2.6, got %v", ver)
+	}
+}
+
+func TestParsePolicySetYAML(t *testing.T) {
+	data := []byte(`# organization defaults
+name: org-baseline
+constraints:
+  - package: rails
+    versions: ">=7.0.0, <8.0.0"
+    reason: supported major
+exclusions:
+  - package: log4j   # CVE
+    versions: '<2.17.0'
+    reason: "CVE-2021-44228"
+overrides:
+  - package: minimist
+    versions: ">=1.2.6"
+`)
+	policy, err := ParsePolicySetYAML(data, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if policy.Name != "org-baseline" || len(policy.Constraints) != 1 || len(policy.Exclusions) != 1 || len(policy.Overrides) != 1 {
+		t.Fatalf("unexpected policy: %+v", policy)
+	}
+	if got := policy.Constraints[0].Versions.String(); got != ">=7.0.0, <8.0.0" {
+		t.Fatalf("unexpected constraint %s", got)
+	}
+	if rule := policy.Exclusions[0]; rule.Package != MakeName("log4j") || rule.Reason != "CVE-2021-44228" {
+		t.Fatalf("unexpected exclusion %+v", rule)
+	}
+
+	if _, err := ParsePolicySetYAML([]byte("exclusions:\n  - package: x\n    version: \"1\"\n"), nil); err == nil {
+		t.Fatalf("expected unknown rule key to be rejected")
+	}
+	if _, err := ParsePolicySetYAML([]byte("overrides: [a, b]\n"), nil); err == nil {
+		t.Fatalf("expected flow sequence to be rejected")
+	}
+}
+
+func TestParsePolicySetYAMLIndentation(t *testing.T) {
+	// PyYAML's default output starts list items at the key's column.
+	data := []byte(`constraints:
+- package: rails
+  versions: '>=7.0.0, <8.0.0'
+- package: puma
+  versions: '>=6.0.0'
+exclusions:
+- package: log4j
+  versions: <2.17.0
+`)
+	policy, err := ParsePolicySetYAML(data, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(policy.Constraints) != 2 || len(policy.Exclusions) != 1 {
+		t.Fatalf("unexpected policy: %+v", policy)
+	}
+	if rule := policy.Constraints[1]; rule.Package != MakeName("puma") {
+		t.Fatalf("unexpected constraint %+v", rule)
+	}
+
+	for name, data := range map[string]string{
+		"item indentation": "constraints:\n  - package: a\n    versions: '>=1'\n    - package: b\n      versions: '>=1'\n",
+		"key indentation":  "constraints:\n  - package: a\n      versions: '>=1'\n",
+		"item outside":     "- package: a\n",
+	} {
+		if _, err := ParsePolicySetYAML([]byte(data), nil); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestPolicyBundleRequiresCompanion(t *testing.T) {
+	source := &InMemorySource{}
+	for _, v := range []string{"1.0.0", "2.0.0"} {
+		ver, _ := ParseSemanticVersion(v)
+		source.AddPackage(MakeName("core"), ver, nil)
+	}
+	pack, _ := ParseSemanticVersion("1.0.0")
+	source.AddPackage(MakeName("plugin-pack"), pack, nil)
+
+	policy := NewPolicySet("org").Bundle(
+		MakeName("plugin-pack"), FullVersionSet(),
+		NewTerm(MakeName("core"), NewVersionSetCondition(mustParseVersionRange(t, ">=2.0.0"))),
+		"plugins need core 2",
+	)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("plugin-pack"), NewVersionSetCondition(FullVersionSet()))
+	solution, err := NewSolverWithOptions([]Source{root, source}, WithPolicy(policy)).Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, ok := solution.GetVersion(MakeName("core")); !ok || ver.String() != "2.0.0" {
+		t.Fatalf("expected the bundle to pull in core 2.0.0, got %v", solution)
+	}
+
+	root.AddPackage(MakeName("core"), NewVersionSetCondition(mustParseVersionRange(t, "<2.0.0")))
+	_, err = NewSolverWithOptions([]Source{root, source}, WithPolicy(policy), WithIncompatibilityTracking(true)).Solve(root.Term())
+	if err == nil {
+		t.Fatalf("expected the bundle to conflict with core <2.0.0")
+	}
+	if !strings.Contains(err.Error(), "requires core >=2.0.0 by policy (org bundle: plugins need core 2)") {
+		t.Fatalf("expected the bundle in the report, got:\n%v", err)
+	}
+}
+
+func TestPolicyExclusiveGroup(t *testing.T) {
+	anyVersion := NewVersionSetCondition(FullVersionSet())
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("app"), SimpleVersion("2.0.0"), []Term{
+		NewTerm(MakeName("pg"), anyVersion),
+		NewTerm(MakeName("mysql2"), anyVersion),
+	})
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{NewTerm(MakeName("pg"), anyVersion)})
+	for _, pkg := range []string{"pg", "mysql2", "sqlite3"} {
+		source.AddPackage(MakeName(pkg), SimpleVersion("1.0.0"), nil)
+	}
+	drivers := []Name{MakeName("pg"), MakeName("mysql2"), MakeName("sqlite3")}
+	policy := NewPolicySet("org").ExclusiveGroup(drivers, "one database driver")
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), anyVersion)
+	solution, err := NewSolverWithOptions([]Source{root, source}, WithPolicy(policy)).Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, _ := solution.GetVersion(MakeName("app")); ver == nil || ver.String() != "1.0.0" {
+		t.Fatalf("expected app 1.0.0 to avoid installing two drivers, got %v", solution)
+	}
+
+	root.AddPackage(MakeName("sqlite3"), anyVersion)
+	_, err = NewSolverWithOptions([]Source{root, source}, WithPolicy(policy), WithIncompatibilityTracking(true)).Solve(root.Term())
+	if err == nil {
+		t.Fatalf("expected the group to be violated")
+	}
+	want := "cannot both be installed by policy (org exclusive group: one database driver, at most one of pg, mysql2, sqlite3)"
+	if !strings.Contains(err.Error(), want) {
+		t.Fatalf("expected %q in the report, got:\n%v", want, err)
+	}
+}
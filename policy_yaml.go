@@ -0,0 +1,229 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ParsePolicySetYAML loads a PolicySet from YAML of the following shape:
+//
+//	name: org-baseline
+//	constraints:
+//	  - package: rails
+//	    versions: ">=7.0.0, <8.0.0"
+//	    reason: supported major
+//	exclusions:
+//	  - package: log4j
+//	    versions: "<2.17.0"
+//	    reason: CVE-2021-44228
+//	overrides:
+//	  - package: minimist
+//	    versions: ">=1.2.6"
+//
+// Only this block-style subset of YAML is accepted; flow collections,
+// anchors and multi-line scalars are rejected with an error naming the line.
+// List items may be indented under their key or start at its column, as
+// PyYAML writes them, but every item of a list and every key of an item must
+// share one indentation.
+// Versions use the ParseVersionRange syntax and are parsed with parse, or the
+// ParseVersionRange default when parse is nil.
+func ParsePolicySetYAML(data []byte, parse VersionParser) (*PolicySet, error) {
+	if parse == nil {
+		parse = parseRangeVersion
+	}
+
+	policy := &PolicySet{}
+	var section *[]PolicyRule
+	var current map[string]string
+	var currentLine int
+	// itemIndent and fieldIndent are the columns of the section's "- " and
+	// of the keys inside its items, fixed by the first item.
+	itemIndent, fieldIndent := -1, -1
+
+	flush := func() error {
+		if current == nil {
+			return nil
+		}
+		rule, err := policyRuleFromFields(current, parse)
+		if err != nil {
+			return fmt.Errorf("policy line %d: %w", currentLine, err)
+		}
+		*section = append(*section, rule)
+		current = nil
+		return nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		raw := stripYAMLComment(scanner.Text())
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		indent := len(raw) - len(strings.TrimLeft(raw, " "))
+		line := strings.TrimSpace(raw)
+		item, isItem := strings.CutPrefix(line, "- ")
+
+		if indent == 0 && (!isItem || section == nil) {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			if isItem {
+				return nil, fmt.Errorf("policy line %d: list item outside a section", lineNo)
+			}
+			itemIndent, fieldIndent = -1, -1
+			key, value, err := splitYAMLPair(line)
+			if err != nil {
+				return nil, fmt.Errorf("policy line %d: %w", lineNo, err)
+			}
+			switch key {
+			case "name":
+				policy.Name = value
+				section = nil
+			case "constraints":
+				section = &policy.Constraints
+			case "exclusions":
+				section = &policy.Exclusions
+			case "overrides":
+				section = &policy.Overrides
+			default:
+				return nil, fmt.Errorf("policy line %d: unknown key %q", lineNo, key)
+			}
+			if section != nil && value != "" {
+				return nil, fmt.Errorf("policy line %d: %s must be a list", lineNo, key)
+			}
+			continue
+		}
+
+		if section == nil {
+			return nil, fmt.Errorf("policy line %d: unexpected indented line", lineNo)
+		}
+		if isItem {
+			if itemIndent < 0 {
+				itemIndent = indent
+			}
+			if indent != itemIndent {
+				return nil, fmt.Errorf("policy line %d: list item indented %d spaces, expected %d", lineNo, indent, itemIndent)
+			}
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			current = make(map[string]string)
+			currentLine = lineNo
+			fieldIndent = indent + len(line) - len(strings.TrimLeft(item, " "))
+			line = strings.TrimSpace(item)
+		} else if current == nil {
+			return nil, fmt.Errorf("policy line %d: expected list item", lineNo)
+		} else if indent != fieldIndent {
+			return nil, fmt.Errorf("policy line %d: key indented %d spaces, expected %d", lineNo, indent, fieldIndent)
+		}
+
+		key, value, err := splitYAMLPair(line)
+		if err != nil {
+			return nil, fmt.Errorf("policy line %d: %w", lineNo, err)
+		}
+		if _, dup := current[key]; dup {
+			return nil, fmt.Errorf("policy line %d: duplicate key %q", lineNo, key)
+		}
+		current[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func policyRuleFromFields(fields map[string]string, parse VersionParser) (PolicyRule, error) {
+	for key := range fields {
+		switch key {
+		case "package", "versions", "reason":
+		default:
+			return PolicyRule{}, fmt.Errorf("unknown rule key %q", key)
+		}
+	}
+	if fields["package"] == "" {
+		return PolicyRule{}, fmt.Errorf("rule is missing package")
+	}
+	if fields["versions"] == "" {
+		return PolicyRule{}, fmt.Errorf("rule for %s is missing versions", fields["package"])
+	}
+	versions, err := parseVersionRangeWith(fields["versions"], parse)
+	if err != nil {
+		return PolicyRule{}, fmt.Errorf("rule for %s: %w", fields["package"], err)
+	}
+	return PolicyRule{
+		Package:  MakeName(fields["package"]),
+		Versions: versions,
+		Reason:   fields["reason"],
+	}, nil
+}
+
+// splitYAMLPair splits "key: value" and unquotes the value.
+func splitYAMLPair(line string) (string, string, error) {
+	key, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return "", "", fmt.Errorf("expected key: value, got %q", line)
+	}
+	key = strings.TrimSpace(key)
+	value = strings.TrimSpace(value)
+	if key == "" {
+		return "", "", fmt.Errorf("missing key in %q", line)
+	}
+
+	switch {
+	case strings.HasPrefix(value, `"`):
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid quoted value %s", value)
+		}
+		value = unquoted
+	case strings.HasPrefix(value, "'"):
+		if len(value) < 2 || !strings.HasSuffix(value, "'") {
+			return "", "", fmt.Errorf("invalid quoted value %s", value)
+		}
+		value = strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	case strings.HasPrefix(value, "[") || strings.HasPrefix(value, "{") ||
+		strings.HasPrefix(value, "&") || strings.HasPrefix(value, "*") ||
+		slices.Contains([]string{"|", "|-", "|+", ">", ">-", ">+"}, value):
+		return "", "", fmt.Errorf("unsupported YAML value %q", value)
+	}
+	return key, value, nil
+}
+
+// stripYAMLComment removes a trailing comment outside of quotes.
+func stripYAMLComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#' && (i == 0 || line[i-1] == ' '):
+			return strings.TrimRight(line[:i], " ")
+		}
+	}
+	return strings.TrimRight(line, " ")
+}
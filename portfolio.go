@@ -0,0 +1,117 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"context"
+	"errors"
+)
+
+// SolverConfig names a set of options raced by SolvePortfolio.
+type SolverConfig struct {
+	Name    string
+	Options []SolverOption
+}
+
+// DefaultPortfolio returns a small set of configurations that explore the
+// search space in different orders.
+func DefaultPortfolio() []SolverConfig {
+	return []SolverConfig{
+		{Name: "lookahead"},
+		{Name: "newest", Options: []SolverOption{WithVersionStrategy(VersionNewest)}},
+		{Name: "bucketed", Options: []SolverOption{WithVersionBucketing(true)}},
+	}
+}
+
+// PortfolioResult reports the outcome of the configuration that finished
+// first.
+type PortfolioResult struct {
+	Solution Solution
+	// Config is the name of the winning configuration.
+	Config string
+	// Stats are the statistics of the winning solve.
+	Stats SolveStats
+}
+
+type portfolioOutcome struct {
+	config   string
+	solution Solution
+	stats    SolveStats
+	learned  []*Incompatibility
+	err      error
+}
+
+// SolvePortfolio races several configurations against the same problem on
+// separate goroutines and returns the first conclusive answer, cancelling the
+// others. A solution from any configuration is accepted, and so is a proof
+// that no solution exists, since every configuration solves the same problem.
+// Inconclusive failures such as ErrIterationLimit only win when every
+// configuration fails; the first such error is returned.
+//
+// Each configuration's options are layered over the solver's own. The Source
+// is shared between goroutines and must be safe for concurrent use.
+// Configurations default to DefaultPortfolio when configs is empty.
+//
+// Experimental: SolvePortfolio, SolverConfig, PortfolioResult and the
+// contents of DefaultPortfolio may change in a minor release.
+//
+// Example:
+//
+//	result, err := solver.SolvePortfolio(ctx, root.Term(), []SolverConfig{
+//	    {Name: "lookahead"},
+//	    {Name: "newest", Options: []SolverOption{WithVersionStrategy(VersionNewest)}},
+//	})
+//	fmt.Println("won by", result.Config)
+func (s *Solver) SolvePortfolio(ctx context.Context, root Term, configs []SolverConfig) (PortfolioResult, error) {
+	if len(configs) == 0 {
+		configs = DefaultPortfolio()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	outcomes := make(chan portfolioOutcome, len(configs))
+	for _, cfg := range configs {
+		solver := s.With(cfg.Options...)
+		go func() {
+			solution, err := solver.SolveContext(ctx, root)
+			outcomes <- portfolioOutcome{
+				config:   cfg.Name,
+				solution: solution,
+				stats:    solver.Stats(),
+				learned:  solver.learned,
+				err:      err,
+			}
+		}()
+	}
+
+	var firstErr error
+	for range configs {
+		out := <-outcomes
+		if out.err == nil || errors.Is(out.err, ErrNoSolution) {
+			s.stats = out.stats
+			s.learned = out.learned
+			return PortfolioResult{Solution: out.solution, Config: out.config, Stats: out.stats}, out.err
+		}
+		if firstErr == nil && !errors.Is(out.err, context.Canceled) {
+			firstErr = out.err
+		}
+	}
+
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+	return PortfolioResult{}, firstErr
+}
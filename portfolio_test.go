@@ -0,0 +1,63 @@
+package pubgrub
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSolvePortfolioReturnsFirstSolution(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("a"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("b"), NewVersionSetCondition(FullVersionSet())),
+	})
+	source.AddPackage(MakeName("b"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("b"), SimpleVersion("2.0.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("a"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	solver := NewSolver(root, source)
+	result, err := solver.SolvePortfolio(context.Background(), root.Term(), []SolverConfig{
+		{Name: "stuck", Options: []SolverOption{WithMaxSteps(1)}},
+		{Name: "oldest", Options: []SolverOption{WithVersionStrategy(VersionOldest)}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Config != "oldest" {
+		t.Fatalf("expected the only conclusive configuration to win, got %q", result.Config)
+	}
+	if ver, _ := result.Solution.GetVersion(MakeName("b")); ver == nil || ver.String() != "1.0.0" {
+		t.Fatalf("expected oldest strategy to pick b 1.0.0, got %v", ver)
+	}
+}
+
+func TestSolvePortfolioAllInconclusive(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("a"), SimpleVersion("1.0.0"), nil)
+	root := NewRootSource()
+	root.AddPackage(MakeName("a"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	_, err := NewSolver(root, source).SolvePortfolio(context.Background(), root.Term(), []SolverConfig{
+		{Name: "one", Options: []SolverOption{WithMaxSteps(1)}},
+		{Name: "two", Options: []SolverOption{WithMaxSteps(1)}},
+	})
+	var limit ErrIterationLimit
+	if !errors.As(err, &limit) {
+		t.Fatalf("expected iteration limit error, got %v", err)
+	}
+}
+
+func TestSolveContextCancelled(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("a"), SimpleVersion("1.0.0"), nil)
+	root := NewRootSource()
+	root.AddPackage(MakeName("a"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewSolver(root, source).SolveContext(ctx, root.Term()); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
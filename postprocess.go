@@ -0,0 +1,112 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"slices"
+	"strings"
+)
+
+// PostProcessor shapes a solution before Solve returns it, for output
+// concerns every caller would otherwise repeat, such as ordering, dropping
+// the root package or annotating versions. Processors configured with
+// WithPostProcessors run in order on every successful solve.
+//
+// Process receives the name of the root package the solve started from and
+// a solution it owns and may modify in place; the returned solution is
+// passed to the next processor. An error fails the solve with that error.
+type PostProcessor interface {
+	Process(root Name, sol Solution) (Solution, error)
+}
+
+// PostProcessorFunc adapts a function to the PostProcessor interface.
+//
+// Example:
+//
+//	dropDev := PostProcessorFunc(func(_ Name, sol Solution) (Solution, error) {
+//	    return slices.DeleteFunc(sol, func(nv NameVersion) bool {
+//	        return strings.HasSuffix(nv.Name.Value(), "-dev")
+//	    }), nil
+//	})
+type PostProcessorFunc func(root Name, sol Solution) (Solution, error)
+
+// Process implements PostProcessor.
+func (f PostProcessorFunc) Process(root Name, sol Solution) (Solution, error) {
+	return f(root, sol)
+}
+
+// SortSolution returns a PostProcessor ordering the solution by package
+// name, so output such as lockfiles does not depend on decision order.
+func SortSolution() PostProcessor {
+	return PostProcessorFunc(func(_ Name, sol Solution) (Solution, error) {
+		slices.SortStableFunc(sol, func(a, b NameVersion) int {
+			return strings.Compare(a.Name.Value(), b.Name.Value())
+		})
+		return sol, nil
+	})
+}
+
+// StripRoot returns a PostProcessor removing the root package, leaving only
+// the packages to install.
+func StripRoot() PostProcessor {
+	return PostProcessorFunc(func(root Name, sol Solution) (Solution, error) {
+		return slices.DeleteFunc(sol, func(nv NameVersion) bool { return nv.Name == root }), nil
+	})
+}
+
+// FillProvenance returns a PostProcessor setting NameVersion.Source, where
+// the solver left it empty, to the name source gives for the version as a
+// ProvenanceSource or NamedSource. Use it when the named source is not one
+// of the solver's sources but wraps them, so the solver cannot see it.
+func FillProvenance(source Source) PostProcessor {
+	return PostProcessorFunc(func(_ Name, sol Solution) (Solution, error) {
+		for i, nv := range sol {
+			if nv.Source == "" {
+				sol[i].Source = sourceOrigin(source, nv.Name, nv.Version)
+			}
+		}
+		return sol, nil
+	})
+}
+
+// AnnotateSolution returns a PostProcessor replacing the Metadata of every
+// resolved version with what annotate returns for it, such as its license.
+// annotate sees the version's current Metadata and may wrap it.
+//
+// Example:
+//
+//	licenses := AnnotateSolution(func(nv NameVersion) any {
+//	    return registry.License(nv.Name, nv.Version)
+//	})
+//	solver := NewSolverWithOptions([]Source{root, registry}, WithPostProcessors(StripRoot(), licenses))
+func AnnotateSolution(annotate func(nv NameVersion) any) PostProcessor {
+	return PostProcessorFunc(func(_ Name, sol Solution) (Solution, error) {
+		for i, nv := range sol {
+			sol[i].Metadata = annotate(nv)
+		}
+		return sol, nil
+	})
+}
+
+// postProcess runs the configured post-processors on sol.
+func (s *Solver) postProcess(root Name, sol Solution) (Solution, error) {
+	var err error
+	for _, processor := range s.options.PostProcessors {
+		if sol, err = processor.Process(root, sol); err != nil {
+			return nil, err
+		}
+	}
+	return sol, nil
+}
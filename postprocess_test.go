@@ -0,0 +1,124 @@
+package pubgrub
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func solutionNames(sol Solution) []string {
+	names := make([]string, len(sol))
+	for i, nv := range sol {
+		names[i] = nv.Name.Value()
+	}
+	return names
+}
+
+func TestPostProcessorsShapeSolution(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("zlib"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("zlib"), NewVersionSetCondition(FullVersionSet())),
+	})
+	source.AddPackage(MakeName("json"), SimpleVersion("2.0.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), NewVersionSetCondition(FullVersionSet()))
+	root.AddPackage(MakeName("json"), NewVersionSetCondition(FullVersionSet()))
+
+	license := AnnotateSolution(func(nv NameVersion) any { return "MIT" })
+
+	solver := NewSolverWithOptions([]Source{root, source},
+		WithPostProcessors(StripRoot(), SortSolution()),
+		WithPostProcessors(license),
+	)
+	solution, err := solver.Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := solutionNames(solution), []string{"app", "json", "zlib"}; !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for _, nv := range solution {
+		if nv.Metadata != "MIT" {
+			t.Fatalf("expected %s to be annotated, got %v", nv, nv.Metadata)
+		}
+	}
+}
+
+func TestPostProcessorErrorFailsSolve(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("json"), SimpleVersion("2.0.0"), nil)
+	root := NewRootSource()
+	root.AddPackage(MakeName("json"), NewVersionSetCondition(FullVersionSet()))
+
+	rejected := errors.New("license not approved")
+	solver := NewSolverWithOptions([]Source{root, source},
+		WithPostProcessors(PostProcessorFunc(func(Name, Solution) (Solution, error) {
+			return nil, rejected
+		})),
+	)
+	if _, err := solver.Solve(root.Term()); !errors.Is(err, rejected) {
+		t.Fatalf("expected the processor's error, got %v", err)
+	}
+}
+
+func TestPostProcessorsRunOnceOnDecomposedSolve(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("zlib"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("zlib"), NewVersionSetCondition(FullVersionSet())),
+	})
+	source.AddPackage(MakeName("json"), SimpleVersion("2.0.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), NewVersionSetCondition(FullVersionSet()))
+	root.AddPackage(MakeName("json"), NewVersionSetCondition(FullVersionSet()))
+
+	runs := 0
+	count := PostProcessorFunc(func(_ Name, sol Solution) (Solution, error) {
+		runs++
+		return sol, nil
+	})
+
+	solver := NewSolverWithOptions([]Source{source}, WithPostProcessors(StripRoot(), SortSolution(), count))
+	solution, err := solver.SolveDecomposed(context.Background(), *root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if runs != 1 {
+		t.Fatalf("expected one post-processing run, got %d", runs)
+	}
+	if got, want := solutionNames(solution), []string{"app", "json", "zlib"}; !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestFillProvenance(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("zlib"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("zlib"), NewVersionSetCondition(FullVersionSet())),
+	})
+	source.AddPackage(MakeName("json"), SimpleVersion("2.0.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), NewVersionSetCondition(FullVersionSet()))
+	root.AddPackage(MakeName("json"), NewVersionSetCondition(FullVersionSet()))
+
+	mirror := NewMirrorSource(Mirror{Name: "internal", Source: source})
+
+	solver := NewSolverWithOptions([]Source{root, source},
+		WithPostProcessors(StripRoot(), FillProvenance(mirror)),
+	)
+	solution, err := solver.Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, nv := range solution {
+		if nv.Source != "internal" {
+			t.Fatalf("expected %s to be attributed to the mirror, got %q", nv, nv.Source)
+		}
+	}
+}
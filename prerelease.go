@@ -0,0 +1,90 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+// PrereleasePolicy decides which prerelease versions inside an allowed range
+// may be picked. Ecosystems differ: npm only considers prereleases of a
+// release the range explicitly mentions, and RubyGems only considers
+// prereleases when the requirement names one.
+type PrereleasePolicy int
+
+const (
+	// PrereleaseInRange allows every prerelease the range contains. This is
+	// the default.
+	PrereleaseInRange PrereleasePolicy = iota
+	// PrereleaseMentioned follows node-semver: a prerelease such as
+	// 1.0.0-beta.2 is a candidate only when a bound of the package's allowed
+	// range is itself a prerelease of 1.0.0, as in ">=1.0.0-alpha.1, <1.0.0".
+	// Ranges such as ">=0.9.0" never select a prerelease. GemVersions
+	// follow RubyGems instead: their prereleases are candidates only when a
+	// bound is itself a GemVersion prerelease, as in ">= 2.1.0.pre1". Other
+	// Version types are unaffected.
+	PrereleaseMentioned
+)
+
+// prereleaseSet wraps an allowed set so Contains applies PrereleaseMentioned.
+// The other VersionSet methods are those of the wrapped set.
+type prereleaseSet struct {
+	VersionSet
+	// mentioned holds the major.minor.patch of each prerelease bound.
+	mentioned map[[3]int]bool
+	// gemPrereleases is set when a bound is a GemVersion prerelease.
+	gemPrereleases bool
+}
+
+// Contains reports whether the wrapped set contains version and, for a
+// prerelease, whether its release is mentioned by a bound.
+func (s prereleaseSet) Contains(version Version) bool {
+	if !s.VersionSet.Contains(version) {
+		return false
+	}
+	switch v := version.(type) {
+	case *SemanticVersion:
+		return v.Prerelease == "" || s.mentioned[[3]int{v.Major, v.Minor, v.Patch}]
+	case *GemVersion:
+		return !v.prerelease() || s.gemPrereleases
+	default:
+		return true
+	}
+}
+
+// candidateSet returns the set pickVersion filters candidates with, applying
+// the configured PrereleasePolicy to allowed.
+func (st *solverState) candidateSet(allowed VersionSet) VersionSet {
+	if st.options.PrereleasePolicy != PrereleaseMentioned {
+		return allowed
+	}
+	set := prereleaseSet{VersionSet: allowed, mentioned: make(map[[3]int]bool)}
+	mention := func(bound versionBound) {
+		if !bound.isFinite() {
+			return
+		}
+		switch v := bound.version.(type) {
+		case *SemanticVersion:
+			if v.Prerelease != "" {
+				set.mentioned[[3]int{v.Major, v.Minor, v.Patch}] = true
+			}
+		case *GemVersion:
+			if v.prerelease() {
+				set.gemPrereleases = true
+			}
+		}
+	}
+	for _, interval := range asIntervalSet(allowed).intervals {
+		mention(interval.lower)
+		mention(interval.upper)
+	}
+	return set
+}
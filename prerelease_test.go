@@ -0,0 +1,67 @@
+package pubgrub
+
+import (
+	"errors"
+	"testing"
+)
+
+func solveLib(t *testing.T, constraint string, opts ...SolverOption) (string, error) {
+	t.Helper()
+	source := &InMemorySource{}
+	for _, raw := range []string{"0.9.0", "0.9.1-rc.1", "1.0.0-alpha.1", "1.0.0-beta.2", "1.0.0", "1.1.0-rc.1"} {
+		source.AddPackage(MakeName("lib"), mustSemver(t, raw), nil)
+	}
+	root := NewRootSource()
+	root.AddPackage(MakeName("lib"), NewVersionSetCondition(mustParseVersionRange(t, constraint)))
+	solver := NewSolverWithOptions([]Source{root, source}, opts...)
+	solution, err := solver.Solve(root.Term())
+	if err != nil {
+		return "", err
+	}
+	ver, ok := solution.GetVersion(MakeName("lib"))
+	if !ok {
+		t.Fatalf("lib missing from %v", solution)
+	}
+	return ver.String(), nil
+}
+
+func TestPrereleaseInRangeIsDefault(t *testing.T) {
+	got, err := solveLib(t, ">=0.9.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "1.1.0-rc.1" {
+		t.Fatalf("expected the newest prerelease in range, got %s", got)
+	}
+}
+
+func TestPrereleaseMentionedChannels(t *testing.T) {
+	mentioned := WithPrereleasePolicy(PrereleaseMentioned)
+	cases := []struct {
+		constraint string
+		want       string
+	}{
+		{">=1.0.0-alpha.1, <1.0.0", "1.0.0-beta.2"},
+		{">=0.9.0", "1.0.0"},
+		{">=0.9.0, <1.0.0", "0.9.0"},
+		{">=0.9.1-rc.1, <1.0.0", "0.9.1-rc.1"},
+		{">=0.9.1-rc.1", "1.0.0"},
+	}
+	for _, tc := range cases {
+		got, err := solveLib(t, tc.constraint, mentioned)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.constraint, err)
+		}
+		if got != tc.want {
+			t.Fatalf("%s: expected %s, got %s", tc.constraint, tc.want, got)
+		}
+	}
+}
+
+func TestPrereleaseMentionedRejectsUnmentionedOnly(t *testing.T) {
+	_, err := solveLib(t, ">=1.0.1", WithPrereleasePolicy(PrereleaseMentioned))
+	var noSolution ErrNoSolutionFound
+	if !errors.As(err, &noSolution) {
+		t.Fatalf("expected ErrNoSolutionFound, got %v", err)
+	}
+}
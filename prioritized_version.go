@@ -0,0 +1,76 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import "fmt"
+
+// PrioritizedVersion pairs any Version with an integer priority, or epoch,
+// that is compared before the version itself. Distributions use it to force
+// hotfix builds above upstream releases without writing a custom Version
+// type: a higher priority always sorts higher, and versions with equal
+// priority fall back to the wrapped ordering.
+//
+// A Version that is not a PrioritizedVersion compares as priority 0, so only
+// the overridden releases need wrapping. Because other Version types do not
+// know about priorities, wrap the bounds of constraints on such packages too.
+//
+// Example:
+//
+//	upstream := NewPrioritizedVersion(NewSemanticVersion(2, 0, 0), 0)
+//	hotfix := NewPrioritizedVersion(NewSemanticVersion(1, 4, 1), 1)
+//	fmt.Println(hotfix.Sort(upstream) > 0) // true
+//	fmt.Println(hotfix)                    // 1:1.4.1
+type PrioritizedVersion struct {
+	Version  Version
+	Priority int
+}
+
+// NewPrioritizedVersion wraps version with the given priority.
+func NewPrioritizedVersion(version Version, priority int) PrioritizedVersion {
+	return PrioritizedVersion{Version: version, Priority: priority}
+}
+
+// Sort compares priorities first and the wrapped versions second.
+func (v PrioritizedVersion) Sort(other Version) int {
+	o, ok := other.(PrioritizedVersion)
+	if !ok {
+		if ptr, isPtr := other.(*PrioritizedVersion); isPtr && ptr != nil {
+			o = *ptr
+		} else {
+			o = PrioritizedVersion{Version: other}
+		}
+	}
+
+	if v.Priority != o.Priority {
+		if v.Priority < o.Priority {
+			return -1
+		}
+		return 1
+	}
+	return v.Version.Sort(o.Version)
+}
+
+// String renders the version in epoch notation, "priority:version", omitting
+// a zero priority.
+func (v PrioritizedVersion) String() string {
+	if v.Priority == 0 {
+		return v.Version.String()
+	}
+	return fmt.Sprintf("%d:%s", v.Priority, v.Version)
+}
+
+var (
+	_ Version = PrioritizedVersion{}
+)
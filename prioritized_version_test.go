@@ -0,0 +1,46 @@
+package pubgrub
+
+import "testing"
+
+func TestPrioritizedVersionOrdering(t *testing.T) {
+	upstream := NewPrioritizedVersion(NewSemanticVersion(2, 0, 0), 0)
+	hotfix := NewPrioritizedVersion(NewSemanticVersion(1, 4, 1), 1)
+	plain := NewSemanticVersion(1, 9, 0)
+
+	if hotfix.Sort(upstream) <= 0 || upstream.Sort(hotfix) >= 0 {
+		t.Fatalf("expected priority to order %s above %s", hotfix, upstream)
+	}
+	if upstream.Sort(plain) <= 0 {
+		t.Fatalf("expected equal priorities to fall back to version order")
+	}
+	if hotfix.Sort(&hotfix) != 0 {
+		t.Fatalf("expected a pointer to compare equal to its value")
+	}
+	if got := hotfix.String(); got != "1:1.4.1" {
+		t.Fatalf("expected epoch notation, got %q", got)
+	}
+	if got := upstream.String(); got != "2.0.0" {
+		t.Fatalf("expected zero priority to be omitted, got %q", got)
+	}
+}
+
+func TestSolverPrefersPrioritizedHotfix(t *testing.T) {
+	lib := MakeName("lib")
+	hotfix := NewPrioritizedVersion(NewSemanticVersion(1, 4, 1), 1)
+
+	source := &InMemorySource{}
+	source.AddPackage(lib, NewPrioritizedVersion(NewSemanticVersion(1, 4, 0), 0), nil)
+	source.AddPackage(lib, NewPrioritizedVersion(NewSemanticVersion(2, 0, 0), 0), nil)
+	source.AddPackage(lib, hotfix, nil)
+
+	root := NewRootSource()
+	root.AddPackage(lib, NewVersionSetCondition(FullVersionSet()))
+
+	solution, err := NewSolver(root, source).Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, ok := solution.GetVersion(lib); !ok || ver.Sort(hotfix) != 0 {
+		t.Fatalf("expected hotfix %s, got %v", hotfix, ver)
+	}
+}
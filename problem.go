@@ -0,0 +1,163 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Problem is a self-contained resolution problem: root requirements plus the
+// answers a Source gave about every package a solve consulted. It is the
+// problem-file format written by RecordingSource, and replays a solve without
+// the original registry, so a failure seen against a live registry becomes a
+// hermetic test fixture.
+//
+// Constraints use the TermRecord form of MarshalIncompatibilities. Conditions
+// that do not convert to a VersionSet, such as unresolved TagConditions, are
+// written with their String form and cannot be replayed.
+type Problem struct {
+	Root     []TermRecord    `json:"root"`
+	Packages []PackageRecord `json:"packages"`
+}
+
+// PackageRecord is what a Source answered about one package.
+type PackageRecord struct {
+	Name string `json:"name"`
+	// Missing marks a package the source reported as not found.
+	Missing bool `json:"missing,omitempty"`
+	// Error is the message of any other error listing the versions.
+	Error    string          `json:"error,omitempty"`
+	Versions []VersionRecord `json:"versions,omitempty"`
+}
+
+// VersionRecord is a published version and, once fetched, its dependencies.
+type VersionRecord struct {
+	Version      string       `json:"version"`
+	Dependencies []TermRecord `json:"dependencies,omitempty"`
+	// Unfetched marks a version whose dependencies were never requested.
+	Unfetched bool `json:"unfetched,omitempty"`
+	// Error is the message of an error fetching the dependencies.
+	Error string `json:"error,omitempty"`
+}
+
+// MarshalProblem serializes a problem to indented JSON.
+//
+// Example:
+//
+//	data, err := MarshalProblem(recorder.Problem(*root))
+//	os.WriteFile("testdata/issue-123.json", data, 0o644)
+func MarshalProblem(p *Problem) ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// UnmarshalProblem decodes a problem written by MarshalProblem.
+func UnmarshalProblem(data []byte) (*Problem, error) {
+	var p Problem
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("decode problem: %w", err)
+	}
+	return &p, nil
+}
+
+// Sources rebuilds the recorded problem as a RootSource and a Source that
+// replays the recorded answers. Versions are parsed with parse, or with the
+// fallback of ParseVersionRange when parse is nil; use the parser matching
+// the original Source. Dependencies of unfetched versions are reported as
+// errors, since the original answer is unknown.
+//
+// Example:
+//
+//	problem, _ := UnmarshalProblem(data)
+//	root, source, err := problem.Sources(nil)
+//	_, err = NewSolver(root, source).Solve(root.Term())
+func (p *Problem) Sources(parse VersionParser) (*RootSource, Source, error) {
+	if parse == nil {
+		parse = parseRangeVersion
+	}
+
+	root := NewRootSource()
+	for _, tr := range p.Root {
+		term, err := termFromRecord(tr, parse)
+		if err != nil {
+			return nil, nil, fmt.Errorf("root: %w", err)
+		}
+		*root = append(*root, term)
+	}
+
+	replay := &replaySource{errors: make(map[string]error)}
+	for _, pkg := range p.Packages {
+		name := MakeName(pkg.Name)
+		switch {
+		case pkg.Missing:
+			continue
+		case pkg.Error != "":
+			replay.errors[pkg.Name] = errors.New(pkg.Error)
+			continue
+		}
+		if replay.versions.Packages == nil {
+			replay.versions.Packages = make(map[Name]map[Version][]Term)
+		}
+		replay.versions.Packages[name] = make(map[Version][]Term, len(pkg.Versions))
+		for _, vr := range pkg.Versions {
+			version, err := parse(vr.Version)
+			if err != nil {
+				return nil, nil, fmt.Errorf("package %s: version %q: %w", pkg.Name, vr.Version, err)
+			}
+			deps := make([]Term, 0, len(vr.Dependencies))
+			for _, tr := range vr.Dependencies {
+				term, err := termFromRecord(tr, parse)
+				if err != nil {
+					return nil, nil, fmt.Errorf("package %s %s: %w", pkg.Name, vr.Version, err)
+				}
+				deps = append(deps, term)
+			}
+			replay.versions.AddPackage(name, version, deps)
+			key := dependencyScoreKey(name, version)
+			switch {
+			case vr.Error != "":
+				replay.errors[key] = errors.New(vr.Error)
+			case vr.Unfetched:
+				replay.errors[key] = fmt.Errorf("dependencies of %s %s were not recorded", FormatName(name), version)
+			}
+		}
+	}
+	return root, replay, nil
+}
+
+// replaySource answers from a recorded Problem.
+type replaySource struct {
+	versions InMemorySource
+	errors   map[string]error // Recorded failures by package or "name@version"
+}
+
+// GetVersions implements Source.
+func (s *replaySource) GetVersions(name Name) ([]Version, error) {
+	if err, ok := s.errors[name.Value()]; ok {
+		return nil, err
+	}
+	return s.versions.GetVersions(name)
+}
+
+// GetDependencies implements Source.
+func (s *replaySource) GetDependencies(name Name, version Version) ([]Term, error) {
+	if err, ok := s.errors[dependencyScoreKey(name, version)]; ok {
+		return nil, err
+	}
+	return s.versions.GetDependencies(name, version)
+}
+
+var _ Source = (*replaySource)(nil)
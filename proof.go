@@ -0,0 +1,91 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+// minimalProof rewrites the derivation of incomp so every derived
+// incompatibility is explained by the known derivation of the same
+// incompatibility (same ID) with the fewest leaves. Conflict resolution
+// often learns a clause several times along different paths, and the one
+// that ends up in the root cause is the first found, not the shortest.
+// known lists the incompatibilities of the solve; their derivations are
+// searched too. The input trees are not modified.
+func minimalProof(incomp *Incompatibility, known []*Incompatibility) *Incompatibility {
+	if incomp == nil {
+		return nil
+	}
+
+	leaves := make(map[*Incompatibility]int)
+	best := make(map[string]*Incompatibility)
+	var visit func(inc *Incompatibility) int
+	visit = func(inc *Incompatibility) int {
+		if n, ok := leaves[inc]; ok {
+			return n
+		}
+		n := 1
+		if inc.Cause1 != nil && inc.Cause2 != nil {
+			n = visit(inc.Cause1) + visit(inc.Cause2)
+		}
+		leaves[inc] = n
+		if inc.Cause1 != nil && inc.Cause2 != nil {
+			id := inc.ID()
+			if current, ok := best[id]; !ok || n < leaves[current] {
+				best[id] = inc
+			}
+		}
+		return n
+	}
+	visit(incomp)
+	for _, inc := range known {
+		visit(inc)
+	}
+
+	rebuilt := make(map[*Incompatibility]*Incompatibility)
+	var rebuild func(inc *Incompatibility) *Incompatibility
+	rebuild = func(inc *Incompatibility) *Incompatibility {
+		if inc.Cause1 == nil || inc.Cause2 == nil {
+			return inc
+		}
+		if done, ok := rebuilt[inc]; ok {
+			return done
+		}
+		rebuilt[inc] = inc // Guards against revisiting while in progress
+		chosen := inc
+		if shorter := best[inc.ID()]; shorter != nil && leaves[shorter] < leaves[inc] {
+			chosen = shorter
+		}
+		cause1, cause2 := rebuild(chosen.Cause1), rebuild(chosen.Cause2)
+		result := chosen
+		if cause1 != chosen.Cause1 || cause2 != chosen.Cause2 {
+			copied := *chosen
+			copied.Cause1, copied.Cause2 = cause1, cause2
+			result = &copied
+		}
+		rebuilt[inc] = result
+		return result
+	}
+	return rebuild(incomp)
+}
+
+// proofLeaves counts the external incompatibilities of a derivation tree,
+// counting shared subtrees once per use as the reporters print them.
+func proofLeaves(inc *Incompatibility) int {
+	if inc == nil {
+		return 0
+	}
+	if inc.Cause1 == nil || inc.Cause2 == nil {
+		return 1
+	}
+	return proofLeaves(inc.Cause1) + proofLeaves(inc.Cause2)
+}
@@ -0,0 +1,52 @@
+package pubgrub
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMinimalProofPrefersShorterDerivation(t *testing.T) {
+	leaf := func(name string) *Incompatibility {
+		return NewIncompatibilityNoVersions(NewTerm(MakeName(name), nil))
+	}
+	lib := []Term{NewTerm(MakeName("lib"), nil)}
+
+	// Two derivations of "lib is forbidden": one with three leaves, one
+	// with two.
+	long := NewIncompatibilityConflict(lib,
+		NewIncompatibilityConflict([]Term{NewTerm(MakeName("a"), nil)}, leaf("a"), leaf("b")),
+		leaf("c"))
+	short := NewIncompatibilityConflict(lib, leaf("d"), leaf("e"))
+	root := NewIncompatibilityConflict([]Term{NewTerm(MakeName("$$root"), nil)}, long, leaf("app"))
+
+	minimal := minimalProof(root, []*Incompatibility{short})
+	if got := proofLeaves(minimal); got != 3 {
+		t.Fatalf("expected the proof to shrink to 3 leaves, got %d", got)
+	}
+	if minimal.Cause1 != short {
+		t.Fatalf("expected the shorter derivation to be used, got %s", minimal.Cause1)
+	}
+	if root.Cause1 != long || proofLeaves(root) != 4 {
+		t.Fatalf("expected the original proof to stay unchanged")
+	}
+	if minimalProof(short, []*Incompatibility{long}) != short {
+		t.Fatalf("expected an already minimal proof to be returned as is")
+	}
+}
+
+func TestWithMinimalProofNeverLengthensErrors(t *testing.T) {
+	root, source := unsolvableWideWorkload(t, 4)
+
+	leaves := func(opts ...SolverOption) int {
+		_, err := NewSolver(root, source).Solve(root.Term(), append([]SolverOption{WithIncompatibilityTracking(true)}, opts...)...)
+		var noSolution *NoSolutionError
+		if !errors.As(err, &noSolution) {
+			t.Fatalf("expected NoSolutionError, got %v", err)
+		}
+		return proofLeaves(noSolution.Incompatibility)
+	}
+
+	if plain, minimal := leaves(), leaves(WithMinimalProof(true)); minimal > plain {
+		t.Fatalf("minimal proof has %d leaves, default has %d", minimal, plain)
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// Propagate runs unit propagation over requirements without making any
+// decisions and returns the tightened allowed version set of every package
+// touched by it. It is intended for cheap "is this combination obviously
+// impossible?" checks, for example in manifest editors, where a full solve
+// would be too slow.
+//
+// When propagation alone proves the requirements unsatisfiable, the returned
+// incompatibility explains why and the map is nil. A nil incompatibility does
+// not guarantee a solution exists; only Solve can answer that.
+//
+// Requirements whose allowed set contains no published version are reported
+// as conflicts as well. Source errors other than missing packages are
+// returned as errors.
+//
+// Example:
+//
+//	allowed, conflict, err := Propagate(requirements, source)
+//	if conflict != nil {
+//	    fmt.Println(NewNoSolutionError(conflict))
+//	}
+func Propagate(requirements []Term, source Source, opts ...SolverOption) (map[Name]VersionSet, *Incompatibility, error) {
+	options := defaultSolverOptions()
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+
+	root := RootSource(requirements)
+	rootTerm := root.Term()
+	rootVersion := SimpleVersion("1")
+
+	state := newSolverState(CombinedSource{root, source}, options, rootTerm.Name)
+	assign := state.partial.seedRoot(rootTerm.Name, rootVersion)
+	state.markAssigned(rootTerm.Name)
+	state.traceAssignment("seed", assign)
+
+	conflict, err := state.registerDependencies(rootTerm.Name, rootVersion, requirements)
+	if err != nil {
+		return nil, nil, &DependencyError{Package: rootTerm.Name, Version: rootVersion, Err: err}
+	}
+	if conflict == nil {
+		state.enqueue(rootTerm.Name)
+		conflict, err = state.propagate(EmptyName())
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if conflict == nil {
+		conflict, err = state.unpublishedConflict()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if conflict != nil {
+		return nil, rootCause(state, conflict), nil
+	}
+
+	allowed := make(map[Name]VersionSet, len(state.partial.perPackage))
+	for name := range state.partial.perPackage {
+		if name == rootTerm.Name {
+			continue
+		}
+		allowed[name] = state.partial.allowedSet(name)
+	}
+	return allowed, nil, nil
+}
+
+// unpublishedConflict reports the first positively required package whose
+// allowed set excludes every published version.
+func (st *solverState) unpublishedConflict() (*Incompatibility, error) {
+	names := make([]Name, 0, len(st.partial.perPackage))
+	for name := range st.partial.perPackage {
+		if name != st.partial.root {
+			names = append(names, name)
+		}
+	}
+	slices.SortFunc(names, func(a, b Name) int { return strings.Compare(a.Value(), b.Value()) })
+
+	for _, name := range names {
+		if !st.partial.isRequired(name) {
+			continue
+		}
+		latest := st.partial.latest(name)
+
+		allowed := st.partial.allowedSet(name)
+		versions, err := st.getVersions(name)
+		if err != nil {
+			var pkgErr *PackageNotFoundError
+			if !errors.As(err, &pkgErr) {
+				return nil, err
+			}
+			versions = nil
+		}
+		if slices.ContainsFunc(versions, allowed.Contains) {
+			continue
+		}
+
+		conflict := NewIncompatibilityNoVersions(termFromAllowedSet(name, allowed))
+		if latest.cause != nil {
+			conflict = resolveIncompatibility(conflict, latest.cause, name)
+		}
+		return conflict, nil
+	}
+	return nil, nil
+}
+
+// rootCause runs conflict resolution on a level-zero conflict, which always
+// ends in the incompatibility that explains the failure.
+func rootCause(st *solverState, conflict *Incompatibility) *Incompatibility {
+	_, _, err := st.resolveConflict(conflict)
+	var nsErr *NoSolutionError
+	if errors.As(err, &nsErr) && nsErr.Incompatibility != nil {
+		return nsErr.Incompatibility
+	}
+	return conflict
+}
@@ -0,0 +1,61 @@
+package pubgrub
+
+import "testing"
+
+func TestPropagateTightensTransitiveRequirements(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("lib"), EqualsCondition{Version: SimpleVersion("2.0.0")}),
+	})
+	source.AddPackage(MakeName("lib"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("lib"), SimpleVersion("2.0.0"), nil)
+
+	allowed, conflict, err := Propagate([]Term{
+		NewTerm(MakeName("app"), EqualsCondition{Version: SimpleVersion("1.0.0")}),
+	}, source)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conflict != nil {
+		t.Fatalf("unexpected conflict: %s", conflict)
+	}
+	if got := allowed[MakeName("app")]; got == nil || got.String() != "==1.0.0" {
+		t.Fatalf("expected app to be pinned, got %v", got)
+	}
+	// lib is only reachable through a decision on app, which Propagate never makes.
+	if _, ok := allowed[MakeName("lib")]; ok {
+		t.Fatalf("expected lib to stay untouched without decisions, got %v", allowed[MakeName("lib")])
+	}
+}
+
+func TestPropagateDetectsContradictoryRequirements(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("lib"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("lib"), SimpleVersion("2.0.0"), nil)
+
+	_, conflict, err := Propagate([]Term{
+		NewTerm(MakeName("lib"), EqualsCondition{Version: SimpleVersion("1.0.0")}),
+		NewTerm(MakeName("lib"), EqualsCondition{Version: SimpleVersion("2.0.0")}),
+	}, source)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conflict == nil {
+		t.Fatalf("expected a conflict for contradictory requirements")
+	}
+}
+
+func TestPropagateDetectsUnpublishedRange(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("lib"), SimpleVersion("1.0.0"), nil)
+
+	_, conflict, err := Propagate([]Term{
+		NewTerm(MakeName("lib"), EqualsCondition{Version: SimpleVersion("3.0.0")}),
+	}, source)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conflict == nil {
+		t.Fatalf("expected a conflict when no published version matches")
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+// Prune returns the packages of sol reachable from keepRoots along the
+// dependency edges the solver retained in NameVersion.Dependencies, in
+// solution order. After a requirement is deleted, passing the remaining
+// direct requirements drops the orphaned packages from a lockfile without a
+// full re-solve. Versions are never changed, so the result stays a valid
+// solution for the remaining requirements.
+//
+// The synthetic root package is kept when present, with its Dependencies
+// narrowed to the kept packages. Packages without retained edges, such as
+// a solution rebuilt by hand, count as leaves.
+//
+// Example:
+//
+//	// "rails" was removed from the Gemfile.
+//	solution = Prune(solution, []Name{MakeName("puma"), MakeName("sidekiq")})
+func Prune(sol Solution, keepRoots []Name) Solution {
+	edges := make(map[Name][]Name, len(sol))
+	for _, nv := range sol {
+		edges[nv.Name] = nv.Dependencies
+	}
+
+	root := MakeName("$$root")
+	keep := make(map[Name]bool, len(sol))
+	for _, name := range keepRoots {
+		if _, ok := edges[name]; !ok || keep[name] {
+			continue
+		}
+		keep[name] = true
+		for dep := range reachable(name, edges) {
+			keep[dep] = true
+		}
+	}
+	delete(keep, root)
+
+	pruned := make(Solution, 0, len(keep)+1)
+	for _, nv := range sol {
+		switch {
+		case nv.Name == root:
+			var deps []Name
+			for _, dep := range nv.Dependencies {
+				if keep[dep] {
+					deps = append(deps, dep)
+				}
+			}
+			nv.Dependencies = deps
+		case !keep[nv.Name]:
+			continue
+		}
+		pruned = append(pruned, nv)
+	}
+	return pruned
+}
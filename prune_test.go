@@ -0,0 +1,46 @@
+package pubgrub
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPruneDropsOrphanedPackages(t *testing.T) {
+	anyVersion := NewVersionSetCondition(FullVersionSet())
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("rails"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("rack"), anyVersion),
+		NewTerm(MakeName("activesupport"), anyVersion),
+	})
+	source.AddPackage(MakeName("sidekiq"), SimpleVersion("1.0.0"), []Term{NewTerm(MakeName("rack"), anyVersion)})
+	for _, pkg := range []string{"rack", "activesupport"} {
+		source.AddPackage(MakeName(pkg), SimpleVersion("1.0.0"), nil)
+	}
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("rails"), anyVersion)
+	root.AddPackage(MakeName("sidekiq"), anyVersion)
+	solution, err := NewSolver(root, source).Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// rails was removed from the requirements.
+	pruned := Prune(solution, []Name{MakeName("sidekiq")})
+	var got []string
+	for _, nv := range pruned {
+		got = append(got, nv.Name.Value())
+	}
+	if !slices.Equal(got, []string{"$$root", "rack", "sidekiq"}) {
+		t.Fatalf("unexpected pruned solution %v", got)
+	}
+	if deps := pruned[0].Dependencies; !slices.Equal(deps, []Name{MakeName("sidekiq")}) {
+		t.Fatalf("expected the root to depend on sidekiq only, got %v", deps)
+	}
+	if version, ok := pruned.GetVersion(MakeName("rack")); !ok || version.String() != "1.0.0" {
+		t.Fatalf("expected rack 1.0.0 to be kept, got %v", version)
+	}
+	if len(solution) != 5 {
+		t.Fatalf("expected Prune not to modify its input, got %v", solution)
+	}
+}
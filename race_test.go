@@ -0,0 +1,7 @@
+//go:build race
+
+package pubgrub
+
+// raceEnabled reports whether the race detector is on; it instruments
+// memory accesses with extra allocations, so allocation budgets do not hold.
+const raceEnabled = true
@@ -0,0 +1,103 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+// maxDependencyRangeScan bounds how many neighbouring releases are inspected
+// in each direction when widening a dependency incompatibility.
+const maxDependencyRangeScan = 64
+
+// dependencyIncompatibility builds the incompatibility for pkg@version
+// depending on dep. With range merging enabled, the package term is widened to
+// the run of adjacent published versions that declare the identical
+// dependency, so that a conflict on dep eliminates every release in the run at
+// once instead of one version per conflict.
+func (st *solverState) dependencyIncompatibility(pkg Name, version Version, dep Term) *Incompatibility {
+	if !st.options.MergeDependencyRanges || pkg == st.partial.root {
+		return NewIncompatibilityFromDependency(pkg, version, dep)
+	}
+	return NewIncompatibilityFromDependencyRange(pkg, st.dependencyRange(pkg, version, dep), dep)
+}
+
+// dependencyRange returns the widest contiguous range of published versions of
+// pkg around version that all declare dep.
+func (st *solverState) dependencyRange(pkg Name, version Version, dep Term) VersionSet {
+	single := (&VersionIntervalSet{}).Singleton(version)
+
+	versions, err := st.getVersions(pkg)
+	if err != nil {
+		return single
+	}
+
+	idx := -1
+	for i, ver := range versions {
+		if ver.Sort(version) == 0 {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return single
+	}
+
+	lo, hi := idx, idx
+	for i := idx - 1; i >= 0 && idx-i <= maxDependencyRangeScan; i-- {
+		if !st.declaresDependency(pkg, versions[i], dep) {
+			break
+		}
+		lo = i
+	}
+	for i := idx + 1; i < len(versions) && i-idx <= maxDependencyRangeScan; i++ {
+		if !st.declaresDependency(pkg, versions[i], dep) {
+			break
+		}
+		hi = i
+	}
+
+	if lo == hi {
+		return single
+	}
+	return NewVersionRangeSet(versions[lo], true, versions[hi], true)
+}
+
+// declaresDependency reports whether pkg@ver declares a dependency identical to dep.
+func (st *solverState) declaresDependency(pkg Name, ver Version, dep Term) bool {
+	deps, err := st.getDependencies(pkg, ver)
+	if err != nil {
+		return false
+	}
+	for _, candidate := range deps {
+		if sameTerm(candidate, dep) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameTerm reports whether two terms express the same constraint.
+// Conditions are compared by their rendered form, which is canonical for the
+// built-in condition types.
+func sameTerm(a, b Term) bool {
+	if a.Name != b.Name || a.Positive != b.Positive {
+		return false
+	}
+	return conditionString(a.Condition) == conditionString(b.Condition)
+}
+
+func conditionString(cond Condition) string {
+	if cond == nil {
+		return "*"
+	}
+	return cond.String()
+}
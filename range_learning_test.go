@@ -0,0 +1,75 @@
+package pubgrub
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func patchReleaseSource(t *testing.T, count int, depFor func(i int) string) *InMemorySource {
+	t.Helper()
+	source := &InMemorySource{}
+	for i := range count {
+		ver, _ := ParseSemanticVersion(fmt.Sprintf("1.0.%d", i))
+		set, err := ParseVersionRange(depFor(i))
+		if err != nil {
+			t.Fatalf("parse range: %v", err)
+		}
+		source.AddPackage(MakeName("foo"), ver, []Term{
+			NewTerm(MakeName("bar"), NewVersionSetCondition(set)),
+		})
+	}
+	bar, _ := ParseSemanticVersion("1.0.0")
+	source.AddPackage(MakeName("bar"), bar, nil)
+	return source
+}
+
+func TestDependencyRangeMergingReducesConflicts(t *testing.T) {
+	source := patchReleaseSource(t, 20, func(int) string { return ">=2.0.0" })
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("foo"), NewVersionSetCondition(FullVersionSet()))
+
+	plain := NewSolver(root, source).EnableIncompatibilityTracking()
+	if _, err := plain.Solve(root.Term()); err == nil {
+		t.Fatal("expected failure without merging")
+	}
+
+	merged := NewSolverWithOptions([]Source{root, source},
+		WithIncompatibilityTracking(true),
+		WithDependencyRangeMerging(true),
+	)
+	_, err := merged.Solve(root.Term())
+	if err == nil {
+		t.Fatal("expected failure with merging")
+	}
+
+	if merged.Stats().Conflicts >= plain.Stats().Conflicts {
+		t.Fatalf("expected fewer conflicts with merging: merged=%d plain=%d",
+			merged.Stats().Conflicts, plain.Stats().Conflicts)
+	}
+	if !strings.Contains(err.Error(), "foo >=1.0.0, <=1.0.19 depends on bar >=2.0.0") {
+		t.Fatalf("expected range-wide explanation, got:\n%s", err)
+	}
+}
+
+func TestDependencyRangeMergingStopsAtDifferentDependency(t *testing.T) {
+	source := patchReleaseSource(t, 5, func(i int) string {
+		if i == 0 {
+			return ">=1.0.0"
+		}
+		return ">=2.0.0"
+	})
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("foo"), NewVersionSetCondition(FullVersionSet()))
+
+	solver := NewSolverWithOptions([]Source{root, source}, WithDependencyRangeMerging(true))
+	solution, err := solver.Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, _ := solution.GetVersion(MakeName("foo")); ver.String() != "1.0.0" {
+		t.Fatalf("expected foo 1.0.0, got %v", ver)
+	}
+}
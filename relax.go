@@ -0,0 +1,255 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// maxRelaxationCandidates bounds how many widenings are attempted per root
+// requirement. Every attempt is a full solve, so the search stays shallow.
+const maxRelaxationCandidates = 8
+
+// Relaxation describes a widened root requirement under which an otherwise
+// unsolvable problem has a solution.
+//
+// Example:
+//
+//	relaxations, _ := SuggestRelaxations(*root, source)
+//	for _, r := range relaxations {
+//	    fmt.Println(r) // change rails >=6.0.0, <6.1.0 to >=6.0.0, <7.1.0
+//	}
+type Relaxation struct {
+	// Package is the root requirement that was widened.
+	Package Name
+	// Original is the condition declared by the user.
+	Original Condition
+	// Suggested is the widened version set that admits a solution.
+	Suggested VersionSet
+	// Added lists the published versions admitted by the widening.
+	Added []Version
+	// Solution is the resolution found under the suggested constraint.
+	Solution Solution
+}
+
+// String returns a human-readable suggestion.
+func (r Relaxation) String() string {
+	original := "*"
+	if r.Original != nil {
+		original = r.Original.String()
+	}
+	return fmt.Sprintf("change %s %s to %s", FormatName(r.Package), original, r.Suggested)
+}
+
+// SuggestRelaxations searches for the smallest widenings of root requirements
+// under which a solution exists.
+//
+// Each requirement is widened independently, one published version at a time,
+// starting with the versions closest to the range the user declared. The first
+// widening that solves is reported for that requirement. Results are ordered by
+// the number of versions admitted, so the least invasive change comes first.
+//
+// Returns nil when the original requirements already solve. Source errors other
+// than a failed resolution abort the search.
+func SuggestRelaxations(root RootSource, source Source, opts ...SolverOption) ([]Relaxation, error) {
+	solved, _, err := solveRelaxed(root, source, opts)
+	if err != nil {
+		return nil, err
+	}
+	if solved {
+		return nil, nil
+	}
+
+	var relaxations []Relaxation
+	for i, req := range root {
+		relaxation, ok, err := relaxRequirement(root, i, req, source, opts)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			relaxations = append(relaxations, relaxation)
+		}
+	}
+
+	slices.SortStableFunc(relaxations, func(a, b Relaxation) int {
+		return len(a.Added) - len(b.Added)
+	})
+	return relaxations, nil
+}
+
+// relaxRequirement tries widenings of a single root requirement in order of
+// distance from the declared range.
+func relaxRequirement(root RootSource, index int, req Term, source Source, opts []SolverOption) (Relaxation, bool, error) {
+	allowed, ok := termAllowedSet(req)
+	if !ok {
+		return Relaxation{}, false, nil
+	}
+
+	versions, err := source.GetVersions(req.Name)
+	if err != nil {
+		var pkgErr *PackageNotFoundError
+		if errors.As(err, &pkgErr) {
+			return Relaxation{}, false, nil
+		}
+		return Relaxation{}, false, err
+	}
+
+	for _, candidate := range relaxationCandidates(allowed, versions) {
+		widened := widenToInclude(allowed, versions, candidate)
+
+		relaxed := slices.Clone(root)
+		relaxed[index] = NewTerm(req.Name, NewVersionSetCondition(widened))
+
+		solved, solution, err := solveRelaxed(relaxed, source, opts)
+		if err != nil {
+			return Relaxation{}, false, err
+		}
+		if !solved {
+			continue
+		}
+
+		var added []Version
+		for _, ver := range versions {
+			if widened.Contains(ver) && !allowed.Contains(ver) {
+				added = append(added, ver)
+			}
+		}
+
+		return Relaxation{
+			Package:   req.Name,
+			Original:  req.Condition,
+			Suggested: widened,
+			Added:     added,
+			Solution:  solution,
+		}, true, nil
+	}
+
+	return Relaxation{}, false, nil
+}
+
+// solveRelaxed runs a solve and folds resolution failures into solved=false.
+func solveRelaxed(root RootSource, source Source, opts []SolverOption) (bool, Solution, error) {
+	solver := NewSolverWithOptions([]Source{root, source}, opts...)
+	solution, err := solver.Solve(root.Term())
+	if err == nil {
+		return true, solution, nil
+	}
+
+	if errors.Is(err, ErrNoSolution) {
+		return false, nil, nil
+	}
+	return false, nil, err
+}
+
+// relaxationCandidates lists published versions outside allowed, nearest first.
+// Distance is measured in published versions from the closest allowed one, so
+// the next release above an upper bound is tried before anything further away.
+func relaxationCandidates(allowed VersionSet, versions []Version) []Version {
+	lowest, highest := -1, -1
+	for i, ver := range versions {
+		if allowed.Contains(ver) {
+			if lowest < 0 {
+				lowest = i
+			}
+			highest = i
+		}
+	}
+
+	type candidate struct {
+		version  Version
+		distance int
+	}
+
+	var candidates []candidate
+	for i, ver := range versions {
+		if allowed.Contains(ver) {
+			continue
+		}
+		distance := i + 1
+		switch {
+		case highest >= 0 && i > highest:
+			distance = i - highest
+		case lowest >= 0 && i < lowest:
+			distance = lowest - i
+		case lowest >= 0:
+			// A hole inside the declared range, e.g. from an exclusion.
+			distance = 1
+		}
+		candidates = append(candidates, candidate{version: ver, distance: distance})
+	}
+
+	// Prefer newer versions among equally distant ones: upgrades are the
+	// more common fix for an over-tight requirement.
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		if a.distance != b.distance {
+			return a.distance - b.distance
+		}
+		return b.version.Sort(a.version)
+	})
+
+	limit := len(candidates)
+	if limit > maxRelaxationCandidates {
+		limit = maxRelaxationCandidates
+	}
+	result := make([]Version, 0, limit)
+	for _, c := range candidates[:limit] {
+		result = append(result, c.version)
+	}
+	return result
+}
+
+// widenToInclude extends allowed so that it reaches target. The gap between the
+// closest allowed published version and target is bridged, and the new bound is
+// placed just below the next published version so the suggestion reads like a
+// hand-written range (">=6.0.0, <7.1.0" rather than "<=7.0.4").
+func widenToInclude(allowed VersionSet, versions []Version, target Version) VersionSet {
+	var below, above Version
+	for _, ver := range versions {
+		if !allowed.Contains(ver) {
+			continue
+		}
+		if ver.Sort(target) < 0 {
+			below = ver
+		} else if above == nil {
+			above = ver
+		}
+	}
+
+	switch {
+	case below != nil:
+		// Widen upwards from the highest allowed version below target.
+		if next := nextPublished(versions, target); next != nil {
+			return allowed.Union(NewVersionRangeSet(below, true, next, false))
+		}
+		return allowed.Union(NewLowerBoundVersionSet(below, true))
+	case above != nil:
+		// Widen downwards to target.
+		return allowed.Union(NewVersionRangeSet(target, true, above, true))
+	default:
+		return allowed.Union(FullVersionSet().Singleton(target))
+	}
+}
+
+// nextPublished returns the first published version strictly above target.
+func nextPublished(versions []Version, target Version) Version {
+	for _, ver := range versions {
+		if ver.Sort(target) > 0 {
+			return ver
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package pubgrub
+
+import "testing"
+
+func TestSuggestRelaxationsWidensUpperBound(t *testing.T) {
+	source := &InMemorySource{}
+	for _, v := range []string{"6.0.0", "7.0.0", "7.1.0"} {
+		ver, _ := ParseSemanticVersion(v)
+		source.AddPackage(MakeName("rails"), ver, nil)
+	}
+
+	needsRails7, _ := ParseVersionRange(">=7.0.0, <7.1.0")
+	plugin, _ := ParseSemanticVersion("1.0.0")
+	source.AddPackage(MakeName("plugin"), plugin, []Term{
+		NewTerm(MakeName("rails"), NewVersionSetCondition(needsRails7)),
+	})
+
+	rails6, _ := ParseVersionRange(">=6.0.0, <6.1.0")
+	root := NewRootSource()
+	root.AddPackage(MakeName("rails"), NewVersionSetCondition(rails6))
+	root.AddPackage(MakeName("plugin"), EqualsCondition{Version: plugin})
+
+	relaxations, err := SuggestRelaxations(*root, source)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(relaxations) != 1 {
+		t.Fatalf("expected one relaxation, got %d: %v", len(relaxations), relaxations)
+	}
+
+	got := relaxations[0]
+	if got.Package != MakeName("rails") {
+		t.Fatalf("expected rails to be relaxed, got %s", got.Package.Value())
+	}
+	if got.Suggested.String() != ">=6.0.0, <7.1.0" {
+		t.Fatalf("unexpected suggestion: %s", got.Suggested)
+	}
+	if len(got.Added) != 1 || got.Added[0].String() != "7.0.0" {
+		t.Fatalf("expected only 7.0.0 to be admitted, got %v", got.Added)
+	}
+	if ver, ok := got.Solution.GetVersion(MakeName("rails")); !ok || ver.String() != "7.0.0" {
+		t.Fatalf("expected relaxed solution to pick rails 7.0.0, got %v", ver)
+	}
+	if want := "change rails >=6.0.0, <6.1.0 to >=6.0.0, <7.1.0"; got.String() != want {
+		t.Fatalf("unexpected description %q, want %q", got.String(), want)
+	}
+}
+
+func TestSuggestRelaxationsSolvableProblem(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("a"), SimpleVersion("1.0.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("a"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	relaxations, err := SuggestRelaxations(*root, source)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if relaxations != nil {
+		t.Fatalf("expected no relaxations for a solvable problem, got %v", relaxations)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+// Replacement is the successor of a renamed package, see WithReplacement.
+type Replacement struct {
+	Name Name
+	// MapVersions translates a version set of the old package into one of
+	// Name. Nil keeps the set unchanged.
+	MapVersions func(VersionSet) VersionSet
+}
+
+// replace rewrites dep to target the successor package. Conditions that do
+// not convert to a VersionSet are kept as written.
+func (r Replacement) replace(dep Term) Term {
+	condition := dep.Condition
+	if r.MapVersions != nil {
+		if set, ok := dependencySet(dep); ok {
+			condition = NewVersionSetCondition(r.MapVersions(set))
+		}
+	}
+	return Term{Name: r.Name, Condition: condition, Positive: dep.Positive}
+}
+
+// replaceDependencies redirects dependencies on replaced packages, calling
+// onReplace, if set, with each original dependency. deps is copied before
+// modification.
+func replaceDependencies(replacements map[Name]Replacement, deps []Term, onReplace func(dep Term, successor Name)) []Term {
+	if len(replacements) == 0 {
+		return deps
+	}
+	var result []Term
+	for i, dep := range deps {
+		replacement, ok := replacements[dep.Name]
+		if !ok {
+			continue
+		}
+		if result == nil {
+			result = append([]Term(nil), deps...)
+		}
+		result[i] = replacement.replace(dep)
+		if onReplace != nil {
+			onReplace(dep, replacement.Name)
+		}
+	}
+	if result == nil {
+		return deps
+	}
+	return result
+}
+
+// redirect applies the configured replacements to the dependencies of
+// name@version, warning once per redirected requirement.
+func (st *solverState) redirect(name Name, version Version, deps []Term) []Term {
+	return replaceDependencies(st.options.Replacements, deps, func(dep Term, successor Name) {
+		key := dependencyScoreKey(name, version) + "->" + dep.Name.Value()
+		if st.replaced[key] {
+			return
+		}
+		if st.replaced == nil {
+			st.replaced = make(map[string]bool)
+		}
+		st.replaced[key] = true
+		st.warn(ReplacementWarning{Dependent: name, Version: version, Old: dep.Name, New: successor})
+	})
+}
@@ -0,0 +1,58 @@
+package pubgrub
+
+import "testing"
+
+func TestReplacementRedirectsRenamedPackage(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("request"), NewVersionSetCondition(mustParseVersionRange(t, ">=2.0.0"))),
+	})
+	source.AddPackage(MakeName("got"), SimpleVersion("11.0.0"), nil)
+	source.AddPackage(MakeName("got"), SimpleVersion("12.0.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	// request 2.x maps onto got 11.x.
+	mapVersions := func(VersionSet) VersionSet { return mustParseVersionRange(t, ">=11.0.0, <12.0.0") }
+	solver := NewSolverWithOptions([]Source{root, source},
+		WithReplacement(MakeName("request"), MakeName("got"), mapVersions),
+	)
+	solution, err := solver.Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, ok := solution.GetVersion(MakeName("got")); !ok || ver.String() != "11.0.0" {
+		t.Fatalf("expected the requirement to be redirected to got 11.0.0, got %v", solution)
+	}
+	if _, ok := solution.GetVersion(MakeName("request")); ok {
+		t.Fatalf("expected the old name to stay out of the solution")
+	}
+
+	warnings := solver.Warnings()
+	if len(warnings) != 1 {
+		t.Fatalf("expected one warning, got %v", warnings)
+	}
+	if got, want := warnings[0].Warning(), "app 1.0.0 requires request, which is replaced by got"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestReplacementKeepsVersionsWithoutMapping(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("fork"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("fork"), SimpleVersion("2.0.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("original"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	solution, err := NewSolverWithOptions([]Source{root, source},
+		WithReplacement(MakeName("original"), MakeName("fork"), nil),
+	).Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, ok := solution.GetVersion(MakeName("fork")); !ok || ver.String() != "1.0.0" {
+		t.Fatalf("expected the root requirement to be redirected unchanged, got %v", solution)
+	}
+}
@@ -17,6 +17,7 @@ package pubgrub
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -26,8 +27,21 @@ type Reporter interface {
 	Report(incomp *Incompatibility) string
 }
 
-// DefaultReporter produces readable error messages with hierarchical structure
-type DefaultReporter struct{}
+// DefaultReporter produces readable error messages with hierarchical structure.
+//
+// Sub-derivations shared by several branches of the tree are printed once;
+// later occurrences are replaced by a one-line "(see above)" reference.
+// MaxDepth additionally bounds how deep the report nests, which keeps output
+// manageable for derivation trees thousands of nodes deep.
+//
+// Example:
+//
+//	err := nsErr.WithReporter(&DefaultReporter{MaxDepth: 20})
+type DefaultReporter struct {
+	// MaxDepth limits the nesting depth of the report. Derivations below the
+	// limit are elided with a marker line. Zero means unlimited.
+	MaxDepth int
+}
 
 // Report implements Reporter
 func (r *DefaultReporter) Report(incomp *Incompatibility) string {
@@ -41,28 +55,29 @@ func (r *DefaultReporter) Report(incomp *Incompatibility) string {
 }
 
 func (r *DefaultReporter) reportIncompatibility(incomp *Incompatibility, lines *[]string, depth int, visited map[*Incompatibility]bool) {
+	indent := strings.Repeat("  ", depth)
+
 	if visited[incomp] {
+		*lines = append(*lines, fmt.Sprintf("%s%s (see above)", indent, r.summary(incomp)))
 		return
 	}
-	visited[incomp] = true
 
-	indent := strings.Repeat("  ", depth)
+	if r.MaxDepth > 0 && depth > r.MaxDepth {
+		*lines = append(*lines, fmt.Sprintf("%s... (derivation truncated at depth %d)", indent, r.MaxDepth))
+		return
+	}
+	visited[incomp] = true
 
 	switch incomp.Kind {
 	case KindNoVersions:
 		if len(incomp.Terms) > 0 {
-			*lines = append(*lines, fmt.Sprintf("%sNo versions of %s satisfy the constraint", indent, incomp.Terms[0]))
+			*lines = append(*lines, indent+upperFirst(incomp.noVersionsStatement()))
 		}
 
 	case KindFromDependency:
 		if len(incomp.Terms) == 2 {
-			// Terms are {P@v, not D@d}, unnegate the dependency for display
-			dep := incomp.Terms[1]
-			if !dep.Positive {
-				dep = dep.Negate()
-			}
-			*lines = append(*lines, fmt.Sprintf("%sBecause %s %s depends on %s",
-				indent, incomp.Package.Value(), incomp.Version, dep))
+			*lines = append(*lines, fmt.Sprintf("%sBecause %s %s %s",
+				indent, incomp.depender(), dependencyVerb(incomp), dependencyTerm(incomp)))
 		}
 
 	case KindConflict:
@@ -78,12 +93,8 @@ func (r *DefaultReporter) reportIncompatibility(incomp *Incompatibility, lines *
 			} else if len(incomp.Terms) == 1 {
 				*lines = append(*lines, fmt.Sprintf("%s%s is forbidden.", indent, incomp.Terms[0]))
 			} else {
-				var termStrs []string
-				for _, term := range incomp.Terms {
-					termStrs = append(termStrs, term.String())
-				}
 				*lines = append(*lines, fmt.Sprintf("%sthese constraints conflict: %s",
-					indent, strings.Join(termStrs, " and ")))
+					indent, joinTerms(incomp.Terms)))
 			}
 		}
 
@@ -92,7 +103,58 @@ func (r *DefaultReporter) reportIncompatibility(incomp *Incompatibility, lines *
 	}
 }
 
-// CollapsedReporter produces a more compact error format
+// summary renders a single-line reference to an incompatibility that has
+// already been reported in full.
+func (r *DefaultReporter) summary(incomp *Incompatibility) string {
+	switch {
+	case incomp.Kind == KindFromDependency && len(incomp.Terms) == 2:
+		return fmt.Sprintf("Because %s %s %s", incomp.depender(), dependencyVerb(incomp), dependencyTerm(incomp))
+	case incomp.Kind == KindNoVersions && len(incomp.Terms) > 0:
+		return upperFirst(incomp.noVersionsStatement())
+	case len(incomp.Terms) == 0:
+		return "version solving has failed"
+	case len(incomp.Terms) == 1:
+		return fmt.Sprintf("%s is forbidden", incomp.Terms[0])
+	default:
+		return fmt.Sprintf("these constraints conflict: %s", joinTerms(incomp.Terms))
+	}
+}
+
+// dependencyTerm returns the dependency of a KindFromDependency
+// incompatibility. Terms are {P@v, not D@d}; the dependency is unnegated for
+// display.
+func dependencyTerm(incomp *Incompatibility) Term {
+	dep := incomp.Terms[1]
+	if !dep.Positive {
+		dep = dep.Negate()
+	}
+	return dep
+}
+
+// dependencyVerb describes how the depender relates to dependencyTerm: a
+// negative dependency, such as a root ban, forbids the package instead.
+func dependencyVerb(incomp *Incompatibility) string {
+	if incomp.Terms[1].Positive {
+		return "forbids"
+	}
+	return "depends on"
+}
+
+func joinTerms(terms []Term) string {
+	termStrs := make([]string, len(terms))
+	for i, term := range terms {
+		termStrs[i] = term.String()
+	}
+	return strings.Join(termStrs, " and ")
+}
+
+// CollapsedReporter produces a compact, prose-style error format.
+//
+// Linear stretches of the derivation are flattened into a single statement,
+// and chained dependencies are merged into transitive sentences such as
+// "app 1.0.0 depends on web == 2.0.0 which depends on http >=2.0.0".
+// Only branches that combine two independently derived facts keep their own
+// intermediate conclusion.
 type CollapsedReporter struct{}
 
 // Report implements Reporter with a collapsed format
@@ -109,7 +171,7 @@ func (r *CollapsedReporter) Report(incomp *Incompatibility) string {
 	}
 
 	// Join with "And because" for readability
-	result := lines[0]
+	result := "Because " + lines[0]
 	for i := 1; i < len(lines); i++ {
 		result += "\nAnd because " + lines[i]
 	}
@@ -122,43 +184,124 @@ func (r *CollapsedReporter) collectLines(incomp *Incompatibility, lines *[]strin
 	}
 	visited[incomp] = true
 
-	switch incomp.Kind {
-	case KindNoVersions:
-		if len(incomp.Terms) > 0 {
-			*lines = append(*lines, fmt.Sprintf("no versions of %s satisfy the constraint", incomp.Terms[0]))
-		}
+	if !isDerived(incomp) {
+		*lines = append(*lines, externalFact(incomp))
+		return
+	}
 
-	case KindFromDependency:
-		if len(incomp.Terms) == 2 {
-			// Terms are {P@v, not D@d}, unnegate the dependency for display
-			dep := incomp.Terms[1]
-			if !dep.Positive {
-				dep = dep.Negate()
-			}
-			*lines = append(*lines, fmt.Sprintf("%s %s depends on %s",
-				incomp.Package.Value(), incomp.Version, dep))
-		}
+	var deps []*Incompatibility
+	var facts []string
+	r.flatten(incomp, &deps, &facts, lines, visited)
 
-	case KindConflict:
-		if incomp.Cause1 != nil && incomp.Cause2 != nil {
-			// Recursively collect from causes
-			r.collectLines(incomp.Cause1, lines, visited)
-			r.collectLines(incomp.Cause2, lines, visited)
-
-			// Add conclusion
-			if len(incomp.Terms) == 1 {
-				*lines = append(*lines, fmt.Sprintf("%s is forbidden", incomp.Terms[0]))
-			} else if len(incomp.Terms) > 1 {
-				var termStrs []string
-				for _, term := range incomp.Terms {
-					termStrs = append(termStrs, term.String())
+	statements := append(dependencyChains(deps), facts...)
+	if len(statements) == 0 {
+		*lines = append(*lines, conclusion(incomp))
+		return
+	}
+	last := len(statements) - 1
+	statements[last] = fmt.Sprintf("%s, %s", statements[last], conclusion(incomp))
+	*lines = append(*lines, statements...)
+}
+
+// flatten gathers the external facts of a linear derivation. A derived cause
+// with at least one external cause of its own continues the same line of
+// reasoning and is folded in; a cause derived purely from other derivations
+// starts a new branch that is reported first and then referenced by its
+// conclusion.
+func (r *CollapsedReporter) flatten(incomp *Incompatibility, deps *[]*Incompatibility, facts *[]string, lines *[]string, visited map[*Incompatibility]bool) {
+	for _, cause := range []*Incompatibility{incomp.Cause1, incomp.Cause2} {
+		switch {
+		case !isDerived(cause):
+			if cause.Kind == KindFromDependency && len(cause.Terms) == 2 {
+				if !slices.Contains(*deps, cause) {
+					*deps = append(*deps, cause)
 				}
-				*lines = append(*lines, fmt.Sprintf("these constraints conflict: %s",
-					strings.Join(termStrs, " and ")))
+			} else {
+				*facts = append(*facts, externalFact(cause))
 			}
+		case visited[cause]:
+			*facts = append(*facts, conclusion(cause))
+		case !isDerived(cause.Cause1) || !isDerived(cause.Cause2):
+			visited[cause] = true
+			r.flatten(cause, deps, facts, lines, visited)
+		default:
+			r.collectLines(cause, lines, visited)
+			*facts = append(*facts, conclusion(cause))
 		}
+	}
+}
 
+// isDerived reports whether incomp was derived by conflict resolution.
+func isDerived(incomp *Incompatibility) bool {
+	return incomp.Kind == KindConflict && incomp.Cause1 != nil && incomp.Cause2 != nil
+}
+
+// externalFact describes an incompatibility that was not derived.
+func externalFact(incomp *Incompatibility) string {
+	switch {
+	case incomp.Kind == KindNoVersions && len(incomp.Terms) > 0:
+		return incomp.noVersionsStatement()
+	case incomp.Kind == KindFromDependency && len(incomp.Terms) == 2:
+		return fmt.Sprintf("%s %s %s", incomp.depender(), dependencyVerb(incomp), dependencyTerm(incomp))
 	default:
-		*lines = append(*lines, incomp.String())
+		return incomp.String()
+	}
+}
+
+// conclusion states what a derived incompatibility establishes.
+func conclusion(incomp *Incompatibility) string {
+	switch len(incomp.Terms) {
+	case 0:
+		return "version solving failed"
+	case 1:
+		return fmt.Sprintf("%s is forbidden", incomp.Terms[0])
+	default:
+		return fmt.Sprintf("%s are incompatible", joinTerms(incomp.Terms))
+	}
+}
+
+// dependencyChains merges dependencies where one's target is the next one's
+// depender into transitive statements. Each dependency is used once; chains
+// start at dependencies no other dependency in the set points to.
+func dependencyChains(deps []*Incompatibility) []string {
+	byDepender := make(map[Name]*Incompatibility, len(deps))
+	targeted := make(map[Name]bool, len(deps))
+	for _, dep := range deps {
+		if _, ok := byDepender[dep.Package]; !ok {
+			byDepender[dep.Package] = dep
+		}
+		targeted[dependencyTerm(dep).Name] = true
+	}
+
+	used := make(map[*Incompatibility]bool, len(deps))
+	var chains []string
+	emit := func(start *Incompatibility) {
+		var b strings.Builder
+		b.WriteString(fmt.Sprintf("%s %s %s", start.depender(), dependencyVerb(start), dependencyTerm(start)))
+		used[start] = true
+		// A forbidden package is never installed, so nothing follows it.
+		for cur := start; !cur.Terms[1].Positive; {
+			next := byDepender[dependencyTerm(cur).Name]
+			if next == nil || used[next] {
+				break
+			}
+			b.WriteString(fmt.Sprintf(" which %s %s", dependencyVerb(next), dependencyTerm(next)))
+			used[next] = true
+			cur = next
+		}
+		chains = append(chains, b.String())
+	}
+
+	for _, dep := range deps {
+		if !used[dep] && !targeted[dep.Package] {
+			emit(dep)
+		}
+	}
+	// Whatever remains is part of a dependency cycle.
+	for _, dep := range deps {
+		if !used[dep] {
+			emit(dep)
+		}
 	}
+	return chains
 }
@@ -0,0 +1,292 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+)
+
+const (
+	htmlNodeWidth  = 160
+	htmlNodeHeight = 28
+	htmlColGap     = 40
+	htmlRowGap     = 60
+	htmlMargin     = 20
+)
+
+// HTMLReporter renders a failed solve as a standalone HTML page, suitable for
+// attaching to CI failures. The page contains a collapsible derivation tree
+// with version ranges highlighted, and an inline SVG of the dependency
+// subgraph involved in the conflict. No external assets are referenced.
+//
+// Example:
+//
+//	if nsErr, ok := AsNoSolution(err); ok {
+//	    page := (&HTMLReporter{Title: "resolution failed"}).Report(nsErr.Incompatibility)
+//	    os.WriteFile("conflict.html", []byte(page), 0o644)
+//	}
+type HTMLReporter struct {
+	// Title is used for the page title and heading.
+	// Default: "Dependency resolution failed"
+	Title string
+}
+
+// Report implements Reporter
+func (r *HTMLReporter) Report(incomp *Incompatibility) string {
+	title := r.Title
+	if title == "" {
+		title = "Dependency resolution failed"
+	}
+
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&b, "<title>%s</title>\n", html.EscapeString(title))
+	b.WriteString(htmlReportStyle)
+	b.WriteString("</head>\n<body>\n")
+	fmt.Fprintf(&b, "<h1>%s</h1>\n", html.EscapeString(title))
+
+	if incomp == nil {
+		b.WriteString("<p>no solution found</p>\n</body>\n</html>\n")
+		return b.String()
+	}
+
+	b.WriteString("<h2>Derivation</h2>\n<div class=\"tree\">\n")
+	r.writeNode(&b, incomp, make(map[*Incompatibility]int))
+	b.WriteString("</div>\n")
+
+	b.WriteString("<h2>Dependencies involved</h2>\n")
+	writeDependencySVG(&b, incomp)
+
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
+
+// writeNode renders one incompatibility as a collapsible element. Shared
+// sub-derivations are rendered once and linked on later occurrences.
+func (r *HTMLReporter) writeNode(b *strings.Builder, incomp *Incompatibility, ids map[*Incompatibility]int) {
+	if id, ok := ids[incomp]; ok {
+		fmt.Fprintf(b, "<div class=\"ref\"><a href=\"#inc-%d\">%s (see above)</a></div>\n", id, htmlStatement(incomp))
+		return
+	}
+	id := len(ids) + 1
+	ids[incomp] = id
+
+	if !isDerived(incomp) {
+		fmt.Fprintf(b, "<div class=\"leaf %s\" id=\"inc-%d\">%s</div>\n", htmlKindClass(incomp.Kind), id, htmlStatement(incomp))
+		return
+	}
+
+	fmt.Fprintf(b, "<details open id=\"inc-%d\">\n<summary>%s</summary>\n", id, htmlStatement(incomp))
+	r.writeNode(b, incomp.Cause1, ids)
+	r.writeNode(b, incomp.Cause2, ids)
+	b.WriteString("</details>\n")
+}
+
+// htmlStatement describes an incompatibility with highlighted ranges.
+func htmlStatement(incomp *Incompatibility) string {
+	switch {
+	case incomp.Kind == KindNoVersions && incomp.Reason != "":
+		return html.EscapeString(upperFirst(incomp.Reason))
+	case incomp.Kind == KindNoVersions && len(incomp.Terms) > 0:
+		return fmt.Sprintf("No versions of %s satisfy the constraint", htmlTerm(incomp.Terms[0]))
+	case incomp.Kind == KindFromDependency && len(incomp.Terms) == 2:
+		return fmt.Sprintf("%s %s %s", htmlDepender(incomp), dependencyVerb(incomp), htmlTerm(dependencyTerm(incomp)))
+	case isDerived(incomp):
+		switch len(incomp.Terms) {
+		case 0:
+			return "version solving failed"
+		case 1:
+			return fmt.Sprintf("%s is forbidden", htmlTerm(incomp.Terms[0]))
+		default:
+			parts := make([]string, len(incomp.Terms))
+			for i, term := range incomp.Terms {
+				parts[i] = htmlTerm(term)
+			}
+			return fmt.Sprintf("%s are incompatible", strings.Join(parts, " and "))
+		}
+	default:
+		return html.EscapeString(incomp.String())
+	}
+}
+
+func htmlTerm(term Term) string {
+	prefix := ""
+	if !term.Positive {
+		prefix = "not "
+		term = term.Negate()
+	}
+	return fmt.Sprintf("%s<span class=\"pkg\">%s</span> <span class=\"range\">%s</span>",
+		prefix, html.EscapeString(FormatName(term.Name)), html.EscapeString(termConstraint(term)))
+}
+
+func htmlDepender(incomp *Incompatibility) string {
+	versions := ""
+	switch {
+	case incomp.Versions != nil:
+		versions = incomp.Versions.String()
+	case incomp.Version != nil:
+		versions = incomp.Version.String()
+	}
+	return fmt.Sprintf("<span class=\"pkg\">%s</span> <span class=\"range\">%s</span>",
+		html.EscapeString(FormatName(incomp.Package)), html.EscapeString(versions))
+}
+
+func htmlKindClass(kind IncompatibilityKind) string {
+	return strings.ReplaceAll(kind.String(), "_", "-")
+}
+
+// dependencyEdge is a package-level edge of the conflict subgraph.
+type dependencyEdge struct {
+	from, to string
+	label    string
+}
+
+// writeDependencySVG draws the dependency incompatibilities reachable from
+// incomp as a layered graph. Packages are placed in columns by their
+// distance from packages nothing else depends on.
+func writeDependencySVG(b *strings.Builder, incomp *Incompatibility) {
+	var edges []dependencyEdge
+	var missing []string
+	visited := make(map[*Incompatibility]bool)
+
+	var walk func(inc *Incompatibility)
+	walk = func(inc *Incompatibility) {
+		if inc == nil || visited[inc] {
+			return
+		}
+		visited[inc] = true
+		switch {
+		case inc.Kind == KindFromDependency && len(inc.Terms) == 2:
+			dep := dependencyTerm(inc)
+			edge := dependencyEdge{from: FormatName(inc.Package), to: FormatName(dep.Name), label: termConstraint(dep)}
+			if !slices.Contains(edges, edge) {
+				edges = append(edges, edge)
+			}
+		case inc.Kind == KindNoVersions && len(inc.Terms) > 0:
+			missing = append(missing, FormatName(inc.Terms[0].Name))
+		}
+		walk(inc.Cause1)
+		walk(inc.Cause2)
+	}
+	walk(incomp)
+
+	if len(edges) == 0 {
+		b.WriteString("<p>No dependency edges are involved.</p>\n")
+		return
+	}
+
+	layers := layerPackages(edges)
+	rows := make(map[int]int)
+	pos := make(map[string][2]int)
+	var names []string
+	for _, e := range edges {
+		for _, name := range []string{e.from, e.to} {
+			if _, ok := pos[name]; ok {
+				continue
+			}
+			col := layers[name]
+			pos[name] = [2]int{col, rows[col]}
+			rows[col]++
+			names = append(names, name)
+		}
+	}
+
+	maxCol, maxRow := 0, 0
+	for _, p := range pos {
+		if p[0] > maxCol {
+			maxCol = p[0]
+		}
+		if p[1] > maxRow {
+			maxRow = p[1]
+		}
+	}
+	width := 2*htmlMargin + (maxCol+1)*htmlNodeWidth + maxCol*(htmlNodeWidth/2+htmlColGap)
+	height := 2*htmlMargin + (maxRow+1)*htmlNodeHeight + maxRow*htmlRowGap
+
+	xy := func(name string) (int, int) {
+		p := pos[name]
+		return htmlMargin + p[0]*(htmlNodeWidth*3/2+htmlColGap), htmlMargin + p[1]*(htmlNodeHeight+htmlRowGap)
+	}
+
+	fmt.Fprintf(b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n", width, height, width, height)
+	b.WriteString("<defs><marker id=\"arrow\" markerWidth=\"8\" markerHeight=\"8\" refX=\"8\" refY=\"4\" orient=\"auto\"><path d=\"M0,0 L8,4 L0,8 z\" fill=\"#555\"/></marker></defs>\n")
+	for _, e := range edges {
+		x1, y1 := xy(e.from)
+		x2, y2 := xy(e.to)
+		x1 += htmlNodeWidth
+		y1 += htmlNodeHeight / 2
+		y2 += htmlNodeHeight / 2
+		fmt.Fprintf(b, "<line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"#555\" marker-end=\"url(#arrow)\"/>\n", x1, y1, x2, y2)
+		fmt.Fprintf(b, "<text class=\"edge\" x=\"%d\" y=\"%d\">%s</text>\n", (x1+x2)/2, (y1+y2)/2-4, html.EscapeString(e.label))
+	}
+	for _, name := range names {
+		x, y := xy(name)
+		class := "node"
+		if slices.Contains(missing, name) {
+			class = "node missing"
+		}
+		fmt.Fprintf(b, "<g class=\"%s\"><rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" rx=\"4\"/>", class, x, y, htmlNodeWidth, htmlNodeHeight)
+		fmt.Fprintf(b, "<text x=\"%d\" y=\"%d\">%s</text></g>\n", x+htmlNodeWidth/2, y+htmlNodeHeight/2+4, html.EscapeString(name))
+	}
+	b.WriteString("</svg>\n")
+}
+
+// layerPackages assigns each package the length of the longest dependency
+// path leading to it, ignoring edges that would close a cycle.
+func layerPackages(edges []dependencyEdge) map[string]int {
+	layers := make(map[string]int)
+	for _, e := range edges {
+		layers[e.from] += 0
+		layers[e.to] += 0
+	}
+	// Bellman-Ford style relaxation bounded by the number of packages keeps
+	// cycles from looping forever.
+	for range len(layers) {
+		changed := false
+		for _, e := range edges {
+			if next := layers[e.from] + 1; next > layers[e.to] && next < len(layers) {
+				layers[e.to] = next
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	return layers
+}
+
+const htmlReportStyle = `<style>
+body { font-family: -apple-system, "Segoe UI", sans-serif; margin: 2em; color: #222; }
+.tree details { margin-left: 1.2em; border-left: 1px solid #ddd; padding-left: .6em; }
+.tree summary { cursor: pointer; }
+.tree .leaf, .tree .ref { margin-left: 1.2em; padding: .1em 0; }
+.tree .ref a { color: #666; }
+.no-versions { color: #b00; }
+.pkg { font-weight: 600; }
+.range { font-family: monospace; background: #fff3c4; padding: 0 .2em; border-radius: 3px; }
+svg .node rect { fill: #eef3ff; stroke: #4a6fd0; }
+svg .node.missing rect { fill: #ffecec; stroke: #b00; }
+svg text { font: 12px monospace; text-anchor: middle; }
+svg text.edge { fill: #555; font-size: 10px; }
+</style>
+`
+
+var (
+	_ Reporter = (*HTMLReporter)(nil)
+)
@@ -0,0 +1,42 @@
+package pubgrub
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHTMLReporter(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("dropdown"), SimpleVersion("2.0.0"), []Term{
+		NewTerm(MakeName("icons"), EqualsCondition{Version: SimpleVersion("2.0.0")}),
+	})
+	source.AddPackage(MakeName("icons"), SimpleVersion("1.0.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("dropdown"), EqualsCondition{Version: SimpleVersion("2.0.0")})
+
+	_, err := NewSolver(root, source).EnableIncompatibilityTracking().Solve(root.Term())
+	nsErr, ok := AsNoSolution(err)
+	if !ok {
+		t.Fatalf("expected no solution, got %v", err)
+	}
+
+	page := (&HTMLReporter{Title: "CI <failure>"}).Report(nsErr.Incompatibility)
+
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"<title>CI &lt;failure&gt;</title>",
+		"<details open",
+		`<span class="range">==2.0.0</span>`,
+		"<svg",
+		`<g class="node missing">`,
+		">icons</text>",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("expected page to contain %q", want)
+		}
+	}
+	if strings.Contains(page, "<failure>") {
+		t.Errorf("expected title to be escaped")
+	}
+}
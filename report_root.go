@@ -0,0 +1,96 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"html"
+	"slices"
+	"strings"
+)
+
+// RootLabelReporter hides the synthetic "$$root" package from another
+// reporter's output. The root is called Label, and derived conclusions drop
+// their trivial root term, so "a == 1.0.0 and $$root == 1 conflict" reads
+// "a == 1.0.0 is forbidden" and the final "$$root == 1 is forbidden" reads
+// as a failed solve.
+//
+// Example:
+//
+//	err := nsErr.WithReporter(&RootLabelReporter{
+//	    Reporter: &CollapsedReporter{},
+//	    Label:    "your project",
+//	})
+type RootLabelReporter struct {
+	// Reporter formats the relabelled derivation.
+	// Default: DefaultReporter
+	Reporter Reporter
+	// Label names the root package.
+	// Default: "root"
+	Label string
+}
+
+// Report implements Reporter
+func (r *RootLabelReporter) Report(incomp *Incompatibility) string {
+	reporter := r.Reporter
+	if reporter == nil {
+		reporter = &DefaultReporter{}
+	}
+	label := r.Label
+	if label == "" {
+		label = "root"
+	}
+	if _, ok := reporter.(*HTMLReporter); ok {
+		label = html.EscapeString(label)
+	}
+
+	out := reporter.Report(elideRoot(incomp, make(map[*Incompatibility]*Incompatibility)))
+	return strings.NewReplacer(
+		"$$root == 1", label,
+		"$$root 1", label,
+		"$$root", label,
+	).Replace(out)
+}
+
+// WithRootLabel returns a new error whose report calls the root package
+// label instead of "$$root", see RootLabelReporter.
+//
+// Example:
+//
+//	if nsErr, ok := AsNoSolution(err); ok {
+//	    fmt.Println(nsErr.WithRootLabel("your project"))
+//	}
+func (e *NoSolutionError) WithRootLabel(label string) *NoSolutionError {
+	return e.WithReporter(&RootLabelReporter{Reporter: e.Reporter, Label: label})
+}
+
+// elideRoot copies the derivation tree with root terms removed from derived
+// incompatibilities. Every derivation holds given the root, so those terms
+// add nothing for the reader. Shared nodes stay shared.
+func elideRoot(incomp *Incompatibility, copies map[*Incompatibility]*Incompatibility) *Incompatibility {
+	if incomp == nil || !isDerived(incomp) {
+		return incomp
+	}
+	if c, ok := copies[incomp]; ok {
+		return c
+	}
+	c := *incomp
+	copies[incomp] = &c
+	c.Terms = slices.DeleteFunc(slices.Clone(incomp.Terms), func(term Term) bool {
+		return term.Name == MakeName("$$root")
+	})
+	c.Cause1 = elideRoot(incomp.Cause1, copies)
+	c.Cause2 = elideRoot(incomp.Cause2, copies)
+	return &c
+}
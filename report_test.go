@@ -16,6 +16,7 @@
 package pubgrub
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 )
@@ -126,6 +127,46 @@ func TestDefaultReporter_Conflict(t *testing.T) {
 	}
 }
 
+func TestDefaultReporter_SharedDerivation(t *testing.T) {
+	reporter := &DefaultReporter{}
+
+	dep := NewTerm(MakeName("B"), EqualsCondition{Version: SimpleVersion("2.0.0")})
+	shared := NewIncompatibilityFromDependency(MakeName("A"), SimpleVersion("1.0.0"), dep)
+	other := NewIncompatibilityNoVersions(dep)
+
+	left := NewIncompatibilityConflict([]Term{NewTerm(MakeName("A"), nil)}, shared, other)
+	conflict := NewIncompatibilityConflict([]Term{}, left, shared)
+
+	result := reporter.Report(conflict)
+	t.Logf("Output:\n%s", result)
+
+	if got := strings.Count(result, "A 1.0.0 depends on B == 2.0.0"); got != 2 {
+		t.Errorf("Expected shared dependency to be printed and referenced once each, got %d mentions", got)
+	}
+	if !strings.Contains(result, "(see above)") {
+		t.Errorf("Expected repeated derivation to be referenced, got: %s", result)
+	}
+}
+
+func TestDefaultReporter_MaxDepth(t *testing.T) {
+	reporter := &DefaultReporter{MaxDepth: 3}
+
+	dep := NewTerm(MakeName("B"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+	chain := NewIncompatibilityFromDependency(MakeName("A"), SimpleVersion("1.0.0"), dep)
+	for i := 0; i < 1000; i++ {
+		leaf := NewIncompatibilityNoVersions(NewTerm(MakeName(fmt.Sprintf("p%d", i)), nil))
+		chain = NewIncompatibilityConflict([]Term{NewTerm(MakeName("A"), nil)}, chain, leaf)
+	}
+
+	result := reporter.Report(chain)
+	if !strings.Contains(result, "derivation truncated at depth 3") {
+		t.Errorf("Expected truncation marker, got: %s", result)
+	}
+	if lines := strings.Count(result, "\n"); lines > 40 {
+		t.Errorf("Expected bounded output, got %d lines", lines)
+	}
+}
+
 func TestCollapsedReporter_NoVersions(t *testing.T) {
 	reporter := &CollapsedReporter{}
 	term := NewTerm(MakeName("foo"), EqualsCondition{Version: SimpleVersion("1.0.0")})
@@ -158,6 +199,42 @@ func TestCollapsedReporter_Conflict(t *testing.T) {
 	}
 }
 
+func TestCollapsedReporter_TransitiveChain(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("web"), EqualsCondition{Version: SimpleVersion("2.0.0")}),
+		NewTerm(MakeName("http"), EqualsCondition{Version: SimpleVersion("1.0.0")}),
+	})
+	source.AddPackage(MakeName("web"), SimpleVersion("2.0.0"), []Term{
+		NewTerm(MakeName("router"), EqualsCondition{Version: SimpleVersion("1.0.0")}),
+	})
+	source.AddPackage(MakeName("router"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("http"), EqualsCondition{Version: SimpleVersion("2.0.0")}),
+	})
+	source.AddPackage(MakeName("http"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("http"), SimpleVersion("2.0.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	solver := NewSolver(root, source).EnableIncompatibilityTracking()
+	_, err := solver.Solve(root.Term())
+	nsErr, ok := err.(*NoSolutionError)
+	if !ok {
+		t.Fatalf("expected NoSolutionError, got %v", err)
+	}
+
+	result := (&CollapsedReporter{}).Report(nsErr.Incompatibility)
+	t.Logf("Output:\n%s", result)
+
+	if !strings.Contains(result, "web == 2.0.0 which depends on router == 1.0.0 which depends on http == 2.0.0") {
+		t.Errorf("Expected transitive chain through web and router, got: %s", result)
+	}
+	if strings.Count(result, "depends on router") != 1 {
+		t.Errorf("Expected each dependency to be stated once, got: %s", result)
+	}
+}
+
 func TestNoSolutionError_Basic(t *testing.T) {
 	term := NewTerm(MakeName("foo"), EqualsCondition{Version: SimpleVersion("1.0.0")})
 	incomp := NewIncompatibilityNoVersions(term)
@@ -486,3 +563,40 @@ func TestConflictWithSingleTerm(t *testing.T) {
 		t.Errorf("expected 'is forbidden' in collapsed message, got %q", msg2)
 	}
 }
+
+func TestRootLabelReporter(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("a"), SimpleVersion("1.0.0"), []Term{NewTerm(MakeName("c"), EqualsCondition{Version: SimpleVersion("1.0.0")})})
+	source.AddPackage(MakeName("b"), SimpleVersion("1.0.0"), []Term{NewTerm(MakeName("c"), EqualsCondition{Version: SimpleVersion("2.0.0")})})
+	source.AddPackage(MakeName("c"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("c"), SimpleVersion("2.0.0"), nil)
+	root := NewRootSource()
+	root.AddPackage(MakeName("a"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+	root.AddPackage(MakeName("b"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	_, err := NewSolverWithOptions([]Source{root, source}, WithIncompatibilityTracking(true)).Solve(root.Term())
+	nsErr, ok := AsNoSolution(err)
+	if !ok {
+		t.Fatalf("expected NoSolutionError, got %v", err)
+	}
+
+	labelled := nsErr.WithRootLabel("your project").Error()
+	if strings.Contains(labelled, "$$root") {
+		t.Fatalf("root leaked into report:\n%s", labelled)
+	}
+	if !strings.Contains(labelled, "Because your project depends on a == 1.0.0") {
+		t.Fatalf("expected labelled root dependency:\n%s", labelled)
+	}
+	if !strings.HasSuffix(labelled, "version solving has failed.") {
+		t.Fatalf("expected the root conclusion to be elided:\n%s", labelled)
+	}
+
+	collapsed := nsErr.WithReporter(&CollapsedReporter{}).WithRootLabel("your project").Error()
+	if strings.Contains(collapsed, "$$root") || !strings.HasSuffix(collapsed, "version solving failed") {
+		t.Fatalf("unexpected collapsed report:\n%s", collapsed)
+	}
+
+	if !strings.Contains(nsErr.Error(), "$$root == 1 is forbidden.") {
+		t.Fatalf("default report should be unchanged:\n%s", nsErr.Error())
+	}
+}
@@ -0,0 +1,263 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// SourceSnapshot is implemented by Sources that can name the version of the
+// data they serve, for example a registry index revision. Two calls returning
+// the same SnapshotID must observe identical package data.
+type SourceSnapshot interface {
+	SnapshotID() string
+}
+
+// SnapshotID combines the snapshot identities of all sources. It returns
+// false when any source cannot identify its data.
+func (s CombinedSource) SnapshotID() (string, bool) {
+	ids := make([]string, 0, len(s))
+	for _, source := range s {
+		id, ok := sourceSnapshotID(source)
+		if !ok {
+			return "", false
+		}
+		ids = append(ids, id)
+	}
+	return strings.Join(ids, "+"), true
+}
+
+// sourceSnapshotID returns the identity of source's data, if known.
+// RootSource carries no package data of its own and identifies as "root";
+// requirements are part of the fingerprint instead.
+func sourceSnapshotID(source Source) (string, bool) {
+	switch src := source.(type) {
+	case SourceSnapshot:
+		return src.SnapshotID(), true
+	case CombinedSource:
+		return src.SnapshotID()
+	case RootSource, *RootSource:
+		return "root", true
+	default:
+		return "", false
+	}
+}
+
+// RequirementFingerprint returns a canonical key for a set of root
+// requirements resolved against the given source snapshot. Requirement order
+// and condition representation do not affect the result.
+func RequirementFingerprint(requirements []Term, snapshot string) string {
+	terms := make([]string, len(requirements))
+	for i, term := range requirements {
+		terms[i] = canonicalTerm(term)
+	}
+	slices.Sort(terms)
+
+	hash := sha256.New()
+	hash.Write([]byte(snapshot))
+	for _, term := range terms {
+		hash.Write([]byte{0})
+		hash.Write([]byte(term))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// ResolutionCacheStats reports cache effectiveness.
+type ResolutionCacheStats struct {
+	Hits      int
+	Misses    int
+	Uncached  int // Solves whose source had no snapshot identity
+	Entries   int
+	Evictions int
+}
+
+type resolutionEntry struct {
+	key          string
+	snapshot     string
+	requirements []Term
+	solution     Solution
+	fingerprint  string // solution.Fingerprint()
+}
+
+// ResolutionCache memoizes solutions for resolver services that see the same
+// requests repeatedly. Entries are keyed by RequirementFingerprint of the root
+// requirements and the snapshot identity of the source, so a new registry
+// revision naturally misses. Sources that do not implement SourceSnapshot are
+// solved without caching, since their data may change at any time.
+//
+// Only successful solutions are cached. Solver options are fixed at
+// construction so cached and fresh results always agree. The cache is safe
+// for concurrent use and evicts the least recently used entry when full.
+//
+// Example:
+//
+//	cache := NewResolutionCache(1024, WithMaxSteps(50000))
+//	solution, err := cache.Solve(*root, registry) // registry implements SourceSnapshot
+//	...
+//	cache.InvalidatePackage(MakeName("rack")) // after a yank
+type ResolutionCache struct {
+	mu         sync.Mutex
+	options    []SolverOption
+	maxEntries int
+	entries    map[string]*list.Element
+	lru        *list.List
+	stats      ResolutionCacheStats
+}
+
+// NewResolutionCache creates a cache holding at most maxEntries solutions
+// (unbounded when maxEntries <= 0). opts are applied to every solve.
+func NewResolutionCache(maxEntries int, opts ...SolverOption) *ResolutionCache {
+	return &ResolutionCache{
+		options:    slices.Clone(opts),
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// Solve returns the cached solution for root against source, solving and
+// caching it on a miss.
+func (c *ResolutionCache) Solve(root RootSource, source Source) (Solution, error) {
+	snapshot, ok := sourceSnapshotID(source)
+	if !ok {
+		c.mu.Lock()
+		c.stats.Uncached++
+		c.mu.Unlock()
+		return NewSolverWithOptions([]Source{root, source}, c.options...).Solve(root.Term())
+	}
+
+	key := RequirementFingerprint(root, snapshot)
+	if solution, ok := c.Get(key); ok {
+		return solution, nil
+	}
+
+	solution, err := NewSolverWithOptions([]Source{root, source}, c.options...).Solve(root.Term())
+	if err != nil {
+		return nil, err
+	}
+
+	c.put(&resolutionEntry{
+		key:          key,
+		snapshot:     snapshot,
+		requirements: slices.Clone([]Term(root)),
+		solution:     solution,
+		fingerprint:  solution.Fingerprint(),
+	})
+	return slices.Clone(solution), nil
+}
+
+// Get returns the solution cached under a fingerprint.
+func (c *ResolutionCache) Get(key string) (Solution, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses++
+		return nil, false
+	}
+	c.stats.Hits++
+	c.lru.MoveToFront(elem)
+	return slices.Clone(elem.Value.(*resolutionEntry).solution), true
+}
+
+// SolutionFingerprint returns the Solution.Fingerprint of the entry cached
+// under key without copying the solution or touching the statistics, so a
+// lockfile can be checked against a cached resolution cheaply.
+func (c *ResolutionCache) SolutionFingerprint(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	return elem.Value.(*resolutionEntry).fingerprint, true
+}
+
+func (c *ResolutionCache) put(entry *resolutionEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[entry.key]; ok {
+		elem.Value = entry
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.entries[entry.key] = c.lru.PushFront(entry)
+
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		c.removeLocked(c.lru.Back())
+		c.stats.Evictions++
+	}
+}
+
+// Invalidate drops the entry with the given fingerprint.
+func (c *ResolutionCache) Invalidate(key string) {
+	c.removeWhere(func(e *resolutionEntry) bool { return e.key == key })
+}
+
+// InvalidateSnapshot drops every entry resolved against a snapshot,
+// typically after the snapshot is retired.
+func (c *ResolutionCache) InvalidateSnapshot(snapshot string) {
+	c.removeWhere(func(e *resolutionEntry) bool { return e.snapshot == snapshot })
+}
+
+// InvalidatePackage drops every entry whose requirements or solution mention
+// name, for example after a release is yanked without a new snapshot.
+func (c *ResolutionCache) InvalidatePackage(name Name) {
+	c.removeWhere(func(e *resolutionEntry) bool {
+		if _, ok := e.solution.GetVersion(name); ok {
+			return true
+		}
+		return slices.ContainsFunc(e.requirements, func(t Term) bool { return t.Name == name })
+	})
+}
+
+// Clear drops every entry. Statistics are kept.
+func (c *ResolutionCache) Clear() {
+	c.removeWhere(func(*resolutionEntry) bool { return true })
+}
+
+// Stats returns a snapshot of the cache statistics.
+func (c *ResolutionCache) Stats() ResolutionCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Entries = c.lru.Len()
+	return stats
+}
+
+func (c *ResolutionCache) removeWhere(match func(*resolutionEntry) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for elem := c.lru.Front(); elem != nil; {
+		next := elem.Next()
+		if match(elem.Value.(*resolutionEntry)) {
+			c.removeLocked(elem)
+		}
+		elem = next
+	}
+}
+
+func (c *ResolutionCache) removeLocked(elem *list.Element) {
+	entry := c.lru.Remove(elem).(*resolutionEntry)
+	delete(c.entries, entry.key)
+}
@@ -0,0 +1,91 @@
+package pubgrub
+
+import "testing"
+
+type snapshotSource struct {
+	*InMemorySource
+	id    string
+	calls int
+}
+
+func (s *snapshotSource) SnapshotID() string { return s.id }
+
+func (s *snapshotSource) GetVersions(name Name) ([]Version, error) {
+	s.calls++
+	return s.InMemorySource.GetVersions(name)
+}
+
+func TestResolutionCacheHitsAndInvalidation(t *testing.T) {
+	mem := &InMemorySource{}
+	mem.AddPackage(MakeName("a"), SimpleVersion("1.0.0"), nil)
+	source := &snapshotSource{InMemorySource: mem, id: "rev-1"}
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("a"), NewVersionSetCondition(FullVersionSet()))
+
+	cache := NewResolutionCache(8)
+	if _, err := cache.Solve(*root, source); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	calls := source.calls
+
+	solution, err := cache.Solve(*root, source)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if source.calls != calls {
+		t.Fatalf("expected cached solve not to query the source")
+	}
+	if ver, _ := solution.GetVersion(MakeName("a")); ver == nil || ver.String() != "1.0.0" {
+		t.Fatalf("unexpected cached solution %v", solution)
+	}
+	if stats := cache.Stats(); stats.Hits != 1 || stats.Entries != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	key := RequirementFingerprint(*root, "rev-1")
+	if fp, ok := cache.SolutionFingerprint(key); !ok || fp != solution.Fingerprint() {
+		t.Fatalf("expected cached fingerprint %s, got %q", solution.Fingerprint(), fp)
+	}
+
+	source.id = "rev-2"
+	if _, err := cache.Solve(*root, source); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if source.calls == calls {
+		t.Fatalf("expected a new snapshot to miss the cache")
+	}
+
+	cache.InvalidatePackage(MakeName("a"))
+	if stats := cache.Stats(); stats.Entries != 0 {
+		t.Fatalf("expected package invalidation to drop all entries, got %+v", stats)
+	}
+}
+
+func TestResolutionCacheSkipsUnidentifiedSources(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("a"), SimpleVersion("1.0.0"), nil)
+	root := NewRootSource()
+	root.AddPackage(MakeName("a"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	cache := NewResolutionCache(8)
+	for range 2 {
+		if _, err := cache.Solve(*root, source); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if stats := cache.Stats(); stats.Uncached != 2 || stats.Entries != 0 {
+		t.Fatalf("expected uncached solves, got %+v", stats)
+	}
+}
+
+func TestRequirementFingerprintIsCanonical(t *testing.T) {
+	a := NewTerm(MakeName("a"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+	b := NewTerm(MakeName("b"), NewVersionSetCondition(FullVersionSet().Singleton(SimpleVersion("2.0.0"))))
+
+	if RequirementFingerprint([]Term{a, b}, "s") != RequirementFingerprint([]Term{b, a}, "s") {
+		t.Fatalf("expected order-independent fingerprint")
+	}
+	if RequirementFingerprint([]Term{a, b}, "s") == RequirementFingerprint([]Term{a, b}, "t") {
+		t.Fatalf("expected snapshot to affect fingerprint")
+	}
+}
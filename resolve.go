@@ -0,0 +1,89 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Resolve solves requirements against source in one call. Requirements map
+// package names to constraint strings in ParseVersionRange syntax; "" and
+// "*" accept any version and "@tag" requires the version a tag points at
+// (see TagCondition). The returned solution lists the resolved packages
+// without the synthetic root package. For anything beyond this, build a
+// RootSource and a Solver directly.
+//
+// Example:
+//
+//	solution, err := Resolve(ctx, map[string]string{
+//	    "rails": ">=7.0.0, <8.0.0",
+//	    "rack":  "*",
+//	}, source, WithIncompatibilityTracking(true))
+func Resolve(ctx context.Context, requirements map[string]string, source Source, opts ...SolverOption) (Solution, error) {
+	root, err := requirementsRoot(requirements)
+	if err != nil {
+		return nil, err
+	}
+
+	solver := NewSolverWithOptions([]Source{root, source}, opts...)
+	solution, err := solver.SolveContext(ctx, root.Term())
+	if err != nil {
+		return nil, err
+	}
+	rootName := root.Term().Name
+	return slices.DeleteFunc(solution, func(nv NameVersion) bool { return nv.Name == rootName }), nil
+}
+
+// requirementsRoot builds a RootSource from name -> constraint strings, in
+// name order so solves are reproducible.
+func requirementsRoot(requirements map[string]string) (*RootSource, error) {
+	names := make([]string, 0, len(requirements))
+	for name := range requirements {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	root := NewRootSource()
+	for _, name := range names {
+		condition, err := parseRequirement(requirements[name])
+		if err != nil {
+			return nil, fmt.Errorf("invalid requirement %s %q: %w", name, requirements[name], err)
+		}
+		root.AddPackage(MakeName(name), condition)
+	}
+	return root, nil
+}
+
+// parseRequirement parses a constraint string accepted by Resolve.
+func parseRequirement(constraint string) (Condition, error) {
+	constraint = strings.TrimSpace(constraint)
+	if constraint == "" {
+		return NewVersionSetCondition(FullVersionSet()), nil
+	}
+	if tag, ok := strings.CutPrefix(constraint, "@"); ok {
+		if tag == "" {
+			return nil, fmt.Errorf("empty tag")
+		}
+		return TagCondition{Tag: tag}, nil
+	}
+	set, err := ParseVersionRange(constraint)
+	if err != nil {
+		return nil, err
+	}
+	return NewVersionSetCondition(set), nil
+}
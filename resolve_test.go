@@ -0,0 +1,41 @@
+package pubgrub
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestResolveParsesRequirements(t *testing.T) {
+	mem := &InMemorySource{}
+	for _, v := range []string{"1.0.0", "2.0.0", "3.0.0-beta"} {
+		mem.AddPackage(MakeName("react"), SimpleVersion(v), nil)
+		mem.AddPackage(MakeName("lodash"), SimpleVersion(v), nil)
+		mem.AddPackage(MakeName("moment"), SimpleVersion(v), nil)
+	}
+	source := taggedTestSource{InMemorySource: mem, tags: map[Name]map[string]Version{
+		MakeName("react"): {"beta": SimpleVersion("2.0.0")},
+	}}
+
+	solution, err := Resolve(context.Background(), map[string]string{
+		"react":  "@beta",
+		"lodash": "<2.0.0",
+		"moment": "",
+	}, source)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(solution) != 3 {
+		t.Fatalf("expected the root to be dropped, got %v", solution)
+	}
+	for pkg, want := range map[string]string{"react": "2.0.0", "lodash": "1.0.0", "moment": "3.0.0-beta"} {
+		if ver, _ := solution.GetVersion(MakeName(pkg)); ver == nil || ver.String() != want {
+			t.Fatalf("expected %s %s, got %v", pkg, want, ver)
+		}
+	}
+
+	_, err = Resolve(context.Background(), map[string]string{"lodash": ">=1.0.0,"}, source)
+	if err == nil || !strings.Contains(err.Error(), `invalid requirement lodash ">=1.0.0,"`) {
+		t.Fatalf("expected a parse error naming the requirement, got %v", err)
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Result is a successful resolution together with metadata describing how
+// each package was fixed. Obtain one with Solver.SolveResult.
+type Result struct {
+	// Solution is the resolved package set, as returned by Solve.
+	Solution Solution
+	// Stats summarizes the work performed, including the steps consumed.
+	Stats SolveStats
+	// Fetches journals the GetDependencies calls made on the source, one
+	// per package version, in the order they were first needed.
+	Fetches []DependencyFetch
+
+	assignments []PackageAssignment
+	// stacks keeps the final assignment stack of every solved package, the
+	// constraint history ExplainChoice draws on.
+	stacks map[Name][]*assignment
+	source Source
+	root   Name
+}
+
+// PackageAssignment describes how a resolved package came to be fixed.
+type PackageAssignment struct {
+	Name    Name
+	Version Version
+	// DecisionLevel is the decision level at which the version was selected.
+	// The root is at level 0; each later decision opens a new level.
+	DecisionLevel int
+	// Allowed is the set of versions permitted by the constraints in force
+	// when the version was selected.
+	Allowed VersionSet
+	// Constraints lists the derived terms that bound Allowed, oldest first.
+	Constraints []Term
+}
+
+// Assignments returns per-package metadata in solution order. It helps
+// explain a surprising choice, such as an old version, on a successful solve:
+// Allowed shows what the constraints left and Constraints shows who imposed
+// them.
+//
+// Example:
+//
+//	result, _ := solver.SolveResult(ctx, root.Term())
+//	for _, a := range result.Assignments() {
+//	    fmt.Printf("%s %s (level %d, allowed %s)\n", a.Name.Value(), a.Version, a.DecisionLevel, a.Allowed)
+//	}
+func (r *Result) Assignments() []PackageAssignment {
+	return r.assignments
+}
+
+// SolveResult is like SolveContext but returns a Result carrying assignment
+// metadata for the solution. Options apply to this call only.
+//
+// Example:
+//
+//	result, err := solver.SolveResult(ctx, root.Term())
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Println(result.Solution)
+func (s *Solver) SolveResult(ctx context.Context, root Term, opts ...SolverOption) (*Result, error) {
+	derived := s.With(opts...)
+	derived.keepState = true
+	solution, err := derived.SolveContext(ctx, root)
+	s.learned = derived.learned
+	s.stats = derived.stats
+	s.timeline = derived.timeline
+	s.warnings = derived.warnings
+	if err != nil {
+		return nil, err
+	}
+	result := newResult(derived.lastState, solution)
+	result.Stats = derived.stats
+	return result, nil
+}
+
+// ExplainChoice reports why the selected version of name was chosen over
+// newer published versions, naming the constraint that excluded each one:
+//
+//	rubyzip 2.4.1 was selected over newer versions:
+//	  3.0.1, 3.0.0 excluded by rubyXL's requirement rubyzip <3.0.0
+//
+// Newer versions that no constraint excluded were passed over by the version
+// selection heuristic, which is reported as such.
+func (r *Result) ExplainChoice(name Name) string {
+	selected, ok := r.Solution.GetVersion(name)
+	if !ok {
+		return fmt.Sprintf("%s is not part of the solution", FormatName(name))
+	}
+	header := fmt.Sprintf("%s %s", FormatName(name), selected)
+
+	versions, err := r.source.GetVersions(name)
+	if err != nil {
+		return fmt.Sprintf("%s was selected; versions unavailable: %v", header, err)
+	}
+
+	// Walk newer versions from the newest down, grouping runs that share a
+	// reason.
+	var lines []string
+	var run []string
+	reason := ""
+	flush := func() {
+		if len(run) > 0 {
+			lines = append(lines, fmt.Sprintf("  %s %s", strings.Join(run, ", "), reason))
+		}
+	}
+	for i := len(versions) - 1; i >= 0; i-- {
+		ver := versions[i]
+		if ver.Sort(selected) <= 0 {
+			break
+		}
+		why := r.exclusionReason(name, ver)
+		if why != reason {
+			flush()
+			run, reason = nil, why
+		}
+		run = append(run, ver.String())
+	}
+	flush()
+
+	if len(lines) == 0 {
+		return header + " is the newest version"
+	}
+	return header + " was selected over newer versions:\n" + strings.Join(lines, "\n")
+}
+
+// exclusionReason names the first constraint in name's history that rules
+// out ver.
+func (r *Result) exclusionReason(name Name, ver Version) string {
+	for _, assign := range r.stacks[name] {
+		if assign.isDecision() || assign.state.Contains(ver) {
+			continue
+		}
+		return "excluded by " + describeConstraint(r.root, name, assign)
+	}
+	return "not excluded; an older version was preferred by version selection"
+}
+
+// describeConstraint renders the origin of a derived assignment, preferring
+// "app's requirement lib <2.0.0" for dependency constraints. root is the
+// root package of the solve.
+func describeConstraint(root, name Name, assign *assignment) string {
+	cause := assign.cause
+	if cause == nil {
+		return assign.term.String()
+	}
+	switch cause.Kind {
+	case KindFromDependency:
+		for _, term := range cause.Terms {
+			if term.Name != name {
+				continue
+			}
+			if !term.Positive {
+				term = term.Negate()
+			}
+			if cause.Package == root {
+				return "the root requirement " + term.String()
+			}
+			return fmt.Sprintf("%s's requirement %s", FormatName(cause.Package), term)
+		}
+	case KindPolicy:
+		return cause.String()
+	}
+	return fmt.Sprintf("%s, because %s", assign.term, cause)
+}
+
+// newResult captures assignment metadata from the final state of a solve.
+func newResult(st *solverState, solution Solution) *Result {
+	result := &Result{
+		Solution: solution,
+		Fetches:  slices.Clone(st.journal),
+		stacks:   make(map[Name][]*assignment, len(solution)),
+		source:   st.source,
+		root:     st.partial.root,
+	}
+	for _, nv := range solution {
+		stack := st.partial.perPackage[nv.Name]
+		result.stacks[nv.Name] = slices.Clone(stack)
+		entry := PackageAssignment{Name: nv.Name, Version: nv.Version, Allowed: fullVersionSet}
+		for _, assign := range stack {
+			if assign.isDecision() {
+				entry.DecisionLevel = assign.decisionLevel
+				break
+			}
+			entry.Allowed = assign.state
+			entry.Constraints = append(entry.Constraints, assign.term)
+		}
+		result.assignments = append(result.assignments, entry)
+	}
+	return result
+}
@@ -0,0 +1,110 @@
+package pubgrub
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSolveResultAssignments(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("lib"), NewVersionSetCondition(mustParseVersionRange(t, "<2.0.0"))),
+	})
+	source.AddPackage(MakeName("lib"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("lib"), SimpleVersion("2.0.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	solver := NewSolver(root, source)
+	result, err := solver.SolveResult(context.Background(), root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Assignments()) != len(result.Solution) {
+		t.Fatalf("expected one assignment per package, got %d for %d", len(result.Assignments()), len(result.Solution))
+	}
+
+	byName := make(map[string]PackageAssignment)
+	for _, a := range result.Assignments() {
+		byName[a.Name.Value()] = a
+	}
+
+	if rootAssign := byName["$$root"]; rootAssign.DecisionLevel != 0 {
+		t.Fatalf("expected root at level 0, got %d", rootAssign.DecisionLevel)
+	}
+	lib := byName["lib"]
+	if lib.Version.String() != "1.0.0" || lib.DecisionLevel == 0 {
+		t.Fatalf("expected lib 1.0.0 decided above level 0, got %+v", lib)
+	}
+	if lib.Allowed.String() != "<2.0.0" {
+		t.Fatalf("expected lib bounded by <2.0.0, got %s", lib.Allowed)
+	}
+	if len(lib.Constraints) != 1 || lib.Constraints[0].String() != "lib <2.0.0" {
+		t.Fatalf("expected app's requirement as the constraint, got %v", lib.Constraints)
+	}
+	if solver.Stats().Decisions == 0 {
+		t.Fatalf("expected stats to be recorded on the receiver")
+	}
+}
+
+func TestSolveResultFailure(t *testing.T) {
+	root := NewRootSource()
+	root.AddPackage(MakeName("missing"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	result, err := NewSolver(root, &InMemorySource{}).SolveResult(context.Background(), root.Term())
+	if err == nil || result != nil {
+		t.Fatalf("expected an error and no result, got %v, %v", result, err)
+	}
+}
+
+func TestResultExplainChoice(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("rubyXL"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("rubyzip"), NewVersionSetCondition(mustParseVersionRange(t, "<3.0.0"))),
+	})
+	for _, v := range []string{"2.3.0", "2.4.1", "3.0.0", "3.0.1"} {
+		source.AddPackage(MakeName("rubyzip"), SimpleVersion(v), nil)
+	}
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("rubyXL"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	result, err := NewSolver(root, source).SolveResult(context.Background(), root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "rubyzip 2.4.1 was selected over newer versions:\n" +
+		"  3.0.1, 3.0.0 excluded by rubyXL's requirement rubyzip <3.0.0"
+	if got := result.ExplainChoice(MakeName("rubyzip")); got != want {
+		t.Fatalf("unexpected explanation:\n%s\nwant:\n%s", got, want)
+	}
+	if got := result.ExplainChoice(MakeName("rubyXL")); got != "rubyXL 1.0.0 is the newest version" {
+		t.Fatalf("unexpected explanation for rubyXL: %s", got)
+	}
+	if got := result.ExplainChoice(MakeName("nokogiri")); got != "nokogiri is not part of the solution" {
+		t.Fatalf("unexpected explanation for missing package: %s", got)
+	}
+}
+
+func TestResultExplainChoiceNamedRoot(t *testing.T) {
+	source := &InMemorySource{}
+	for _, v := range []string{"2.4.1", "3.0.0"} {
+		source.AddPackage(MakeName("rubyzip"), SimpleVersion(v), nil)
+	}
+
+	root := NewRootSourceNamed(MakeName("myapp"))
+	root.AddPackage(MakeName("rubyzip"), NewVersionSetCondition(mustParseVersionRange(t, "<3.0.0")))
+
+	result, err := NewSolver(root, source).SolveResult(context.Background(), root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "rubyzip 2.4.1 was selected over newer versions:\n" +
+		"  3.0.0 excluded by the root requirement rubyzip <3.0.0"
+	if got := result.ExplainChoice(MakeName("rubyzip")); got != want {
+		t.Fatalf("unexpected explanation:\n%s\nwant:\n%s", got, want)
+	}
+}
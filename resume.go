@@ -0,0 +1,89 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// errHandleFinished is returned when resuming a solve that already ended.
+var errHandleFinished = errors.New("solve handle already finished")
+
+// SolveHandle is a solve interrupted by its step budget. It is carried by
+// ErrIterationLimit and keeps the complete solver state alive, so callers
+// with strict per-invocation budgets can spread one hard solve over several
+// invocations instead of failing at the limit. A handle can be resumed once;
+// a resume that runs out of budget again returns a new handle in its
+// ErrIterationLimit.
+type SolveHandle struct {
+	solver *Solver
+	state  *solverState
+}
+
+// Steps returns the number of steps consumed so far.
+func (h *SolveHandle) Steps() int {
+	if h == nil || h.state == nil {
+		return 0
+	}
+	return h.state.steps
+}
+
+// ResumeSolve continues an interrupted solve for up to additionalSteps more
+// steps; additionalSteps <= 0 removes the limit. The solve keeps the options
+// it was started with, and its learned incompatibilities, statistics and
+// warnings are recorded on the receiver. Stats().Steps counts every step
+// since the solve began.
+//
+// Example:
+//
+//	_, err := solver.Solve(root.Term(), WithMaxSteps(1000))
+//	var limit ErrIterationLimit
+//	for errors.As(err, &limit) && limit.Handle != nil {
+//	    _, err = solver.ResumeSolve(limit.Handle, 1000)
+//	}
+func (s *Solver) ResumeSolve(handle *SolveHandle, additionalSteps int) (Solution, error) {
+	return s.ResumeSolveContext(context.Background(), handle, additionalSteps)
+}
+
+// ResumeSolveContext is like ResumeSolve but stops with ctx.Err() once ctx is
+// done. A cancelled resume finishes the handle.
+func (s *Solver) ResumeSolveContext(ctx context.Context, handle *SolveHandle, additionalSteps int) (solution Solution, err error) {
+	if handle == nil || handle.state == nil {
+		return nil, errHandleFinished
+	}
+	state := handle.state
+	state.ctx = ctx
+	handle.state = nil
+
+	runner := handle.solver.With()
+	started := time.Now()
+	defer runner.logPhaseTimes(state)
+	defer func() {
+		state.clock.solveTime += time.Since(started)
+		s.learned = runner.learned
+		s.stats = state.snapshotStats()
+		s.timeline = state.buildTimeline(solution)
+		s.warnings = state.warnings
+	}()
+	defer runner.logHeuristicStats(state)
+
+	limit := 0
+	if additionalSteps > 0 {
+		limit = state.steps + additionalSteps
+	}
+	return runner.run(ctx, state, limit, nil)
+}
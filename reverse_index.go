@@ -0,0 +1,103 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ReverseIndex maps packages to their known dependents: for each package,
+// the distinct constraints declared on it by every published version of the
+// packages in a universe. It answers "who depends on rack, and with which
+// ranges" for impact analysis, advisory blast-radius queries and upgrade
+// planning.
+//
+// The index is computed lazily on the first query and then cached, so one
+// ReverseIndex can serve many queries; build a new one to pick up changes in
+// the source. It is safe for concurrent use.
+type ReverseIndex struct {
+	source   Source
+	universe []Name
+
+	mu         sync.Mutex
+	dependents map[Name][]AggregatedRequirement // nil until built
+}
+
+// BuildReverseIndex returns a reverse dependency index over the packages in
+// universe. No source calls are made until the index is first queried.
+// Packages the source does not know are skipped.
+//
+// Example:
+//
+//	index := BuildReverseIndex(registry, []Name{MakeName("rails"), MakeName("sidekiq")})
+//	reqs, err := index.Dependents(MakeName("rack"))
+//	for _, req := range reqs {
+//	    fmt.Println(req) // rack >=2.2.4 (rails 7.1.0, rails 7.1.1)
+//	}
+func BuildReverseIndex(source Source, universe []Name) *ReverseIndex {
+	return &ReverseIndex{source: source, universe: universe}
+}
+
+// Dependents returns the constraints declared on name, in the order they
+// were found, each with the package versions declaring it.
+func (r *ReverseIndex) Dependents(name Name) ([]AggregatedRequirement, error) {
+	index, err := r.Map()
+	if err != nil {
+		return nil, err
+	}
+	return index[name], nil
+}
+
+// Map returns the whole index, keyed by depended-upon package. The map is
+// shared by all callers and must not be modified.
+func (r *ReverseIndex) Map() (map[Name][]AggregatedRequirement, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.dependents != nil {
+		return r.dependents, nil
+	}
+
+	dependents := make(map[Name][]AggregatedRequirement)
+	positions := make(map[string]int) // Constraint -> index in dependents[constraint.Name]
+	for _, name := range r.universe {
+		versions, err := r.source.GetVersions(name)
+		if err != nil {
+			if isMissingPackage(err) {
+				continue
+			}
+			return nil, fmt.Errorf("reverse index: versions of %s: %w", FormatName(name), err)
+		}
+		for _, version := range versions {
+			deps, err := r.source.GetDependencies(name, version)
+			if err != nil {
+				return nil, fmt.Errorf("reverse index: dependencies of %s %s: %w", FormatName(name), version, err)
+			}
+			for _, dep := range deps {
+				key := dep.String()
+				i, ok := positions[key]
+				if !ok {
+					i = len(dependents[dep.Name])
+					positions[key] = i
+					dependents[dep.Name] = append(dependents[dep.Name], AggregatedRequirement{Requirement: dep})
+				}
+				declarer := NameVersion{Name: name, Version: version}
+				dependents[dep.Name][i].Declarers = append(dependents[dep.Name][i].Declarers, declarer)
+			}
+		}
+	}
+	r.dependents = dependents
+	return dependents, nil
+}
@@ -0,0 +1,51 @@
+package pubgrub
+
+import "testing"
+
+func TestReverseIndexListsDependentsLazily(t *testing.T) {
+	below3 := NewTerm(MakeName("rubyzip"), NewVersionSetCondition(mustParseVersionRange(t, "<3.0.0")))
+	atLeast2 := NewTerm(MakeName("rubyzip"), NewVersionSetCondition(mustParseVersionRange(t, ">=2.0.0")))
+	inner := &InMemorySource{}
+	inner.AddPackage(MakeName("rubyXL"), SimpleVersion("3.4.25"), []Term{below3})
+	inner.AddPackage(MakeName("rubyXL"), SimpleVersion("3.4.26"), []Term{below3})
+	inner.AddPackage(MakeName("caxlsx"), SimpleVersion("4.0.0"), []Term{
+		atLeast2,
+		NewTerm(MakeName("rubyXL"), NewVersionSetCondition(FullVersionSet())),
+	})
+	inner.AddPackage(MakeName("unrelated"), SimpleVersion("1.0.0"), []Term{below3})
+	source := &mockCountingSource{source: inner}
+
+	index := BuildReverseIndex(source, []Name{MakeName("rubyXL"), MakeName("caxlsx"), MakeName("ghost")})
+	if source.versionsCalls != 0 || source.depsCalls != 0 {
+		t.Fatalf("expected no source calls before the first query, got %d and %d", source.versionsCalls, source.depsCalls)
+	}
+
+	reqs, err := index.Dependents(MakeName("rubyzip"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reqs) != 2 {
+		t.Fatalf("expected two distinct requirements, got %v", reqs)
+	}
+	if got := reqs[0].String(); got != "rubyzip <3.0.0 (rubyXL 3.4.25, rubyXL 3.4.26)" {
+		t.Fatalf("unexpected first requirement: %s", got)
+	}
+	if got := reqs[1].String(); got != "rubyzip >=2.0.0 (caxlsx 4.0.0)" {
+		t.Fatalf("unexpected second requirement: %s", got)
+	}
+
+	calls := source.versionsCalls + source.depsCalls
+	reqs, err = index.Dependents(MakeName("rubyXL"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reqs) != 1 || reqs[0].Declarers[0].Name != MakeName("caxlsx") {
+		t.Fatalf("expected caxlsx to depend on rubyXL, got %v", reqs)
+	}
+	if got := source.versionsCalls + source.depsCalls; got != calls {
+		t.Fatalf("expected the index to be cached, got %d more source calls", got-calls)
+	}
+	if reqs, _ := index.Dependents(MakeName("caxlsx")); len(reqs) != 0 {
+		t.Fatalf("expected caxlsx to have no dependents, got %v", reqs)
+	}
+}
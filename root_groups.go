@@ -0,0 +1,280 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+)
+
+// RequirementGroup is a labelled set of root requirements that share an
+// origin, such as a Gemfile group or a plugin's manifest.
+type RequirementGroup struct {
+	// Label names the origin, for example "Gemfile group :test".
+	Label string
+	// Terms are the requirements declared by the group, in order.
+	Terms []Term
+}
+
+// GroupedRequirement is a root requirement together with the group that
+// declared it.
+type GroupedRequirement struct {
+	// Term is the requirement as declared.
+	Term Term
+	// Group is the label of the declaring group; empty for requirements
+	// added without one.
+	Group string
+}
+
+// String returns the requirement followed by its origin, for example
+// "rack >=3.0.0 (from Gemfile group :test)".
+func (r GroupedRequirement) String() string {
+	if r.Group == "" {
+		return r.Term.String()
+	}
+	return fmt.Sprintf("%s (from %s)", r.Term, r.Group)
+}
+
+// GroupedRootSource is a RootSource whose requirements are grouped under
+// labels, so a failed solve can be traced back to the manifest section or
+// plugin each conflicting requirement came from rather than just to a
+// package name. Requirements added through the embedded RootSource belong to
+// no group.
+//
+// Example:
+//
+//	root := NewGroupedRootSource()
+//	root.AddGroupPackage("Gemfile", MakeName("rails"), railsCondition)
+//	root.AddGroupPackage("Gemfile group :test", MakeName("rack"), rackCondition)
+//	solver := NewSolver(root, registry).EnableIncompatibilityTracking()
+//	if _, err := solver.Solve(root.Term()); err != nil {
+//	    for _, req := range root.ConflictingRequirements(err) {
+//	        fmt.Println(req) // rack >=3.0.0 (from Gemfile group :test)
+//	    }
+//	}
+type GroupedRootSource struct {
+	RootSource
+	// labels[i] is the group of RootSource[i]; shorter than RootSource
+	// when trailing requirements were added without a group.
+	labels []string
+}
+
+// NewGroupedRootSource creates a new empty grouped root source.
+func NewGroupedRootSource() *GroupedRootSource {
+	return &GroupedRootSource{}
+}
+
+// AddGroupPackage adds a requirement declared by the group label.
+func (s *GroupedRootSource) AddGroupPackage(label string, name Name, condition Condition) {
+	s.AddGroupTerm(label, NewTerm(name, condition))
+}
+
+// AddGroupTerm adds a requirement term, positive or negative, declared by the
+// group label.
+func (s *GroupedRootSource) AddGroupTerm(label string, term Term) {
+	for len(s.labels) < len(s.RootSource) {
+		s.labels = append(s.labels, "")
+	}
+	s.RootSource = append(s.RootSource, term)
+	s.labels = append(s.labels, label)
+}
+
+// Requirements returns every root requirement with its group, in the order
+// they were added.
+func (s *GroupedRootSource) Requirements() []GroupedRequirement {
+	result := make([]GroupedRequirement, len(s.RootSource))
+	for i, term := range s.RootSource {
+		result[i] = GroupedRequirement{Term: term, Group: s.label(i)}
+	}
+	return result
+}
+
+// Groups returns the labelled groups in the order their first requirement
+// was added. Requirements without a group are omitted.
+func (s *GroupedRootSource) Groups() []RequirementGroup {
+	var groups []RequirementGroup
+	index := make(map[string]int)
+	for i, term := range s.RootSource {
+		label := s.label(i)
+		if label == "" {
+			continue
+		}
+		j, ok := index[label]
+		if !ok {
+			j = len(groups)
+			index[label] = j
+			groups = append(groups, RequirementGroup{Label: label})
+		}
+		groups[j].Terms = append(groups[j].Terms, term)
+	}
+	return groups
+}
+
+// ConflictingRequirements returns the root requirements the failure in err
+// rests on: those named by the root dependency facts at the leaves of its
+// derivation, together with the groups that declared them. The solver merges
+// requirements on the same package, so every requirement on a package the
+// derivation mentions is returned.
+//
+// It returns nil when err carries no derivation, that is when it is not a
+// NoSolutionError from a solve with incompatibility tracking.
+func (s *GroupedRootSource) ConflictingRequirements(err error) []GroupedRequirement {
+	var nsErr *NoSolutionError
+	if !errors.As(err, &nsErr) || nsErr.Incompatibility == nil {
+		return nil
+	}
+	return s.conflicting(nsErr.Incompatibility)
+}
+
+// MinimalConflict returns a minimal set of root requirements that cannot be
+// satisfied together: dropping any one of them lets the rest solve. The
+// search starts from ConflictingRequirements and removes one requirement at a
+// time, re-solving after each removal, so it costs one solve per candidate.
+//
+// Returns nil when the requirements solve. Source errors other than a failed
+// resolution abort the search.
+func (s *GroupedRootSource) MinimalConflict(source Source, opts ...SolverOption) ([]GroupedRequirement, error) {
+	solver := NewSolverWithOptions([]Source{s.RootSource, source}, append(slices.Clone(opts), WithIncompatibilityTracking(true))...)
+	_, err := solver.Solve(s.Term())
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, ErrNoSolution) {
+		return nil, err
+	}
+
+	core := s.ConflictingRequirements(err)
+	if len(core) == 0 {
+		core = s.Requirements()
+	}
+	for i := 0; i < len(core); {
+		rest := slices.Delete(slices.Clone(core), i, i+1)
+		solved, _, err := solveRelaxed(groupedTerms(rest), source, opts)
+		if err != nil {
+			return nil, err
+		}
+		if solved {
+			i++
+			continue
+		}
+		core = rest
+	}
+	return core, nil
+}
+
+// label returns the group of the i-th requirement.
+func (s *GroupedRootSource) label(i int) string {
+	if i < len(s.labels) {
+		return s.labels[i]
+	}
+	return ""
+}
+
+// conflicting returns the requirements on packages named by root dependency
+// leaves of incomp, in declaration order.
+func (s *GroupedRootSource) conflicting(incomp *Incompatibility) []GroupedRequirement {
+	named := make(map[Name]bool)
+	for _, leaf := range conflictLeaves(incomp) {
+		if leaf.Kind != KindFromDependency || leaf.Package != MakeName("$$root") {
+			continue
+		}
+		for _, term := range leaf.Terms {
+			if term.Name != leaf.Package {
+				named[term.Name] = true
+			}
+		}
+	}
+
+	var result []GroupedRequirement
+	for i, term := range s.RootSource {
+		if named[term.Name] {
+			result = append(result, GroupedRequirement{Term: term, Group: s.label(i)})
+		}
+	}
+	return result
+}
+
+// groupedTerms returns the terms of reqs as a root source.
+func groupedTerms(reqs []GroupedRequirement) RootSource {
+	terms := make(RootSource, len(reqs))
+	for i, req := range reqs {
+		terms[i] = req.Term
+	}
+	return terms
+}
+
+// GroupReporter appends the origin of each conflicting root requirement to
+// another reporter's output, see GroupedRootSource.
+//
+// Example:
+//
+//	err := nsErr.WithReporter(&GroupReporter{
+//	    Reporter: &RootLabelReporter{Label: "Gemfile"},
+//	    Groups:   root,
+//	})
+type GroupReporter struct {
+	// Reporter formats the derivation.
+	// Default: DefaultReporter
+	Reporter Reporter
+	// Groups holds the root requirements and their labels.
+	Groups *GroupedRootSource
+}
+
+// Report implements Reporter
+func (r *GroupReporter) Report(incomp *Incompatibility) string {
+	reporter := r.Reporter
+	if reporter == nil {
+		reporter = &DefaultReporter{}
+	}
+	out := reporter.Report(incomp)
+	if r.Groups == nil {
+		return out
+	}
+
+	reqs := r.Groups.conflicting(incomp)
+	if !slices.ContainsFunc(reqs, func(req GroupedRequirement) bool { return req.Group != "" }) {
+		return out
+	}
+	_, isHTML := reporter.(*HTMLReporter)
+	var b strings.Builder
+	b.WriteString(out)
+	b.WriteString("\n\nConflicting requirements:")
+	for _, req := range reqs {
+		line := req.String()
+		if isHTML {
+			line = html.EscapeString(line)
+		}
+		b.WriteString("\n  ")
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+// WithRequirementGroups returns a new error whose report lists the groups
+// that declared the conflicting root requirements, see GroupReporter.
+//
+// Example:
+//
+//	if nsErr, ok := AsNoSolution(err); ok {
+//	    fmt.Println(nsErr.WithRequirementGroups(root))
+//	}
+func (e *NoSolutionError) WithRequirementGroups(groups *GroupedRootSource) *NoSolutionError {
+	return e.WithReporter(&GroupReporter{Reporter: e.Reporter, Groups: groups})
+}
+
+var _ Source = &GroupedRootSource{}
@@ -0,0 +1,149 @@
+package pubgrub
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGroupedRootSourceGroups(t *testing.T) {
+	root := NewGroupedRootSource()
+	root.AddGroupPackage("Gemfile", MakeName("rails"), EqualsCondition{Version: mustSemver(t, "7.0.0")})
+	root.AddGroupPackage("Gemfile", MakeName("puma"), EqualsCondition{Version: mustSemver(t, "6.0.0")})
+	root.AddGroupPackage("Gemfile group :test", MakeName("capybara"), EqualsCondition{Version: mustSemver(t, "3.0.0")})
+	root.AddGroupPackage("plugin sidekiq", MakeName("rack"), NewVersionSetCondition(mustParseVersionRange(t, ">=2.0.0")))
+	root.AddPackage(MakeName("bundler"), NewVersionSetCondition(FullVersionSet()))
+
+	groups := root.Groups()
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 groups, got %v", groups)
+	}
+	if groups[0].Label != "Gemfile" || len(groups[0].Terms) != 2 {
+		t.Fatalf("unexpected first group: %v", groups[0])
+	}
+	reqs := root.Requirements()
+	if len(reqs) != 5 || reqs[4].Group != "" {
+		t.Fatalf("expected ungrouped bundler requirement last, got %v", reqs)
+	}
+	if got := reqs[2].String(); got != "capybara == 3.0.0 (from Gemfile group :test)" {
+		t.Fatalf("unexpected requirement string %q", got)
+	}
+
+	root.AddGroupPackage("plugin sidekiq", MakeName("redis"), nil)
+	if reqs := root.Requirements(); reqs[4].Group != "" || reqs[5].Group != "plugin sidekiq" {
+		t.Fatalf("grouping shifted after an ungrouped requirement: %v", reqs)
+	}
+}
+
+func TestGroupedRootSourceConflictingRequirements(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("rack"), mustSemver(t, "2.0.0"), nil)
+	source.AddPackage(MakeName("rack"), mustSemver(t, "3.0.0"), nil)
+	source.AddPackage(MakeName("puma"), mustSemver(t, "6.0.0"), nil)
+	source.AddPackage(MakeName("rails"), mustSemver(t, "7.0.0"), []Term{
+		NewTerm(MakeName("rack"), NewVersionSetCondition(mustParseVersionRange(t, ">=3.0.0"))),
+	})
+	source.AddPackage(MakeName("capybara"), mustSemver(t, "3.0.0"), []Term{
+		NewTerm(MakeName("rack"), NewVersionSetCondition(mustParseVersionRange(t, "<3.0.0"))),
+	})
+
+	root := NewGroupedRootSource()
+	root.AddGroupPackage("Gemfile", MakeName("rails"), EqualsCondition{Version: mustSemver(t, "7.0.0")})
+	root.AddGroupPackage("Gemfile", MakeName("puma"), EqualsCondition{Version: mustSemver(t, "6.0.0")})
+	root.AddGroupPackage("Gemfile group :test", MakeName("capybara"), EqualsCondition{Version: mustSemver(t, "3.0.0")})
+	root.AddGroupPackage("plugin sidekiq", MakeName("rack"), NewVersionSetCondition(mustParseVersionRange(t, ">=2.0.0")))
+
+	_, err := NewSolver(root, source).EnableIncompatibilityTracking().Solve(root.Term())
+	if err == nil {
+		t.Fatal("expected the solve to fail")
+	}
+
+	groups := make(map[string]bool)
+	for _, req := range root.ConflictingRequirements(err) {
+		groups[req.Group] = true
+		if req.Term.Name == MakeName("puma") {
+			t.Fatalf("puma takes no part in the conflict: %v", req)
+		}
+	}
+	if !groups["Gemfile"] || !groups["Gemfile group :test"] {
+		t.Fatalf("expected both Gemfile groups to be blamed, got %v", groups)
+	}
+
+	if root.ConflictingRequirements(ErrNoSolutionFound{}) != nil {
+		t.Fatal("expected nil without a derivation")
+	}
+}
+
+func TestGroupedRootSourceMinimalConflict(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("rack"), mustSemver(t, "2.0.0"), nil)
+	source.AddPackage(MakeName("rack"), mustSemver(t, "3.0.0"), nil)
+	source.AddPackage(MakeName("puma"), mustSemver(t, "6.0.0"), nil)
+	source.AddPackage(MakeName("rails"), mustSemver(t, "7.0.0"), []Term{
+		NewTerm(MakeName("rack"), NewVersionSetCondition(mustParseVersionRange(t, ">=3.0.0"))),
+	})
+	source.AddPackage(MakeName("capybara"), mustSemver(t, "3.0.0"), []Term{
+		NewTerm(MakeName("rack"), NewVersionSetCondition(mustParseVersionRange(t, "<3.0.0"))),
+	})
+
+	root := NewGroupedRootSource()
+	root.AddGroupPackage("Gemfile", MakeName("rails"), EqualsCondition{Version: mustSemver(t, "7.0.0")})
+	root.AddGroupPackage("Gemfile", MakeName("puma"), EqualsCondition{Version: mustSemver(t, "6.0.0")})
+	root.AddGroupPackage("Gemfile group :test", MakeName("capybara"), EqualsCondition{Version: mustSemver(t, "3.0.0")})
+	root.AddGroupPackage("plugin sidekiq", MakeName("rack"), NewVersionSetCondition(mustParseVersionRange(t, ">=2.0.0")))
+
+	core, err := root.MinimalConflict(source)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []string
+	for _, req := range core {
+		got = append(got, req.String())
+	}
+	want := []string{
+		"rails == 7.0.0 (from Gemfile)",
+		"capybara == 3.0.0 (from Gemfile group :test)",
+	}
+	if strings.Join(got, "; ") != strings.Join(want, "; ") {
+		t.Fatalf("unexpected minimal conflict %v, want %v", got, want)
+	}
+
+	solvable := NewGroupedRootSource()
+	solvable.AddGroupPackage("Gemfile", MakeName("puma"), nil)
+	if core, err := solvable.MinimalConflict(source); err != nil || core != nil {
+		t.Fatalf("expected nil for a solvable root, got %v, %v", core, err)
+	}
+}
+
+func TestGroupReporterListsOrigins(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("rack"), mustSemver(t, "2.0.0"), nil)
+	source.AddPackage(MakeName("rack"), mustSemver(t, "3.0.0"), nil)
+	source.AddPackage(MakeName("puma"), mustSemver(t, "6.0.0"), nil)
+	source.AddPackage(MakeName("rails"), mustSemver(t, "7.0.0"), []Term{
+		NewTerm(MakeName("rack"), NewVersionSetCondition(mustParseVersionRange(t, ">=3.0.0"))),
+	})
+	source.AddPackage(MakeName("capybara"), mustSemver(t, "3.0.0"), []Term{
+		NewTerm(MakeName("rack"), NewVersionSetCondition(mustParseVersionRange(t, "<3.0.0"))),
+	})
+
+	root := NewGroupedRootSource()
+	root.AddGroupPackage("Gemfile", MakeName("rails"), EqualsCondition{Version: mustSemver(t, "7.0.0")})
+	root.AddGroupPackage("Gemfile", MakeName("puma"), EqualsCondition{Version: mustSemver(t, "6.0.0")})
+	root.AddGroupPackage("Gemfile group :test", MakeName("capybara"), EqualsCondition{Version: mustSemver(t, "3.0.0")})
+	root.AddGroupPackage("plugin sidekiq", MakeName("rack"), NewVersionSetCondition(mustParseVersionRange(t, ">=2.0.0")))
+
+	_, err := NewSolver(root, source).EnableIncompatibilityTracking().Solve(root.Term())
+	nsErr, ok := AsNoSolution(err)
+	if !ok {
+		t.Fatalf("expected a NoSolutionError, got %v", err)
+	}
+
+	msg := nsErr.WithRootLabel("Gemfile").WithRequirementGroups(root).Error()
+	if !strings.Contains(msg, "Conflicting requirements:") ||
+		!strings.Contains(msg, "capybara == 3.0.0 (from Gemfile group :test)") {
+		t.Fatalf("expected group attribution in report:\n%s", msg)
+	}
+	if strings.Contains(msg, "$$root") {
+		t.Fatalf("expected the root label to be kept:\n%s", msg)
+	}
+}
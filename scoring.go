@@ -0,0 +1,112 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+// ScoreRequest describes a candidate version being scored by VersionLookahead.
+type ScoreRequest struct {
+	Package Name
+	Version Version
+	// Dependencies are the version's dependencies after augmentation,
+	// overrides and replacements. Nil when they could not be fetched.
+	Dependencies []Term
+	// Allowed returns the versions of a package the partial solution still
+	// allows.
+	Allowed func(Name) VersionSet
+}
+
+// ScoreProvider rates candidate versions for VersionLookahead. The solver
+// scores the newest few allowed versions of a package and decides on the
+// highest score, breaking ties with SolverOptions.TieBreak. A score of
+// ScoreConflict or below marks a version whose dependencies cannot be met
+// under the current assumptions.
+//
+// Scores are memoized per package version for the duration of a solve, so
+// a provider should not depend on anything that changes between decisions
+// beyond what ScoreRequest exposes at first use.
+//
+// Experimental: ScoreProvider, ScoreRequest and the scores DependencyScore
+// returns may change in a minor release.
+//
+// Example:
+//
+//	// Prefer versions without native extensions, then fall back to the
+//	// built-in scoring.
+//	scorer := ScoreProviderFunc(func(req ScoreRequest) int {
+//	    score := DependencyScore.Score(req)
+//	    if hasNativeExtension(req.Package, req.Version) {
+//	        score -= 500
+//	    }
+//	    return score
+//	})
+type ScoreProvider interface {
+	Score(req ScoreRequest) int
+}
+
+// ScoreProviderFunc adapts a function to the ScoreProvider interface.
+type ScoreProviderFunc func(req ScoreRequest) int
+
+// Score implements ScoreProvider.
+func (f ScoreProviderFunc) Score(req ScoreRequest) int {
+	return f(req)
+}
+
+// ScoreConflict is the score DependencyScore gives a version with a
+// dependency no allowed version satisfies.
+const ScoreConflict = versionScoreConflictPenalty
+
+// DependencyScore is the built-in ScoreProvider. It rates a version by the
+// flexibility of its dependencies: each dependency adds more the more
+// versions it leaves allowed, and a dependency nothing satisfies yields
+// ScoreConflict. This avoids versions whose dependencies would immediately
+// lead to conflicts or narrow the search space too aggressively.
+var DependencyScore ScoreProvider = ScoreProviderFunc(dependencyScore)
+
+func dependencyScore(req ScoreRequest) int {
+	totalScore := versionScoreBaseline
+	if len(req.Dependencies) == 0 {
+		return totalScore
+	}
+
+	for _, dep := range req.Dependencies {
+		// For each dependency, estimate how many versions it could accept
+		// We'll use a simple heuristic: tighter constraints = lower score
+		projected, err := applyTermToAllowed(req.Allowed(dep.Name), dep)
+		if err != nil {
+			// If we can't project the constraint, treat it as neutral.
+			totalScore += versionScoreBaseline
+			continue
+		}
+		score := constraintScoreForSet(projected)
+		switch {
+		case score == constraintScoreEmpty:
+			// No versions satisfy this dependency under the current assumptions.
+			return versionScoreConflictPenalty
+		case score >= constraintScoreUnbounded:
+			totalScore += versionScoreUnboundedBonus
+		case score == constraintScoreUnknown:
+			totalScore += versionScoreBaseline
+		default:
+			if adjustment := versionScoreBaseline - score; adjustment > 0 {
+				totalScore += adjustment
+			} else {
+				totalScore++
+			}
+		}
+	}
+
+	return totalScore
+}
+
+var _ ScoreProvider = ScoreProviderFunc(nil)
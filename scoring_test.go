@@ -0,0 +1,80 @@
+package pubgrub
+
+import "testing"
+
+func TestDependencyScoreIsDefault(t *testing.T) {
+	full := NewVersionSetCondition(FullVersionSet())
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("native"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("lib"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("lib"), SimpleVersion("2.0.0"), nil)
+	source.AddPackage(MakeName("lib"), SimpleVersion("3.0.0"), []Term{NewTerm(MakeName("native"), full)})
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("lib"), full)
+
+	solution, err := NewSolver(root, source).Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, _ := solution.GetVersion(MakeName("lib")); ver.String() != "3.0.0" {
+		t.Fatalf("expected the unconstrained dependency to win, got lib %v", ver)
+	}
+}
+
+func TestScoreProviderChangesSelection(t *testing.T) {
+	full := NewVersionSetCondition(FullVersionSet())
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("native"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("lib"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("lib"), SimpleVersion("2.0.0"), nil)
+	source.AddPackage(MakeName("lib"), SimpleVersion("3.0.0"), []Term{NewTerm(MakeName("native"), full)})
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("lib"), full)
+
+	var requests []ScoreRequest
+	avoidNative := ScoreProviderFunc(func(req ScoreRequest) int {
+		requests = append(requests, req)
+		score := DependencyScore.Score(req)
+		for _, dep := range req.Dependencies {
+			if dep.Name == MakeName("native") {
+				score -= 5000
+			}
+		}
+		return score
+	})
+
+	solver := NewSolverWithOptions([]Source{root, source}, WithScoreProvider(avoidNative))
+	solution, err := solver.Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, _ := solution.GetVersion(MakeName("lib")); ver.String() != "2.0.0" {
+		t.Fatalf("expected the newest version without native, got lib %v", ver)
+	}
+	if _, ok := solution.GetVersion(MakeName("native")); ok {
+		t.Fatalf("expected native to stay out of the solution")
+	}
+	if len(requests) != 3 {
+		t.Fatalf("expected each lib version to be scored once, got %d requests", len(requests))
+	}
+	for _, req := range requests {
+		if req.Package != MakeName("lib") || req.Allowed == nil {
+			t.Fatalf("unexpected request %+v", req)
+		}
+	}
+}
+
+func TestScoreConflictFromDependencyScore(t *testing.T) {
+	dep := NewTerm(MakeName("x"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+	score := DependencyScore.Score(ScoreRequest{
+		Package:      MakeName("lib"),
+		Version:      SimpleVersion("1.0.0"),
+		Dependencies: []Term{dep},
+		Allowed:      func(Name) VersionSet { return EmptyVersionSet() },
+	})
+	if score != ScoreConflict {
+		t.Fatalf("expected ScoreConflict, got %d", score)
+	}
+}
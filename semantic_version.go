@@ -103,7 +103,14 @@ func (sv *SemanticVersion) String() string {
 // 1. Compare major, minor, patch numerically
 // 2. Pre-release versions have lower precedence than normal versions
 // 3. Build metadata is ignored for comparison
+// 4. Against a GemVersion, the RubyGems rules of GemVersion apply
 func (sv *SemanticVersion) Sort(other Version) int {
+	if nd, ok := other.(NumericDottedVersion); ok {
+		return -nd.compareSemantic(sv)
+	}
+	if gv, ok := other.(*GemVersion); ok {
+		return -gv.Sort(sv)
+	}
 	otherSV, ok := other.(*SemanticVersion)
 	if !ok {
 		// Fallback to string comparison if types don't match
@@ -223,6 +230,69 @@ func NewSemanticVersionWithPrerelease(major, minor, patch int, prerelease string
 	}
 }
 
+// CoerceSemanticVersion normalizes a malformed version string from registry
+// data into a SemanticVersion and describes every change it made, so adapters
+// can log them instead of silently falling back to SimpleVersion and losing
+// ordering. It handles:
+//   - a leading "v" or "=" ("v1.2.3" -> "1.2.3")
+//   - missing minor and patch segments ("1.2" -> "1.2.0")
+//   - zero padding ("1.02.3" -> "1.2.3")
+//
+// Strings that cannot be coerced return an error. That includes segments
+// beyond the patch ("1.2.3.4"): no SemanticVersion keeps them ordered, so
+// such versions need NumericDottedVersion instead. A well-formed version
+// returns no changes.
+//
+// Example:
+//
+//	ver, changes, err := CoerceSemanticVersion("v1.02")
+//	// ver is 1.2.0; changes lists the removed prefix, the stripped padding
+//	// and the added patch segment
+func CoerceSemanticVersion(s string) (*SemanticVersion, []string, error) {
+	var changes []string
+	raw := strings.TrimSpace(s)
+	if raw != s {
+		changes = append(changes, "trimmed surrounding whitespace")
+	}
+	for _, prefix := range []string{"=", "v", "V"} {
+		if rest, ok := strings.CutPrefix(raw, prefix); ok {
+			changes = append(changes, fmt.Sprintf("removed leading %q", prefix))
+			raw = rest
+		}
+	}
+
+	sv := &SemanticVersion{}
+	core, build, _ := strings.Cut(raw, "+")
+	sv.Build = build
+	core, sv.Prerelease, _ = strings.Cut(core, "-")
+
+	segments := strings.Split(core, ".")
+	numbers := make([]int, 0, 3)
+	for i, segment := range segments {
+		if segment == "" || strings.Trim(segment, "0123456789") != "" {
+			return nil, nil, fmt.Errorf("cannot coerce version %q: invalid segment %q", s, segment)
+		}
+		if i >= 3 {
+			return nil, nil, fmt.Errorf("cannot coerce version %q: segments beyond the patch would not keep their order, use NumericDottedVersion", s)
+		}
+		if trimmed := strings.TrimLeft(segment, "0"); len(segment) > 1 && trimmed != segment {
+			changes = append(changes, fmt.Sprintf("stripped zero padding from %q", segment))
+		}
+		n, err := strconv.Atoi(segment)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot coerce version %q: %w", s, err)
+		}
+		numbers = append(numbers, n)
+	}
+	for _, missing := range []string{"minor", "patch"}[len(numbers)-1:] {
+		changes = append(changes, fmt.Sprintf("added missing %s segment 0", missing))
+	}
+
+	numbers = append(numbers, 0, 0)
+	sv.Major, sv.Minor, sv.Patch = numbers[0], numbers[1], numbers[2]
+	return sv, changes, nil
+}
+
 // Verify interface compliance
 var (
 	_ Version = (*SemanticVersion)(nil)
@@ -15,6 +15,7 @@
 package pubgrub_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/contriboss/pubgrub-go"
@@ -359,3 +360,54 @@ func TestEdgeCases(t *testing.T) {
 		}
 	})
 }
+
+func TestCoerceSemanticVersion(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		changes int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"1.2.3-beta.1+abc", "1.2.3-beta.1+abc", 0},
+		{"v1.2", "1.2.0", 2},
+		{"=1.2.3", "1.2.3", 1},
+		{"1.02.3", "1.2.3", 1},
+		{" V01 ", "1.0.0", 5},
+	}
+
+	for _, tt := range tests {
+		got, changes, err := pubgrub.CoerceSemanticVersion(tt.input)
+		if err != nil {
+			t.Fatalf("CoerceSemanticVersion(%q) error: %v", tt.input, err)
+		}
+		if got.String() != tt.want {
+			t.Fatalf("CoerceSemanticVersion(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+		if len(changes) != tt.changes {
+			t.Fatalf("CoerceSemanticVersion(%q) reported %d changes, want %d: %v", tt.input, len(changes), tt.changes, changes)
+		}
+	}
+
+	for _, input := range []string{"", "1..2", "1.x.3", "1.2.3.beta", "latest"} {
+		if _, _, err := pubgrub.CoerceSemanticVersion(input); err == nil {
+			t.Fatalf("CoerceSemanticVersion(%q) expected error", input)
+		}
+	}
+
+	// A fourth segment would have to move to build metadata, where 1.2.3.4
+	// and 1.2.3.5 compare equal.
+	for _, input := range []string{"1.2.3.4", "1.2.3.4+build"} {
+		_, _, err := pubgrub.CoerceSemanticVersion(input)
+		if err == nil || !strings.Contains(err.Error(), "NumericDottedVersion") {
+			t.Fatalf("CoerceSemanticVersion(%q) = %v, want an error suggesting NumericDottedVersion", input, err)
+		}
+	}
+}
+
+func TestCoerceSemanticVersionPreservesOrdering(t *testing.T) {
+	older, _, _ := pubgrub.CoerceSemanticVersion("v1.9")
+	newer, _, _ := pubgrub.CoerceSemanticVersion("1.010.0")
+	if older.Sort(newer) >= 0 {
+		t.Fatalf("expected %s < %s", older, newer)
+	}
+}
@@ -20,7 +20,8 @@ import "strings"
 // SimpleVersion provides a basic string-based version implementation.
 // Versions are compared lexicographically using string comparison.
 //
-// For semantic versioning support, use SemanticVersion instead.
+// For semantic versioning support, use SemanticVersion instead; for dotted
+// numeric strings with any number of segments, use NumericDottedVersion.
 //
 // Example:
 //
@@ -0,0 +1,110 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import "fmt"
+
+// PackageSlack describes how much room the constraints of a solution leave a
+// resolved package to move up.
+type PackageSlack struct {
+	Name    Name
+	Current Version
+	// Newer counts the published versions newer than Current.
+	Newer int
+	// Slack counts the newer versions every requirement in the solution
+	// accepts. Newer - Slack versions are held back by constraints.
+	Slack int
+	// Tightest is the requirement accepting the fewest published versions,
+	// the one to relax first; nil when nothing in the solution requires the
+	// package.
+	Tightest *OutdatedBlocker
+}
+
+// String renders a one-line summary of the slack.
+func (p PackageSlack) String() string {
+	s := fmt.Sprintf("%s %s: %d of %d newer versions allowed", FormatName(p.Name), p.Current, p.Slack, p.Newer)
+	if p.Tightest != nil {
+		s += ", tightest: " + p.Tightest.describe()
+	}
+	return s
+}
+
+// describe names the dependent imposing the requirement.
+func (b OutdatedBlocker) describe() string {
+	if b.Dependent == MakeName("$$root") {
+		return "root " + b.Requirement.String()
+	}
+	return fmt.Sprintf("%s %s requires %s", FormatName(b.Dependent), b.Version, b.Requirement)
+}
+
+// SlackReport reports, for every resolved package of sol, how many newer
+// versions exist within its binding constraints and which requirement binds
+// it tightest, so maintainers can target the constraints holding back
+// upgrades. Packages are listed in solution order; the root and packages
+// whose versions cannot be fetched are skipped.
+//
+// Example:
+//
+//	for _, pkg := range SlackReport(solution, source) {
+//	    if pkg.Slack < pkg.Newer {
+//	        fmt.Println(pkg)
+//	    }
+//	}
+func SlackReport(sol Solution, source Source) []PackageSlack {
+	requirements := solutionRequirements(sol, source)
+
+	var report []PackageSlack
+	for _, nv := range sol {
+		if nv.Name == MakeName("$$root") {
+			continue
+		}
+		versions, err := source.GetVersions(nv.Name)
+		if err != nil {
+			continue
+		}
+
+		entry := PackageSlack{Name: nv.Name, Current: nv.Version}
+		reqs := requirements[nv.Name]
+		for _, ver := range versions {
+			if ver.Sort(nv.Version) <= 0 {
+				continue
+			}
+			entry.Newer++
+			if acceptedByAll(ver, reqs) {
+				entry.Slack++
+			}
+		}
+
+		tightest := -1
+		for _, req := range reqs {
+			accepted := 0
+			for _, ver := range versions {
+				if req.term.SatisfiedBy(ver) {
+					accepted++
+				}
+			}
+			if entry.Tightest == nil || accepted < tightest {
+				tightest = accepted
+				entry.Tightest = &OutdatedBlocker{
+					Dependent:   req.dependent.Name,
+					Version:     req.dependent.Version,
+					Requirement: req.term,
+				}
+			}
+		}
+		report = append(report, entry)
+	}
+	return report
+}
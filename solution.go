@@ -16,8 +16,12 @@
 package pubgrub
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"iter"
+	"slices"
+	"strings"
 )
 
 // NameVersion represents a resolved package with its selected version.
@@ -25,11 +29,21 @@ import (
 type NameVersion struct {
 	Name    Name
 	Version Version
+	// Source names the source that supplied the version when it was
+	// resolved from a NamedSource or ProvenanceSource within a
+	// CombinedSource, and is empty otherwise.
+	Source string
+	// Dependencies lists the packages of the solution this version depends
+	// on, as retained by the solver. Prune walks these edges.
+	Dependencies []Name
+	// Metadata is what a MetadataProvider source attached to the version,
+	// or nil.
+	Metadata any
 }
 
 // String returns a human-readable representation of the package-version pair.
 func (n NameVersion) String() string {
-	return fmt.Sprintf("%s %s", n.Name.Value(), n.Version)
+	return fmt.Sprintf("%s %s", FormatName(n.Name), n.Version)
 }
 
 // Solution represents the complete set of resolved package versions.
@@ -75,3 +89,28 @@ func (s Solution) All() iter.Seq[NameVersion] {
 		}
 	}
 }
+
+// Fingerprint returns a stable hash of the solution over its sorted
+// name, version and source tuples. Two solutions have the same fingerprint
+// exactly when they select the same versions from the same sources, whatever
+// their order, so lockfile tooling can use it to detect drift.
+//
+// Example:
+//
+//	if solution.Fingerprint() != lock.Fingerprint {
+//	    log.Println("lockfile is out of date")
+//	}
+func (s Solution) Fingerprint() string {
+	entries := make([]string, len(s))
+	for i, nv := range s {
+		entries[i] = strings.Join([]string{nv.Name.Value(), nv.Version.String(), nv.Source}, "\x00")
+	}
+	slices.Sort(entries)
+
+	hash := sha256.New()
+	for _, entry := range entries {
+		hash.Write([]byte(entry))
+		hash.Write([]byte{0xff})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
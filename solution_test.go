@@ -0,0 +1,51 @@
+package pubgrub
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSolutionFingerprintIsStable(t *testing.T) {
+	a := NameVersion{Name: MakeName("a"), Version: SimpleVersion("1.0.0")}
+	b := NameVersion{Name: MakeName("b"), Version: SimpleVersion("2.0.0"), Source: "internal"}
+
+	base := Solution{a, b}.Fingerprint()
+	if got := (Solution{b, a}).Fingerprint(); got != base {
+		t.Fatalf("expected order not to matter")
+	}
+
+	bumped := b
+	bumped.Version = SimpleVersion("2.0.1")
+	moved := b
+	moved.Source = "public"
+	for _, changed := range []Solution{{a, bumped}, {a, moved}, {a}} {
+		if changed.Fingerprint() == base {
+			t.Fatalf("expected %v to change the fingerprint", changed)
+		}
+	}
+}
+
+func TestSolutionRecordsNamedSources(t *testing.T) {
+	root, internal, public := duplicateSources()
+
+	var fingerprints []string
+	for _, precedence := range []SourcePrecedence{FirstSourceWins, LastSourceWins} {
+		solver := NewSolverWithOptions([]Source{root, internal, public}, WithSourcePrecedence(precedence))
+		solution, err := solver.Solve(root.Term())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		idx := slices.IndexFunc(solution, func(nv NameVersion) bool { return nv.Name == MakeName("secret") })
+		want := "internal"
+		if precedence == LastSourceWins {
+			want = "public"
+		}
+		if idx < 0 || solution[idx].Source != want {
+			t.Fatalf("expected secret from %s, got %+v", want, solution)
+		}
+		fingerprints = append(fingerprints, solution.Fingerprint())
+	}
+	if fingerprints[0] == fingerprints[1] {
+		t.Fatalf("expected the supplying source to change the fingerprint")
+	}
+}
@@ -15,7 +15,12 @@
 
 package pubgrub
 
-import "strings"
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
 
 // Solver implements the PubGrub dependency resolution algorithm with CDCL.
 //
@@ -46,7 +51,21 @@ type Solver struct {
 	Source  Source
 	options SolverOptions
 
-	learned []*Incompatibility
+	learned  []*Incompatibility
+	stats    SolveStats
+	timeline []PackageTimeline
+	warnings []Warning
+
+	// keepState retains the final solver state in lastState so SolveResult
+	// can inspect it; only set on private derived solvers.
+	keepState bool
+	lastState *solverState
+
+	// optional records the optional dependencies registered by the most
+	// recent solve, and skipOptional those a retry leaves out; see
+	// OptionalCondition. skipOptional is only set on private derived solvers.
+	optional     map[string]Name
+	skipOptional map[string]bool
 }
 
 // NewSolver creates a new solver with default options from multiple sources.
@@ -76,6 +95,9 @@ func NewSolverWithOptions(sources []Source, opts ...SolverOption) *Solver {
 	}
 }
 
+// Configure applies opts to the solver in place and returns it for chaining.
+// It must not be called while a Solve is running; prefer With to derive an
+// independently configured solver.
 func (s *Solver) Configure(opts ...SolverOption) *Solver {
 	for _, opt := range opts {
 		if opt != nil {
@@ -85,6 +107,30 @@ func (s *Solver) Configure(opts ...SolverOption) *Solver {
 	return s
 }
 
+// With returns a new solver sharing the receiver's Source with a copy of its
+// options, modified by opts. The receiver is left untouched, so a base solver
+// can safely be specialized from several goroutines.
+//
+// Example:
+//
+//	base := NewSolver(root, source)
+//	strict := base.With(WithMaxSteps(1000))
+//	traced := base.With(WithIncompatibilityTracking(true))
+func (s *Solver) With(opts ...SolverOption) *Solver {
+	options := s.options
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+
+	return &Solver{
+		Source:  s.Source,
+		options: options,
+		learned: nil,
+	}
+}
+
 func (s *Solver) EnableIncompatibilityTracking() *Solver {
 	return s.Configure(WithIncompatibilityTracking(true))
 }
@@ -131,11 +177,69 @@ func (s *Solver) debug(msg string, args ...any) {
 	}
 }
 
-func (s *Solver) Solve(root Term) (Solution, error) {
+// Solve resolves the dependencies of root. Options passed here apply to this
+// call only and are layered over the solver's configured options; learned
+// incompatibilities and statistics are still recorded on the receiver.
+//
+// Example:
+//
+//	solution, err := solver.Solve(root.Term(), WithMaxSteps(500))
+func (s *Solver) Solve(root Term, opts ...SolverOption) (Solution, error) {
+	return s.SolveContext(context.Background(), root, opts...)
+}
+
+// SolveContext is like Solve but stops with ctx.Err() once ctx is done.
+// Cancellation is checked once per solver step, and ctx is passed to every
+// call on a ContextSource, so a network-backed source can abandon a request
+// in flight; a call that fails because ctx ended also makes the solve
+// return ctx.Err() itself rather than a DependencyError or VersionsError.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	solution, err := solver.SolveContext(ctx, root.Term())
+func (s *Solver) SolveContext(ctx context.Context, root Term, opts ...SolverOption) (solution Solution, err error) {
+	if len(opts) > 0 {
+		derived := s.With(opts...)
+		derived.keepState = s.keepState
+		solution, err := derived.SolveContext(ctx, root)
+		s.adopt(derived)
+		return solution, err
+	}
+	if s.options.TrackOnFailure && !s.options.TrackIncompatibilities {
+		return s.solveTrackingOnFailure(ctx, root)
+	}
+
+	solution, err = s.solve(ctx, root)
+	if err != nil && s.skipOptional == nil && len(s.optional) > 0 && errors.Is(err, ErrNoSolution) {
+		return s.solveSkippingOptional(ctx, root, err)
+	}
+	return solution, err
+}
+
+// solve runs a single resolution of root with the solver's options.
+func (s *Solver) solve(ctx context.Context, root Term) (solution Solution, err error) {
 	s.debug("starting solver", "root", root)
 
+	started := time.Now()
 	state := newSolverState(s.Source, s.options, root.Name)
+	state.ctx = ctx
+	state.skipOptional = s.skipOptional
+	defer s.logPhaseTimes(state)
 	defer s.logHeuristicStats(state)
+	defer func() {
+		state.clock.solveTime += time.Since(started)
+		s.stats = state.snapshotStats()
+	}()
+	defer func() { s.timeline = state.buildTimeline(solution) }()
+	defer func() {
+		s.warnings = state.warnings
+		s.optional = state.optional
+	}()
+	if s.keepState {
+		s.lastState = state
+	}
 
 	version, err := extractDecisionVersion(root)
 	if err != nil {
@@ -148,29 +252,71 @@ func (s *Solver) Solve(root Term) (Solution, error) {
 
 	s.debug("seeded root", "package", root.Name, "version", version)
 
-	deps, err := s.Source.GetDependencies(root.Name, version)
+	deps, err := state.getDependencies(root.Name, version)
 	if err != nil {
+		if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+			return nil, ctx.Err()
+		}
 		return nil, &DependencyError{Package: root.Name, Version: version, Err: err}
 	}
 
-	var conflict *Incompatibility
 	if depConflict, err := state.registerDependencies(root.Name, version, deps); err != nil {
 		return nil, &DependencyError{Package: root.Name, Version: version, Err: err}
 	} else if depConflict != nil {
-		conflict = depConflict
+		state.pendingConflict = depConflict
 	}
 
 	state.enqueue(assign.name)
 
-	var propagateSeed Name
+	return s.run(ctx, state, s.options.MaxSteps, s.options.StepBudget)
+}
+
+// run drives the main CDCL loop until a solution or failure, or until the
+// total step count reaches limit (0 means unlimited), or the limit computed
+// by budget when one is given. The loop position is kept in state so an
+// exhausted budget can be resumed with ResumeSolve.
+func (s *Solver) run(ctx context.Context, state *solverState, limit int, budget StepBudget) (Solution, error) {
+	solution, err := s.loop(ctx, state, limit, budget)
+	if err != nil && ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+		// A Source call gave up because ctx ended; report that, not the
+		// failed call.
+		return nil, ctx.Err()
+	}
+	if s.options.AggregateSourceErrors {
+		solution, err = state.aggregateSourceErrors(solution, err)
+	}
+	if err != nil {
+		return solution, err
+	}
+	return s.postProcess(state.partial.root, solution)
+}
 
-	for steps := 0; ; steps++ {
-		if s.options.MaxSteps > 0 && steps >= s.options.MaxSteps {
-			return nil, ErrIterationLimit{Steps: s.options.MaxSteps}
+// loop is the body of run.
+func (s *Solver) loop(ctx context.Context, state *solverState, limit int, budget StepBudget) (Solution, error) {
+	conflict := state.pendingConflict
+	propagateSeed := state.propagateSeed
+	state.pendingConflict, state.propagateSeed = nil, EmptyName()
+	defer state.clock.enterPhase(nil)
+
+	for steps := state.steps; ; steps++ {
+		if limit := state.stepLimit(limit, budget); limit > 0 && steps >= limit {
+			state.pendingConflict, state.propagateSeed = conflict, propagateSeed
+			return nil, ErrIterationLimit{Steps: limit, Handle: &SolveHandle{solver: s, state: state}}
 		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		state.clock.endSelection()
+		if state.clock.exceeded != (Name{}) {
+			return nil, state.clock.packageTimeout()
+		}
+		state.steps = steps + 1
 
 		if conflict != nil {
+			state.clock.enterPhase(&state.clock.conflictTime)
+			state.conflicts++
 			s.debug("resolving conflict", "step", steps, "conflict", conflict)
+			state.journalIncompatibility(JournalConflict, Name{}, state.partial.decisionLvl, conflict)
 			_, pivot, err := state.resolveConflict(conflict)
 			if err != nil {
 				if ns, ok := err.(*NoSolutionError); ok {
@@ -187,7 +333,9 @@ func (s *Solver) Solve(root Term) (Solution, error) {
 
 		seed := propagateSeed
 		propagateSeed = EmptyName()
+		state.clock.enterPhase(&state.clock.propagationTime)
 		propConflict, err := state.propagate(seed)
+		state.clock.enterPhase(&state.clock.selectionTime)
 		if err != nil {
 			return nil, err
 		}
@@ -197,32 +345,37 @@ func (s *Solver) Solve(root Term) (Solution, error) {
 		}
 
 		if state.partial.isComplete() {
-			return state.partial.buildSolution(), nil
+			return state.solution(), nil
 		}
 
-		nextPkg, ok := state.partial.nextDecisionCandidate()
+		nextPkg, ok := state.nextPackage()
 		if !ok {
 			s.debug("solution found", "step", steps)
-			return state.partial.buildSolution(), nil
+			return state.solution(), nil
 		}
 
-		allowed := state.partial.allowedSet(nextPkg)
-		allowedStr := "<nil>"
-		if allowed != nil {
-			allowedStr = allowed.String()
+		// Rendering the selection context allocates, so only do it when
+		// someone is listening.
+		if s.options.Logger != nil {
+			allowed := state.partial.allowedSet(nextPkg)
+			allowedStr := "<nil>"
+			if allowed != nil {
+				allowedStr = allowed.String()
+			}
+			pending := state.partial.pendingPackages()
+
+			// Log constraint score for the selected package (heuristic debugging)
+			constraintScore := state.partial.constraintScore(nextPkg)
+			s.debug("selecting package",
+				"step", steps,
+				"package", nextPkg,
+				"allowed", allowedStr,
+				"constraint_score", constraintScore,
+				"pending", joinNameValues(pending),
+			)
 		}
-		pending := state.partial.pendingPackages()
-
-		// Log constraint score for the selected package (heuristic debugging)
-		constraintScore := state.partial.constraintScore(nextPkg)
-		s.debug("selecting package",
-			"step", steps,
-			"package", nextPkg,
-			"allowed", allowedStr,
-			"constraint_score", constraintScore,
-			"pending", joinNameValues(pending),
-		)
 
+		state.clock.beginSelection(nextPkg)
 		ver, found, score, err := state.pickVersion(nextPkg)
 		if err != nil {
 			return nil, err
@@ -248,16 +401,26 @@ func (s *Solver) Solve(root Term) (Solution, error) {
 		)
 
 		assign := state.partial.addDecision(nextPkg, ver)
+		state.decisions++
 		state.traceAssignment("decision", assign)
 		state.markAssigned(assign.name)
+		if hook := s.options.Hooks.AfterDecision; hook != nil {
+			hook(nextPkg, ver)
+		}
 
-		deps, err := s.Source.GetDependencies(nextPkg, ver)
+		deps, err := state.getDependencies(nextPkg, ver)
+		if err != nil && state.aggregates(err) {
+			state.recordSourceFailure(nextPkg, ver, err)
+			conflict = unfetchedDependencies(nextPkg, ver)
+			state.addIncompatibility(conflict)
+			continue
+		}
 		if err != nil {
-			return nil, &DependencyError{Package: nextPkg, Version: ver, Err: err}
+			return nil, &DependencyError{Package: nextPkg, Version: ver, Chain: state.requirementChain(nextPkg), Err: err}
 		}
 
 		if depConflict, err := state.registerDependencies(nextPkg, ver, deps); err != nil {
-			return nil, &DependencyError{Package: nextPkg, Version: ver, Err: err}
+			return nil, &DependencyError{Package: nextPkg, Version: ver, Chain: state.requirementChain(nextPkg), Err: err}
 		} else if depConflict != nil {
 			conflict = depConflict
 			continue
@@ -273,7 +436,7 @@ func joinNameValues(names []Name) string {
 	}
 	values := make([]string, len(names))
 	for i, name := range names {
-		values[i] = name.Value()
+		values[i] = FormatName(name)
 	}
 	return strings.Join(values, ",")
 }
@@ -296,6 +459,38 @@ func extractDecisionVersion(root Term) (Version, error) {
 	}
 }
 
+// solveTrackingOnFailure solves without tracking and, if no solution
+// exists, solves again with tracking for the detailed error, applying
+// retryOpts to the second attempt only.
+func (s *Solver) solveTrackingOnFailure(ctx context.Context, root Term, retryOpts ...SolverOption) (Solution, error) {
+	attempt := s.With(WithTrackingOnFailure(false))
+	attempt.keepState = s.keepState
+	solution, err := attempt.SolveContext(ctx, root)
+	s.adopt(attempt)
+	var noSolution ErrNoSolutionFound
+	if !errors.As(err, &noSolution) {
+		return solution, err
+	}
+
+	s.debug("no solution, retrying with incompatibility tracking")
+	first := s.stats
+	retry := s.With(WithTrackingOnFailure(false), WithIncompatibilityTracking(true)).With(retryOpts...)
+	retry.keepState = s.keepState
+	solution, err = retry.SolveContext(ctx, root)
+	s.adopt(retry)
+	s.stats = first.add(s.stats)
+	return solution, err
+}
+
+// adopt records the outcome of a solve run by a derived solver.
+func (s *Solver) adopt(derived *Solver) {
+	s.learned = derived.learned
+	s.stats = derived.stats
+	s.timeline = derived.timeline
+	s.warnings = derived.warnings
+	s.lastState = derived.lastState
+}
+
 func (s *Solver) fail(state *solverState, incomp *Incompatibility) (Solution, error) {
 	if s.options.TrackIncompatibilities {
 		if state != nil {
@@ -305,11 +500,19 @@ func (s *Solver) fail(state *solverState, incomp *Incompatibility) (Solution, er
 			term := fallbackTerm(nil)
 			incomp = NewIncompatibilityNoVersions(term)
 		}
-		return nil, NewNoSolutionError(incomp)
+		if s.options.MinimalProof && state != nil {
+			incomp = minimalProof(incomp, state.learned)
+		}
+		err := NewNoSolutionError(incomp)
+		if s.options.Reporter != nil {
+			err.Reporter = s.options.Reporter
+		}
+		err.Missing = state.missingPackages()
+		return nil, state.frozenConflict(incomp, err)
 	}
 
 	term := fallbackTerm(incomp)
-	return nil, ErrNoSolutionFound{Term: term}
+	return nil, state.frozenConflict(incomp, ErrNoSolutionFound{Term: term, Missing: state.missingPackages()})
 }
 
 func fallbackTerm(incomp *Incompatibility) Term {
@@ -15,7 +15,13 @@
 
 package pubgrub
 
-import "log/slog"
+import (
+	"io"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+)
 
 // SolverOptions configures the behavior of the dependency solver.
 //
@@ -29,6 +35,11 @@ type SolverOptions struct {
 	// When disabled, returns simple ErrNoSolutionFound.
 	TrackIncompatibilities bool
 
+	// TrackOnFailure solves without tracking first and, when no solution
+	// exists, solves again with tracking to build the detailed error.
+	// Default: false
+	TrackOnFailure bool
+
 	// MaxSteps limits the number of solver iterations.
 	// Set to 0 to disable the limit (not recommended for untrusted inputs).
 	// Default: 100000
@@ -37,6 +48,209 @@ type SolverOptions struct {
 	// Logger enables debug logging of solver operations.
 	// When nil, no logging is performed.
 	Logger *slog.Logger
+
+	// MergeDependencyRanges widens dependency incompatibilities to cover
+	// adjacent releases that declare the same dependency.
+	// Default: false
+	MergeDependencyRanges bool
+
+	// BucketEquivalentVersions treats consecutive releases with identical
+	// dependencies as a single candidate during version selection.
+	// Default: false
+	BucketEquivalentVersions bool
+
+	// Consistency guards against Sources whose data changes mid-solve.
+	// Default: ConsistencySnapshot
+	Consistency ConsistencyMode
+
+	// RecordTimeline keeps the full assignment history so Solver.Timeline
+	// can explain which constraint eliminated each candidate version.
+	// Default: false
+	RecordTimeline bool
+
+	// Augmenter rewrites dependencies after they are fetched.
+	// Default: nil
+	Augmenter DependencyAugmenter
+
+	// Policies are organization rules applied to every solve.
+	// Default: nil
+	Policies []*PolicySet
+
+	// VersionStrategy selects how a version is picked for a package.
+	// Default: VersionLookahead
+	VersionStrategy VersionStrategy
+
+	// CacheEvaluations memoizes incompatibility evaluations by the allowed
+	// sets of the packages involved.
+	// Default: false
+	CacheEvaluations bool
+
+	// PropagationOrder selects which queued package unit propagation
+	// visits next.
+	// Default: PropagationFIFO
+	PropagationOrder PropagationOrder
+
+	// OnWarning receives warnings as they are raised; they are also
+	// available from Solver.Warnings after the solve.
+	// Default: nil
+	OnWarning func(Warning)
+
+	// StrictSources turns DuplicateVersionWarning into an
+	// ErrDuplicateVersion failure.
+	// Default: false
+	StrictSources bool
+
+	// SourcePrecedence decides which source supplies a version published
+	// by several sources of a CombinedSource.
+	// Default: FirstSourceWins
+	SourcePrecedence SourcePrecedence
+
+	// StepBudget computes the step limit from the size of the problem
+	// encountered so far, replacing MaxSteps when set.
+	// Default: nil
+	StepBudget StepBudget
+
+	// Locked is a previous solution, typically read from a lockfile.
+	// Default: nil
+	Locked Solution
+
+	// Frozen names the packages held at their Locked versions.
+	// Default: nil
+	Frozen []Name
+
+	// Replacements redirects requirements on renamed packages to their
+	// successors, keyed by the old name.
+	// Default: nil
+	Replacements map[Name]Replacement
+
+	// Hooks adjust version selection for ecosystem quirks.
+	// Default: no hooks
+	Hooks SolverHooks
+
+	// PrereleasePolicy decides which prereleases in an allowed range are
+	// candidates.
+	// Default: PrereleaseInRange
+	PrereleasePolicy PrereleasePolicy
+
+	// TieBreak decides between versions VersionLookahead scores equally.
+	// Default: TieBreakNewest
+	TieBreak TieBreak
+
+	// ScoreProvider rates candidate versions for VersionLookahead.
+	// Default: DependencyScore
+	ScoreProvider ScoreProvider
+
+	// Installed maps packages already present in the environment to their
+	// installed versions.
+	// Default: nil
+	Installed map[Name]Version
+
+	// Upgradable names the installed packages allowed to change version.
+	// Default: nil
+	Upgradable []Name
+
+	// PackageTimeout aborts the solve with ErrPackageTimeout once the time
+	// spent on a single package exceeds it.
+	// Default: 0 (no limit)
+	PackageTimeout time.Duration
+
+	// MinimalProof searches for the shortest derivation of the failure
+	// before it is reported.
+	// Default: false
+	MinimalProof bool
+
+	// AggregateSourceErrors sets failing Source calls aside and reports
+	// them together in a MultiSourceError.
+	// Default: false
+	AggregateSourceErrors bool
+
+	// DecisionJournal receives one JSON line per decision, derivation,
+	// conflict and backtrack.
+	// Default: nil (no journal)
+	DecisionJournal io.Writer
+
+	// NameScope admits every package name the solve encounters.
+	// Default: nil (no scope)
+	NameScope *NameScope
+
+	// Preferred maps packages to the version to pick whenever it is still
+	// allowed, typically the versions of a lockfile.
+	// Default: nil
+	Preferred map[Name]Version
+
+	// PostProcessors shape every solution before it is returned, in order.
+	// Default: nil
+	PostProcessors []PostProcessor
+
+	// DecisionStrategy chooses the next package to decide and its version.
+	// Default: nil (most constrained package first, versions by
+	// VersionStrategy)
+	DecisionStrategy DecisionStrategy
+
+	// Reporter formats the NoSolutionError of a failed solve.
+	// Default: DefaultReporter
+	Reporter Reporter
+}
+
+// VersionStrategy controls version selection during decisions.
+type VersionStrategy int
+
+const (
+	// VersionLookahead scores the newest few allowed versions by how
+	// constrained their dependencies are and picks the best one.
+	VersionLookahead VersionStrategy = iota
+	// VersionNewest always picks the highest allowed version.
+	VersionNewest
+	// VersionOldest always picks the lowest allowed version, which is useful
+	// for minimal-version testing.
+	VersionOldest
+)
+
+// PropagationOrder controls the discipline of the unit propagation queue.
+// The order does not change which solutions exist, but it changes which
+// conflict is found first, and on wide graphs that affects how quickly the
+// solver learns useful clauses.
+//
+// Experimental: the available orders may change in a minor release.
+type PropagationOrder int
+
+const (
+	// PropagationFIFO visits packages in the order they were queued,
+	// spreading propagation breadth-first.
+	PropagationFIFO PropagationOrder = iota
+	// PropagationLIFO visits the most recently queued package first,
+	// following a single chain of consequences depth-first.
+	PropagationLIFO
+	// PropagationMostConstrained visits the queued package with the
+	// smallest allowed set first, where conflicts are most likely.
+	PropagationMostConstrained
+)
+
+// StepBudget returns the step limit for a problem of the given size:
+// packages is the number of packages whose versions have been listed and
+// versions the total number of versions those listings returned. It is
+// re-evaluated every step, so the limit grows as the solver discovers more
+// of the graph. A result <= 0 means unlimited.
+type StepBudget func(packages, versions int) int
+
+// ScaledStepBudget allows perVersion steps for every package version
+// encountered, clamped to [floor, ceiling]. A ceiling <= 0 leaves the budget
+// unbounded above.
+//
+// Example:
+//
+//	budget := ScaledStepBudget(20, 1000, 1000000)
+func ScaledStepBudget(perVersion, floor, ceiling int) StepBudget {
+	return func(packages, versions int) int {
+		steps := perVersion * versions
+		if steps < floor {
+			steps = floor
+		}
+		if ceiling > 0 && steps > ceiling {
+			steps = ceiling
+		}
+		return steps
+	}
 }
 
 // SolverOption is a functional option for configuring the solver.
@@ -49,6 +263,7 @@ func defaultSolverOptions() SolverOptions {
 	return SolverOptions{
 		TrackIncompatibilities: false,
 		MaxSteps:               defaultMaxSteps,
+		Consistency:            ConsistencySnapshot,
 	}
 }
 
@@ -68,6 +283,24 @@ func WithIncompatibilityTracking(enabled bool) SolverOption {
 	}
 }
 
+// WithTrackingOnFailure makes a solve without incompatibility tracking
+// retry with tracking when it finds no solution, returning the detailed
+// NoSolutionError. Successful solves keep the speed of untracked solving,
+// while failures pay for a second solve. It has no effect when tracking is
+// already enabled. The stats of a retried solve cover both attempts.
+//
+// Example:
+//
+//	solver := NewSolverWithOptions(
+//	    []Source{root, source},
+//	    WithTrackingOnFailure(true),
+//	)
+func WithTrackingOnFailure(enabled bool) SolverOption {
+	return func(opts *SolverOptions) {
+		opts.TrackOnFailure = enabled
+	}
+}
+
 // WithMaxSteps sets the maximum number of solver iterations.
 // Use 0 to disable the limit (allows unbounded execution).
 //
@@ -106,3 +339,583 @@ func WithLogger(logger *slog.Logger) SolverOption {
 		opts.Logger = logger
 	}
 }
+
+// WithDependencyRangeMerging enables range-wide dependency incompatibilities.
+// When a package version is selected, its dependency clauses are widened to
+// the neighbouring releases that declare an identical dependency. A conflict
+// on that dependency then eliminates the whole run of releases at once, which
+// keeps the number of learned clauses small for packages with hundreds of
+// patch releases.
+//
+// Widening inspects the dependencies of neighbouring versions, so it is best
+// combined with a CachedSource for network-backed sources.
+//
+// Example:
+//
+//	solver := NewSolverWithOptions(
+//	    []Source{root, source},
+//	    WithDependencyRangeMerging(true),
+//	)
+func WithDependencyRangeMerging(enabled bool) SolverOption {
+	return func(opts *SolverOptions) {
+		opts.MergeDependencyRanges = enabled
+	}
+}
+
+// WithVersionBucketing enables equivalent-version bucketing. Consecutive
+// releases that declare identical dependencies are grouped, and version
+// selection scores one representative per bucket, deciding on the bucket's
+// best allowed version. Enabling bucketing also enables dependency range
+// merging, so a conflict learned for one member rules out the whole bucket.
+//
+// Experimental: the bucketing criteria may change in a minor release.
+//
+// Example:
+//
+//	solver := NewSolverWithOptions(
+//	    []Source{root, source},
+//	    WithVersionBucketing(true),
+//	)
+func WithVersionBucketing(enabled bool) SolverOption {
+	return func(opts *SolverOptions) {
+		opts.BucketEquivalentVersions = enabled
+		if enabled {
+			opts.MergeDependencyRanges = true
+		}
+	}
+}
+
+// WithConsistencyCheck guards a solve against Sources that return different
+// data for the same query while the solve is running, for example a registry
+// receiving a publish mid-resolution.
+//
+// ConsistencySnapshot (the default) wraps the Source in a CachedSource so every
+// query is answered from the first response. ConsistencyDetect fails the solve
+// with ErrInconsistentSource when a change is observed instead, which surfaces
+// misbehaving backends. ConsistencyOff passes every query straight through.
+//
+// Example:
+//
+//	solver := NewSolverWithOptions(
+//	    []Source{root, registry},
+//	    WithConsistencyCheck(ConsistencyDetect),
+//	)
+func WithConsistencyCheck(mode ConsistencyMode) SolverOption {
+	return func(opts *SolverOptions) {
+		opts.Consistency = mode
+	}
+}
+
+// WithTimeline enables recording of the assignment history, exposed after
+// each solve through Solver.Timeline. Recording keeps every assignment alive
+// for the duration of the solve, so it is off by default.
+//
+// Example:
+//
+//	solver := NewSolverWithOptions(
+//	    []Source{root, source},
+//	    WithTimeline(true),
+//	)
+func WithTimeline(enabled bool) SolverOption {
+	return func(opts *SolverOptions) {
+		opts.RecordTimeline = enabled
+	}
+}
+
+// WithDependencyAugmenter installs a hook that rewrites the dependencies of
+// every package version after GetDependencies, for example to inject an
+// implicit runtime requirement or drop development-only terms.
+//
+// Example:
+//
+//	solver := NewSolverWithOptions(
+//	    []Source{root, source},
+//	    WithDependencyAugmenter(DependencyAugmenterFunc(stripDevDependencies)),
+//	)
+func WithDependencyAugmenter(augmenter DependencyAugmenter) SolverOption {
+	return func(opts *SolverOptions) {
+		opts.Augmenter = augmenter
+	}
+}
+
+// WithPolicy applies a PolicySet to the solve. The option may be given
+// several times; all policies apply, and for overrides of the same package
+// the last policy wins.
+//
+// Example:
+//
+//	solver := NewSolverWithOptions(
+//	    []Source{root, source},
+//	    WithPolicy(orgPolicy),
+//	)
+func WithPolicy(policy *PolicySet) SolverOption {
+	return func(opts *SolverOptions) {
+		if policy != nil {
+			opts.Policies = append(slices.Clip(opts.Policies), policy)
+		}
+	}
+}
+
+// WithVersionStrategy selects how versions are picked during decisions.
+// Different strategies explore the search space in different orders, which
+// makes them good candidates for a portfolio (see Solver.SolvePortfolio).
+//
+// Example:
+//
+//	solver := NewSolverWithOptions(
+//	    []Source{root, source},
+//	    WithVersionStrategy(VersionNewest),
+//	)
+func WithVersionStrategy(strategy VersionStrategy) SolverOption {
+	return func(opts *SolverOptions) {
+		opts.VersionStrategy = strategy
+	}
+}
+
+// WithEvaluationCache enables memoizing incompatibility evaluations. After a
+// shallow backjump the solver re-propagates through package states it has
+// already seen; the cache answers those evaluations without redoing the set
+// algebra. Hit rates are reported by Solver.Stats.
+//
+// Example:
+//
+//	solver := NewSolverWithOptions(
+//	    []Source{root, source},
+//	    WithEvaluationCache(true),
+//	)
+func WithEvaluationCache(enabled bool) SolverOption {
+	return func(opts *SolverOptions) {
+		opts.CacheEvaluations = enabled
+	}
+}
+
+// WithPropagationOrder selects the unit propagation queue discipline.
+//
+// Experimental: see PropagationOrder.
+//
+// Example:
+//
+//	solver := NewSolverWithOptions(
+//	    []Source{root, source},
+//	    WithPropagationOrder(PropagationMostConstrained),
+//	)
+func WithPropagationOrder(order PropagationOrder) SolverOption {
+	return func(opts *SolverOptions) {
+		opts.PropagationOrder = order
+	}
+}
+
+// WithWarningHandler registers a function called for every warning raised
+// during a solve, such as a DuplicateVersionWarning.
+//
+// Example:
+//
+//	solver := NewSolverWithOptions(
+//	    []Source{root, internal, public},
+//	    WithWarningHandler(func(w Warning) { log.Println(w.Warning()) }),
+//	)
+func WithWarningHandler(handler func(Warning)) SolverOption {
+	return func(opts *SolverOptions) {
+		opts.OnWarning = handler
+	}
+}
+
+// WithStrictSources makes a version published by more than one source a
+// hard error instead of a warning, guarding against dependency-confusion
+// attacks where a public registry shadows a private package.
+//
+// Example:
+//
+//	solver := NewSolverWithOptions(
+//	    []Source{root, internal, public},
+//	    WithStrictSources(true),
+//	)
+func WithStrictSources(enabled bool) SolverOption {
+	return func(opts *SolverOptions) {
+		opts.StrictSources = enabled
+	}
+}
+
+// WithSourcePrecedence selects which source of a CombinedSource supplies a
+// version that several sources publish. Dependencies are then fetched from
+// that same source.
+//
+// Example:
+//
+//	solver := NewSolverWithOptions(
+//	    []Source{root, upstream, overlay},
+//	    WithSourcePrecedence(LastSourceWins),
+//	)
+func WithSourcePrecedence(precedence SourcePrecedence) SolverOption {
+	return func(opts *SolverOptions) {
+		opts.SourcePrecedence = precedence
+	}
+}
+
+// WithStepBudget scales the step limit with the problem size instead of
+// using the flat MaxSteps, so large legitimate graphs are not cut off early
+// while small adversarial ones still stop quickly. Pass nil to go back to
+// MaxSteps.
+//
+// Example:
+//
+//	solver := NewSolverWithOptions(
+//	    []Source{root, source},
+//	    WithStepBudget(ScaledStepBudget(20, 1000, 1000000)),
+//	)
+func WithStepBudget(budget StepBudget) SolverOption {
+	return func(opts *SolverOptions) {
+		opts.StepBudget = budget
+	}
+}
+
+// WithLockfile supplies a previous solution, typically read from a lockfile.
+// On its own it changes nothing; options such as WithFrozen decide how the
+// locked versions are used.
+//
+// Example:
+//
+//	solver := NewSolverWithOptions(
+//	    []Source{root, source},
+//	    WithLockfile(locked),
+//	    WithFrozen(MakeName("rails"), MakeName("rack")),
+//	)
+func WithLockfile(locked Solution) SolverOption {
+	return func(opts *SolverOptions) {
+		opts.Locked = locked
+	}
+}
+
+// WithFrozen holds the named packages at their versions in the lockfile
+// given with WithLockfile, while every other package stays free to change.
+// This supports "update everything except these" workflows; freezing all but
+// a few packages gives "update only these". A frozen package still only
+// appears in the solution if something requires it. Names missing from the
+// lockfile are not constrained. The option may be given several times.
+//
+// Example:
+//
+//	solver := NewSolverWithOptions(
+//	    []Source{root, source},
+//	    WithLockfile(locked),
+//	    WithFrozen(MakeName("rails")),
+//	)
+func WithFrozen(names ...Name) SolverOption {
+	return func(opts *SolverOptions) {
+		opts.Frozen = append(slices.Clip(opts.Frozen), names...)
+	}
+}
+
+// WithReplacement redirects every requirement on old to new, for packages
+// renamed by their ecosystem. mapVersion translates the required versions of
+// old into versions of new; nil keeps them unchanged. Each redirected
+// requirement raises a ReplacementWarning. Root requirements are redirected
+// too.
+//
+// Example:
+//
+//	solver := NewSolverWithOptions(
+//	    []Source{root, source},
+//	    WithReplacement(MakeName("request"), MakeName("got"), func(VersionSet) VersionSet {
+//	        return FullVersionSet()
+//	    }),
+//	)
+func WithReplacement(old, new Name, mapVersion func(VersionSet) VersionSet) SolverOption {
+	return func(opts *SolverOptions) {
+		replacements := make(map[Name]Replacement, len(opts.Replacements)+1)
+		maps.Copy(replacements, opts.Replacements)
+		replacements[old] = Replacement{Name: new, MapVersions: mapVersion}
+		opts.Replacements = replacements
+	}
+}
+
+// WithHooks installs lifecycle hooks around version selection, replacing
+// any hooks set before.
+//
+// Example:
+//
+//	preferStable := func(name Name, candidates []Version) []Version {
+//	    return slices.DeleteFunc(candidates, isPrerelease)
+//	}
+//	solver := NewSolverWithOptions(
+//	    []Source{root, source},
+//	    WithHooks(SolverHooks{BeforePick: preferStable}),
+//	)
+func WithHooks(hooks SolverHooks) SolverOption {
+	return func(opts *SolverOptions) {
+		opts.Hooks = hooks
+	}
+}
+
+// WithPrereleasePolicy sets which prerelease versions inside an allowed range
+// may be picked. Use PrereleaseMentioned for npm-style ecosystems.
+//
+// Example:
+//
+//	solver := NewSolverWithOptions(
+//	    []Source{root, registry},
+//	    WithPrereleasePolicy(PrereleaseMentioned),
+//	)
+func WithPrereleasePolicy(policy PrereleasePolicy) SolverOption {
+	return func(opts *SolverOptions) {
+		opts.PrereleasePolicy = policy
+	}
+}
+
+// WithTieBreak sets how VersionLookahead chooses between equally scored
+// versions.
+//
+// Experimental: see TieBreak.
+//
+// Example:
+//
+//	solver := NewSolverWithOptions(
+//	    []Source{root, source},
+//	    WithTieBreak(TieBreakFewerDependencies),
+//	)
+func WithTieBreak(tieBreak TieBreak) SolverOption {
+	return func(opts *SolverOptions) {
+		opts.TieBreak = tieBreak
+	}
+}
+
+// WithScoreProvider replaces the scoring VersionLookahead uses to choose
+// between candidate versions. Wrap DependencyScore to adjust the built-in
+// scores rather than replace them.
+//
+// Experimental: see ScoreProvider.
+//
+// Example:
+//
+//	solver := NewSolverWithOptions(
+//	    []Source{root, source},
+//	    WithScoreProvider(ScoreProviderFunc(func(req ScoreRequest) int {
+//	        return DependencyScore.Score(req) - len(req.Dependencies)
+//	    })),
+//	)
+func WithScoreProvider(provider ScoreProvider) SolverOption {
+	return func(opts *SolverOptions) {
+		opts.ScoreProvider = provider
+	}
+}
+
+// WithInstalled supplies the packages already installed in the environment,
+// the way system package managers resolve against the current system. Every
+// installed package is required from the root and held at its installed
+// version, as if decided before the solve began, unless it is named by
+// WithUpgradable. Conflicts with an installed version are reported as policy
+// requirements of the root. The option may be given several times.
+//
+// Example:
+//
+//	solver := NewSolverWithOptions(
+//	    []Source{root, source},
+//	    WithInstalled(map[Name]Version{
+//	        MakeName("libc"): SimpleVersion("2.39"),
+//	    }),
+//	)
+func WithInstalled(installed map[Name]Version) SolverOption {
+	return func(opts *SolverOptions) {
+		merged := make(map[Name]Version, len(opts.Installed)+len(installed))
+		maps.Copy(merged, opts.Installed)
+		maps.Copy(merged, installed)
+		opts.Installed = merged
+	}
+}
+
+// WithUpgradable lets the named installed packages move off their installed
+// versions when the requirements demand it. They stay in the solution and
+// keep their installed version whenever it is allowed, so nothing is touched
+// unless necessary. Names that are not installed are ignored. The option may
+// be given several times.
+//
+// Example:
+//
+//	solver := NewSolverWithOptions(
+//	    []Source{root, source},
+//	    WithInstalled(installed),
+//	    WithUpgradable(MakeName("openssl")),
+//	)
+func WithUpgradable(names ...Name) SolverOption {
+	return func(opts *SolverOptions) {
+		opts.Upgradable = append(slices.Clip(opts.Upgradable), names...)
+	}
+}
+
+// WithPackageTimeout aborts the solve with ErrPackageTimeout once the time
+// attributed to a single package exceeds budget: the Source calls about it
+// plus the solver's work selecting its versions (see SolveStats.Hotspots).
+// Unlike a deadline on the whole solve, the error names the dependency that
+// is slow. The budget is checked between solver steps, so a single slow
+// Source call is not interrupted; use SolveContext for that. Zero disables
+// the limit.
+//
+// Example:
+//
+//	solver := NewSolverWithOptions(
+//	    []Source{root, source},
+//	    WithPackageTimeout(5*time.Second),
+//	)
+func WithPackageTimeout(budget time.Duration) SolverOption {
+	return func(opts *SolverOptions) {
+		opts.PackageTimeout = budget
+	}
+}
+
+// WithMinimalProof makes a failed solve explain itself with the shortest
+// derivation it knows. Conflict resolution often learns the same clause
+// along several paths, and the first one found, which ends up in the error,
+// can be far longer than necessary. With this option every derived step of
+// the explanation is replaced by the derivation of the same clause with the
+// fewest leaves before the error is formatted. The search costs a pass over
+// every learned clause, so it is off by default. It needs incompatibility
+// tracking, as enabled by WithIncompatibilityTracking or
+// WithTrackingOnFailure.
+//
+// Example:
+//
+//	solver := NewSolverWithOptions(
+//	    []Source{root, source},
+//	    WithIncompatibilityTracking(true),
+//	    WithMinimalProof(true),
+//	)
+func WithMinimalProof(enabled bool) SolverOption {
+	return func(opts *SolverOptions) {
+		opts.MinimalProof = enabled
+	}
+}
+
+// WithReporter sets the Reporter that formats the NoSolutionError of a
+// failed solve, sparing callers a NoSolutionError.WithReporter call at every
+// error site. It only matters when incompatibility tracking produces a
+// NoSolutionError.
+//
+// Example:
+//
+//	solver := NewSolverWithOptions(
+//	    []Source{root, source},
+//	    WithIncompatibilityTracking(true),
+//	    WithReporter(&CollapsedReporter{}),
+//	)
+func WithReporter(reporter Reporter) SolverOption {
+	return func(opts *SolverOptions) {
+		opts.Reporter = reporter
+	}
+}
+
+// WithSourceErrorAggregation makes a solve keep going when a Source call
+// fails. A package whose versions cannot be listed, or a version whose
+// dependencies cannot be fetched, is treated as unavailable, and the solve
+// then returns a *MultiSourceError listing every failed call, plus the
+// packages reported as not found if the solve failed, so all registry
+// problems surface at once instead of one per attempt. Since the remaining
+// search ran without those answers, a solution found anyway is not
+// returned.
+//
+// Example:
+//
+//	_, err := NewSolverWithOptions(
+//	    []Source{root, registry},
+//	    WithSourceErrorAggregation(true),
+//	).Solve(root.Term())
+//	var multi *MultiSourceError
+//	if errors.As(err, &multi) {
+//	    fmt.Println(multi) // one line per failing package or version
+//	}
+func WithSourceErrorAggregation(enabled bool) SolverOption {
+	return func(opts *SolverOptions) {
+		opts.AggregateSourceErrors = enabled
+	}
+}
+
+// WithDecisionJournal writes one JSON line per solver event to w: every
+// decision, derivation, conflict and backtrack, each a JournalEntry with a
+// timestamp and the step it happened in. The journal is meant for offline
+// analysis with tools such as jq or pandas, without writing Go against the
+// hooks API. Writes are unbuffered and unsynchronized, so wrap w in a
+// bufio.Writer for large solves and do not share it between concurrent
+// solves. A write error ends the journal but not the solve.
+//
+// Example:
+//
+//	f, _ := os.Create("solve.jsonl")
+//	defer f.Close()
+//	solver := NewSolverWithOptions([]Source{root, source}, WithDecisionJournal(f))
+//	// jq -c 'select(.event == "backtrack")' solve.jsonl
+func WithDecisionJournal(w io.Writer) SolverOption {
+	return func(opts *SolverOptions) {
+		opts.DecisionJournal = w
+	}
+}
+
+// WithNameScope admits every dependency name the solve encounters to scope,
+// so Stats on the scope accounts for the names of the solve and its limits
+// bound them. A solve that would exceed a limit fails with a
+// DependencyError wrapping ErrNameLimit. Use one scope per untrusted
+// manifest, or share one across the solves of a request.
+//
+// Example:
+//
+//	scope := &NameScope{MaxNames: 10_000, MaxBytes: 1 << 20}
+//	solver := NewSolverWithOptions([]Source{root, source}, WithNameScope(scope))
+func WithNameScope(scope *NameScope) SolverOption {
+	return func(opts *SolverOptions) {
+		opts.NameScope = scope
+	}
+}
+
+// WithPreferredVersions makes the solver pick the given version of a package
+// whenever the constraints still allow it, the "keep what I have" behaviour
+// of a lockfile. Unlike WithFrozen, a preferred version is only a bias: it
+// gives way when the requirements exclude it or it leads to a conflict, and
+// it does not pull the package into the solution. Installed versions take
+// precedence. The option may be given several times.
+//
+// Example:
+//
+//	preferred := make(map[Name]Version)
+//	for nv := range locked.All() {
+//	    preferred[nv.Name] = nv.Version
+//	}
+//	solver := NewSolverWithOptions([]Source{root, source}, WithPreferredVersions(preferred))
+func WithPreferredVersions(preferred map[Name]Version) SolverOption {
+	return func(opts *SolverOptions) {
+		merged := make(map[Name]Version, len(opts.Preferred)+len(preferred))
+		maps.Copy(merged, opts.Preferred)
+		maps.Copy(merged, preferred)
+		opts.Preferred = merged
+	}
+}
+
+// WithPostProcessors appends processors that shape every solution before
+// the solve returns it, see PostProcessor. The option may be given several
+// times.
+//
+// Example:
+//
+//	solver := NewSolverWithOptions(
+//	    []Source{root, source},
+//	    WithPostProcessors(StripRoot(), SortSolution()),
+//	)
+func WithPostProcessors(processors ...PostProcessor) SolverOption {
+	return func(opts *SolverOptions) {
+		opts.PostProcessors = append(slices.Clip(opts.PostProcessors), processors...)
+	}
+}
+
+// WithDecisionStrategy installs a custom heuristic for choosing the next
+// package to decide and the version to try, see DecisionStrategy.
+//
+// Experimental: see DecisionStrategy.
+//
+// Example:
+//
+//	solver := NewSolverWithOptions(
+//	    []Source{root, source},
+//	    WithDecisionStrategy(popularFirst{downloads: stats}),
+//	)
+func WithDecisionStrategy(strategy DecisionStrategy) SolverOption {
+	return func(opts *SolverOptions) {
+		opts.DecisionStrategy = strategy
+	}
+}
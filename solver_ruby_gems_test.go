@@ -140,7 +140,7 @@ func (m *MapSource) GetVersions(name Name) ([]Version, error) {
 
 	result := make([]Version, 0, len(versions))
 	for _, pv := range versions {
-		result = append(result, SimpleVersion(pv.version))
+		result = append(result, ParseDottedVersion(pv.version))
 	}
 	return result, nil
 }
@@ -210,3 +210,419 @@ func TestSolverHandlesPrereleaseRanges(t *testing.T) {
 		t.Fatalf("expected prerelease selection 1.0.0-beta.1, got %s", got)
 	}
 }
+
+func TestSolverDropsTautologicalSelfDependency(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("a"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("a"), EqualsCondition{Version: SimpleVersion("1.0.0")}),
+		NewTerm(MakeName("b"), nil),
+		NewTerm(MakeName("b"), EqualsCondition{Version: SimpleVersion("1.0.0")}),
+	})
+	source.AddPackage(MakeName("b"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("b"), SimpleVersion("2.0.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("a"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	solution, err := NewSolver(root, source).Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, _ := solution.GetVersion(MakeName("b")); ver.String() != "1.0.0" {
+		t.Fatalf("expected merged duplicate constraint to select b 1.0.0, got %v", ver)
+	}
+}
+
+func TestSolverReportsUnsatisfiableSelfDependency(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("a"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("a"), SimpleVersion("2.0.0"), []Term{
+		NewTerm(MakeName("a"), EqualsCondition{Version: SimpleVersion("3.0.0")}),
+	})
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("a"), NewVersionSetCondition(FullVersionSet()))
+
+	solution, err := NewSolver(root, source).Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, _ := solution.GetVersion(MakeName("a")); ver.String() != "1.0.0" {
+		t.Fatalf("expected fallback to a 1.0.0, got %v", ver)
+	}
+
+	pinned := NewRootSource()
+	pinned.AddPackage(MakeName("a"), EqualsCondition{Version: SimpleVersion("2.0.0")})
+
+	_, err = NewSolver(pinned, source).EnableIncompatibilityTracking().Solve(pinned.Term())
+	if err == nil {
+		t.Fatal("expected failure for pinned self-contradictory version")
+	}
+	if !strings.Contains(err.Error(), "a 2.0.0 depends on itself (a == 3.0.0), which it does not satisfy") {
+		t.Fatalf("expected self-dependency diagnostic, got:\n%s", err)
+	}
+}
+
+// everyVersion is a condition with no version-set form that every version
+// satisfies.
+type everyVersion struct{}
+
+func (everyVersion) String() string { return "any" }
+
+func (everyVersion) Satisfies(Version) bool { return true }
+
+func TestSolverDropsUnconvertibleSatisfiedSelfDependency(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("a"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("a"), everyVersion{}),
+	})
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("a"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	solution, err := NewSolver(root, source).EnableIncompatibilityTracking().Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, _ := solution.GetVersion(MakeName("a")); ver.String() != "1.0.0" {
+		t.Fatalf("expected a 1.0.0, got %v", ver)
+	}
+}
+
+func TestSolverWithDerivesIndependentOptions(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("a"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("b"), EqualsCondition{Version: SimpleVersion("1.0.0")}),
+	})
+	source.AddPackage(MakeName("b"), SimpleVersion("1.0.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("a"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	base := NewSolver(root, source)
+	limited := base.With(WithMaxSteps(1))
+
+	if _, err := limited.Solve(root.Term()); err == nil {
+		t.Fatalf("expected derived solver to hit its step limit")
+	}
+	if _, err := base.Solve(root.Term()); err != nil {
+		t.Fatalf("expected base solver to be unaffected, got %v", err)
+	}
+}
+
+func TestSolvePerCallOptions(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("a"), SimpleVersion("1.0.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("a"), EqualsCondition{Version: SimpleVersion("2.0.0")})
+
+	solver := NewSolver(root, source)
+	_, err := solver.Solve(root.Term(), WithIncompatibilityTracking(true))
+	if _, ok := err.(*NoSolutionError); !ok {
+		t.Fatalf("expected per-call tracking to produce NoSolutionError, got %T", err)
+	}
+
+	_, err = solver.Solve(root.Term())
+	if _, ok := err.(ErrNoSolutionFound); !ok {
+		t.Fatalf("expected per-call option not to persist, got %T", err)
+	}
+}
+
+func TestSolvePerCallOptionsKeepState(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("a"), SimpleVersion("1.0.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("a"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	solver := NewSolver(root, source)
+	solver.keepState = true
+	if _, err := solver.Solve(root.Term(), WithMaxSteps(100)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if solver.lastState == nil || solver.lastState.options.MaxSteps != 100 {
+		t.Fatalf("expected the per-call solve state to be adopted, got %+v", solver.lastState)
+	}
+}
+
+func TestPropagationOrdersAgree(t *testing.T) {
+	orders := []PropagationOrder{PropagationFIFO, PropagationLIFO, PropagationMostConstrained}
+
+	root, source := conflictingWideWorkload(t, 6)
+	var want Solution
+	for _, order := range orders {
+		solver := NewSolverWithOptions([]Source{root, source},
+			WithPropagationOrder(order),
+			WithVersionStrategy(VersionNewest),
+		)
+		solution, err := solver.Solve(root.Term())
+		if err != nil {
+			t.Fatalf("order %d: unexpected error: %v", order, err)
+		}
+		if want == nil {
+			want = solution
+			continue
+		}
+		for _, nv := range want {
+			if ver, ok := solution.GetVersion(nv.Name); !ok || ver.Sort(nv.Version) != 0 {
+				t.Fatalf("order %d: expected %s %s, got %v", order, nv.Name.Value(), nv.Version, ver)
+			}
+		}
+	}
+
+	failing := NewRootSource()
+	failing.AddPackage(MakeName("pkg0"), EqualsCondition{Version: SimpleVersion("9.9.9")})
+	for _, order := range orders {
+		solver := NewSolverWithOptions([]Source{failing, source}, WithPropagationOrder(order))
+		if _, err := solver.Solve(failing.Term()); !errors.Is(err, ErrNoSolution) {
+			t.Fatalf("order %d: expected no solution, got %v", order, err)
+		}
+	}
+}
+
+func TestResumeSolveAcrossBudgets(t *testing.T) {
+	root, source := conflictingWideWorkload(t, 6)
+
+	reference := NewSolver(root, source)
+	want, err := reference.Solve(root.Term())
+	if err != nil {
+		t.Fatalf("reference solve failed: %v", err)
+	}
+	total := reference.Stats().Steps
+	if total < 4 {
+		t.Fatalf("workload too small to split: %d steps", total)
+	}
+
+	solver := NewSolver(root, source)
+	solution, err := solver.Solve(root.Term(), WithMaxSteps(2))
+	resumes := 0
+	var limit ErrIterationLimit
+	for errors.As(err, &limit) {
+		if limit.Handle == nil {
+			t.Fatalf("expected a resumable handle")
+		}
+		if limit.Handle.Steps() != limit.Steps {
+			t.Fatalf("handle reports %d steps, error %d", limit.Handle.Steps(), limit.Steps)
+		}
+		resumes++
+		solution, err = solver.ResumeSolve(limit.Handle, 2)
+		if _, again := solver.ResumeSolve(limit.Handle, 2); again == nil {
+			t.Fatalf("expected a spent handle to be rejected")
+		}
+	}
+	if err != nil {
+		t.Fatalf("resumed solve failed: %v", err)
+	}
+	if resumes == 0 {
+		t.Fatalf("expected the budget to interrupt the solve")
+	}
+	if got := solver.Stats().Steps; got != total {
+		t.Fatalf("expected %d steps across resumes, got %d", total, got)
+	}
+	for nv := range want.All() {
+		if got, ok := solution.GetVersion(nv.Name); !ok || got.Sort(nv.Version) != 0 {
+			t.Fatalf("resumed solution differs for %s: %v vs %v", nv.Name.Value(), got, nv.Version)
+		}
+	}
+}
+
+func TestScaledStepBudgetClamps(t *testing.T) {
+	budget := ScaledStepBudget(10, 100, 1000)
+	for _, tc := range []struct{ versions, want int }{
+		{versions: 3, want: 100},
+		{versions: 50, want: 500},
+		{versions: 500, want: 1000},
+	} {
+		if got := budget(1, tc.versions); got != tc.want {
+			t.Fatalf("budget for %d versions: expected %d, got %d", tc.versions, tc.want, got)
+		}
+	}
+}
+
+func TestStepBudgetScalesWithProblemSize(t *testing.T) {
+	root, source := conflictingWideWorkload(t, 6)
+
+	var maxPackages, maxVersions int
+	budget := func(packages, versions int) int {
+		if packages > maxPackages {
+			maxPackages, maxVersions = packages, versions
+		}
+		return 2 + versions
+	}
+	// A flat limit of 2 would stop this solve; the scaled budget grows with
+	// the graph instead.
+	solver := NewSolverWithOptions([]Source{root, source}, WithMaxSteps(2), WithStepBudget(budget))
+	if _, err := solver.Solve(root.Term()); err != nil {
+		t.Fatalf("expected the scaled budget to allow the solve, got %v", err)
+	}
+	if maxPackages != 7 || maxVersions != 27 {
+		t.Fatalf("expected 7 packages and 27 versions encountered, got %d and %d", maxPackages, maxVersions)
+	}
+
+	tight := NewSolverWithOptions([]Source{root, source}, WithStepBudget(ScaledStepBudget(0, 1, 0)))
+	_, err := tight.Solve(root.Term())
+	var limit ErrIterationLimit
+	if !errors.As(err, &limit) || limit.Steps != 1 {
+		t.Fatalf("expected the floor to stop the solve after 1 step, got %v", err)
+	}
+}
+
+func TestRootForbidExcludesPackage(t *testing.T) {
+	full := NewVersionSetCondition(FullVersionSet())
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("app"), SimpleVersion("2.0.0"), []Term{NewTerm(MakeName("mysql2"), full)})
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("mysql2"), SimpleVersion("1.0.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), full)
+	root.Forbid(MakeName("mysql2"))
+
+	solution, err := NewSolver(root, source).Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, _ := solution.GetVersion(MakeName("app")); ver == nil || ver.String() != "1.0.0" {
+		t.Fatalf("expected app to fall back to 1.0.0, got %v", ver)
+	}
+	if _, ok := solution.GetVersion(MakeName("mysql2")); ok {
+		t.Fatalf("expected mysql2 to stay out of the solution")
+	}
+
+	root.AddPackage(MakeName("app"), EqualsCondition{Version: SimpleVersion("2.0.0")})
+	_, err = NewSolver(root, source).EnableIncompatibilityTracking().Solve(root.Term())
+	if err == nil {
+		t.Fatalf("expected the ban to conflict with app 2.0.0")
+	}
+	if !strings.Contains(err.Error(), "$$root 1 forbids mysql2") {
+		t.Fatalf("expected the ban in the report, got:\n%v", err)
+	}
+}
+
+func TestNamedRootSource(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("lodash"), SimpleVersion("1.0.0"), nil)
+
+	root := NewRootSourceNamed(MakeName("myapp"))
+	root.AddPackage(MakeName("lodash"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	solution, err := NewSolver(root, source).Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := solution.GetVersion(MakeName("myapp")); !ok {
+		t.Fatalf("expected myapp in the solution, got %v", solution)
+	}
+	if _, ok := solution.GetVersion(MakeName("$$root")); ok {
+		t.Fatalf("expected no $$root in the solution, got %v", solution)
+	}
+
+	root.AddPackage(MakeName("lodash"), EqualsCondition{Version: SimpleVersion("2.0.0")})
+	_, err = NewSolver(root, source).EnableIncompatibilityTracking().Solve(root.Term())
+	if err == nil {
+		t.Fatalf("expected conflicting lodash requirements to fail")
+	}
+	if msg := err.Error(); strings.Contains(msg, "$$root") || !strings.Contains(msg, "myapp 1 depends on lodash") {
+		t.Fatalf("expected the project name in the report, got:\n%s", msg)
+	}
+}
+
+func TestRepeatedDecisionReusesDependencyClauses(t *testing.T) {
+	pin := func(name, version string) Term {
+		return NewTerm(MakeName(name), EqualsCondition{Version: SimpleVersion(version)})
+	}
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("a"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("a"), SimpleVersion("2.0.0"), []Term{pin("e", "1.0.0")})
+	source.AddPackage(MakeName("b"), SimpleVersion("1.0.0"), []Term{pin("a", "1.0.0")})
+	source.AddPackage(MakeName("b"), SimpleVersion("2.0.0"), []Term{pin("a", "1.0.0")})
+	source.AddPackage(MakeName("c"), SimpleVersion("1.0.0"), []Term{pin("e", "1.0.0")})
+	source.AddPackage(MakeName("c"), SimpleVersion("2.0.0"), []Term{pin("e", "1.0.0")})
+	source.AddPackage(MakeName("e"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("e"), SimpleVersion("2.0.0"), nil)
+
+	root := NewRootSource()
+	for _, name := range []string{"a", "b", "c"} {
+		root.AddPackage(MakeName(name), NewVersionSetCondition(FullVersionSet()))
+	}
+
+	solver := NewSolverWithOptions([]Source{root, source},
+		WithVersionStrategy(VersionNewest),
+		WithIncompatibilityTracking(true),
+	)
+	if _, err := solver.Solve(root.Term()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if solver.Stats().ReusedRegistrations == 0 {
+		t.Fatalf("expected a decision to be repeated after backtracking, stats %+v", solver.Stats())
+	}
+
+	seen := make(map[string]bool)
+	for _, incomp := range solver.GetIncompatibilities() {
+		if incomp.Kind != KindFromDependency {
+			continue
+		}
+		key := incomp.String()
+		if seen[key] {
+			t.Fatalf("dependency clause registered twice: %s", key)
+		}
+		seen[key] = true
+	}
+}
+
+func TestTrackingOnFailureRetriesWithTracking(t *testing.T) {
+	root, source := unsolvableWideWorkload(t, 4)
+
+	plain := NewSolverWithOptions([]Source{root, source})
+	_, err := plain.Solve(root.Term())
+	var noSolution ErrNoSolutionFound
+	if !errors.As(err, &noSolution) {
+		t.Fatalf("expected ErrNoSolutionFound without tracking, got %v", err)
+	}
+
+	solver := NewSolverWithOptions([]Source{root, source}, WithTrackingOnFailure(true))
+	_, err = solver.Solve(root.Term())
+	var detailed *NoSolutionError
+	if !errors.As(err, &detailed) {
+		t.Fatalf("expected a tracked NoSolutionError after retrying, got %v", err)
+	}
+	if len(solver.GetIncompatibilities()) == 0 {
+		t.Fatalf("expected the retry's learned clauses to be kept")
+	}
+	if got, want := solver.Stats().Steps, 2*plain.Stats().Steps; got != want {
+		t.Fatalf("expected stats to cover both attempts: %d steps, want %d", got, want)
+	}
+
+	solvable, source := conflictingWideWorkload(t, 4)
+	solver = NewSolverWithOptions([]Source{solvable, source}, WithTrackingOnFailure(true))
+	if _, err := solver.Solve(solvable.Term()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(solver.GetIncompatibilities()) != 0 {
+		t.Fatalf("expected a successful solve not to be retried with tracking")
+	}
+}
+
+func TestSolveWithDiagnosticsEscalatesFailures(t *testing.T) {
+	root, source := unsolvableWideWorkload(t, 4)
+
+	solver := NewSolver(root, source)
+	_, err := solver.SolveWithDiagnostics(root.Term())
+	var noSolution *NoSolutionError
+	if !errors.As(err, &noSolution) {
+		t.Fatalf("expected a detailed NoSolutionError, got %v", err)
+	}
+	if !strings.Contains(noSolution.Log, "resolving conflict") {
+		t.Fatalf("expected the retry's debug log on the error, got %q", noSolution.Log)
+	}
+	if solver.options.TrackIncompatibilities || solver.options.Logger != nil {
+		t.Fatalf("expected the solver's options to be left unchanged")
+	}
+
+	solvable, source := conflictingWideWorkload(t, 4)
+	solver = NewSolver(solvable, source)
+	if _, err := solver.SolveWithDiagnostics(solvable.Term()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -16,7 +16,9 @@
 package pubgrub
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"slices"
 )
 
@@ -39,11 +41,49 @@ type CombinedSource []Source
 
 // GetVersions queries all sources and returns the combined set of versions
 // in sorted order. Returns an error only if all sources fail with non-NotFound errors.
+//
+// A version published by several sources is listed once, taken from the
+// first source that has it, which is also the source GetDependencies
+// consults. Versions are compared with Sort, so "1.0" and "1.0.0" from
+// different sources count as the same version when their type says so.
 func (s CombinedSource) GetVersions(name Name) ([]Version, error) {
-	var ret []Version
+	return s.GetVersionsCtx(context.Background(), name)
+}
+
+// GetVersionsCtx implements ContextSource, passing ctx to each source.
+func (s CombinedSource) GetVersionsCtx(ctx context.Context, name Name) ([]Version, error) {
+	winners, err := s.collectVersions(ctx, name, FirstSourceWins, nil)
+	return winnerVersions(winners), err
+}
+
+// sourcedVersion is a version tagged with the index of its source.
+type sourcedVersion struct {
+	version Version
+	source  int
+}
+
+// winnerVersions strips the source indices from collected versions.
+func winnerVersions(winners []sourcedVersion) []Version {
+	if winners == nil {
+		return nil
+	}
+	versions := make([]Version, len(winners))
+	for i, winner := range winners {
+		versions[i] = winner.version
+	}
+	return versions
+}
+
+// collectVersions implements GetVersions, returning each version with the
+// source that supplies it. Duplicate versions are resolved by precedence;
+// when onDuplicate is set, it is called for each shadowed copy with the
+// indices of the winning and the shadowed source, and a non-nil error aborts
+// the query.
+func (s CombinedSource) collectVersions(ctx context.Context, name Name, precedence SourcePrecedence, onDuplicate func(ver Version, winner, shadowed int) error) ([]sourcedVersion, error) {
+	var all []sourcedVersion
 	var sawNotFound bool
-	for _, source := range s {
-		versions, err := source.GetVersions(name)
+	for i, source := range s {
+		versions, err := getVersionsCtx(ctx, source, name)
 		if err != nil {
 			var pkgErr *PackageNotFoundError
 			if errors.As(err, &pkgErr) {
@@ -52,29 +92,57 @@ func (s CombinedSource) GetVersions(name Name) ([]Version, error) {
 			}
 			return nil, err
 		}
-		ret = append(ret, versions...)
+		for _, ver := range versions {
+			all = append(all, sourcedVersion{version: ver, source: i})
+		}
 	}
 
-	if len(ret) == 0 {
+	if len(all) == 0 {
 		if sawNotFound {
 			return nil, &PackageNotFoundError{Package: name}
 		}
 		return nil, &PackageNotFoundError{Package: name}
 	}
 
-	// sort the versions
-	slices.SortFunc(ret, func(a Version, b Version) int {
-		return a.Sort(b)
+	// Sort by version, then by source preference, so the winner of each run
+	// of equal versions comes first.
+	slices.SortStableFunc(all, func(a, b sourcedVersion) int {
+		if c := a.version.Sort(b.version); c != 0 {
+			return c
+		}
+		if precedence == LastSourceWins {
+			return b.source - a.source
+		}
+		return a.source - b.source
 	})
 
+	ret := make([]sourcedVersion, 0, len(all))
+	for i := 0; i < len(all); {
+		winner := all[i]
+		ret = append(ret, winner)
+		j := i + 1
+		for ; j < len(all) && all[j].version.Sort(winner.version) == 0; j++ {
+			if onDuplicate != nil && all[j].source != winner.source {
+				if err := onDuplicate(winner.version, winner.source, all[j].source); err != nil {
+					return nil, err
+				}
+			}
+		}
+		i = j
+	}
 	return ret, nil
 }
 
 // GetDependencies queries sources in order and returns dependencies from the
 // first source that has the specified package version.
 func (s CombinedSource) GetDependencies(name Name, version Version) ([]Term, error) {
+	return s.GetDependenciesCtx(context.Background(), name, version)
+}
+
+// GetDependenciesCtx implements ContextSource, passing ctx to each source.
+func (s CombinedSource) GetDependenciesCtx(ctx context.Context, name Name, version Version) ([]Term, error) {
 	for _, source := range s {
-		deps, err := source.GetDependencies(name, version)
+		deps, err := getDependenciesCtx(ctx, source, name, version)
 		if err != nil {
 			var pkgErr *PackageNotFoundError
 			var verErr *PackageVersionNotFoundError
@@ -94,6 +162,172 @@ func (s CombinedSource) GetDependencies(name Name, version Version) ([]Term, err
 	return nil, &PackageVersionNotFoundError{Package: name, Version: version}
 }
 
+// VersionMetadata implements MetadataProvider with the first metadata the
+// sources provide, in order.
+func (s CombinedSource) VersionMetadata(name Name, version Version) any {
+	for _, source := range s {
+		if metadata := versionMetadata(source, name, version); metadata != nil {
+			return metadata
+		}
+	}
+	return nil
+}
+
+// SourcePrecedence decides which source of a CombinedSource supplies a
+// version that several sources publish.
+type SourcePrecedence int
+
+const (
+	// FirstSourceWins prefers the earliest source, matching how
+	// CombinedSource.GetDependencies consults sources. This is the default.
+	FirstSourceWins SourcePrecedence = iota
+	// LastSourceWins prefers the latest source, for setups where later
+	// sources are overlays that should shadow earlier ones.
+	LastSourceWins
+)
+
+// NamedSource is implemented by sources that can identify themselves in
+// diagnostics such as DuplicateVersionWarning.
+type NamedSource interface {
+	Source
+	SourceName() string
+}
+
+// ProvenanceSource is implemented by sources that delegate to other sources,
+// such as MirrorSource, and can name the one that served a version. Its
+// answer takes precedence over SourceName in NameVersion.Source.
+type ProvenanceSource interface {
+	Source
+	// Provenance names the source that served version, or "" if unknown.
+	Provenance(name Name, version Version) string
+}
+
+// sourceOrigin names the source that supplied version through source, or
+// returns "" when it cannot identify itself.
+func sourceOrigin(source Source, name Name, version Version) string {
+	if p, ok := source.(ProvenanceSource); ok {
+		if origin := p.Provenance(name, version); origin != "" {
+			return origin
+		}
+	}
+	if named, ok := source.(NamedSource); ok {
+		return named.SourceName()
+	}
+	return ""
+}
+
+// sourceName identifies the source at index i of a CombinedSource.
+func sourceName(source Source, i int) string {
+	if named, ok := source.(NamedSource); ok {
+		return named.SourceName()
+	}
+	return fmt.Sprintf("%T (source %d)", source, i)
+}
+
+// combinedView is the solver's view of a CombinedSource. It behaves like the
+// CombinedSource but notices versions published by more than one source,
+// warning about them or, in strict mode, failing.
+type combinedView struct {
+	CombinedSource
+	precedence SourcePrecedence
+	strict     bool
+	warn       func(Warning)
+	reported   map[string]bool
+	// origins names the source supplying each listed version, keyed by
+	// dependencyScoreKey; see sourceOrigin.
+	origins map[string]string
+	// suppliers holds the index of the MetadataProvider supplying each
+	// listed version, keyed by dependencyScoreKey.
+	suppliers map[string]int
+}
+
+func newCombinedView(sources CombinedSource, precedence SourcePrecedence, strict bool, warn func(Warning)) *combinedView {
+	return &combinedView{
+		CombinedSource: sources,
+		precedence:     precedence,
+		strict:         strict,
+		warn:           warn,
+		reported:       make(map[string]bool),
+		origins:        make(map[string]string),
+	}
+}
+
+// GetVersions returns the combined versions, reporting duplicates.
+func (v *combinedView) GetVersions(name Name) ([]Version, error) {
+	return v.GetVersionsCtx(context.Background(), name)
+}
+
+// GetVersionsCtx implements ContextSource.
+func (v *combinedView) GetVersionsCtx(ctx context.Context, name Name) ([]Version, error) {
+	winners, err := v.collectVersions(ctx, name, v.precedence, func(ver Version, first, dup int) error {
+		warning := DuplicateVersionWarning{
+			Package: name,
+			Version: ver,
+			Sources: [2]string{sourceName(v.CombinedSource[first], first), sourceName(v.CombinedSource[dup], dup)},
+		}
+		if v.strict {
+			return ErrDuplicateVersion{DuplicateVersionWarning: warning}
+		}
+		if key := dependencyScoreKey(name, ver); !v.reported[key] {
+			v.reported[key] = true
+			v.warn(warning)
+		}
+		return nil
+	})
+	for _, winner := range winners {
+		source := v.CombinedSource[winner.source]
+		if _, ok := source.(MetadataProvider); ok {
+			if v.suppliers == nil {
+				v.suppliers = make(map[string]int)
+			}
+			v.suppliers[dependencyScoreKey(name, winner.version)] = winner.source
+		}
+		if origin := sourceOrigin(source, name, winner.version); origin != "" {
+			v.origins[dependencyScoreKey(name, winner.version)] = origin
+		}
+	}
+	return winnerVersions(winners), err
+}
+
+// origin returns the name of the source that supplied version, or "" when
+// that source cannot identify itself.
+func (v *combinedView) origin(name Name, version Version) string {
+	return v.origins[dependencyScoreKey(name, version)]
+}
+
+// VersionMetadata implements MetadataProvider, asking the source that
+// supplied version. Versions supplied by sources without metadata have
+// none.
+func (v *combinedView) VersionMetadata(name Name, version Version) any {
+	if v.suppliers == nil {
+		return nil
+	}
+	if i, ok := v.suppliers[dependencyScoreKey(name, version)]; ok {
+		return versionMetadata(v.CombinedSource[i], name, version)
+	}
+	return nil
+}
+
+// GetDependencies consults the sources in precedence order, so dependencies
+// come from the same source as the version GetVersions reported.
+func (v *combinedView) GetDependencies(name Name, version Version) ([]Term, error) {
+	return v.GetDependenciesCtx(context.Background(), name, version)
+}
+
+// GetDependenciesCtx implements ContextSource.
+func (v *combinedView) GetDependenciesCtx(ctx context.Context, name Name, version Version) ([]Term, error) {
+	if v.precedence != LastSourceWins {
+		return v.CombinedSource.GetDependenciesCtx(ctx, name, version)
+	}
+	reversed := slices.Clone(v.CombinedSource)
+	slices.Reverse(reversed)
+	return reversed.GetDependenciesCtx(ctx, name, version)
+}
+
 var (
-	_ Source = CombinedSource{}
+	_ ContextSource = CombinedSource{}
+	_ ContextSource = (*combinedView)(nil)
+
+	_ MetadataProvider = CombinedSource{}
+	_ MetadataProvider = (*combinedView)(nil)
 )
@@ -0,0 +1,57 @@
+package pubgrub
+
+import "testing"
+
+func TestCombinedSourceDeduplicatesVersions(t *testing.T) {
+	upstream := &InMemorySource{}
+	upstream.AddPackage(MakeName("lib"), SimpleVersion("1.0.0"), nil)
+	upstream.AddPackage(MakeName("lib"), SimpleVersion("2.0.0"), nil)
+	overlay := &InMemorySource{}
+	overlay.AddPackage(MakeName("lib"), SimpleVersion("1.0.0"), nil)
+
+	versions, err := CombinedSource{upstream, overlay}.GetVersions(MakeName("lib"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(versions) != 2 || versions[0].String() != "1.0.0" || versions[1].String() != "2.0.0" {
+		t.Fatalf("expected [1.0.0 2.0.0], got %v", versions)
+	}
+}
+
+func TestSourcePrecedenceSelectsDependencies(t *testing.T) {
+	upstream := &InMemorySource{}
+	upstream.AddPackage(MakeName("lib"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("old"), EqualsCondition{Version: SimpleVersion("1.0.0")}),
+	})
+	upstream.AddPackage(MakeName("old"), SimpleVersion("1.0.0"), nil)
+
+	overlay := &InMemorySource{}
+	overlay.AddPackage(MakeName("lib"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("patched"), EqualsCondition{Version: SimpleVersion("1.0.0")}),
+	})
+	overlay.AddPackage(MakeName("patched"), SimpleVersion("1.0.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("lib"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	first, err := NewSolver(root, upstream, overlay).Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := first.GetVersion(MakeName("old")); !ok {
+		t.Fatalf("expected upstream dependencies by default, got %v", first)
+	}
+
+	last, err := NewSolverWithOptions([]Source{root, upstream, overlay},
+		WithSourcePrecedence(LastSourceWins),
+	).Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := last.GetVersion(MakeName("patched")); !ok {
+		t.Fatalf("expected overlay dependencies with LastSourceWins, got %v", last)
+	}
+	if _, ok := last.GetVersion(MakeName("old")); ok {
+		t.Fatalf("expected upstream dependencies to be shadowed, got %v", last)
+	}
+}
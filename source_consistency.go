@@ -0,0 +1,128 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"context"
+	"strings"
+)
+
+// ConsistencyMode controls how the solver guards against Sources whose data
+// changes while a solve is running.
+type ConsistencyMode int
+
+const (
+	// ConsistencyOff trusts the Source to return stable data.
+	ConsistencyOff ConsistencyMode = iota
+	// ConsistencyDetect re-queries the Source as usual but compares every
+	// answer with the first one seen for the same key, failing the solve with
+	// ErrInconsistentSource on a mismatch.
+	ConsistencyDetect
+	// ConsistencySnapshot wraps the Source in a CachedSource for the duration
+	// of the solve so the solver sees a single, frozen view of the data.
+	// This is the default.
+	ConsistencySnapshot
+)
+
+// consistencyGuard records the first answer for each query and rejects later
+// answers that differ from it.
+type consistencyGuard struct {
+	source   Source
+	versions map[Name]string
+	deps     map[string]string
+}
+
+func newConsistencyGuard(source Source) *consistencyGuard {
+	return &consistencyGuard{
+		source:   source,
+		versions: make(map[Name]string),
+		deps:     make(map[string]string),
+	}
+}
+
+// GetVersions forwards to the wrapped source and verifies the result.
+func (g *consistencyGuard) GetVersions(name Name) ([]Version, error) {
+	return g.GetVersionsCtx(context.Background(), name)
+}
+
+// GetVersionsCtx implements ContextSource.
+func (g *consistencyGuard) GetVersionsCtx(ctx context.Context, name Name) ([]Version, error) {
+	versions, err := getVersionsCtx(ctx, g.source, name)
+	if err != nil {
+		return nil, err
+	}
+
+	sig := versionListSignature(versions)
+	if prev, ok := g.versions[name]; ok && prev != sig {
+		return nil, ErrInconsistentSource{Package: name, Previous: prev, Current: sig}
+	}
+	g.versions[name] = sig
+	return versions, nil
+}
+
+// GetDependencies forwards to the wrapped source and verifies the result.
+func (g *consistencyGuard) GetDependencies(name Name, version Version) ([]Term, error) {
+	return g.GetDependenciesCtx(context.Background(), name, version)
+}
+
+// GetDependenciesCtx implements ContextSource.
+func (g *consistencyGuard) GetDependenciesCtx(ctx context.Context, name Name, version Version) ([]Term, error) {
+	deps, err := getDependenciesCtx(ctx, g.source, name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	key := dependencyScoreKey(name, version)
+	sig := dependencySignature(deps)
+	if prev, ok := g.deps[key]; ok && prev != sig {
+		return nil, ErrInconsistentSource{Package: name, Version: version, Previous: prev, Current: sig}
+	}
+	g.deps[key] = sig
+	return deps, nil
+}
+
+// versionListSignature renders a version list for comparison.
+func versionListSignature(versions []Version) string {
+	parts := make([]string, len(versions))
+	for i, ver := range versions {
+		parts[i] = ver.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// guardSource applies the configured consistency mode to source.
+func guardSource(source Source, mode ConsistencyMode) Source {
+	switch mode {
+	case ConsistencyDetect:
+		return newConsistencyGuard(source)
+	case ConsistencySnapshot:
+		if _, ok := source.(*CachedSource); ok {
+			return source
+		}
+		return NewCachedSource(source)
+	default:
+		return source
+	}
+}
+
+// VersionMetadata implements MetadataProvider, passing the call through.
+func (g *consistencyGuard) VersionMetadata(name Name, version Version) any {
+	return versionMetadata(g.source, name, version)
+}
+
+var (
+	_ ContextSource    = (*consistencyGuard)(nil)
+	_ MetadataProvider = (*consistencyGuard)(nil)
+)
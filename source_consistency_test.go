@@ -0,0 +1,66 @@
+package pubgrub
+
+import (
+	"errors"
+	"testing"
+)
+
+// mutatingSource publishes a new dependency for foo after the first lookup,
+// simulating a registry that changes while a solve is running.
+type mutatingSource struct {
+	InMemorySource
+	calls int
+}
+
+func (m *mutatingSource) GetDependencies(name Name, version Version) ([]Term, error) {
+	m.calls++
+	if name == MakeName("foo") && m.calls > 1 {
+		return []Term{NewTerm(MakeName("bar"), EqualsCondition{Version: SimpleVersion("2.0.0")})}, nil
+	}
+	return m.InMemorySource.GetDependencies(name, version)
+}
+
+func newMutatingSource() *mutatingSource {
+	source := &mutatingSource{}
+	source.AddPackage(MakeName("foo"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("bar"), EqualsCondition{Version: SimpleVersion("1.0.0")}),
+	})
+	source.AddPackage(MakeName("bar"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("bar"), SimpleVersion("2.0.0"), nil)
+	return source
+}
+
+func TestConsistencyGuardDetectsChangedDependencies(t *testing.T) {
+	guard := newConsistencyGuard(newMutatingSource())
+
+	if _, err := guard.GetDependencies(MakeName("foo"), SimpleVersion("1.0.0")); err != nil {
+		t.Fatalf("unexpected error on first lookup: %v", err)
+	}
+	_, err := guard.GetDependencies(MakeName("foo"), SimpleVersion("1.0.0"))
+
+	var inconsistent ErrInconsistentSource
+	if !errors.As(err, &inconsistent) {
+		t.Fatalf("expected ErrInconsistentSource, got %v", err)
+	}
+	if inconsistent.Package != MakeName("foo") || inconsistent.Version == nil {
+		t.Fatalf("unexpected error details: %+v", inconsistent)
+	}
+	if inconsistent.Previous == inconsistent.Current {
+		t.Fatalf("expected differing answers, got %q twice", inconsistent.Previous)
+	}
+}
+
+func TestSolverSnapshotsSourceByDefault(t *testing.T) {
+	source := newMutatingSource()
+	root := NewRootSource()
+	root.AddPackage(MakeName("foo"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	solver := NewSolver(root, source)
+	solution, err := solver.Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, ok := solution.GetVersion(MakeName("bar")); !ok || ver.String() != "1.0.0" {
+		t.Fatalf("expected bar 1.0.0 from the first answer, got %v", ver)
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package pubgrub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SourceFailure is one failed Source call set aside by a solve with
+// WithSourceErrorAggregation.
+type SourceFailure struct {
+	Package Name
+	// Version is the version whose dependencies could not be fetched, or nil
+	// when listing the package's versions failed.
+	Version Version
+	// Chain lists the packages that led to Package being required, ending
+	// with Package.
+	Chain []Name
+	Err   error
+}
+
+// Error implements the error interface.
+func (f SourceFailure) Error() string {
+	if f.Version == nil {
+		return fmt.Sprintf("versions of %s%s: %v", FormatName(f.Package), chainSuffix(f.Chain), f.Err)
+	}
+	return fmt.Sprintf("dependencies of %s %s%s: %v", FormatName(f.Package), f.Version, chainSuffix(f.Chain), f.Err)
+}
+
+// Unwrap returns the underlying error.
+func (f SourceFailure) Unwrap() error {
+	return f.Err
+}
+
+// MultiSourceError reports every Source call that failed during a solve
+// with WithSourceErrorAggregation, so all registry problems can be fixed in
+// one pass. Failures are sorted by package and version. errors.Is and
+// errors.As see each failure's error and Err, so errors.Is(err,
+// ErrNoSolution) reports whether the solve also failed.
+//
+// Example:
+//
+//	var multi *MultiSourceError
+//	if errors.As(err, &multi) {
+//	    for _, failure := range multi.Failures {
+//	        log.Print(failure)
+//	    }
+//	}
+type MultiSourceError struct {
+	Failures []SourceFailure
+	// Err is the solve's own error once the failing calls were set aside,
+	// or nil if it found a solution despite them.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *MultiSourceError) Error() string {
+	var b strings.Builder
+	if len(e.Failures) == 1 {
+		b.WriteString("1 source call failed:")
+	} else {
+		fmt.Fprintf(&b, "%d source calls failed:", len(e.Failures))
+	}
+	for _, failure := range e.Failures {
+		b.WriteString("\n  - ")
+		b.WriteString(failure.Error())
+	}
+	if e.Err != nil {
+		b.WriteString("\n")
+		b.WriteString(e.Err.Error())
+	}
+	return b.String()
+}
+
+// Unwrap returns the failures followed by Err.
+func (e *MultiSourceError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failures)+1)
+	for _, failure := range e.Failures {
+		errs = append(errs, failure)
+	}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	return errs
+}
+
+// aggregates reports whether a failed Source call should be set aside
+// rather than end the solve. Calls that failed because the solve's context
+// ended are never set aside.
+func (st *solverState) aggregates(err error) bool {
+	if !st.options.AggregateSourceErrors {
+		return false
+	}
+	return st.ctx.Err() == nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// recordSourceFailure sets aside a failed Source call about name, or about
+// name@version when version is not nil.
+func (st *solverState) recordSourceFailure(name Name, version Version, err error) {
+	failure := SourceFailure{Package: name, Version: version, Chain: st.requirementChain(name), Err: err}
+	if !slices.ContainsFunc(st.sourceFailures, failure.sameCall) {
+		st.sourceFailures = append(st.sourceFailures, failure)
+	}
+	st.debug("source call failed, continuing", "failure", failure.Error())
+}
+
+// unfetchedDependencies returns an incompatibility ruling out a version
+// whose dependencies could not be fetched.
+func unfetchedDependencies(name Name, version Version) *Incompatibility {
+	return &Incompatibility{
+		Terms:  []Term{NewTerm(name, EqualsCondition{Version: version})},
+		Kind:   KindNoVersions,
+		Reason: fmt.Sprintf("dependencies of %s %s could not be fetched", FormatName(name), version),
+	}
+}
+
+// sameCall reports whether f and other are about the same Source call.
+func (f SourceFailure) sameCall(other SourceFailure) bool {
+	if f.Package != other.Package || (f.Version == nil) != (other.Version == nil) {
+		return false
+	}
+	return f.Version == nil || f.Version.Sort(other.Version) == 0
+}
+
+// aggregateSourceErrors turns the outcome of a solve that set failing
+// Source calls aside into a MultiSourceError. Packages the source reported
+// as not found are listed too when the solve failed, since they may be why.
+// Outcomes other than a solution or a failed solve, such as cancellation,
+// are returned as they are.
+func (st *solverState) aggregateSourceErrors(solution Solution, err error) (Solution, error) {
+	if err != nil && !errors.Is(err, ErrNoSolution) {
+		return solution, err
+	}
+	failures := slices.Clone(st.sourceFailures)
+	if err != nil {
+		for _, name := range st.missingPackages() {
+			failures = append(failures, SourceFailure{Package: name, Chain: st.requirementChain(name), Err: st.missing[name]})
+		}
+	}
+	if len(failures) == 0 {
+		return solution, err
+	}
+	slices.SortStableFunc(failures, func(a, b SourceFailure) int {
+		if c := strings.Compare(a.Package.Value(), b.Package.Value()); c != 0 {
+			return c
+		}
+		switch {
+		case a.Version == nil && b.Version == nil:
+			return 0
+		case a.Version == nil:
+			return -1
+		case b.Version == nil:
+			return 1
+		}
+		return a.Version.Sort(b.Version)
+	})
+	return nil, &MultiSourceError{Failures: failures, Err: err}
+}
+
+var (
+	_ error = SourceFailure{}
+	_ error = (*MultiSourceError)(nil)
+)
@@ -0,0 +1,130 @@
+package pubgrub
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// brokenSource fails to list the versions of the packages in versions and
+// to fetch the dependencies of the "name@version" keys in deps.
+type brokenSource struct {
+	Source
+	versions map[string]bool
+	deps     map[string]bool
+}
+
+func (s *brokenSource) GetVersions(name Name) ([]Version, error) {
+	if s.versions[name.Value()] {
+		return nil, errors.New("502 bad gateway")
+	}
+	return s.Source.GetVersions(name)
+}
+
+func (s *brokenSource) GetDependencies(name Name, version Version) ([]Term, error) {
+	if s.deps[dependencyScoreKey(name, version)] {
+		return nil, errors.New("malformed gemspec")
+	}
+	return s.Source.GetDependencies(name, version)
+}
+
+func TestSourceErrorAggregationListsEveryFailure(t *testing.T) {
+	source := &InMemorySource{}
+	anyVersion := NewVersionSetCondition(FullVersionSet())
+	source.AddPackage(MakeName("rails"), SimpleVersion("7.0.0"), []Term{NewTerm(MakeName("rack-session"), anyVersion)})
+	source.AddPackage(MakeName("rails"), SimpleVersion("6.0.0"), []Term{NewTerm(MakeName("rack"), anyVersion)})
+	source.AddPackage(MakeName("rack"), SimpleVersion("3.0.0"), nil)
+	source.AddPackage(MakeName("rack"), SimpleVersion("2.0.0"), []Term{NewTerm(MakeName("puma"), anyVersion)})
+	root := NewRootSource()
+	root.AddPackage(MakeName("rails"), anyVersion)
+	broken := &brokenSource{
+		Source:   source,
+		versions: map[string]bool{"puma": true},
+		deps:     map[string]bool{dependencyScoreKey(MakeName("rack"), SimpleVersion("3.0.0")): true},
+	}
+
+	solver := NewSolverWithOptions([]Source{root, broken},
+		WithIncompatibilityTracking(true),
+		WithSourceErrorAggregation(true),
+	)
+	_, err := solver.Solve(root.Term())
+
+	var multi *MultiSourceError
+	if !errors.As(err, &multi) {
+		t.Fatalf("expected MultiSourceError, got %v", err)
+	}
+	if !errors.Is(err, ErrNoSolution) {
+		t.Fatalf("expected the solve to fail too, got %v", multi.Err)
+	}
+	var got []string
+	for _, failure := range multi.Failures {
+		got = append(got, failure.Package.Value())
+	}
+	if want := "puma,rack,rack-session"; strings.Join(got, ",") != want {
+		t.Fatalf("expected failures for %s, got %v", want, got)
+	}
+	if multi.Failures[1].Version == nil || multi.Failures[1].Version.String() != "3.0.0" {
+		t.Fatalf("expected rack 3.0.0 dependencies to fail, got %v", multi.Failures[1])
+	}
+	var notFound *PackageNotFoundError
+	if !errors.As(err, &notFound) || notFound.Package != MakeName("rack-session") {
+		t.Fatalf("expected the missing rack-session to be reported, got %v", err)
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, "3 source calls failed") || !strings.Contains(msg, "502 bad gateway") || !strings.Contains(msg, "malformed gemspec") {
+		t.Fatalf("unexpected message:\n%s", msg)
+	}
+}
+
+func TestSourceErrorAggregationReportsDespiteSolution(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("rails"), SimpleVersion("7.0.0"), []Term{
+		NewTerm(MakeName("rack"), NewVersionSetCondition(FullVersionSet())),
+	})
+	source.AddPackage(MakeName("rack"), SimpleVersion("2.0.0"), nil)
+	source.AddPackage(MakeName("rack"), SimpleVersion("3.0.0"), nil)
+	source.AddPackage(MakeName("puma"), SimpleVersion("6.0.0"), nil)
+	root := NewRootSource()
+	root.AddPackage(MakeName("rails"), NewVersionSetCondition(FullVersionSet()))
+	root.AddPackage(MakeName("puma"), NewVersionSetCondition(FullVersionSet()))
+	broken := &brokenSource{
+		Source: source,
+		deps:   map[string]bool{dependencyScoreKey(MakeName("rack"), SimpleVersion("3.0.0")): true},
+	}
+
+	_, err := NewSolverWithOptions([]Source{root, broken}, WithSourceErrorAggregation(true)).Solve(root.Term())
+	var multi *MultiSourceError
+	if !errors.As(err, &multi) {
+		t.Fatalf("expected MultiSourceError, got %v", err)
+	}
+	if multi.Err != nil || len(multi.Failures) != 1 {
+		t.Fatalf("expected a single failure and a solvable problem, got %v", err)
+	}
+	if errors.Is(err, ErrNoSolution) {
+		t.Fatalf("the solve itself succeeded")
+	}
+}
+
+func TestSourceErrorsAbortWithoutAggregation(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("rails"), SimpleVersion("7.0.0"), []Term{
+		NewTerm(MakeName("rack"), NewVersionSetCondition(FullVersionSet())),
+	})
+	source.AddPackage(MakeName("rack"), SimpleVersion("2.0.0"), nil)
+	source.AddPackage(MakeName("rack"), SimpleVersion("3.0.0"), nil)
+	source.AddPackage(MakeName("puma"), SimpleVersion("6.0.0"), nil)
+	root := NewRootSource()
+	root.AddPackage(MakeName("rails"), NewVersionSetCondition(FullVersionSet()))
+	root.AddPackage(MakeName("puma"), NewVersionSetCondition(FullVersionSet()))
+	broken := &brokenSource{Source: source, versions: map[string]bool{"puma": true}}
+
+	_, err := NewSolver(root, broken).Solve(root.Term())
+	var versionsErr *VersionsError
+	if !errors.As(err, &versionsErr) || versionsErr.Package != MakeName("puma") {
+		t.Fatalf("expected VersionsError for puma, got %v", err)
+	}
+	var multi *MultiSourceError
+	if errors.As(err, &multi) {
+		t.Fatalf("aggregation is off by default")
+	}
+}
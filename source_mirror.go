@@ -0,0 +1,270 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Mirror is one registry endpoint of a MirrorSource.
+type Mirror struct {
+	Name   string
+	Source Source
+}
+
+// MirrorHealth summarizes how a mirror has behaved, see MirrorSource.Health.
+type MirrorHealth struct {
+	Name     string
+	Requests int
+	Failures int
+	// ConsecutiveFailures counts failures since the last success.
+	ConsecutiveFailures int
+	// Latency is the mean duration of the mirror's calls.
+	Latency   time.Duration
+	LastError error
+	// Healthy reports whether the mirror is tried in its configured order;
+	// unhealthy mirrors are tried last.
+	Healthy bool
+}
+
+// DefaultMirrorFailureThreshold is the number of consecutive failures after
+// which a MirrorSource tries a mirror last.
+const DefaultMirrorFailureThreshold = 3
+
+// MirrorSource reads through a primary registry and falls back to mirrors
+// when a call fails. Mirrors are tried in order; one that fails
+// FailureThreshold times in a row is tried after the healthy ones until it
+// succeeds again. A "not found" answer is an answer, not a failure, and is
+// returned without consulting further mirrors. The mirror that listed each
+// package is reported as its provenance, so NameVersion.Source shows which
+// mirror served it. It is safe for concurrent use.
+//
+// Example:
+//
+//	source := NewMirrorSource(
+//	    Mirror{Name: "rubygems.org", Source: primary},
+//	    Mirror{Name: "mirror.internal", Source: fallback},
+//	)
+//	solution, err := NewSolver(root, source).Solve(root.Term())
+//	// solution[i].Source names the mirror that served each package
+type MirrorSource struct {
+	Mirrors []Mirror
+	// FailureThreshold is the number of consecutive failures after which a
+	// mirror is tried last. Zero means DefaultMirrorFailureThreshold.
+	FailureThreshold int
+
+	mu     sync.Mutex
+	health []mirrorHealth
+	served map[Name]string // Mirror that listed each package
+}
+
+// mirrorHealth accumulates the statistics behind MirrorHealth.
+type mirrorHealth struct {
+	requests    int
+	failures    int
+	consecutive int
+	elapsed     time.Duration
+	lastErr     error
+}
+
+// NewMirrorSource returns a MirrorSource trying primary first, then mirrors
+// in order.
+func NewMirrorSource(primary Mirror, mirrors ...Mirror) *MirrorSource {
+	return &MirrorSource{Mirrors: append([]Mirror{primary}, mirrors...)}
+}
+
+// SourceName implements NamedSource with the primary's name.
+func (m *MirrorSource) SourceName() string {
+	if len(m.Mirrors) == 0 {
+		return "mirrors"
+	}
+	return m.Mirrors[0].Name
+}
+
+// GetVersions implements Source.
+func (m *MirrorSource) GetVersions(name Name) ([]Version, error) {
+	return m.GetVersionsCtx(context.Background(), name)
+}
+
+// GetVersionsCtx implements ContextSource, passing ctx to each mirror tried.
+func (m *MirrorSource) GetVersionsCtx(ctx context.Context, name Name) ([]Version, error) {
+	var versions []Version
+	served, err := m.try(ctx, func(source Source) error {
+		var err error
+		versions, err = getVersionsCtx(ctx, source, name)
+		return err
+	})
+	if served != "" {
+		m.mu.Lock()
+		if m.served == nil {
+			m.served = make(map[Name]string)
+		}
+		m.served[name] = served
+		m.mu.Unlock()
+	}
+	return versions, err
+}
+
+// GetDependencies implements Source.
+func (m *MirrorSource) GetDependencies(name Name, version Version) ([]Term, error) {
+	return m.GetDependenciesCtx(context.Background(), name, version)
+}
+
+// GetDependenciesCtx implements ContextSource, passing ctx to each mirror
+// tried.
+func (m *MirrorSource) GetDependenciesCtx(ctx context.Context, name Name, version Version) ([]Term, error) {
+	var deps []Term
+	_, err := m.try(ctx, func(source Source) error {
+		var err error
+		deps, err = getDependenciesCtx(ctx, source, name, version)
+		return err
+	})
+	return deps, err
+}
+
+// Provenance implements ProvenanceSource, naming the mirror that listed
+// the versions of name.
+func (m *MirrorSource) Provenance(name Name, _ Version) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.served[name]
+}
+
+// VersionMetadata implements MetadataProvider, asking the mirror that
+// listed the versions of name.
+func (m *MirrorSource) VersionMetadata(name Name, version Version) any {
+	served := m.Provenance(name, version)
+	for _, mirror := range m.Mirrors {
+		if mirror.Name == served {
+			return versionMetadata(mirror.Source, name, version)
+		}
+	}
+	return nil
+}
+
+// Health returns a snapshot of every mirror's statistics, in configured
+// order.
+func (m *MirrorSource) Health() []MirrorHealth {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ensureHealth()
+
+	result := make([]MirrorHealth, len(m.Mirrors))
+	for i, mirror := range m.Mirrors {
+		h := m.health[i]
+		result[i] = MirrorHealth{
+			Name:                mirror.Name,
+			Requests:            h.requests,
+			Failures:            h.failures,
+			ConsecutiveFailures: h.consecutive,
+			LastError:           h.lastErr,
+			Healthy:             h.consecutive < m.threshold(),
+		}
+		if h.requests > 0 {
+			result[i].Latency = h.elapsed / time.Duration(h.requests)
+		}
+	}
+	return result
+}
+
+// try calls query on each mirror until one answers, returning the name of
+// the mirror that did. When every mirror fails, the errors are joined. Once
+// ctx is done no further mirror is tried, and the failure is not held against
+// the mirror that was interrupted.
+func (m *MirrorSource) try(ctx context.Context, query func(Source) error) (string, error) {
+	var errs []error
+	for _, i := range m.order() {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		mirror := m.Mirrors[i]
+		start := time.Now()
+		err := query(mirror.Source)
+		if err != nil && ctx.Err() != nil {
+			return "", err
+		}
+		failed := err != nil && !isMissingPackage(err)
+		m.observe(i, time.Since(start), failed, err)
+		if !failed {
+			return mirror.Name, err
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", mirror.Name, err))
+	}
+	if len(errs) == 0 {
+		return "", errors.New("mirror source has no mirrors")
+	}
+	return "", fmt.Errorf("all mirrors failed: %w", errors.Join(errs...))
+}
+
+// order returns the mirror indices to try: healthy mirrors in configured
+// order, then unhealthy ones.
+func (m *MirrorSource) order() []int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ensureHealth()
+
+	order := make([]int, 0, len(m.Mirrors))
+	var unhealthy []int
+	for i := range m.Mirrors {
+		if m.health[i].consecutive >= m.threshold() {
+			unhealthy = append(unhealthy, i)
+			continue
+		}
+		order = append(order, i)
+	}
+	return append(order, unhealthy...)
+}
+
+// observe records the outcome of a call to mirror i.
+func (m *MirrorSource) observe(i int, elapsed time.Duration, failed bool, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ensureHealth()
+
+	h := &m.health[i]
+	h.requests++
+	h.elapsed += elapsed
+	if failed {
+		h.failures++
+		h.consecutive++
+		h.lastErr = err
+		return
+	}
+	h.consecutive = 0
+}
+
+// ensureHealth sizes the statistics to the mirror list. m.mu must be held.
+func (m *MirrorSource) ensureHealth() {
+	for len(m.health) < len(m.Mirrors) {
+		m.health = append(m.health, mirrorHealth{})
+	}
+}
+
+func (m *MirrorSource) threshold() int {
+	if m.FailureThreshold > 0 {
+		return m.FailureThreshold
+	}
+	return DefaultMirrorFailureThreshold
+}
+
+var (
+	_ NamedSource      = (*MirrorSource)(nil)
+	_ ProvenanceSource = (*MirrorSource)(nil)
+	_ MetadataProvider = (*MirrorSource)(nil)
+)
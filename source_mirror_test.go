@@ -0,0 +1,79 @@
+package pubgrub
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// flakySource fails every call while down is set.
+type flakySource struct {
+	Source
+	down  bool
+	calls int
+}
+
+func (s *flakySource) GetVersions(name Name) ([]Version, error) {
+	s.calls++
+	if s.down {
+		return nil, errors.New("connection refused")
+	}
+	return s.Source.GetVersions(name)
+}
+
+func (s *flakySource) GetDependencies(name Name, version Version) ([]Term, error) {
+	s.calls++
+	if s.down {
+		return nil, errors.New("connection refused")
+	}
+	return s.Source.GetDependencies(name, version)
+}
+
+func TestMirrorSourceFallsBackAndReportsProvenance(t *testing.T) {
+	registry := &InMemorySource{}
+	registry.AddPackage(MakeName("rails"), SimpleVersion("7.0.0"), []Term{
+		NewTerm(MakeName("rack"), NewVersionSetCondition(FullVersionSet())),
+	})
+	registry.AddPackage(MakeName("rack"), SimpleVersion("3.0.0"), nil)
+	primary := &flakySource{Source: registry, down: true}
+	fallback := &flakySource{Source: registry}
+	source := NewMirrorSource(
+		Mirror{Name: "primary", Source: primary},
+		Mirror{Name: "fallback", Source: fallback},
+	)
+	source.FailureThreshold = 2
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("rails"), NewVersionSetCondition(FullVersionSet()))
+	solution, err := NewSolver(root, source).Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, nv := range solution {
+		if nv.Name != root.Term().Name && nv.Source != "fallback" {
+			t.Fatalf("expected %s to be served by the fallback, got %q", nv.Name.Value(), nv.Source)
+		}
+	}
+
+	health := source.Health()
+	if health[0].Healthy || health[0].Failures != 2 || !strings.Contains(health[0].LastError.Error(), "refused") {
+		t.Fatalf("expected the primary to be demoted after two failures, got %+v", health[0])
+	}
+	if !health[1].Healthy || health[1].Failures != 0 || health[1].Requests == 0 {
+		t.Fatalf("unexpected fallback health %+v", health[1])
+	}
+	if primary.calls != 2 {
+		t.Fatalf("expected the demoted primary to be skipped while the fallback answers, got %d calls", primary.calls)
+	}
+
+	// A "not found" answer is authoritative.
+	if _, err := source.GetVersions(MakeName("absent")); !isMissingPackage(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+
+	fallback.down = true
+	_, err = source.GetVersions(MakeName("rails"))
+	if err == nil || !strings.Contains(err.Error(), "all mirrors failed") || !strings.Contains(err.Error(), "fallback: connection refused") {
+		t.Fatalf("expected every mirror's failure, got %v", err)
+	}
+}
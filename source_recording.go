@@ -0,0 +1,173 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// RecordingSource wraps a Source and records every GetVersions and
+// GetDependencies answer, so a solve against a live registry can be saved as
+// a Problem and replayed hermetically. Answers are passed through unchanged.
+// It is safe for concurrent use.
+//
+// Example:
+//
+//	recorder := NewRecordingSource(registry)
+//	_, err := NewSolver(root, recorder).Solve(root.Term())
+//	if err != nil {
+//	    data, _ := MarshalProblem(recorder.Problem(*root))
+//	    os.WriteFile("testdata/repro.json", data, 0o644)
+//	}
+type RecordingSource struct {
+	Source Source
+
+	mu       sync.Mutex
+	packages map[Name]*recordedPackage
+}
+
+// recordedPackage holds the answers recorded for one package.
+type recordedPackage struct {
+	missing  bool
+	err      error
+	versions []Version
+	deps     map[string]recordedDependencies // By version string
+}
+
+type recordedDependencies struct {
+	deps []Term
+	err  error
+}
+
+// NewRecordingSource returns a RecordingSource wrapping source.
+func NewRecordingSource(source Source) *RecordingSource {
+	return &RecordingSource{Source: source}
+}
+
+// GetVersions implements Source.
+func (r *RecordingSource) GetVersions(name Name) ([]Version, error) {
+	return r.GetVersionsCtx(context.Background(), name)
+}
+
+// GetVersionsCtx implements ContextSource. Answers interrupted by ctx are
+// not recorded, so a cancelled solve does not leave errors in the Problem.
+func (r *RecordingSource) GetVersionsCtx(ctx context.Context, name Name) ([]Version, error) {
+	versions, err := getVersionsCtx(ctx, r.Source, name)
+	if err != nil && ctx.Err() != nil {
+		return versions, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pkg := r.record(name)
+	pkg.missing, pkg.err, pkg.versions = false, nil, slices.Clone(versions)
+	if err != nil {
+		if isMissingPackage(err) {
+			pkg.missing = true
+		} else {
+			pkg.err = err
+		}
+	}
+	return versions, err
+}
+
+// GetDependencies implements Source.
+func (r *RecordingSource) GetDependencies(name Name, version Version) ([]Term, error) {
+	return r.GetDependenciesCtx(context.Background(), name, version)
+}
+
+// GetDependenciesCtx implements ContextSource. Answers interrupted by ctx
+// are not recorded.
+func (r *RecordingSource) GetDependenciesCtx(ctx context.Context, name Name, version Version) ([]Term, error) {
+	deps, err := getDependenciesCtx(ctx, r.Source, name, version)
+	if err != nil && ctx.Err() != nil {
+		return deps, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pkg := r.record(name)
+	if pkg.deps == nil {
+		pkg.deps = make(map[string]recordedDependencies)
+	}
+	pkg.deps[version.String()] = recordedDependencies{deps: slices.Clone(deps), err: err}
+	if !slices.ContainsFunc(pkg.versions, func(v Version) bool { return v.Sort(version) == 0 }) {
+		pkg.versions = append(pkg.versions, version)
+	}
+	return deps, err
+}
+
+// record returns the entry for name, creating it. r.mu must be held.
+func (r *RecordingSource) record(name Name) *recordedPackage {
+	if r.packages == nil {
+		r.packages = make(map[Name]*recordedPackage)
+	}
+	pkg, ok := r.packages[name]
+	if !ok {
+		pkg = &recordedPackage{}
+		r.packages[name] = pkg
+	}
+	return pkg
+}
+
+// Problem returns the answers recorded so far as a Problem with the given
+// root requirements, typically the RootSource of the solve. Packages are
+// sorted by name and versions in source order, so recording the same solve
+// twice yields the same file.
+func (r *RecordingSource) Problem(requirements []Term) *Problem {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	problem := &Problem{Root: make([]TermRecord, 0, len(requirements))}
+	for _, term := range requirements {
+		problem.Root = append(problem.Root, termRecord(term))
+	}
+
+	names := make([]Name, 0, len(r.packages))
+	for name := range r.packages {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b Name) int { return strings.Compare(a.Value(), b.Value()) })
+
+	for _, name := range names {
+		pkg := r.packages[name]
+		record := PackageRecord{Name: name.Value(), Missing: pkg.missing}
+		if pkg.err != nil {
+			record.Error = pkg.err.Error()
+		}
+		for _, version := range pkg.versions {
+			vr := VersionRecord{Version: version.String()}
+			fetched, ok := pkg.deps[version.String()]
+			switch {
+			case !ok:
+				vr.Unfetched = true
+			case fetched.err != nil:
+				vr.Error = fetched.err.Error()
+			default:
+				for _, dep := range fetched.deps {
+					vr.Dependencies = append(vr.Dependencies, termRecord(dep))
+				}
+			}
+			record.Versions = append(record.Versions, vr)
+		}
+		problem.Packages = append(problem.Packages, record)
+	}
+	return problem
+}
+
+var _ ContextSource = (*RecordingSource)(nil)
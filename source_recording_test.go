@@ -0,0 +1,54 @@
+package pubgrub
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestRecordingSourceReplaysSolve(t *testing.T) {
+	for _, unsolvable := range []bool{false, true} {
+		var root *RootSource
+		var live Source
+		if unsolvable {
+			root, live = unsolvableWideWorkload(t, 4)
+		} else {
+			root, live = conflictingWideWorkload(t, 4)
+		}
+		root.AddPackage(MakeName("absent"), BestEffortCondition{})
+
+		recorder := NewRecordingSource(live)
+		solver := NewSolverWithOptions([]Source{root, recorder}, WithIncompatibilityTracking(true))
+		want, wantErr := solver.Solve(root.Term())
+
+		data, err := MarshalProblem(recorder.Problem(*root))
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		problem, err := UnmarshalProblem(data)
+		if err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		replayRoot, replay, err := problem.Sources(nil)
+		if err != nil {
+			t.Fatalf("sources: %v", err)
+		}
+
+		got, gotErr := NewSolverWithOptions([]Source{replayRoot, replay}, WithIncompatibilityTracking(true)).Solve(replayRoot.Term())
+		if (wantErr == nil) != (gotErr == nil) {
+			t.Fatalf("replay error %v, live error %v", gotErr, wantErr)
+		}
+		if wantErr != nil {
+			var live, replayed *NoSolutionError
+			if !errors.As(wantErr, &live) || !errors.As(gotErr, &replayed) {
+				t.Fatalf("expected no-solution errors, got %v and %v", gotErr, wantErr)
+			}
+			if live.Incompatibility.ID() != replayed.Incompatibility.ID() || !slices.Equal(live.Missing, replayed.Missing) {
+				t.Fatalf("replay explains differently:\n%v\nlive:\n%v", gotErr, wantErr)
+			}
+		}
+		if got.Fingerprint() != want.Fingerprint() {
+			t.Fatalf("replay solved %v, live solved %v", got, want)
+		}
+	}
+}
@@ -34,7 +34,16 @@ type RootSource []Term
 
 // GetVersions returns a single version for the root package only.
 func (s RootSource) GetVersions(name Name) ([]Version, error) {
-	rootName := MakeName("$$root")
+	return s.versions(MakeName("$$root"), name)
+}
+
+// GetDependencies returns the user's initial requirements for the root package.
+func (s RootSource) GetDependencies(name Name, version Version) ([]Term, error) {
+	return s.dependencies(MakeName("$$root"), name, version)
+}
+
+// versions answers GetVersions for a root package called rootName.
+func (s RootSource) versions(rootName, name Name) ([]Version, error) {
 	if name != rootName {
 		return nil, &PackageNotFoundError{Package: name}
 	}
@@ -42,9 +51,8 @@ func (s RootSource) GetVersions(name Name) ([]Version, error) {
 	return []Version{SimpleVersion("1")}, nil
 }
 
-// GetDependencies returns the user's initial requirements for the root package.
-func (s RootSource) GetDependencies(name Name, version Version) ([]Term, error) {
-	rootName := MakeName("$$root")
+// dependencies answers GetDependencies for a root package called rootName.
+func (s RootSource) dependencies(rootName, name Name, version Version) ([]Term, error) {
 	if name != rootName {
 		return nil, &PackageNotFoundError{Package: name}
 	}
@@ -62,6 +70,19 @@ func (s *RootSource) AddPackage(name Name, condition Condition) {
 	*s = append(*s, NewTerm(name, condition))
 }
 
+// Forbid adds a requirement that no version of name is part of the
+// solution. Packages that can only be satisfied by installing name then fail
+// to resolve, and the report names the ban. To forbid only some versions,
+// append NewNegativeTerm(name, condition) instead.
+//
+// Example:
+//
+//	root.AddPackage(MakeName("rails"), NewVersionSetCondition(FullVersionSet()))
+//	root.Forbid(MakeName("mysql2"))
+func (s *RootSource) Forbid(name Name) {
+	*s = append(*s, NewNegativeTerm(name, NewVersionSetCondition(FullVersionSet())))
+}
+
 // Term returns the term representing the root package itself.
 // This is the starting term passed to Solver.Solve().
 func (s *RootSource) Term() Term {
@@ -73,6 +94,50 @@ func NewRootSource() *RootSource {
 	return &RootSource{}
 }
 
+// NamedRootSource is a RootSource whose virtual package carries the
+// embedding tool's project name instead of "$$root", so logs, traces and
+// error messages read "myapp 1 depends on ..." rather than exposing the
+// placeholder. Requirements are added through the embedded RootSource.
+//
+// Example:
+//
+//	root := NewRootSourceNamed(MakeName("myapp"))
+//	root.AddPackage(MakeName("lodash"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+//	solver := NewSolver(root, registry)
+//	solution, _ := solver.Solve(root.Term())
+type NamedRootSource struct {
+	RootSource
+	name Name
+}
+
+// NewRootSourceNamed creates a new empty root source whose package is called
+// name. The name must not collide with a package any other source provides.
+func NewRootSourceNamed(name Name) *NamedRootSource {
+	return &NamedRootSource{name: name}
+}
+
+// Name returns the name of the root package.
+func (s *NamedRootSource) Name() Name {
+	return s.name
+}
+
+// GetVersions returns a single version for the root package only.
+func (s *NamedRootSource) GetVersions(name Name) ([]Version, error) {
+	return s.versions(s.name, name)
+}
+
+// GetDependencies returns the user's initial requirements for the root package.
+func (s *NamedRootSource) GetDependencies(name Name, version Version) ([]Term, error) {
+	return s.dependencies(s.name, name, version)
+}
+
+// Term returns the term representing the root package itself.
+// This is the starting term passed to Solver.Solve().
+func (s *NamedRootSource) Term() Term {
+	return NewTerm(s.name, EqualsCondition{SimpleVersion("1")})
+}
+
 var (
 	_ Source = &RootSource{}
+	_ Source = &NamedRootSource{}
 )
@@ -0,0 +1,224 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// ValidationMode controls how ValidatingSource treats invalid metadata.
+type ValidationMode int
+
+const (
+	// ValidationReject returns an InvalidMetadataError for any problem found.
+	ValidationReject ValidationMode = iota
+	// ValidationFix repairs problems that have an unambiguous fix (dropping
+	// tautological self-dependencies, merging compatible duplicates, sorting
+	// version lists) and rejects the rest.
+	ValidationFix
+)
+
+// InvalidMetadataError reports package metadata that failed validation.
+type InvalidMetadataError struct {
+	Package Name
+	// Version is nil for problems with the version list itself.
+	Version Version
+	// Dependency is the offending term, if the problem concerns one.
+	Dependency *Term
+	Reason     string
+}
+
+// Error implements the error interface.
+func (e *InvalidMetadataError) Error() string {
+	subject := FormatName(e.Package)
+	if e.Version != nil {
+		subject = fmt.Sprintf("%s %s", subject, e.Version)
+	}
+	if e.Dependency != nil {
+		return fmt.Sprintf("invalid dependency %s of %s: %s", e.Dependency, subject, e.Reason)
+	}
+	return fmt.Sprintf("invalid metadata for %s: %s", subject, e.Reason)
+}
+
+// ValidatingSource wraps a Source and checks the metadata it returns before
+// the solver sees it, protecting resolution from malformed registry data.
+//
+// Checks performed:
+//   - version lists contain no nil entries and are sorted without duplicates
+//   - dependencies have non-empty names
+//   - dependency conditions can be converted to version sets
+//   - packages do not depend on themselves
+//   - repeated dependencies on one package are not contradictory
+//
+// Example:
+//
+//	source := NewValidatingSource(registry, ValidationFix)
+//	solver := NewSolver(root, source)
+type ValidatingSource struct {
+	source Source
+	mode   ValidationMode
+}
+
+// NewValidatingSource creates a validating wrapper around source.
+func NewValidatingSource(source Source, mode ValidationMode) *ValidatingSource {
+	return &ValidatingSource{source: source, mode: mode}
+}
+
+// GetVersions returns the validated version list for a package.
+func (v *ValidatingSource) GetVersions(name Name) ([]Version, error) {
+	return v.GetVersionsCtx(context.Background(), name)
+}
+
+// GetVersionsCtx implements ContextSource, passing ctx to the wrapped source.
+func (v *ValidatingSource) GetVersionsCtx(ctx context.Context, name Name) ([]Version, error) {
+	versions, err := getVersionsCtx(ctx, v.source, name)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ver := range versions {
+		if ver == nil {
+			return nil, &InvalidMetadataError{Package: name, Reason: "version list contains nil"}
+		}
+	}
+
+	ordered := slices.IsSortedFunc(versions, func(a, b Version) int { return a.Sort(b) })
+	unique := true
+	for i := 1; i < len(versions) && ordered; i++ {
+		if versions[i-1].Sort(versions[i]) == 0 {
+			unique = false
+			break
+		}
+	}
+	if ordered && unique {
+		return versions, nil
+	}
+	if v.mode != ValidationFix {
+		reason := "version list is not sorted"
+		if ordered {
+			reason = "version list contains duplicates"
+		}
+		return nil, &InvalidMetadataError{Package: name, Reason: reason}
+	}
+
+	fixed := slices.Clone(versions)
+	slices.SortFunc(fixed, func(a, b Version) int { return a.Sort(b) })
+	fixed = slices.CompactFunc(fixed, func(a, b Version) bool { return a.Sort(b) == 0 })
+	return fixed, nil
+}
+
+// GetDependencies returns the validated dependencies of a package version.
+func (v *ValidatingSource) GetDependencies(name Name, version Version) ([]Term, error) {
+	return v.GetDependenciesCtx(context.Background(), name, version)
+}
+
+// GetDependenciesCtx implements ContextSource, passing ctx to the wrapped
+// source.
+func (v *ValidatingSource) GetDependenciesCtx(ctx context.Context, name Name, version Version) ([]Term, error) {
+	deps, err := getDependenciesCtx(ctx, v.source, name, version)
+	if err != nil {
+		return nil, err
+	}
+	return normalizeDependencies(name, version, deps, v.mode == ValidationFix)
+}
+
+// normalizeDependencies validates a dependency list. When fix is true, self
+// dependencies satisfied by version are dropped and compatible duplicates are
+// merged into a single term; every other problem is returned as an
+// *InvalidMetadataError.
+func normalizeDependencies(name Name, version Version, deps []Term, fix bool) ([]Term, error) {
+	invalid := func(dep Term, reason string) error {
+		return &InvalidMetadataError{Package: name, Version: version, Dependency: &dep, Reason: reason}
+	}
+
+	result := make([]Term, 0, len(deps))
+	index := make(map[Name]int, len(deps))
+
+	for _, dep := range deps {
+		if dep.Name == EmptyName() {
+			if fix {
+				continue
+			}
+			return nil, invalid(dep, "dependency name is empty")
+		}
+
+		if dep.Name == name {
+			if !dep.SatisfiedBy(version) {
+				return nil, invalid(dep, "package depends on itself at a version it does not satisfy")
+			}
+			if fix {
+				continue
+			}
+			return nil, invalid(dep, "package depends on itself")
+		}
+
+		if _, ok := dependencySet(dep); !ok {
+			return nil, invalid(dep, "condition cannot be converted to a version set")
+		}
+
+		i, seen := index[dep.Name]
+		if !seen {
+			index[dep.Name] = len(result)
+			result = append(result, dep)
+			continue
+		}
+
+		merged := combineDependencies(result[i], dep)
+		if merged.Positive {
+			if set, _ := termAllowedSet(merged); set.IsEmpty() {
+				return nil, invalid(dep, fmt.Sprintf("contradicts %s", result[i]))
+			}
+		}
+		if !fix {
+			// Compatible duplicates are harmless; keep them as declared.
+			result = append(result, dep)
+			continue
+		}
+		result[i] = merged
+	}
+
+	return result, nil
+}
+
+// combineDependencies merges two convertible dependency terms on the same
+// package. Mixed polarities combine into the positive term minus the excluded
+// versions.
+func combineDependencies(a, b Term) Term {
+	if merged, ok := mergeTerms(a, b); ok {
+		return merged
+	}
+	if !a.Positive {
+		a, b = b, a
+	}
+	allowed, _ := termAllowedSet(a)
+	forbidden, _ := termForbiddenSet(b)
+	return termFromAllowedSet(a.Name, allowed.Intersection(forbidden.Complement()))
+}
+
+// dependencySet converts a dependency term's condition to a version set,
+// regardless of polarity.
+func dependencySet(dep Term) (VersionSet, bool) {
+	if dep.Positive {
+		return termAllowedSet(dep)
+	}
+	return termForbiddenSet(dep)
+}
+
+var (
+	_ Source = (*ValidatingSource)(nil)
+	_ error  = (*InvalidMetadataError)(nil)
+)
@@ -0,0 +1,89 @@
+package pubgrub
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidatingSourceRejectsInvalidDependencies(t *testing.T) {
+	gt2, _ := ParseVersionRange(">=2.0.0")
+	lt1, _ := ParseVersionRange("<1.0.0")
+
+	cases := []struct {
+		name   string
+		deps   []Term
+		reason string
+	}{
+		{"empty name", []Term{NewTerm(EmptyName(), nil)}, "name is empty"},
+		{"self dependency", []Term{NewTerm(MakeName("app"), EqualsCondition{Version: SimpleVersion("1.0.0")})}, "depends on itself"},
+		{"contradictory duplicates", []Term{
+			NewTerm(MakeName("lib"), NewVersionSetCondition(gt2)),
+			NewTerm(MakeName("lib"), NewVersionSetCondition(lt1)),
+		}, "contradicts"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := &InMemorySource{}
+			source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), tc.deps)
+
+			validating := NewValidatingSource(source, ValidationReject)
+			_, err := validating.GetDependencies(MakeName("app"), SimpleVersion("1.0.0"))
+
+			var metaErr *InvalidMetadataError
+			if !errors.As(err, &metaErr) {
+				t.Fatalf("expected InvalidMetadataError, got %v", err)
+			}
+			if !strings.Contains(metaErr.Error(), tc.reason) {
+				t.Fatalf("expected reason %q, got %q", tc.reason, metaErr.Error())
+			}
+		})
+	}
+}
+
+func TestValidatingSourceFixesRepairableDependencies(t *testing.T) {
+	gt1, _ := ParseVersionRange(">=1.0.0")
+	lt2, _ := ParseVersionRange("<2.0.0")
+
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("app"), EqualsCondition{Version: SimpleVersion("1.0.0")}),
+		NewTerm(MakeName("lib"), NewVersionSetCondition(gt1)),
+		NewTerm(MakeName("lib"), NewVersionSetCondition(lt2)),
+	})
+
+	validating := NewValidatingSource(source, ValidationFix)
+	deps, err := validating.GetDependencies(MakeName("app"), SimpleVersion("1.0.0"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(deps) != 1 {
+		t.Fatalf("expected a single merged dependency, got %v", deps)
+	}
+	if got := deps[0].String(); got != "lib >=1.0.0, <2.0.0" {
+		t.Fatalf("unexpected merged dependency %q", got)
+	}
+}
+
+type unsortedSource struct{ InMemorySource }
+
+func (s *unsortedSource) GetVersions(Name) ([]Version, error) {
+	return []Version{SimpleVersion("2.0.0"), SimpleVersion("1.0.0"), SimpleVersion("2.0.0")}, nil
+}
+
+func TestValidatingSourceVersionList(t *testing.T) {
+	source := &unsortedSource{}
+
+	if _, err := NewValidatingSource(source, ValidationReject).GetVersions(MakeName("x")); err == nil {
+		t.Fatal("expected unsorted version list to be rejected")
+	}
+
+	versions, err := NewValidatingSource(source, ValidationFix).GetVersions(MakeName("x"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(versions) != 2 || versions[0].String() != "1.0.0" || versions[1].String() != "2.0.0" {
+		t.Fatalf("expected sorted, deduplicated versions, got %v", versions)
+	}
+}
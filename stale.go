@@ -0,0 +1,189 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import "fmt"
+
+// StaleKind classifies a stale dependency constraint.
+type StaleKind int
+
+const (
+	// StaleUnsatisfiable marks a constraint that no published version of the
+	// dependency satisfies, so any version declaring it can never be used.
+	StaleUnsatisfiable StaleKind = iota
+	// StaleVacuousBound marks an upper bound so far above every published
+	// version that it cannot be doing its job, typically a typo such as
+	// "<30.0.0" for "<3.0.0". Only SemanticVersions are checked: a bound more
+	// than one major release above the newest version is flagged.
+	StaleVacuousBound
+)
+
+// StaleConstraint is a dependency constraint flagged by FindStaleConstraints.
+type StaleConstraint struct {
+	// Dependent declared the constraint; it is the root package for root
+	// requirements, with a nil Version.
+	Dependent  Name
+	Version    Version
+	Dependency Term
+	Kind       StaleKind
+}
+
+// String describes the finding.
+func (c StaleConstraint) String() string {
+	declarer := "root"
+	if c.Dependent != MakeName("$$root") {
+		declarer = fmt.Sprintf("%s %s", FormatName(c.Dependent), c.Version)
+	}
+	switch c.Kind {
+	case StaleUnsatisfiable:
+		return fmt.Sprintf("%s requires %s, which matches no published version", declarer, c.Dependency)
+	default:
+		return fmt.Sprintf("%s requires %s, whose upper bound is far above every published version", declarer, c.Dependency)
+	}
+}
+
+// FindStaleConstraints walks every published version of every package
+// reachable from root and flags dependency constraints that cannot matter:
+// ranges containing no published version and upper bounds far above any
+// published version. These are metadata bugs that otherwise surface as
+// confusing resolution results. Missing packages are skipped, and the walk
+// is bounded like DependencyComponents.
+//
+// Example:
+//
+//	stale, err := FindStaleConstraints(*root, registry)
+//	for _, c := range stale {
+//	    log.Println(c)
+//	}
+func FindStaleConstraints(root RootSource, source Source) ([]StaleConstraint, error) {
+	published := make(map[Name][]Version)
+	versionsOf := func(name Name) ([]Version, bool, error) {
+		if versions, ok := published[name]; ok {
+			return versions, versions != nil, nil
+		}
+		versions, err := source.GetVersions(name)
+		if err != nil {
+			if isMissingPackage(err) {
+				published[name] = nil
+				return nil, false, nil
+			}
+			return nil, false, err
+		}
+		if versions == nil {
+			versions = []Version{}
+		}
+		published[name] = versions
+		return versions, true, nil
+	}
+
+	var stale []StaleConstraint
+	check := func(dependent Name, version Version, deps []Term) error {
+		for _, dep := range deps {
+			if !dep.Positive {
+				continue
+			}
+			versions, ok, err := versionsOf(dep.Name)
+			if err != nil {
+				return err
+			}
+			if !ok || len(versions) == 0 {
+				continue
+			}
+			finding := StaleConstraint{Dependent: dependent, Version: version, Dependency: dep}
+			switch {
+			case !anySatisfies(dep, versions):
+				finding.Kind = StaleUnsatisfiable
+			case vacuousUpperBound(dep, versions[len(versions)-1]):
+				finding.Kind = StaleVacuousBound
+			default:
+				continue
+			}
+			stale = append(stale, finding)
+		}
+		return nil
+	}
+
+	if err := check(MakeName("$$root"), nil, root); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[Name]bool)
+	queue := make([]Name, 0, len(root))
+	for _, req := range root {
+		queue = append(queue, req.Name)
+	}
+	for len(queue) > 0 && len(seen) <= maxComponentScan {
+		name := queue[0]
+		queue = queue[1:]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		versions, _, err := versionsOf(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, ver := range versions {
+			deps, err := source.GetDependencies(name, ver)
+			if err != nil {
+				if isMissingPackage(err) {
+					continue
+				}
+				return nil, err
+			}
+			if err := check(name, ver, deps); err != nil {
+				return nil, err
+			}
+			for _, dep := range deps {
+				queue = append(queue, dep.Name)
+			}
+		}
+	}
+	return stale, nil
+}
+
+// anySatisfies reports whether a published version satisfies dep.
+func anySatisfies(dep Term, versions []Version) bool {
+	for _, ver := range versions {
+		if dep.SatisfiedBy(ver) {
+			return true
+		}
+	}
+	return false
+}
+
+// vacuousUpperBound reports whether dep has a finite upper bound more than
+// one major release above newest.
+func vacuousUpperBound(dep Term, newest Version) bool {
+	latest, ok := newest.(*SemanticVersion)
+	if !ok {
+		return false
+	}
+	set, ok := dependencySet(dep)
+	if !ok {
+		return false
+	}
+	intervals, ok := set.(*VersionIntervalSet)
+	if !ok || len(intervals.intervals) == 0 {
+		return false
+	}
+	upper := intervals.intervals[len(intervals.intervals)-1].upper
+	if !upper.isFinite() {
+		return false
+	}
+	bound, ok := upper.version.(*SemanticVersion)
+	return ok && bound.Major > latest.Major+1
+}
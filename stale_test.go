@@ -0,0 +1,41 @@
+package pubgrub
+
+import "testing"
+
+func TestFindStaleConstraints(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("lib"), NewSemanticVersion(1, 0, 0), nil)
+	source.AddPackage(MakeName("lib"), NewSemanticVersion(2, 1, 0), nil)
+	source.AddPackage(MakeName("app"), NewSemanticVersion(1, 0, 0), []Term{
+		NewTerm(MakeName("lib"), NewVersionSetCondition(mustParseVersionRange(t, ">=1.0.0, <30.0.0"))),
+		NewTerm(MakeName("helper"), NewVersionSetCondition(mustParseVersionRange(t, ">=5.0.0"))),
+	})
+	source.AddPackage(MakeName("helper"), NewSemanticVersion(1, 0, 0), []Term{
+		NewTerm(MakeName("lib"), NewVersionSetCondition(mustParseVersionRange(t, ">=1.0.0, <3.0.0"))),
+	})
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), NewVersionSetCondition(FullVersionSet()))
+	root.AddPackage(MakeName("helper"), NewVersionSetCondition(FullVersionSet()))
+
+	stale, err := FindStaleConstraints(*root, source)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stale) != 2 {
+		t.Fatalf("expected two findings, got %v", stale)
+	}
+	byDep := map[Name]StaleConstraint{}
+	for _, c := range stale {
+		if c.Dependent != MakeName("app") {
+			t.Fatalf("unexpected dependent in %v", c)
+		}
+		byDep[c.Dependency.Name] = c
+	}
+	if byDep[MakeName("lib")].Kind != StaleVacuousBound {
+		t.Fatalf("expected vacuous bound on lib, got %v", stale)
+	}
+	if byDep[MakeName("helper")].Kind != StaleUnsatisfiable {
+		t.Fatalf("expected unsatisfiable helper constraint, got %v", stale)
+	}
+}
@@ -15,7 +15,14 @@
 
 package pubgrub
 
-import "errors"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
 
 // solverState maintains all mutable state during CDCL-based dependency resolution.
 // It coordinates between:
@@ -32,31 +39,103 @@ import "errors"
 //  6. Backtrack (undo decisions to earlier state)
 type solverState struct {
 	source            Source                      // Package version and dependency source
+	view              *combinedView               // Unguarded CombinedSource view, if any
+	frozen            map[*Incompatibility]bool   // Pins added by WithFrozen
+	replaced          map[string]bool             // Redirected requirements already warned about
+	dropped           map[string]bool             // Best-effort and optional requirements already warned about
+	optional          map[string]Name             // Optional dependencies registered, see OptionalCondition
+	skipOptional      map[string]bool             // Optional dependencies left out of this solve
+	tagged            []TaggedSource              // Sources resolving TagConditions
+	tags              map[string]Version          // Resolved tags: "name@tag" -> version
 	options           SolverOptions               // Solver configuration
+	ctx               context.Context             // Context passed to ContextSources
 	partial           *partialSolution            // Current partial solution
 	incompatibilities map[Name][]*Incompatibility // Incompatibilities indexed by package
 	learned           []*Incompatibility          // Learned incompatibilities (for error reporting)
 	queue             []Name                      // Unit propagation queue
 	queued            map[Name]bool               // Tracks which packages are queued
 
-	depScoreCache       map[string]int // Memoized dependency scores: "name@version" -> score
-	depScoreCacheHits   int            // Number of cache hits
-	depScoreCacheMisses int            // Number of cache misses
-	depScoreAPICalls    int            // Number of source.GetDependencies calls
+	depScoreCache       map[string]int                  // Memoized dependency scores: "name@version" -> score
+	depScoreCacheHits   int                             // Number of cache hits
+	depScoreCacheMisses int                             // Number of cache misses
+	depScoreAPICalls    int                             // Number of source.GetDependencies calls
+	signatures          map[string]string               // Memoized dependency signatures for version bucketing
+	depLists            map[string][]Term               // Memoized dependency lists for TieBreakFewerDependencies
+	registered          map[string]registration         // Dependency clauses per "name@version", see registerDependencies
+	reregistrations     int                             // Decisions that reused a registration
+	fetched             map[string]fetchedDependencies  // GetDependencies answers per "name@version"
+	fetchHits           int                             // Dependency lookups answered by fetched
+	journal             []DependencyFetch               // GetDependencies calls in order
+	evalCache           map[evalCacheKey]evalCacheEntry // Memoized incompatibility evaluations
+	evalCacheHits       int                             // Number of evaluation cache hits
+	evalCacheMisses     int                             // Number of evaluation cache misses
+	timeline            []timelineEvent                 // Assignment history, when RecordTimeline is set
+	warnings            []Warning                       // Warnings raised so far
+	referenced          map[Name]bool                   // Packages checked for published versions
+	missing             map[Name]error                  // Packages the source reported as not found
+	sourceFailures      []SourceFailure                 // Source errors set aside, when AggregateSourceErrors is set
+	journalOut          *json.Encoder                   // Decision journal encoder, when DecisionJournal is set
+	journalErr          error                           // First decision journal write error
+	pendingConflict     *Incompatibility                // Main loop conflict, kept across step budgets
+	propagateSeed       Name                            // Main loop propagation seed, kept across step budgets
+	listed              map[Name]bool                   // Packages whose versions have been listed
+	listedVersions      int                             // Total versions returned by those listings
+	clock               phaseClock                      // Per-phase timings
+
+	steps          int // Main loop iterations
+	decisions      int // Version selections
+	derivations    int // Assignments derived by unit propagation
+	conflicts      int // Conflicts handed to conflict resolution
+	backtracks     int // Backjumps performed
+	learnedClauses int // Incompatibilities learned from conflicts
 }
 
 // newSolverState creates a new solver state for the given source and root package.
+// The source is wrapped according to options: CombinedSources report versions
+// published by several sources, and the consistency guard is applied last.
 func newSolverState(source Source, options SolverOptions, root Name) *solverState {
-	return &solverState{
-		source:            source,
+	st := &solverState{
 		options:           options,
+		ctx:               context.Background(),
+		clock:             phaseClock{budget: options.PackageTimeout},
 		partial:           newPartialSolution(root),
 		incompatibilities: make(map[Name][]*Incompatibility),
 		learned:           make([]*Incompatibility, 0),
 		queue:             make([]Name, 0),
 		queued:            make(map[Name]bool),
 		depScoreCache:     make(map[string]int),
+		propagateSeed:     EmptyName(),
+	}
+	st.tagged = taggedSources(source)
+	if combined, ok := source.(CombinedSource); ok {
+		source = newCombinedView(combined, options.SourcePrecedence, options.StrictSources, st.warn)
+	}
+	if view, ok := source.(*combinedView); ok {
+		st.view = view
+	}
+	st.source = guardSource(source, options.Consistency)
+
+	// Policy rules are known up front; they are not learned clauses.
+	for _, policy := range options.Policies {
+		for _, inc := range policy.incompatibilities() {
+			for _, term := range inc.Terms {
+				st.incompatibilities[term.Name] = append(st.incompatibilities[term.Name], inc)
+			}
+		}
+	}
+	for _, inc := range installedIncompatibilities(options, root) {
+		for _, term := range inc.Terms {
+			st.incompatibilities[term.Name] = append(st.incompatibilities[term.Name], inc)
+		}
+	}
+	for _, inc := range frozenIncompatibilities(options) {
+		st.incompatibilities[inc.Package] = append(st.incompatibilities[inc.Package], inc)
+		if st.frozen == nil {
+			st.frozen = make(map[*Incompatibility]bool)
+		}
+		st.frozen[inc] = true
 	}
+	return st
 }
 
 // enqueue adds a package to the unit propagation queue if not already queued.
@@ -68,13 +147,35 @@ func (st *solverState) enqueue(name Name) {
 	st.queued[name] = true
 }
 
-// dequeue removes and returns the next package from the propagation queue.
+// dequeue removes and returns the next package from the propagation queue,
+// following the configured PropagationOrder.
 func (st *solverState) dequeue() (Name, bool) {
 	if len(st.queue) == 0 {
 		return EmptyName(), false
 	}
-	name := st.queue[0]
-	st.queue = st.queue[1:]
+
+	var name Name
+	switch st.options.PropagationOrder {
+	case PropagationLIFO:
+		name = st.queue[len(st.queue)-1]
+		st.queue = st.queue[:len(st.queue)-1]
+	case PropagationMostConstrained:
+		best, bestScore := 0, st.partial.constraintScore(st.queue[0])
+		for i := 1; i < len(st.queue); i++ {
+			if score := st.partial.constraintScore(st.queue[i]); score < bestScore {
+				best, bestScore = i, score
+			}
+		}
+		name = st.queue[best]
+		st.queue = slices.Delete(st.queue, best, best+1)
+	default:
+		name = st.queue[0]
+		st.queue = st.queue[1:]
+	}
+	if len(st.queue) == 0 {
+		// Rewind so the next propagation reuses the backing array.
+		st.queue = st.queue[:0:cap(st.queue)]
+	}
 	delete(st.queued, name)
 	return name, true
 }
@@ -103,12 +204,14 @@ func (st *solverState) debug(msg string, args ...any) {
 }
 
 func (st *solverState) traceAssignment(event string, assign *assignment) {
+	st.recordTimeline(assign)
+	st.journalAssignment(assign)
 	if st.options.Logger == nil || assign == nil {
 		return
 	}
 	st.options.Logger.Debug("assignment",
 		"event", event,
-		"package", assign.name.Value(),
+		"package", FormatName(assign.name),
 		"detail", assign.describe(),
 	)
 }
@@ -142,7 +245,7 @@ func (st *solverState) propagate(start Name) (*Incompatibility, error) {
 			switch relation {
 			case relationSatisfied:
 				st.debug("conflict detected during propagation",
-					"package", pkg.Value(),
+					"package", FormatName(pkg),
 					"incompatibility", inc.String(),
 				)
 				return inc, nil
@@ -152,7 +255,7 @@ func (st *solverState) propagate(start Name) (*Incompatibility, error) {
 				}
 				derived := unsatisfied.Negate()
 				st.debug("unit propagation",
-					"package", pkg.Value(),
+					"package", FormatName(pkg),
 					"incompatibility", inc.String(),
 					"derived_term", derived.String(),
 				)
@@ -164,12 +267,13 @@ func (st *solverState) propagate(start Name) (*Incompatibility, error) {
 					return nil, err
 				}
 				if assign != nil {
+					st.derivations++
 					st.traceAssignment("derivation", assign)
 					st.markAssigned(assign.name)
 				}
 				if changed && assign != nil {
 					st.debug("enqueueing package after derivation",
-						"package", assign.name.Value(),
+						"package", FormatName(assign.name),
 						"term", assign.term.String(),
 					)
 					st.enqueue(assign.name)
@@ -193,11 +297,19 @@ const (
 // evaluateIncompatibility determines the relationship between an incompatibility
 // and the current partial solution.
 func (st *solverState) evaluateIncompatibility(inc *Incompatibility) (incompatibilityRelation, *Term, error) {
+	if st.options.CacheEvaluations {
+		return st.cachedEvaluation(inc)
+	}
+	return st.evaluateTerms(inc)
+}
+
+// evaluateTerms computes the relation of each term of inc to the partial solution.
+func (st *solverState) evaluateTerms(inc *Incompatibility) (incompatibilityRelation, *Term, error) {
 	var unsatisfied *Term
 
 	for _, term := range inc.Terms {
 		allowed := st.partial.allowedSet(term.Name)
-		rel, err := relationForTerm(term, allowed, st.partial.hasAssignments(term.Name))
+		rel, err := relationForTerm(term, allowed, st.partial.isRequired(term.Name))
 		if err != nil {
 			return relationInconclusive, nil, err
 		}
@@ -223,24 +335,33 @@ func (st *solverState) evaluateIncompatibility(inc *Incompatibility) (incompatib
 }
 
 // relationForTerm determines the relationship between a single term and the
-// current allowed version set for its package.
-func relationForTerm(term Term, allowed VersionSet, hasAssignment bool) (incompatibilityRelation, error) {
+// current allowed version set for its package. required reports whether the
+// package must be selected at all; a package constrained only by negative
+// terms may still be absent, which satisfies every negative term and no
+// positive one.
+func relationForTerm(term Term, allowed VersionSet, required bool) (incompatibilityRelation, error) {
 	if allowed == nil {
-		allowed = FullVersionSet()
+		allowed = fullVersionSet
+	}
+
+	// Exact-version terms dominate propagation (decisions and dependency
+	// clauses); answer them without materializing a singleton set.
+	if ver, ok := termVersion(term); ok {
+		return relationForVersion(term.Positive, ver, allowed, required), nil
 	}
 
 	if term.Positive {
-		required, ok := termAllowedSet(term)
+		set, ok := termAllowedSet(term)
 		if !ok {
 			return relationInconclusive, nil
 		}
-		if allowed.IsSubset(required) {
-			if hasAssignment {
+		if allowed.IsSubset(set) {
+			if required {
 				return relationSatisfied, nil
 			}
 			return relationInconclusive, nil
 		}
-		if allowed.IsDisjoint(required) {
+		if allowed.IsDisjoint(set) {
 			return relationContradicted, nil
 		}
 		return relationInconclusive, nil
@@ -255,7 +376,7 @@ func relationForTerm(term Term, allowed VersionSet, hasAssignment bool) (incompa
 		return relationSatisfied, nil
 	}
 	if allowed.IsSubset(forbidden) {
-		if hasAssignment {
+		if required {
 			return relationContradicted, nil
 		}
 		return relationInconclusive, nil
@@ -263,6 +384,41 @@ func relationForTerm(term Term, allowed VersionSet, hasAssignment bool) (incompa
 	return relationInconclusive, nil
 }
 
+// relationForVersion is relationForTerm specialized to a term matching the
+// single version ver.
+func relationForVersion(positive bool, ver Version, allowed VersionSet, required bool) incompatibilityRelation {
+	// allowed is a subset of {ver} exactly when it is empty or {ver}.
+	subset := allowed.IsEmpty()
+	if !subset {
+		single, ok := singletonVersionFromSet(allowed)
+		subset = ok && single.Sort(ver) == 0
+	}
+
+	if positive {
+		if subset {
+			if required {
+				return relationSatisfied
+			}
+			return relationInconclusive
+		}
+		if !allowed.Contains(ver) {
+			return relationContradicted
+		}
+		return relationInconclusive
+	}
+
+	if !allowed.Contains(ver) {
+		return relationSatisfied
+	}
+	if subset {
+		if required {
+			return relationContradicted
+		}
+		return relationInconclusive
+	}
+	return relationInconclusive
+}
+
 // resolveIncompatibility performs conflict resolution by merging two incompatibilities.
 // This is the core of CDCL's learned clause generation.
 //
@@ -356,11 +512,65 @@ func mergeTerms(a, b Term) (Term, bool) {
 
 // registerDependencies adds incompatibilities for a package version's dependencies.
 // Returns a conflict incompatibility if constraint application fails.
+//
+// Self-dependencies are handled before registration: one satisfied by version
+// is a tautology and dropped, any other makes the version unusable and is
+// reported as a KindSelfDependency conflict. Repeated dependencies on the same
+// package are merged into a single constraint.
+//
+// Registrations are memoized per package version: a decision repeated after
+// backtracking reapplies the existing clauses rather than adding duplicates.
 func (st *solverState) registerDependencies(pkg Name, version Version, deps []Term) (*Incompatibility, error) {
+	key := dependencyScoreKey(pkg, version)
+	if reg, ok := st.registered[key]; ok {
+		st.reregistrations++
+		return st.reapplyDependencies(reg)
+	}
+
+	deps, conflict := st.normalizeDependencies(pkg, version, deps)
+	if conflict != nil {
+		st.addIncompatibility(conflict)
+		st.remember(key, registration{conflict: conflict})
+		return conflict, nil
+	}
+
+	reg := registration{deps: deps, incomps: make([]*Incompatibility, 0, len(deps))}
 	for _, dep := range deps {
-		incomp := NewIncompatibilityFromDependency(pkg, version, dep)
+		if unpublished := st.unpublished(pkg, dep); unpublished != nil {
+			st.addIncompatibility(unpublished)
+		}
+		incomp := st.dependencyIncompatibility(pkg, version, dep)
 		st.addIncompatibility(incomp)
-		conflict, err := st.applyConstraint(dep, incomp)
+		reg.incomps = append(reg.incomps, incomp)
+	}
+	st.remember(key, reg)
+	return st.reapplyDependencies(reg)
+}
+
+// registration records the clauses created for one package version, so a
+// decision repeated after backtracking reuses them instead of adding
+// identical incompatibilities.
+type registration struct {
+	deps     []Term
+	incomps  []*Incompatibility
+	conflict *Incompatibility
+}
+
+func (st *solverState) remember(key string, reg registration) {
+	if st.registered == nil {
+		st.registered = make(map[string]registration)
+	}
+	st.registered[key] = reg
+}
+
+// reapplyDependencies applies the constraints of a registration to the
+// partial solution.
+func (st *solverState) reapplyDependencies(reg registration) (*Incompatibility, error) {
+	if reg.conflict != nil {
+		return reg.conflict, nil
+	}
+	for i, dep := range reg.deps {
+		conflict, err := st.applyConstraint(dep, reg.incomps[i])
 		if err != nil {
 			return nil, err
 		}
@@ -371,6 +581,167 @@ func (st *solverState) registerDependencies(pkg Name, version Version, deps []Te
 	return nil, nil
 }
 
+// getVersions lists the versions of name. A PackageNotFoundError is
+// remembered for the rest of the solve, so packages referenced from many
+// places are looked up once even when the Source does not cache negative
+// answers (network sources often pay full latency for them).
+func (st *solverState) getVersions(name Name) ([]Version, error) {
+	if err, ok := st.missing[name]; ok {
+		return nil, err
+	}
+	start := time.Now()
+	versions, err := getVersionsCtx(st.ctx, st.source, name)
+	st.clock.sourceCall(name, start)
+	if err == nil && !st.listed[name] {
+		if st.listed == nil {
+			st.listed = make(map[Name]bool)
+		}
+		st.listed[name] = true
+		st.listedVersions += len(versions)
+	}
+	var pkgErr *PackageNotFoundError
+	if err != nil && errors.As(err, &pkgErr) {
+		if st.missing == nil {
+			st.missing = make(map[Name]error)
+		}
+		st.missing[name] = err
+	}
+	return versions, err
+}
+
+// solution builds the solution from the partial solution, naming the source
+// of each version where known and retaining the dependency edges between
+// resolved packages.
+func (st *solverState) solution() Solution {
+	solution := st.partial.buildSolution()
+	resolved := make(map[Name]bool, len(solution))
+	for _, nv := range solution {
+		resolved[nv.Name] = true
+	}
+	// The edges of all packages share one backing array.
+	var edges []Name
+	for i, nv := range solution {
+		if st.view != nil {
+			solution[i].Source = st.view.origin(nv.Name, nv.Version)
+		}
+		solution[i].Metadata = versionMetadata(st.source, nv.Name, nv.Version)
+		start := len(edges)
+		for _, dep := range st.registered[dependencyScoreKey(nv.Name, nv.Version)].deps {
+			if dep.Positive && resolved[dep.Name] && !slices.Contains(edges[start:], dep.Name) {
+				edges = append(edges, dep.Name)
+			}
+		}
+		if len(edges) > start {
+			solution[i].Dependencies = edges[start:len(edges):len(edges)]
+		}
+	}
+	return solution
+}
+
+// stepLimit returns the step limit in force: the StepBudget evaluated on the
+// problem size seen so far, or limit when no budget is configured.
+func (st *solverState) stepLimit(limit int, budget StepBudget) int {
+	if budget == nil {
+		return limit
+	}
+	return budget(len(st.listed), st.listedVersions)
+}
+
+// missingPackages returns the packages recorded by getVersions as not found,
+// sorted by name.
+func (st *solverState) missingPackages() []Name {
+	if st == nil || len(st.missing) == 0 {
+		return nil
+	}
+	names := make([]Name, 0, len(st.missing))
+	for name := range st.missing {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b Name) int { return strings.Compare(a.Value(), b.Value()) })
+	return names
+}
+
+// unpublished checks, the first time a package is referenced, whether the
+// source lists any versions of it. For a package with an empty version list,
+// or one the source does not know (CombinedSource reports an empty listing
+// that way), it returns an incompatibility forbidding the package outright,
+// so the failure names the package and how it was required instead of
+// surfacing later as a vague "no versions satisfy" conflict. Other lookup
+// errors are left for version selection to report, but are recorded right
+// away under AggregateSourceErrors, in case the solve fails before then.
+func (st *solverState) unpublished(pkg Name, dep Term) *Incompatibility {
+	if !dep.Positive || st.referenced[dep.Name] {
+		return nil
+	}
+	if st.referenced == nil {
+		st.referenced = make(map[Name]bool)
+	}
+	st.referenced[dep.Name] = true
+
+	versions, err := st.getVersions(dep.Name)
+	if err != nil {
+		var pkgErr *PackageNotFoundError
+		if !errors.As(err, &pkgErr) {
+			if st.aggregates(err) {
+				st.recordSourceFailure(dep.Name, nil, err)
+			}
+			return nil
+		}
+	} else if len(versions) > 0 {
+		return nil
+	}
+
+	chain := []Name{dep.Name}
+	if pkg != st.partial.root {
+		chain = append(st.requirementChain(pkg), dep.Name)
+	}
+	return NewIncompatibilityUnpublished(dep.Name, chain)
+}
+
+// normalizeDependencies removes tautological self-dependencies and merges
+// duplicate constraints with the same rules as ValidatingSource in
+// ValidationFix mode. It returns a conflict if pkg@version depends on itself
+// at a version it does not satisfy. Other metadata problems leave deps as
+// declared; propagation reaches the same conclusion from them.
+func (st *solverState) normalizeDependencies(pkg Name, version Version, deps []Term) ([]Term, *Incompatibility) {
+	if !needsNormalization(pkg, deps) {
+		return deps, nil
+	}
+
+	result, err := normalizeDependencies(pkg, version, deps, true)
+	var invalid *InvalidMetadataError
+	switch {
+	case err == nil:
+		return result, nil
+	case errors.As(err, &invalid) && invalid.Dependency != nil && invalid.Dependency.Name == pkg:
+		st.debug("self-dependency not satisfied",
+			"package", FormatName(pkg),
+			"version", version,
+			"dependency", invalid.Dependency.String(),
+		)
+		return nil, NewIncompatibilitySelfDependency(pkg, version, *invalid.Dependency)
+	default:
+		st.debug("keeping dependencies as declared", "package", FormatName(pkg), "version", version, "reason", err)
+		return deps, nil
+	}
+}
+
+// needsNormalization reports whether deps contains a self-dependency or a
+// repeated package, keeping the common case free of allocations.
+func needsNormalization(pkg Name, deps []Term) bool {
+	for i, dep := range deps {
+		if dep.Name == pkg {
+			return true
+		}
+		for _, prev := range deps[:i] {
+			if prev.Name == dep.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // applyConstraint applies a dependency constraint to the partial solution.
 // Returns a conflict incompatibility if the constraint cannot be satisfied.
 func (st *solverState) applyConstraint(term Term, cause *Incompatibility) (*Incompatibility, error) {
@@ -418,29 +789,71 @@ const (
 // Selection strategy:
 //  1. Get all available versions from the source
 //  2. Filter to versions matching current constraints
-//  3. Use lookahead heuristic: prefer versions whose dependencies have larger
-//     search spaces (less constrained), falling back to highest version on ties
+//  3. Keep an installed or preferred version that is still allowed
+//  4. Ask the DecisionStrategy, if one is configured
+//  5. Use lookahead heuristic: score the newest candidates with the
+//     ScoreProvider (by default, prefer versions whose dependencies have
+//     larger search spaces) and break ties with the configured TieBreak
 func (st *solverState) pickVersion(name Name) (Version, bool, int, error) {
 	allowed := st.partial.allowedSet(name)
 	if allowed == nil || allowed.IsEmpty() {
 		return nil, false, 0, nil
 	}
 
-	versions, err := st.source.GetVersions(name)
+	versions, err := st.getVersions(name)
 	if err != nil {
 		var pkgErr *PackageNotFoundError
 		var verErr *PackageVersionNotFoundError
 		if errors.As(err, &pkgErr) || errors.As(err, &verErr) {
 			return nil, false, 0, nil
 		}
-		return nil, false, 0, err
+		if st.aggregates(err) {
+			st.recordSourceFailure(name, nil, err)
+			return nil, false, 0, nil
+		}
+		return nil, false, 0, &VersionsError{Package: name, Chain: st.requirementChain(name), Err: err}
+	}
+	allowed = st.candidateSet(allowed)
+	if st.options.Hooks.BeforePick != nil {
+		versions = st.beforePick(name, versions, allowed)
+	}
+	if ver, ok := st.installedPick(name, versions, allowed); ok {
+		return ver, true, versionScoreBaseline, nil
+	}
+	if ver, ok := st.preferredPick(name, versions, allowed); ok {
+		return ver, true, versionScoreBaseline, nil
+	}
+	if ver, ok := st.strategyPick(name, versions, allowed); ok {
+		return ver, true, versionScoreBaseline, nil
 	}
 
-	candidates := make([]Version, 0, maxVersionScoreCandidates)
-	for i := len(versions) - 1; i >= 0 && len(candidates) < maxVersionScoreCandidates; i-- {
-		ver := versions[i]
-		if allowed.Contains(ver) {
-			candidates = append(candidates, ver)
+	switch st.options.VersionStrategy {
+	case VersionNewest:
+		for i := len(versions) - 1; i >= 0; i-- {
+			if allowed.Contains(versions[i]) {
+				return versions[i], true, versionScoreBaseline, nil
+			}
+		}
+		return nil, false, 0, nil
+	case VersionOldest:
+		for _, ver := range versions {
+			if allowed.Contains(ver) {
+				return ver, true, versionScoreBaseline, nil
+			}
+		}
+		return nil, false, 0, nil
+	}
+
+	var candidates []Version
+	if st.options.BucketEquivalentVersions {
+		candidates = st.bucketCandidates(name, versions, allowed)
+	} else {
+		candidates = make([]Version, 0, maxVersionScoreCandidates)
+		for i := len(versions) - 1; i >= 0 && len(candidates) < maxVersionScoreCandidates; i-- {
+			ver := versions[i]
+			if allowed.Contains(ver) {
+				candidates = append(candidates, ver)
+			}
 		}
 	}
 
@@ -459,7 +872,7 @@ func (st *solverState) pickVersion(name Name) (Version, bool, int, error) {
 		case score > bestScore:
 			bestVer = ver
 			bestScore = score
-		case score == bestScore && ver.Sort(bestVer) > 0:
+		case score == bestScore && st.preferOnTie(name, ver, bestVer):
 			bestVer = ver
 			bestScore = score
 		}
@@ -472,12 +885,8 @@ func (st *solverState) pickVersion(name Name) (Version, bool, int, error) {
 	return bestVer, true, bestScore, nil
 }
 
-// scoreVersionByDependencies estimates how "good" a version choice is by
-// analyzing the flexibility of its dependencies. Higher scores indicate
-// dependencies with more available versions (less constrained).
-//
-// This helps avoid picking versions whose dependencies will immediately
-// lead to conflicts or narrow the search space too aggressively.
+// scoreVersionByDependencies rates a candidate version with the configured
+// ScoreProvider, memoizing the score per package version.
 func (st *solverState) scoreVersionByDependencies(name Name, ver Version) int {
 	if st.depScoreCache == nil {
 		st.depScoreCache = make(map[string]int)
@@ -498,46 +907,22 @@ func (st *solverState) scoreVersionByDependencies(name Name, ver Version) int {
 func (st *solverState) computeDependencyScore(name Name, ver Version) int {
 	st.depScoreAPICalls++
 
-	deps, err := st.source.GetDependencies(name, ver)
+	deps, err := st.getDependencies(name, ver)
 	if err != nil {
 		// If we can't fetch dependencies, assign neutral score
 		return versionScoreBaseline
 	}
 
-	totalScore := versionScoreBaseline
-	if len(deps) == 0 {
-		return totalScore
-	}
-
-	for _, dep := range deps {
-		// For each dependency, estimate how many versions it could accept
-		// We'll use a simple heuristic: tighter constraints = lower score
-		depAllowed := st.partial.allowedSet(dep.Name)
-		projected, err := applyTermToAllowed(depAllowed, dep)
-		if err != nil {
-			// If we can't project the constraint, treat it as neutral.
-			totalScore += versionScoreBaseline
-			continue
-		}
-		score := constraintScoreForSet(projected)
-		switch {
-		case score == constraintScoreEmpty:
-			// No versions satisfy this dependency under the current assumptions.
-			return versionScoreConflictPenalty
-		case score >= constraintScoreUnbounded:
-			totalScore += versionScoreUnboundedBonus
-		case score == constraintScoreUnknown:
-			totalScore += versionScoreBaseline
-		default:
-			if adjustment := versionScoreBaseline - score; adjustment > 0 {
-				totalScore += adjustment
-			} else {
-				totalScore++
-			}
-		}
+	provider := st.options.ScoreProvider
+	if provider == nil {
+		provider = DependencyScore
 	}
-
-	return totalScore
+	return provider.Score(ScoreRequest{
+		Package:      name,
+		Version:      ver,
+		Dependencies: deps,
+		Allowed:      st.partial.allowedSet,
+	})
 }
 
 func dependencyScoreKey(name Name, ver Version) string {
@@ -557,12 +942,11 @@ func dependencyScoreKey(name Name, ver Version) string {
 //  4. If satisfier is a derivation, resolve it with its cause and continue
 func (st *solverState) resolveConflict(conflict *Incompatibility) (*Incompatibility, Name, error) {
 	for {
-		satisfier := st.partial.satisfier(conflict)
+		satisfier, prevLevel := st.partial.conflictLevels(conflict)
 		if satisfier == nil {
 			return nil, EmptyName(), NewNoSolutionError(conflict)
 		}
 
-		prevLevel := st.partial.previousDecisionLevel(conflict, satisfier)
 		st.debug("conflict analysis iteration",
 			"conflict", conflict.String(),
 			"satisfier", satisfier.describe(),
@@ -576,9 +960,12 @@ func (st *solverState) resolveConflict(conflict *Incompatibility) (*Incompatibil
 
 		if satisfier.isDecision() && prevLevel < satisfier.decisionLevel {
 			st.partial.backtrack(prevLevel)
+			st.backtracks++
+			st.learnedClauses++
+			st.journalIncompatibility(JournalBacktrack, satisfier.name, prevLevel, conflict)
 			if st.options.Logger != nil {
 				st.options.Logger.Debug("backtracked after conflict",
-					"pivot", satisfier.name.Value(),
+					"pivot", FormatName(satisfier.name),
 					"target_level", prevLevel,
 					"learned", conflict.String(),
 					"state", st.partial.snapshot(),
@@ -593,13 +980,47 @@ func (st *solverState) resolveConflict(conflict *Incompatibility) (*Incompatibil
 		}
 
 		st.debug("resolving with cause",
-			"pivot", satisfier.name.Value(),
+			"pivot", FormatName(satisfier.name),
 			"cause", satisfier.cause.String(),
 		)
 		conflict = resolveIncompatibility(conflict, satisfier.cause, satisfier.name)
 		st.debug("derived new conflict",
-			"pivot", satisfier.name.Value(),
+			"pivot", FormatName(satisfier.name),
 			"conflict", conflict.String(),
 		)
 	}
 }
+
+// requirementChain reconstructs why name is required by following the
+// dependency incompatibilities that derived it back to a root requirement.
+// The chain starts at the root requirement and ends with name.
+func (st *solverState) requirementChain(name Name) []Name {
+	chain := []Name{name}
+	seen := map[Name]bool{name: true}
+
+	for current := name; ; {
+		parent, ok := st.requiredBy(current)
+		if !ok || parent == st.partial.root || seen[parent] {
+			break
+		}
+		seen[parent] = true
+		chain = append(chain, parent)
+		current = parent
+	}
+
+	slices.Reverse(chain)
+	return chain
+}
+
+// requiredBy returns the package whose dependency first required name.
+func (st *solverState) requiredBy(name Name) (Name, bool) {
+	for _, assign := range st.partial.perPackage[name] {
+		if assign.isDecision() || !assign.term.Positive || assign.cause == nil {
+			continue
+		}
+		if cause := assign.cause; cause.Kind == KindFromDependency && cause.Package != name {
+			return cause.Package, true
+		}
+	}
+	return EmptyName(), false
+}
@@ -0,0 +1,188 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import "time"
+
+// SolveStats summarizes the work performed by a single Solve call.
+// Counters are collected unconditionally; they are cheap integer increments
+// on paths that already allocate.
+//
+// Example:
+//
+//	solver := NewSolver(root, source)
+//	_, err := solver.Solve(root.Term())
+//	stats := solver.Stats()
+//	fmt.Printf("%d decisions, %d conflicts\n", stats.Decisions, stats.Conflicts)
+type SolveStats struct {
+	// Steps is the number of main loop iterations consumed.
+	Steps int
+	// Decisions counts version selections made by the solver.
+	Decisions int
+	// Derivations counts assignments derived by unit propagation.
+	Derivations int
+	// Conflicts counts conflicts handed to conflict resolution.
+	Conflicts int
+	// Backtracks counts backjumps performed after learning a clause.
+	Backtracks int
+	// LearnedClauses counts incompatibilities learned from conflicts.
+	LearnedClauses int
+	// ReusedRegistrations counts decisions on a package version whose
+	// dependency clauses were already registered by an earlier decision
+	// undone by backtracking.
+	ReusedRegistrations int
+
+	// DepScoreCacheHits and DepScoreCacheMisses describe the lookahead
+	// cache used when scoring candidate versions.
+	DepScoreCacheHits   int
+	DepScoreCacheMisses int
+	// DepScoreAPICalls counts dependency lookups made for scoring; see
+	// DependencyFetches for the calls that reached the source.
+	DepScoreAPICalls int
+
+	// DependencyFetches counts GetDependencies calls made on the source, at
+	// most one per package version; DependencyFetchHits counts lookups
+	// answered from earlier fetches instead.
+	DependencyFetches   int
+	DependencyFetchHits int
+
+	// EvalCacheHits and EvalCacheMisses describe the incompatibility
+	// evaluation cache enabled by WithEvaluationCache.
+	EvalCacheHits   int
+	EvalCacheMisses int
+
+	// SolveTime is the wall time spent in Solve, including any resumed
+	// runs. PropagationTime, ConflictTime and SelectionTime split the main
+	// loop's share of it between unit propagation, conflict analysis and
+	// version selection; SourceTime is the time spent waiting on the Source,
+	// which is excluded from the other three. A solve dominated by
+	// SourceTime is I/O bound and will gain more from caching than from
+	// solver tuning.
+	SolveTime       time.Duration
+	PropagationTime time.Duration
+	ConflictTime    time.Duration
+	SelectionTime   time.Duration
+	SourceTime      time.Duration
+
+	// Hotspots lists the packages that took the most time, longest first,
+	// at most MaxHotspots of them. It answers "which dependency makes my
+	// resolve slow"; see also WithPackageTimeout.
+	Hotspots []PackageTime
+
+	// Names accounts for the distinct package names the solve held clauses
+	// about, a measure of the memory it kept reachable.
+	Names NameStats
+}
+
+// MaxHotspots is the number of packages SolveStats.Hotspots reports.
+const MaxHotspots = 10
+
+// PackageTime is the time a solve spent on one package.
+type PackageTime struct {
+	Package Name
+	// SolverTime is the time spent selecting versions of the package,
+	// including the dependency registration of each decision, excluding
+	// Source calls.
+	SolverTime time.Duration
+	// SourceTime is the time spent in Source calls about the package:
+	// listing its versions, fetching its dependencies and resolving its
+	// tags.
+	SourceTime time.Duration
+	// SourceCalls counts those calls.
+	SourceCalls int
+}
+
+// Total returns SolverTime plus SourceTime.
+func (p PackageTime) Total() time.Duration {
+	return p.SolverTime + p.SourceTime
+}
+
+// Stats returns statistics for the most recent Solve call.
+func (s *Solver) Stats() SolveStats {
+	return s.stats
+}
+
+// snapshotStats copies the counters accumulated in state.
+func (st *solverState) snapshotStats() SolveStats {
+	return SolveStats{
+		Steps:               st.steps,
+		Decisions:           st.decisions,
+		Derivations:         st.derivations,
+		Conflicts:           st.conflicts,
+		Backtracks:          st.backtracks,
+		LearnedClauses:      st.learnedClauses,
+		ReusedRegistrations: st.reregistrations,
+		DepScoreCacheHits:   st.depScoreCacheHits,
+		DepScoreCacheMisses: st.depScoreCacheMisses,
+		DepScoreAPICalls:    st.depScoreAPICalls,
+		DependencyFetches:   len(st.journal),
+		DependencyFetchHits: st.fetchHits,
+		EvalCacheHits:       st.evalCacheHits,
+		EvalCacheMisses:     st.evalCacheMisses,
+		SolveTime:           st.clock.solveTime,
+		PropagationTime:     st.clock.propagationTime,
+		ConflictTime:        st.clock.conflictTime,
+		SelectionTime:       st.clock.selectionTime,
+		SourceTime:          st.clock.sourceTime,
+		Hotspots:            st.clock.hotspots(),
+		Names:               st.nameStats(),
+	}
+}
+
+// add returns the field-wise sum of two statistics.
+func (s SolveStats) add(other SolveStats) SolveStats {
+	return SolveStats{
+		Steps:               s.Steps + other.Steps,
+		Decisions:           s.Decisions + other.Decisions,
+		Derivations:         s.Derivations + other.Derivations,
+		Conflicts:           s.Conflicts + other.Conflicts,
+		Backtracks:          s.Backtracks + other.Backtracks,
+		LearnedClauses:      s.LearnedClauses + other.LearnedClauses,
+		ReusedRegistrations: s.ReusedRegistrations + other.ReusedRegistrations,
+		DepScoreCacheHits:   s.DepScoreCacheHits + other.DepScoreCacheHits,
+		DepScoreCacheMisses: s.DepScoreCacheMisses + other.DepScoreCacheMisses,
+		DepScoreAPICalls:    s.DepScoreAPICalls + other.DepScoreAPICalls,
+		DependencyFetches:   s.DependencyFetches + other.DependencyFetches,
+		DependencyFetchHits: s.DependencyFetchHits + other.DependencyFetchHits,
+		EvalCacheHits:       s.EvalCacheHits + other.EvalCacheHits,
+		EvalCacheMisses:     s.EvalCacheMisses + other.EvalCacheMisses,
+		SolveTime:           s.SolveTime + other.SolveTime,
+		PropagationTime:     s.PropagationTime + other.PropagationTime,
+		ConflictTime:        s.ConflictTime + other.ConflictTime,
+		SelectionTime:       s.SelectionTime + other.SelectionTime,
+		SourceTime:          s.SourceTime + other.SourceTime,
+		Hotspots:            mergeHotspots(s.Hotspots, other.Hotspots),
+		Names:               maxNameStats(s.Names, other.Names),
+	}
+}
+
+// maxNameStats returns the larger of two name accounts: a retried solve
+// holds the names of each attempt in turn, not both at once.
+func maxNameStats(a, b NameStats) NameStats {
+	if b.Names > a.Names {
+		return b
+	}
+	return a
+}
+
+// EvalCacheHitRate returns the fraction of incompatibility evaluations
+// answered by the evaluation cache, or 0 when it was not used.
+func (s SolveStats) EvalCacheHitRate() float64 {
+	total := s.EvalCacheHits + s.EvalCacheMisses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.EvalCacheHits) / float64(total)
+}
@@ -0,0 +1,145 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TaggedSource is implemented by sources that publish named channels, or
+// dist-tags, such as latest, stable, beta or nightly, each pointing at one
+// version of a package.
+type TaggedSource interface {
+	Source
+	// Tags returns the versions the tags of name point at.
+	Tags(name Name) (map[string]Version, error)
+}
+
+// TagCondition requires the version a tag of the package points at, the way
+// npm and conda users write "foo@beta". The solver resolves it against the
+// TaggedSources among its sources before the requirement is used, so it can
+// appear in root requirements as well as in dependencies.
+//
+// Example:
+//
+//	root.AddPackage(MakeName("react"), TagCondition{Tag: "beta"})
+type TagCondition struct {
+	Tag string
+}
+
+// String returns the condition in "@tag" form.
+func (c TagCondition) String() string {
+	return "@" + c.Tag
+}
+
+// Satisfies reports false: an unresolved tag matches no version.
+func (c TagCondition) Satisfies(Version) bool {
+	return false
+}
+
+// ParseTagRequirement parses a "name@tag" requirement.
+//
+// Example:
+//
+//	term, err := ParseTagRequirement("react@beta")
+func ParseTagRequirement(s string) (Term, error) {
+	name, tag, ok := strings.Cut(s, "@")
+	if !ok || name == "" || tag == "" {
+		return Term{}, fmt.Errorf("invalid tag requirement %q: want name@tag", s)
+	}
+	return NewTerm(MakeName(name), TagCondition{Tag: tag}), nil
+}
+
+// UnknownTagError is returned when no TaggedSource defines a required tag.
+type UnknownTagError struct {
+	Package Name
+	Tag     string
+}
+
+// Error implements the error interface
+func (e *UnknownTagError) Error() string {
+	return fmt.Sprintf("package %s has no tag %q", FormatName(e.Package), e.Tag)
+}
+
+// taggedSources collects the TaggedSources in source, looking inside
+// CombinedSources, in precedence order.
+func taggedSources(source Source) []TaggedSource {
+	switch src := source.(type) {
+	case TaggedSource:
+		return []TaggedSource{src}
+	case CombinedSource:
+		var tagged []TaggedSource
+		for _, member := range src {
+			tagged = append(tagged, taggedSources(member)...)
+		}
+		return tagged
+	default:
+		return nil
+	}
+}
+
+// resolveTags replaces TagConditions in deps with the tagged versions. The
+// first TaggedSource defining a tag wins. deps is copied before modification.
+func (st *solverState) resolveTags(deps []Term) ([]Term, error) {
+	var result []Term
+	for i, dep := range deps {
+		cond, ok := dep.Condition.(TagCondition)
+		if !ok {
+			continue
+		}
+		version, err := st.tagVersion(dep.Name, cond.Tag)
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			result = append([]Term(nil), deps...)
+		}
+		result[i] = Term{Name: dep.Name, Condition: EqualsCondition{Version: version}, Positive: dep.Positive}
+	}
+	if result == nil {
+		return deps, nil
+	}
+	return result, nil
+}
+
+// tagVersion looks up the version tag of name points at. Answers are kept
+// for the rest of the solve so a tag cannot move mid-solve.
+func (st *solverState) tagVersion(name Name, tag string) (Version, error) {
+	key := name.Value() + "@" + tag
+	if version, ok := st.tags[key]; ok {
+		return version, nil
+	}
+	for _, source := range st.tagged {
+		start := time.Now()
+		tags, err := source.Tags(name)
+		st.clock.sourceCall(name, start)
+		if err != nil {
+			if isMissingPackage(err) {
+				continue
+			}
+			return nil, err
+		}
+		if version, ok := tags[tag]; ok {
+			if st.tags == nil {
+				st.tags = make(map[string]Version)
+			}
+			st.tags[key] = version
+			return version, nil
+		}
+	}
+	return nil, &UnknownTagError{Package: name, Tag: tag}
+}
@@ -0,0 +1,68 @@
+package pubgrub
+
+import (
+	"errors"
+	"testing"
+)
+
+type taggedTestSource struct {
+	*InMemorySource
+	tags map[Name]map[string]Version
+}
+
+func (s taggedTestSource) Tags(name Name) (map[string]Version, error) {
+	tags, ok := s.tags[name]
+	if !ok {
+		return nil, &PackageNotFoundError{Package: name}
+	}
+	return tags, nil
+}
+
+func TestTagConditionResolvesThroughTaggedSource(t *testing.T) {
+	mem := &InMemorySource{}
+	for _, v := range []string{"17.0.0", "18.0.0", "19.0.0-beta"} {
+		mem.AddPackage(MakeName("react"), SimpleVersion(v), nil)
+	}
+	mem.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("react"), TagCondition{Tag: "stable"}),
+	})
+	source := taggedTestSource{InMemorySource: mem, tags: map[Name]map[string]Version{
+		MakeName("react"): {"stable": SimpleVersion("17.0.0"), "beta": SimpleVersion("19.0.0-beta")},
+	}}
+
+	req, err := ParseTagRequirement("react@beta")
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	root := NewRootSource()
+	*root = append(*root, req)
+	solution, err := NewSolver(root, source).Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, _ := solution.GetVersion(MakeName("react")); ver == nil || ver.String() != "19.0.0-beta" {
+		t.Fatalf("expected the beta tag, got %v", ver)
+	}
+
+	deps := NewRootSource()
+	deps.AddPackage(MakeName("app"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+	solution, err = NewSolver(deps, source).Solve(deps.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, _ := solution.GetVersion(MakeName("react")); ver == nil || ver.String() != "17.0.0" {
+		t.Fatalf("expected the dependency's stable tag, got %v", ver)
+	}
+
+	unknown := NewRootSource()
+	unknown.AddPackage(MakeName("react"), TagCondition{Tag: "nightly"})
+	_, err = NewSolver(unknown, source).Solve(unknown.Term())
+	var tagErr *UnknownTagError
+	if !errors.As(err, &tagErr) || tagErr.Tag != "nightly" {
+		t.Fatalf("expected UnknownTagError, got %v", err)
+	}
+
+	if _, err := ParseTagRequirement("react"); err == nil {
+		t.Fatalf("expected a requirement without a tag to be rejected")
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Target is one build target resolved by SolveTargets, such as an
+// application, its CLI or its test suite. Each target is solved from its own
+// virtual root package whose dependencies are Requirements.
+type Target struct {
+	Name         string
+	Requirements []Term
+}
+
+// TargetError reports the target whose solve failed in SolveTargets.
+type TargetError struct {
+	Target string
+	Err    error
+}
+
+// Error implements the error interface
+func (e *TargetError) Error() string {
+	return fmt.Sprintf("target %s: %v", e.Target, e.Err)
+}
+
+// Unwrap returns the underlying solve error.
+func (e *TargetError) Unwrap() error {
+	return e.Err
+}
+
+// SolveTargets resolves several targets that share most of their
+// requirements, returning one Solution per target keyed by name. The
+// solutions agree on versions wherever the targets allow it, so a build
+// system installs one dependency set plus a few target-specific extras
+// instead of near-duplicate sets.
+//
+// The requirements of all targets are first solved together; each target is
+// then solved on its own, preferring the versions of that shared solution
+// (see WithPreferredVersions). When the targets cannot be satisfied together,
+// each target prefers the versions chosen by the targets before it instead,
+// so earlier targets take precedence. Every solve uses opts.
+//
+// A target that fails to resolve aborts the call with a *TargetError.
+//
+// Example:
+//
+//	solutions, err := SolveTargets([]Target{
+//	    {Name: "app", Requirements: appReqs},
+//	    {Name: "test", Requirements: append(slices.Clone(appReqs), rspec)},
+//	}, registry)
+func SolveTargets(targets []Target, source Source, opts ...SolverOption) (map[string]Solution, error) {
+	var union RootSource
+	for i, target := range targets {
+		if slices.ContainsFunc(targets[:i], func(t Target) bool { return t.Name == target.Name }) {
+			return nil, fmt.Errorf("duplicate target %q", target.Name)
+		}
+		union = append(union, target.Requirements...)
+	}
+
+	preferred := make(map[Name]Version)
+	shared, err := solveTarget(union, source, opts, preferred)
+	switch {
+	case err == nil:
+		preferTarget(preferred, shared, union.Term().Name)
+	case !errors.Is(err, ErrNoSolution):
+		return nil, err
+	}
+
+	solutions := make(map[string]Solution, len(targets))
+	for _, target := range targets {
+		root := RootSource(slices.Clone(target.Requirements))
+		solution, err := solveTarget(root, source, opts, preferred)
+		if err != nil {
+			return nil, &TargetError{Target: target.Name, Err: err}
+		}
+		solutions[target.Name] = solution
+		preferTarget(preferred, solution, root.Term().Name)
+	}
+	return solutions, nil
+}
+
+// solveTarget solves root against source, preferring the preferred versions.
+func solveTarget(root RootSource, source Source, opts []SolverOption, preferred map[Name]Version) (Solution, error) {
+	solverOpts := append(slices.Clone(opts), WithPreferredVersions(preferred))
+	return NewSolverWithOptions([]Source{root, source}, solverOpts...).Solve(root.Term())
+}
+
+// preferTarget adds the versions of solution for packages without a
+// preference yet, skipping the root package.
+func preferTarget(preferred map[Name]Version, solution Solution, root Name) {
+	for nv := range solution.All() {
+		if _, ok := preferred[nv.Name]; !ok && nv.Name != root {
+			preferred[nv.Name] = nv.Version
+		}
+	}
+}
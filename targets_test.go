@@ -0,0 +1,98 @@
+package pubgrub
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSolveTargetsSharesVersions(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("rack"), mustSemver(t, "2.2.0"), nil)
+	source.AddPackage(MakeName("rack"), mustSemver(t, "3.0.0"), nil)
+	source.AddPackage(MakeName("rails"), mustSemver(t, "7.0.0"), []Term{
+		NewTerm(MakeName("rack"), NewVersionSetCondition(mustParseVersionRange(t, ">=2.0.0"))),
+	})
+	source.AddPackage(MakeName("thor"), mustSemver(t, "1.0.0"), nil)
+	source.AddPackage(MakeName("json"), mustSemver(t, "1.0.0"), nil)
+	source.AddPackage(MakeName("json"), mustSemver(t, "2.0.0"), nil)
+
+	anyVersion := NewVersionSetCondition(FullVersionSet())
+	targets := []Target{
+		{Name: "app", Requirements: []Term{NewTerm(MakeName("rails"), anyVersion)}},
+		{Name: "cli", Requirements: []Term{
+			NewTerm(MakeName("thor"), anyVersion),
+			NewTerm(MakeName("rack"), NewVersionSetCondition(mustParseVersionRange(t, "<3.0.0"))),
+		}},
+	}
+
+	solutions, err := SolveTargets(targets, source, WithVersionStrategy(VersionNewest))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, name := range []string{"app", "cli"} {
+		ver, ok := solutions[name].GetVersion(MakeName("rack"))
+		if !ok || ver.String() != "2.2.0" {
+			t.Fatalf("expected %s to share rack 2.2.0, got %v", name, ver)
+		}
+	}
+	if _, ok := solutions["app"].GetVersion(MakeName("thor")); ok {
+		t.Fatalf("expected app to leave out the cli's thor: %v", solutions["app"])
+	}
+}
+
+func TestSolveTargetsWithConflictingTargets(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("rack"), mustSemver(t, "2.2.0"), nil)
+	source.AddPackage(MakeName("rack"), mustSemver(t, "3.0.0"), nil)
+	source.AddPackage(MakeName("json"), mustSemver(t, "1.0.0"), nil)
+	source.AddPackage(MakeName("json"), mustSemver(t, "2.0.0"), nil)
+
+	targets := []Target{
+		{Name: "app", Requirements: []Term{
+			NewTerm(MakeName("json"), NewVersionSetCondition(mustParseVersionRange(t, ">=2.0.0"))),
+			NewTerm(MakeName("rack"), NewVersionSetCondition(mustParseVersionRange(t, ">=3.0.0"))),
+		}},
+		{Name: "legacy", Requirements: []Term{
+			NewTerm(MakeName("json"), NewVersionSetCondition(mustParseVersionRange(t, "<2.0.0"))),
+			NewTerm(MakeName("rack"), NewVersionSetCondition(FullVersionSet())),
+		}},
+	}
+
+	solutions, err := SolveTargets(targets, source, WithVersionStrategy(VersionOldest))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, _ := solutions["legacy"].GetVersion(MakeName("json")); ver.String() != "1.0.0" {
+		t.Fatalf("expected legacy to keep json 1.0.0, got %v", ver)
+	}
+	// The oldest rack is 2.2.0, but legacy follows the earlier app target.
+	if ver, _ := solutions["legacy"].GetVersion(MakeName("rack")); ver.String() != "3.0.0" {
+		t.Fatalf("expected legacy to follow app's rack, got %v", ver)
+	}
+}
+
+func TestSolveTargetsReportsFailingTarget(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("rack"), mustSemver(t, "2.2.0"), nil)
+	source.AddPackage(MakeName("rack"), mustSemver(t, "3.0.0"), nil)
+	source.AddPackage(MakeName("rails"), mustSemver(t, "7.0.0"), []Term{
+		NewTerm(MakeName("rack"), NewVersionSetCondition(mustParseVersionRange(t, ">=2.0.0"))),
+	})
+	source.AddPackage(MakeName("json"), mustSemver(t, "1.0.0"), nil)
+	source.AddPackage(MakeName("json"), mustSemver(t, "2.0.0"), nil)
+
+	targets := []Target{
+		{Name: "app", Requirements: []Term{NewTerm(MakeName("rails"), NewVersionSetCondition(FullVersionSet()))}},
+		{Name: "docs", Requirements: []Term{NewTerm(MakeName("json"), NewVersionSetCondition(mustParseVersionRange(t, ">=3.0.0")))}},
+	}
+
+	_, err := SolveTargets(targets, source)
+	var targetErr *TargetError
+	if !errors.As(err, &targetErr) || targetErr.Target != "docs" || !errors.Is(err, ErrNoSolution) {
+		t.Fatalf("expected a no-solution TargetError for docs, got %v", err)
+	}
+
+	if _, err := SolveTargets(append(targets[:1:1], targets[0]), source); err == nil {
+		t.Fatal("expected an error for duplicate target names")
+	}
+}
@@ -39,25 +39,37 @@ func (t Term) String() string {
 
 	if t.Positive {
 		if cond == "*" {
-			return t.Name.Value()
+			return FormatName(t.Name)
 		}
-		return fmt.Sprintf("%s %s", t.Name.Value(), cond)
+		return fmt.Sprintf("%s %s", FormatName(t.Name), cond)
 	}
 
 	if cond == "*" {
-		return fmt.Sprintf("not %s", t.Name.Value())
+		return fmt.Sprintf("not %s", FormatName(t.Name))
 	}
-	return fmt.Sprintf("not %s %s", t.Name.Value(), cond)
+	return fmt.Sprintf("not %s %s", FormatName(t.Name), cond)
 }
 
-// NewTerm creates a positive term requiring the package to satisfy the condition.
+// NewTerm creates a positive term requiring the package to satisfy the
+// condition. A nil condition means any version and is replaced with
+// AnyCondition.
 func NewTerm(name Name, condition Condition) Term {
-	return Term{Name: name, Condition: condition, Positive: true}
+	return Term{Name: name, Condition: anyIfNil(condition), Positive: true}
 }
 
-// NewNegativeTerm creates a negative term excluding versions matching the condition.
+// NewNegativeTerm creates a negative term excluding versions matching the
+// condition. A nil condition excludes every version and is replaced with
+// AnyCondition.
 func NewNegativeTerm(name Name, condition Condition) Term {
-	return Term{Name: name, Condition: condition, Positive: false}
+	return Term{Name: name, Condition: anyIfNil(condition), Positive: false}
+}
+
+// anyIfNil returns condition, or AnyCondition when it is nil.
+func anyIfNil(condition Condition) Condition {
+	if condition == nil {
+		return AnyCondition{}
+	}
+	return condition
 }
 
 // Negate returns the logical negation of the term.
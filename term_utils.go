@@ -1,6 +1,76 @@
 package pubgrub
 
-import "fmt"
+import (
+	"fmt"
+	"sync"
+)
+
+// termVersion returns the version of an exact-version term.
+func termVersion(term Term) (Version, bool) {
+	switch cond := term.Condition.(type) {
+	case EqualsCondition:
+		return cond.Version, cond.Version != nil
+	case *EqualsCondition:
+		if cond == nil {
+			return nil, false
+		}
+		return cond.Version, cond.Version != nil
+	default:
+		return nil, false
+	}
+}
+
+// singletonCacheLimit bounds each singleton cache; a full cache is cleared.
+const singletonCacheLimit = 4096
+
+// Singleton sets of exact pins, shared by version value.
+var (
+	simpleSingletons   singletonCache[SimpleVersion]
+	semanticSingletons singletonCache[SemanticVersion]
+)
+
+// singletonCache maps version values to their shared singleton set.
+type singletonCache[K comparable] struct {
+	mu   sync.RWMutex
+	sets map[K]VersionSet
+}
+
+func (c *singletonCache[K]) get(key K, v Version) VersionSet {
+	c.mu.RLock()
+	set, ok := c.sets[key]
+	c.mu.RUnlock()
+	if ok {
+		return set
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if set, ok := c.sets[key]; ok {
+		return set
+	}
+	if c.sets == nil || len(c.sets) >= singletonCacheLimit {
+		c.sets = make(map[K]VersionSet)
+	}
+	set = (&VersionIntervalSet{}).Singleton(v)
+	c.sets[key] = set
+	return set
+}
+
+// singletonSet returns the VersionSet containing only v. Exact-pin terms are
+// converted on every evaluation, so the sets of SimpleVersions and
+// SemanticVersions are cached and shared rather than reallocated; other
+// Version types are converted afresh. Sets are immutable, so sharing is safe.
+func singletonSet(v Version) VersionSet {
+	switch ver := v.(type) {
+	case SimpleVersion:
+		return simpleSingletons.get(ver, v)
+	case *SemanticVersion:
+		if ver != nil {
+			return semanticSingletons.get(*ver, v)
+		}
+	}
+	return (&VersionIntervalSet{}).Singleton(v)
+}
 
 func termAllowedSet(term Term) (VersionSet, bool) {
 	if !term.Positive {
@@ -11,12 +81,12 @@ func termAllowedSet(term Term) (VersionSet, bool) {
 	case nil:
 		return (&VersionIntervalSet{}).Full(), true
 	case EqualsCondition:
-		return (&VersionIntervalSet{}).Singleton(cond.Version), true
+		return singletonSet(cond.Version), true
 	case *EqualsCondition:
 		if cond == nil {
 			return (&VersionIntervalSet{}).Full(), true
 		}
-		return (&VersionIntervalSet{}).Singleton(cond.Version), true
+		return singletonSet(cond.Version), true
 	case *VersionSetCondition:
 		if cond == nil || cond.Set == nil {
 			return (&VersionIntervalSet{}).Full(), true
@@ -25,7 +95,7 @@ func termAllowedSet(term Term) (VersionSet, bool) {
 	case VersionSetConverter:
 		return cond.ToVersionSet(), true
 	default:
-		return nil, false
+		return registeredVersionSet(cond)
 	}
 }
 
@@ -38,12 +108,12 @@ func termForbiddenSet(term Term) (VersionSet, bool) {
 	case nil:
 		return (&VersionIntervalSet{}).Full(), true
 	case EqualsCondition:
-		return (&VersionIntervalSet{}).Singleton(cond.Version), true
+		return singletonSet(cond.Version), true
 	case *EqualsCondition:
 		if cond == nil {
 			return (&VersionIntervalSet{}).Full(), true
 		}
-		return (&VersionIntervalSet{}).Singleton(cond.Version), true
+		return singletonSet(cond.Version), true
 	case *VersionSetCondition:
 		if cond == nil || cond.Set == nil {
 			return (&VersionIntervalSet{}).Full(), true
@@ -52,7 +122,7 @@ func termForbiddenSet(term Term) (VersionSet, bool) {
 	case VersionSetConverter:
 		return cond.ToVersionSet(), true
 	default:
-		return nil, false
+		return registeredVersionSet(cond)
 	}
 }
 
@@ -108,9 +178,22 @@ func termFromForbiddenSet(name Name, set VersionSet) Term {
 	}
 }
 
+// VersionSetEqualer is implemented by VersionSets that can test exact
+// equality more cheaply than two IsSubset calls, typically by comparing
+// canonical forms. The solver uses it to decide whether a derivation changed
+// a package's allowed versions, so it must never report semantically equal
+// sets as different.
+type VersionSetEqualer interface {
+	Equal(other VersionSet) bool
+}
+
+// setsEqual reports whether a and b contain the same versions.
 func setsEqual(a, b VersionSet) bool {
 	if a == nil || b == nil {
 		return a == nil && b == nil
 	}
+	if eq, ok := a.(VersionSetEqualer); ok {
+		return eq.Equal(b)
+	}
 	return a.IsSubset(b) && b.IsSubset(a)
 }
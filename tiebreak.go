@@ -0,0 +1,79 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import "math"
+
+// TieBreak decides between candidate versions that VersionLookahead scores
+// equally.
+//
+// Experimental: new tie-breaks may be added and existing ones refined in a
+// minor release.
+type TieBreak int
+
+const (
+	// TieBreakNewest prefers the newest of the tied versions. This is the
+	// default.
+	TieBreakNewest TieBreak = iota
+	// TieBreakFewerDependencies prefers the tied version that pulls in the
+	// fewest dependencies not already satisfied by a decision, falling back
+	// to the newest. Smaller closures leave fewer packages to conflict.
+	TieBreakFewerDependencies
+)
+
+// preferOnTie reports whether ver should replace best when both score
+// equally.
+func (st *solverState) preferOnTie(name Name, ver, best Version) bool {
+	if st.options.TieBreak == TieBreakFewerDependencies {
+		if open, bestOpen := st.openDependencies(name, ver), st.openDependencies(name, best); open != bestOpen {
+			return open < bestOpen
+		}
+	}
+	return ver.Sort(best) > 0
+}
+
+// openDependencies counts the positive dependencies of name@ver that no
+// current decision satisfies. Dependency lists are memoized; the count is
+// not, since decisions change as the solve proceeds. Versions whose
+// dependencies cannot be fetched count as maximally open.
+func (st *solverState) openDependencies(name Name, ver Version) int {
+	key := dependencyScoreKey(name, ver)
+	deps, ok := st.depLists[key]
+	if !ok {
+		var err error
+		deps, err = st.getDependencies(name, ver)
+		if err != nil {
+			return math.MaxInt
+		}
+		if st.depLists == nil {
+			st.depLists = make(map[string][]Term)
+		}
+		st.depLists[key] = deps
+	}
+
+	open := 0
+	for _, dep := range deps {
+		if !dep.Positive {
+			continue
+		}
+		if st.partial.hasDecision(dep.Name) {
+			if projected, err := applyTermToAllowed(st.partial.allowedSet(dep.Name), dep); err == nil && !projected.IsEmpty() {
+				continue
+			}
+		}
+		open++
+	}
+	return open
+}
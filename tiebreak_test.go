@@ -0,0 +1,47 @@
+package pubgrub
+
+import "testing"
+
+func TestTieBreakNewestByDefault(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("x"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("y"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("lib"), SimpleVersion("1.0.0"), []Term{NewTerm(MakeName("x"), EqualsCondition{Version: SimpleVersion("1.0.0")})})
+	source.AddPackage(MakeName("lib"), SimpleVersion("2.0.0"), []Term{NewTerm(MakeName("y"), EqualsCondition{Version: SimpleVersion("1.0.0")})})
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("x"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+	root.AddPackage(MakeName("lib"), NewVersionSetCondition(FullVersionSet()))
+
+	solution, err := NewSolver(root, source).Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, _ := solution.GetVersion(MakeName("lib")); ver.String() != "2.0.0" {
+		t.Fatalf("expected lib 2.0.0, got %v", ver)
+	}
+}
+
+func TestTieBreakFewerDependencies(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("x"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("y"), SimpleVersion("1.0.0"), nil)
+	source.AddPackage(MakeName("lib"), SimpleVersion("1.0.0"), []Term{NewTerm(MakeName("x"), EqualsCondition{Version: SimpleVersion("1.0.0")})})
+	source.AddPackage(MakeName("lib"), SimpleVersion("2.0.0"), []Term{NewTerm(MakeName("y"), EqualsCondition{Version: SimpleVersion("1.0.0")})})
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("x"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+	root.AddPackage(MakeName("lib"), NewVersionSetCondition(FullVersionSet()))
+
+	solver := NewSolverWithOptions([]Source{root, source}, WithTieBreak(TieBreakFewerDependencies))
+	solution, err := solver.Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, _ := solution.GetVersion(MakeName("lib")); ver.String() != "1.0.0" {
+		t.Fatalf("expected lib 1.0.0, whose dependency is already decided, got %v", ver)
+	}
+	if _, ok := solution.GetVersion(MakeName("y")); ok {
+		t.Fatalf("expected y to stay out of the solution, got %v", solution)
+	}
+}
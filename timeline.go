@@ -0,0 +1,142 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+// PackageTimeline lists every published version of a package touched by a
+// solve together with what happened to it. It is shaped for rendering
+// "why not?" tables and serializes directly to JSON.
+//
+// Example:
+//
+//	solver := NewSolverWithOptions([]Source{root, source}, WithTimeline(true))
+//	_, err := solver.Solve(root.Term())
+//	for _, pkg := range solver.Timeline() {
+//	    for _, v := range pkg.Versions {
+//	        fmt.Printf("%s %s: %s\n", pkg.Package, v.Version, v.Constraint)
+//	    }
+//	}
+type PackageTimeline struct {
+	Package  string        `json:"package"`
+	Versions []VersionFate `json:"versions"`
+}
+
+// VersionFate records the first constraint that eliminated a version.
+// Steps refer to solver main loop iterations, starting at 1; zero means the
+// event never happened.
+type VersionFate struct {
+	Version string `json:"version"`
+	// Selected is set when the version is part of the final solution.
+	Selected bool `json:"selected,omitempty"`
+	// TriedAt is the step at which the solver first decided on this version.
+	TriedAt int `json:"tried_at,omitempty"`
+	// EliminatedAt is the step at which a derived constraint first excluded
+	// this version. The constraint may later have been retracted by
+	// backtracking.
+	EliminatedAt int `json:"eliminated_at,omitempty"`
+	// Constraint is the derived term that excluded the version.
+	Constraint string `json:"constraint,omitempty"`
+	// Cause describes the incompatibility the constraint was derived from,
+	// and CauseID is its stable identifier.
+	Cause   string `json:"cause,omitempty"`
+	CauseID string `json:"cause_id,omitempty"`
+}
+
+// timelineEvent is an assignment recorded together with the step it was
+// made in. Assignments are never mutated, so keeping the pointer after
+// backtracking is safe.
+type timelineEvent struct {
+	step   int
+	assign *assignment
+}
+
+// Timeline returns the version timeline of the most recent Solve call, or nil
+// when timeline recording was not enabled with WithTimeline.
+func (s *Solver) Timeline() []PackageTimeline {
+	return s.timeline
+}
+
+// recordTimeline appends an assignment to the timeline when recording.
+func (st *solverState) recordTimeline(assign *assignment) {
+	if !st.options.RecordTimeline || assign == nil {
+		return
+	}
+	st.timeline = append(st.timeline, timelineEvent{step: st.steps, assign: assign})
+}
+
+// buildTimeline joins recorded events with the published versions of every
+// package they touch. Packages appear in the order they were first touched.
+func (st *solverState) buildTimeline(solution Solution) []PackageTimeline {
+	if !st.options.RecordTimeline {
+		return nil
+	}
+
+	var order []Name
+	events := make(map[Name][]timelineEvent)
+	for _, ev := range st.timeline {
+		name := ev.assign.name
+		if name == st.partial.root {
+			continue
+		}
+		if _, ok := events[name]; !ok {
+			order = append(order, name)
+		}
+		events[name] = append(events[name], ev)
+	}
+
+	result := make([]PackageTimeline, 0, len(order))
+	for _, name := range order {
+		versions, err := st.getVersions(name)
+		if err != nil {
+			versions = nil
+		}
+
+		selected, hasSelected := solution.GetVersion(name)
+		pkg := PackageTimeline{Package: name.Value(), Versions: make([]VersionFate, 0, len(versions))}
+		for _, ver := range versions {
+			fate := VersionFate{Version: ver.String()}
+			if hasSelected && selected.Sort(ver) == 0 {
+				fate.Selected = true
+			}
+			for _, ev := range events[name] {
+				assign := ev.assign
+				if assign.isDecision() {
+					if fate.TriedAt == 0 && assign.version.Sort(ver) == 0 {
+						fate.TriedAt = ev.step
+					}
+					continue
+				}
+				if fate.EliminatedAt == 0 && assignmentExcludes(assign, ver) {
+					fate.EliminatedAt = ev.step
+					fate.Constraint = assign.term.String()
+					if assign.cause != nil {
+						fate.Cause = assign.cause.String()
+						fate.CauseID = assign.cause.ID()
+					}
+				}
+			}
+			pkg.Versions = append(pkg.Versions, fate)
+		}
+		result = append(result, pkg)
+	}
+	return result
+}
+
+// assignmentExcludes reports whether a derivation rules out version.
+func assignmentExcludes(assign *assignment, version Version) bool {
+	if assign.term.Positive {
+		return assign.allowed != nil && !assign.allowed.Contains(version)
+	}
+	return assign.forbidden != nil && assign.forbidden.Contains(version)
+}
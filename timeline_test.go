@@ -0,0 +1,62 @@
+package pubgrub
+
+import "testing"
+
+func TestSolverTimelineExplainsEliminatedVersions(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("lib"), EqualsCondition{Version: SimpleVersion("2.0.0")}),
+	})
+	for _, v := range []string{"1.0.0", "2.0.0", "3.0.0"} {
+		source.AddPackage(MakeName("lib"), SimpleVersion(v), nil)
+	}
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	solver := NewSolverWithOptions([]Source{root, source}, WithTimeline(true))
+	if _, err := solver.Solve(root.Term()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var lib *PackageTimeline
+	for i, pkg := range solver.Timeline() {
+		if pkg.Package == "lib" {
+			lib = &solver.Timeline()[i]
+		}
+	}
+	if lib == nil || len(lib.Versions) != 3 {
+		t.Fatalf("expected a timeline entry for all three lib versions, got %+v", solver.Timeline())
+	}
+
+	for _, fate := range lib.Versions {
+		switch fate.Version {
+		case "2.0.0":
+			if !fate.Selected || fate.TriedAt == 0 || fate.EliminatedAt != 0 {
+				t.Fatalf("expected lib 2.0.0 to be tried and selected, got %+v", fate)
+			}
+		default:
+			if fate.Selected || fate.EliminatedAt == 0 || fate.CauseID == "" {
+				t.Fatalf("expected lib %s to be eliminated with a cause, got %+v", fate.Version, fate)
+			}
+			if fate.Cause != "app 1.0.0 depends on lib == 2.0.0" {
+				t.Fatalf("unexpected cause for lib %s: %q", fate.Version, fate.Cause)
+			}
+		}
+	}
+}
+
+func TestSolverTimelineDisabledByDefault(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("a"), SimpleVersion("1.0.0"), nil)
+	root := NewRootSource()
+	root.AddPackage(MakeName("a"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+
+	solver := NewSolver(root, source)
+	if _, err := solver.Solve(root.Term()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if solver.Timeline() != nil {
+		t.Fatalf("expected no timeline without WithTimeline")
+	}
+}
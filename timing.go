@@ -0,0 +1,169 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// phaseClock attributes main loop wall time to the phase in progress. Time
+// spent in Source calls made during a phase is charged to SourceTime only,
+// so the phases and SourceTime do not overlap.
+type phaseClock struct {
+	phase *time.Duration // Phase being timed, nil when stopped
+	start time.Time      // When phase began
+	io    time.Duration  // sourceTime when phase began
+
+	solveTime       time.Duration // Wall time inside Solve and ResumeSolve
+	propagationTime time.Duration // Unit propagation
+	conflictTime    time.Duration // Conflict analysis and backjumping
+	selectionTime   time.Duration // Version selection and recording decisions
+	sourceTime      time.Duration // Source calls
+
+	budget    time.Duration        // Per-package budget, 0 for none
+	packages  map[Name]PackageTime // Time attributed to each package
+	exceeded  Name                 // First package over budget, zero if none
+	selecting Name                 // Package being selected, zero if none
+	selStart  time.Time            // When its selection began
+	selIO     time.Duration        // sourceTime when its selection began
+}
+
+// enterPhase charges the time since the current phase began to it and
+// starts timing next. A nil next stops the clock.
+func (c *phaseClock) enterPhase(next *time.Duration) {
+	if next == nil {
+		c.endSelection()
+	}
+	now := time.Now()
+	if c.phase != nil {
+		*c.phase += now.Sub(c.start) - (c.sourceTime - c.io)
+	}
+	c.phase, c.start, c.io = next, now, c.sourceTime
+}
+
+// sourceCall charges the time since start to Source I/O about name.
+func (c *phaseClock) sourceCall(name Name, start time.Time) {
+	elapsed := time.Since(start)
+	c.sourceTime += elapsed
+	c.charge(name, 0, elapsed, 1)
+}
+
+// beginSelection starts attributing solver time to the selection of a
+// version of name, until endSelection.
+func (c *phaseClock) beginSelection(name Name) {
+	c.endSelection()
+	c.selecting, c.selStart, c.selIO = name, time.Now(), c.sourceTime
+}
+
+// endSelection charges the selection in progress, if any, to its package.
+// Source calls made meanwhile are charged by sourceCall instead.
+func (c *phaseClock) endSelection() {
+	if c.selecting == (Name{}) {
+		return
+	}
+	c.charge(c.selecting, time.Since(c.selStart)-(c.sourceTime-c.selIO), 0, 0)
+	c.selecting = Name{}
+}
+
+// charge adds time to name and notes the first package to exceed the
+// budget.
+func (c *phaseClock) charge(name Name, solver, source time.Duration, calls int) {
+	if c.packages == nil {
+		c.packages = make(map[Name]PackageTime)
+	}
+	entry := c.packages[name]
+	entry.Package = name
+	entry.SolverTime += solver
+	entry.SourceTime += source
+	entry.SourceCalls += calls
+	c.packages[name] = entry
+	if c.budget > 0 && entry.Total() > c.budget && c.exceeded == (Name{}) {
+		c.exceeded = name
+	}
+}
+
+// hotspots returns the MaxHotspots packages that took the most time.
+func (c *phaseClock) hotspots() []PackageTime {
+	if len(c.packages) == 0 {
+		return nil
+	}
+	times := make([]PackageTime, 0, len(c.packages))
+	for _, entry := range c.packages {
+		times = append(times, entry)
+	}
+	return topHotspots(times)
+}
+
+// topHotspots sorts times by total time, longest first, and keeps the first
+// MaxHotspots.
+func topHotspots(times []PackageTime) []PackageTime {
+	slices.SortFunc(times, func(a, b PackageTime) int {
+		if c := cmp.Compare(b.Total(), a.Total()); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Package.Value(), b.Package.Value())
+	})
+	if len(times) > MaxHotspots {
+		times = times[:MaxHotspots]
+	}
+	return slices.Clip(times)
+}
+
+// mergeHotspots sums the per-package times of two hotspot lists.
+func mergeHotspots(a, b []PackageTime) []PackageTime {
+	if len(a) == 0 || len(b) == 0 {
+		return append(slices.Clip(a), b...)
+	}
+	merged := slices.Clone(a)
+	for _, entry := range b {
+		i := slices.IndexFunc(merged, func(m PackageTime) bool { return m.Package == entry.Package })
+		if i < 0 {
+			merged = append(merged, entry)
+			continue
+		}
+		merged[i].SolverTime += entry.SolverTime
+		merged[i].SourceTime += entry.SourceTime
+		merged[i].SourceCalls += entry.SourceCalls
+	}
+	return topHotspots(merged)
+}
+
+// packageTimeout reports the package that exceeded the budget.
+func (c *phaseClock) packageTimeout() error {
+	entry := c.packages[c.exceeded]
+	return ErrPackageTimeout{Package: c.exceeded, Elapsed: entry.Total(), SourceTime: entry.SourceTime, Budget: c.budget}
+}
+
+// logPhaseTimes logs where the wall time of the solve went, telling
+// algorithmic slowness apart from a slow Source. Nothing is computed without
+// a Logger, as boxing the timings and ranking hotspots allocates.
+func (s *Solver) logPhaseTimes(state *solverState) {
+	if state == nil || s.options.Logger == nil {
+		return
+	}
+	s.debug("phase timings",
+		"solve", state.clock.solveTime,
+		"propagation", state.clock.propagationTime,
+		"conflict", state.clock.conflictTime,
+		"selection", state.clock.selectionTime,
+		"source", state.clock.sourceTime,
+	)
+	if hotspots := state.clock.hotspots(); len(hotspots) > 0 {
+		s.debug("slowest package", "package", hotspots[0].Package, "time", hotspots[0].Total(), "source", hotspots[0].SourceTime)
+	}
+}
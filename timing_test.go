@@ -0,0 +1,97 @@
+package pubgrub
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// slowSource delays every dependency lookup.
+type slowSource struct {
+	Source
+	delay time.Duration
+	only  Name // Package to delay, zero for all
+	calls int
+}
+
+func (s *slowSource) GetDependencies(name Name, version Version) ([]Term, error) {
+	s.calls++
+	if s.only == (Name{}) || s.only == name {
+		time.Sleep(s.delay)
+	}
+	return s.Source.GetDependencies(name, version)
+}
+
+func TestPhaseTimesSeparateSourceIO(t *testing.T) {
+	root, inner := conflictingWideWorkload(t, 4)
+	source := &slowSource{Source: inner, delay: 2 * time.Millisecond}
+
+	solver := NewSolver(root, source)
+	if _, err := solver.Solve(root.Term()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stats := solver.Stats()
+
+	if floor := time.Duration(source.calls) * source.delay; stats.SourceTime < floor {
+		t.Fatalf("source time %v below %d delayed calls (%v)", stats.SourceTime, source.calls, floor)
+	}
+	algorithmic := stats.PropagationTime + stats.ConflictTime + stats.SelectionTime
+	if algorithmic <= 0 || stats.PropagationTime <= 0 {
+		t.Fatalf("expected main loop phases to be timed, stats %+v", stats)
+	}
+	if algorithmic >= stats.SourceTime {
+		t.Fatalf("source delays leaked into solver phases: %v algorithmic, %v source", algorithmic, stats.SourceTime)
+	}
+	if algorithmic+stats.SourceTime > stats.SolveTime {
+		t.Fatalf("phases (%v) and source (%v) exceed solve time %v", algorithmic, stats.SourceTime, stats.SolveTime)
+	}
+}
+
+func slowPackageWorkload() (*RootSource, Source) {
+	anyVersion := NewVersionSetCondition(FullVersionSet())
+	inner := &InMemorySource{}
+	inner.AddPackage(MakeName("app"), SimpleVersion("1.0.0"), []Term{
+		NewTerm(MakeName("nokogiri"), anyVersion),
+		NewTerm(MakeName("rack"), anyVersion),
+	})
+	for _, v := range []string{"1.0.0", "1.1.0", "1.2.0"} {
+		inner.AddPackage(MakeName("nokogiri"), SimpleVersion(v), nil)
+	}
+	inner.AddPackage(MakeName("rack"), SimpleVersion("1.0.0"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("app"), anyVersion)
+	return root, &slowSource{Source: inner, delay: 5 * time.Millisecond, only: MakeName("nokogiri")}
+}
+
+func TestHotspotsNameSlowPackage(t *testing.T) {
+	root, source := slowPackageWorkload()
+	solver := NewSolver(root, source)
+	if _, err := solver.Solve(root.Term()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	hotspots := solver.Stats().Hotspots
+	if len(hotspots) == 0 || hotspots[0].Package != MakeName("nokogiri") {
+		t.Fatalf("expected nokogiri to be the slowest package, got %+v", hotspots)
+	}
+	if hotspots[0].SourceTime < 5*time.Millisecond || hotspots[0].SourceCalls == 0 {
+		t.Fatalf("expected nokogiri's source calls to be attributed to it, got %+v", hotspots[0])
+	}
+	if len(hotspots) > MaxHotspots {
+		t.Fatalf("expected at most %d hotspots, got %d", MaxHotspots, len(hotspots))
+	}
+}
+
+func TestPackageTimeoutNamesSlowPackage(t *testing.T) {
+	root, source := slowPackageWorkload()
+	_, err := NewSolver(root, source).Solve(root.Term(), WithPackageTimeout(time.Millisecond))
+
+	var timeout ErrPackageTimeout
+	if !errors.As(err, &timeout) {
+		t.Fatalf("expected ErrPackageTimeout, got %v", err)
+	}
+	if timeout.Package != MakeName("nokogiri") || timeout.Elapsed <= timeout.Budget {
+		t.Fatalf("expected nokogiri to exceed the budget, got %+v", timeout)
+	}
+}
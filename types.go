@@ -89,7 +89,8 @@ type Condition interface {
 //
 // Built-in conditions (EqualsCondition, VersionSetCondition) are already handled
 // by the solver. Custom condition types should implement this interface to enable
-// solver support.
+// solver support; types from other packages, which cannot gain a method, can be
+// registered with RegisterConditionConverter instead.
 //
 // Example custom condition:
 //
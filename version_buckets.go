@@ -0,0 +1,133 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"slices"
+	"strings"
+)
+
+// maxBucketScan bounds how many allowed versions are inspected while forming
+// buckets for a single decision.
+const maxBucketScan = 64
+
+// VersionBucket groups consecutive versions of a package that declare
+// identical dependencies. Choosing any member of a bucket has the same effect
+// on the rest of the graph, so the solver only needs to consider one of them.
+type VersionBucket struct {
+	// Versions lists the bucket members in ascending order.
+	Versions []Version
+}
+
+// Best returns the highest version in the bucket.
+func (b VersionBucket) Best() Version {
+	if len(b.Versions) == 0 {
+		return nil
+	}
+	return b.Versions[len(b.Versions)-1]
+}
+
+// BucketVersions partitions every published version of a package into buckets
+// of consecutive releases with identical dependency sets, lowest first.
+//
+// Example:
+//
+//	buckets, _ := BucketVersions(source, MakeName("rubyzip"))
+//	for _, b := range buckets {
+//	    fmt.Printf("%d equivalent versions, best %s\n", len(b.Versions), b.Best())
+//	}
+func BucketVersions(source Source, name Name) ([]VersionBucket, error) {
+	versions, err := source.GetVersions(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var buckets []VersionBucket
+	prev := ""
+	for i, ver := range versions {
+		deps, err := source.GetDependencies(name, ver)
+		if err != nil {
+			return nil, err
+		}
+		sig := dependencySignature(deps)
+		if i == 0 || sig != prev {
+			buckets = append(buckets, VersionBucket{})
+		}
+		last := &buckets[len(buckets)-1]
+		last.Versions = append(last.Versions, ver)
+		prev = sig
+	}
+	return buckets, nil
+}
+
+// bucketCandidates returns the best allowed version from each of the highest
+// buckets, so lookahead scoring compares distinct dependency shapes rather
+// than several patch releases that would score identically.
+func (st *solverState) bucketCandidates(name Name, versions []Version, allowed VersionSet) []Version {
+	candidates := make([]Version, 0, maxVersionScoreCandidates)
+	prev := ""
+	scanned := 0
+
+	for i := len(versions) - 1; i >= 0 && scanned < maxBucketScan; i-- {
+		ver := versions[i]
+		if !allowed.Contains(ver) {
+			continue
+		}
+		scanned++
+
+		sig, ok := st.versionSignature(name, ver)
+		if ok && len(candidates) > 0 && sig == prev {
+			// Same bucket as the previous candidate; its best member is
+			// already represented.
+			continue
+		}
+		if len(candidates) == maxVersionScoreCandidates {
+			break
+		}
+		candidates = append(candidates, ver)
+		prev = sig
+	}
+
+	return candidates
+}
+
+// versionSignature returns a memoized dependency signature for name@ver.
+func (st *solverState) versionSignature(name Name, ver Version) (string, bool) {
+	key := dependencyScoreKey(name, ver)
+	if sig, ok := st.signatures[key]; ok {
+		return sig, true
+	}
+
+	deps, err := st.getDependencies(name, ver)
+	if err != nil {
+		return "", false
+	}
+	sig := dependencySignature(deps)
+	if st.signatures == nil {
+		st.signatures = make(map[string]string)
+	}
+	st.signatures[key] = sig
+	return sig, true
+}
+
+// dependencySignature renders a dependency list in an order-independent form.
+func dependencySignature(deps []Term) string {
+	parts := make([]string, len(deps))
+	for i, dep := range deps {
+		parts[i] = dep.String()
+	}
+	slices.Sort(parts)
+	return strings.Join(parts, "\n")
+}
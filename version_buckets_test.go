@@ -0,0 +1,56 @@
+package pubgrub
+
+import "testing"
+
+func TestBucketVersionsGroupsIdenticalDependencies(t *testing.T) {
+	source := patchReleaseSource(t, 6, func(i int) string {
+		if i < 2 {
+			return ">=1.0.0"
+		}
+		return ">=2.0.0"
+	})
+
+	buckets, err := BucketVersions(source, MakeName("foo"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(buckets) != 2 {
+		t.Fatalf("expected 2 buckets, got %d", len(buckets))
+	}
+	if len(buckets[0].Versions) != 2 || buckets[0].Best().String() != "1.0.1" {
+		t.Fatalf("unexpected first bucket: %v", buckets[0].Versions)
+	}
+	if len(buckets[1].Versions) != 4 || buckets[1].Best().String() != "1.0.5" {
+		t.Fatalf("unexpected second bucket: %v", buckets[1].Versions)
+	}
+}
+
+func TestVersionBucketingSkipsEquivalentFailures(t *testing.T) {
+	source := patchReleaseSource(t, 10, func(i int) string {
+		if i == 0 {
+			return ">=1.0.0"
+		}
+		return ">=2.0.0"
+	})
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("foo"), NewVersionSetCondition(FullVersionSet()))
+
+	solver := NewSolverWithOptions([]Source{root, source}, WithVersionBucketing(true))
+	solution, err := solver.Solve(root.Term())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ver, _ := solution.GetVersion(MakeName("foo")); ver.String() != "1.0.0" {
+		t.Fatalf("expected foo 1.0.0, got %v", ver)
+	}
+
+	plain := NewSolver(root, source)
+	if _, err := plain.Solve(root.Term()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if solver.Stats().Decisions >= plain.Stats().Decisions {
+		t.Fatalf("expected bucketing to need fewer decisions: bucketed=%d plain=%d",
+			solver.Stats().Decisions, plain.Stats().Decisions)
+	}
+}
@@ -134,8 +134,17 @@ func (iv versionInterval) overlaps(other versionInterval) bool {
 // touches returns true if this interval overlaps or is adjacent to other.
 // Adjacent intervals can be merged without creating a gap.
 func (iv versionInterval) touches(other versionInterval) bool {
-	return !upperLessThanLower(iv.upper, other.lower) &&
-		!upperLessThanLower(other.upper, iv.lower)
+	return !leavesGap(iv.upper, other.lower) && !leavesGap(other.upper, iv.lower)
+}
+
+// leavesGap reports whether some version lies between upper and a lower
+// bound starting after it. Bounds on the same version abut, such as "<1.5.0"
+// followed by ">=1.5.0", unless both exclude it.
+func leavesGap(upper, lower versionBound) bool {
+	if upper.isFinite() && lower.isFinite() && upper.version.Sort(lower.version) == 0 {
+		return !upper.inclusive && !lower.inclusive
+	}
+	return upperLessThanLower(upper, lower)
 }
 
 // merge combines two intervals into a single interval spanning both.
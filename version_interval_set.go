@@ -235,6 +235,31 @@ func (s *VersionIntervalSet) IsDisjoint(other VersionSet) bool {
 	return true
 }
 
+// Equal reports whether other contains exactly the versions of s. Interval
+// sets are kept normalized, which makes their intervals a canonical form, so
+// two interval sets are compared bound by bound and the comparison stops at
+// the first difference. Bounds are compared with Sort, so versions that sort
+// equal, such as "1.0" and "1.0.0" where their type says so, match.
+func (s *VersionIntervalSet) Equal(other VersionSet) bool {
+	o, ok := other.(*VersionIntervalSet)
+	if !ok {
+		return s.IsSubset(other) && other.IsSubset(s)
+	}
+	if s == o {
+		return true
+	}
+	if len(s.intervals) != len(o.intervals) {
+		return false
+	}
+	for i, interval := range s.intervals {
+		if compareLower(interval.lower, o.intervals[i].lower) != 0 ||
+			compareUpper(interval.upper, o.intervals[i].upper) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
 // Intervals returns an iterator over the internal version intervals.
 // This enables using range-over-function syntax:
 //
@@ -301,6 +326,29 @@ func intervalToString(interval versionInterval) string {
 	return strings.Join(parts, ", ")
 }
 
+// CanonicalVersionSet returns set in canonical form: a normalized
+// VersionIntervalSet whose intervals are sorted, disjoint and merged, so sets
+// containing the same versions have the same intervals however they were
+// built. Sets of other types are returned unchanged unless they are empty.
+//
+// Example:
+//
+//	a := CanonicalVersionSet(set.Complement().Complement())
+//	fmt.Println(a.(*VersionIntervalSet).Equal(set)) // true
+func CanonicalVersionSet(set VersionSet) VersionSet {
+	if set == nil {
+		return &VersionIntervalSet{}
+	}
+	iv, ok := set.(*VersionIntervalSet)
+	if !ok {
+		if set.IsEmpty() {
+			return &VersionIntervalSet{}
+		}
+		return set
+	}
+	return newVersionIntervalSet(iv.cloneIntervals())
+}
+
 // asIntervalSet converts a VersionSet to VersionIntervalSet or panics.
 // This is used internally for type assertion with a helpful error message.
 func asIntervalSet(set VersionSet) *VersionIntervalSet {
@@ -345,5 +393,6 @@ func singletonVersionFromSet(set VersionSet) (Version, bool) {
 }
 
 var (
-	_ VersionSet = (*VersionIntervalSet)(nil)
+	_ VersionSet        = (*VersionIntervalSet)(nil)
+	_ VersionSetEqualer = (*VersionIntervalSet)(nil)
 )
@@ -0,0 +1,106 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+// RangeBuilder builds a VersionSet from typed comparisons, avoiding the
+// format-then-parse round trip of ParseVersionRange. Comparisons in a clause
+// are combined with AND, and Or starts a new clause, mirroring the ","
+// and "||" operators of the range syntax.
+//
+// A RangeBuilder is a value: every method returns an updated copy, so a
+// partially built range can be reused as a common prefix.
+//
+// Example:
+//
+//	set := Range().GTE(v1).LT(v2).Or().Exact(v3).Build()
+//	// same as ParseVersionRange(">=1.0.0, <2.0.0 || ==3.0.0")
+type RangeBuilder struct {
+	// done is the union of the completed clauses.
+	done VersionSet
+	// clause is the intersection of the comparisons in the current clause.
+	clause VersionSet
+}
+
+// Range starts a new range whose first clause allows every version.
+func Range() RangeBuilder {
+	return RangeBuilder{done: EmptyVersionSet(), clause: FullVersionSet()}
+}
+
+// GTE restricts the current clause to versions >= v.
+func (b RangeBuilder) GTE(v Version) RangeBuilder {
+	return b.and(intervalSetFromBounds(newLowerBound(v, true), positiveInfinityBound()))
+}
+
+// GT restricts the current clause to versions > v.
+func (b RangeBuilder) GT(v Version) RangeBuilder {
+	return b.and(intervalSetFromBounds(newLowerBound(v, false), positiveInfinityBound()))
+}
+
+// LTE restricts the current clause to versions <= v.
+func (b RangeBuilder) LTE(v Version) RangeBuilder {
+	return b.and(intervalSetFromBounds(negativeInfinityBound(), newUpperBound(v, true)))
+}
+
+// LT restricts the current clause to versions < v.
+func (b RangeBuilder) LT(v Version) RangeBuilder {
+	return b.and(intervalSetFromBounds(negativeInfinityBound(), newUpperBound(v, false)))
+}
+
+// Exact restricts the current clause to v.
+func (b RangeBuilder) Exact(v Version) RangeBuilder {
+	return b.and(intervalSetFromBounds(newLowerBound(v, true), newUpperBound(v, true)))
+}
+
+// Except removes v from the current clause.
+func (b RangeBuilder) Except(v Version) RangeBuilder {
+	return b.and(intervalSetFromBounds(newLowerBound(v, true), newUpperBound(v, true)).Complement())
+}
+
+// Or completes the current clause and starts a new one allowing every
+// version.
+func (b RangeBuilder) Or() RangeBuilder {
+	return RangeBuilder{done: b.build(), clause: FullVersionSet()}
+}
+
+// Build returns the union of all clauses.
+func (b RangeBuilder) Build() VersionSet {
+	return b.build()
+}
+
+// Condition returns the built range as a Condition, ready for NewTerm.
+func (b RangeBuilder) Condition() Condition {
+	return NewVersionSetCondition(b.build())
+}
+
+func (b RangeBuilder) and(set VersionSet) RangeBuilder {
+	b.ensure()
+	b.clause = b.clause.Intersection(set)
+	return b
+}
+
+func (b RangeBuilder) build() VersionSet {
+	b.ensure()
+	return b.done.Union(b.clause)
+}
+
+// ensure makes the zero RangeBuilder behave like Range().
+func (b *RangeBuilder) ensure() {
+	if b.done == nil {
+		b.done = EmptyVersionSet()
+	}
+	if b.clause == nil {
+		b.clause = FullVersionSet()
+	}
+}
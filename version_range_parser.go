@@ -36,11 +36,77 @@ import (
 //	ParseVersionRange("!=1.5.0")             // Not 1.5.0
 //
 // The parser tries to interpret versions as SemanticVersion first,
-// falling back to SimpleVersion if parsing fails. This allows mixing
-// version types within a constraint string.
+// falling back to NumericDottedVersion for other dotted numerics and to
+// SimpleVersion for opaque strings. This allows mixing version types within
+// a constraint string.
+//
+// Parsing is lenient: a bare version means an exact match, an empty string
+// means any version, and malformed operators such as "> =1.0" end up as
+// opaque versions. Use ParseVersionRangeStrict to reject such input.
 func ParseVersionRange(s string) (VersionSet, error) {
+	return parseVersionRangeWith(s, parseRangeVersion)
+}
+
+// ParseVersionRangeStrict parses the syntax of ParseVersionRange but rejects
+// ambiguous input that ParseVersionRange accepts:
+//   - bare versions such as "1.2", which some ecosystems read as an exact
+//     match and others as a compatible range; write "==1.2"
+//   - spaces inside or doubled operators, such as "> =1.0" or ">==1.0"
+//   - versions containing spaces, such as ">=1.0 beta"
+//   - the empty string; write "*" for any version
+//
+// Example:
+//
+//	ParseVersionRangeStrict(">=1.0.0, <2.0.0") // [1.0.0, 2.0.0)
+//	ParseVersionRangeStrict("1.2")             // error: ambiguous bare version
+func ParseVersionRangeStrict(s string) (VersionSet, error) {
+	return RangeParser{Strict: true}.Parse(s)
+}
+
+// RangeParser parses constraint strings with the grammar of one ecosystem,
+// so each Source adapter can pick how forgiving to be and how versions are
+// read.
+//
+// Example:
+//
+//	parser := RangeParser{Strict: true, Version: func(s string) (Version, error) {
+//	    return ParseSemanticVersion(s)
+//	}}
+//	set, err := parser.Parse(">=1.0.0, <2.0.0")
+type RangeParser struct {
+	// Strict rejects ambiguous input, see ParseVersionRangeStrict.
+	// Default: false (the lenient ParseVersionRange behavior)
+	Strict bool
+	// Version parses each version of the range.
+	// Default: the fallback chain of ParseVersionRange
+	Version VersionParser
+}
+
+// Parse parses s into a VersionSet.
+func (p RangeParser) Parse(s string) (VersionSet, error) {
+	parse := p.Version
+	if parse == nil {
+		parse = parseRangeVersion
+	}
+	if p.Strict {
+		return parseVersionRange(s, parse, true)
+	}
+	return parseVersionRangeWith(s, parse)
+}
+
+// parseVersionRangeWith parses a range leniently using parseVersion for the
+// versions in each expression.
+func parseVersionRangeWith(s string, parseVersion func(string) (Version, error)) (VersionSet, error) {
+	return parseVersionRange(s, parseVersion, false)
+}
+
+// parseVersionRange parses a range, rejecting ambiguous input when strict.
+func parseVersionRange(s string, parseVersion func(string) (Version, error), strict bool) (VersionSet, error) {
 	s = strings.TrimSpace(s)
 
+	if s == "" && strict {
+		return nil, fmt.Errorf("empty range; use * for any version")
+	}
 	if s == "" || s == "*" {
 		return (&VersionIntervalSet{}).Full(), nil
 	}
@@ -67,7 +133,7 @@ func ParseVersionRange(s string) (VersionSet, error) {
 				return nil, fmt.Errorf("invalid empty constraint in %q", orPart)
 			}
 
-			set, err := parseRangeExpression(token)
+			set, err := parseRangeExpression(token, parseVersion, strict)
 			if err != nil {
 				return nil, err
 			}
@@ -84,24 +150,39 @@ func ParseVersionRange(s string) (VersionSet, error) {
 	return result, nil
 }
 
-// parseRangeExpression parses a single range expression like ">=1.0.0" or "!=2.0.0"
-func parseRangeExpression(expr string) (VersionSet, error) {
+// parseRangeVersion parses a version string, trying SemanticVersion first and
+// falling back to ParseDottedVersion, so dotted numerics with more than three
+// segments still compare numerically.
+func parseRangeVersion(raw string) (Version, error) {
+	if sv, err := ParseSemanticVersion(raw); err == nil {
+		return sv, nil
+	}
+
+	return ParseDottedVersion(raw), nil
+}
+
+// parseRangeExpression parses a single range expression like ">=1.0.0" or
+// "!=2.0.0". In strict mode, bare versions and malformed operators are
+// errors.
+func parseRangeExpression(expr string, parse func(string) (Version, error), strict bool) (VersionSet, error) {
 	expr = strings.TrimSpace(expr)
 	if expr == "" {
 		return nil, fmt.Errorf("empty range expression")
 	}
 
-	// Helper to parse version string (try SemanticVersion first, fallback to SimpleVersion)
 	parseVersion := func(raw string) (Version, error) {
 		if raw == "" {
 			return nil, fmt.Errorf("missing version in range expression")
 		}
-
-		if sv, err := ParseSemanticVersion(raw); err == nil {
-			return sv, nil
+		if strict {
+			if strings.ContainsAny(raw[:1], "<>=!~^") {
+				return nil, fmt.Errorf("malformed operator in %q", expr)
+			}
+			if strings.ContainsAny(raw, " \t") {
+				return nil, fmt.Errorf("space inside version in %q", expr)
+			}
 		}
-
-		return SimpleVersion(raw), nil
+		return parse(raw)
 	}
 
 	// Define operators and their VersionSet builders
@@ -167,6 +248,12 @@ func parseRangeExpression(expr string) (VersionSet, error) {
 	}
 
 	// No operator found, treat as exact version match
+	if strict {
+		if strings.ContainsAny(expr[:1], "<>=!~^") {
+			return nil, fmt.Errorf("unknown operator in %q", expr)
+		}
+		return nil, fmt.Errorf("ambiguous bare version %q; write ==%s for an exact match", expr, expr)
+	}
 	version, err := parseVersion(expr)
 	if err != nil {
 		return nil, err
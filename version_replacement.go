@@ -0,0 +1,237 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// maxReplacementCandidates bounds how many versions SuggestReplacement tries.
+// Every attempt is at least one full solve, so the search stays shallow.
+const maxReplacementCandidates = 8
+
+// VersionReplacement proposes the version a locked package should move to
+// after its locked version was banned, e.g. by a security exclusion.
+//
+// Example:
+//
+//	r, err := SuggestReplacement(*root, locked, MakeName("rack"), vulnerable, source)
+//	if err == nil {
+//	    fmt.Println(r) // replace rack 2.2.3 with 2.2.8
+//	}
+type VersionReplacement struct {
+	// Package is the package whose locked version is banned.
+	Package Name
+	// Current is the banned locked version.
+	Current Version
+	// Suggested is the nearest version outside the ban that resolves.
+	Suggested Version
+	// Distance counts published versions from Current to Suggested; the
+	// adjacent release is 1.
+	Distance int
+	// Changes lists the other packages whose locked version must change
+	// along with Package, at their new versions, sorted by name. Empty when
+	// the replacement touches nothing else.
+	Changes []NameVersion
+	// Solution is the resolution with the replacement applied.
+	Solution Solution
+}
+
+// String returns a human-readable suggestion.
+func (r VersionReplacement) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "replace %s %s with %s", FormatName(r.Package), r.Current, r.Suggested)
+	for i, change := range r.Changes {
+		if i == 0 {
+			b.WriteString(", also updating ")
+		} else {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s to %s", FormatName(change.Name), change.Version)
+	}
+	return b.String()
+}
+
+// SuggestReplacement finds the nearest replacement for the locked version of
+// pkg when excluded bans it, keeping the rest of the lockfile as it is
+// wherever possible, so an update bot can open a precise upgrade PR.
+//
+// Published versions outside excluded are tried by their distance from the
+// locked version, newer first among equally distant ones. Versions every
+// dependent in the locked solution already accepts are tried before the
+// rest, since they can be swapped in without touching the dependents. Each
+// candidate is solved with every other locked package frozen; packages are
+// unfrozen only when a FrozenConflictError names them, and they are reported
+// in Changes.
+//
+// The locked version is never suggested. Errors match ErrNoSolution when no
+// candidate resolves; source errors abort the search.
+func SuggestReplacement(root RootSource, locked Solution, pkg Name, excluded VersionSet, source Source, opts ...SolverOption) (VersionReplacement, error) {
+	current, ok := locked.GetVersion(pkg)
+	if !ok {
+		return VersionReplacement{}, fmt.Errorf("%s is not in the lockfile", FormatName(pkg))
+	}
+	versions, err := source.GetVersions(pkg)
+	if err != nil {
+		return VersionReplacement{}, err
+	}
+
+	requirements := solutionRequirements(locked, source)[pkg]
+	for _, term := range root {
+		if term.Name == pkg {
+			requirements = append(requirements, outdatedRequirement{
+				dependent: NameVersion{Name: MakeName("$$root"), Version: SimpleVersion("1")},
+				term:      term,
+			})
+		}
+	}
+
+	err = fmt.Errorf("no published version of %s outside the exclusion: %w", FormatName(pkg), ErrNoSolution)
+	for _, candidate := range replacementCandidates(current, versions, excluded, requirements) {
+		solution, solveErr := solveReplacement(root, locked, pkg, candidate.version, source, opts)
+		if solveErr != nil {
+			if !errors.Is(solveErr, ErrNoSolution) {
+				return VersionReplacement{}, solveErr
+			}
+			err = solveErr
+			continue
+		}
+		return VersionReplacement{
+			Package:   pkg,
+			Current:   current,
+			Suggested: candidate.version,
+			Distance:  candidate.distance,
+			Changes:   lockChanges(locked, solution, pkg),
+			Solution:  solution,
+		}, nil
+	}
+	return VersionReplacement{}, err
+}
+
+// replacementCandidate is a version SuggestReplacement may try.
+type replacementCandidate struct {
+	version  Version
+	distance int
+	accepted bool // Every dependent accepts the version
+}
+
+// replacementCandidates lists the published versions outside excluded,
+// those accepted by every requirement first, then nearest to current first.
+func replacementCandidates(current Version, versions []Version, excluded VersionSet, requirements []outdatedRequirement) []replacementCandidate {
+	pos, published := slices.BinarySearchFunc(versions, current, func(v, target Version) int { return v.Sort(target) })
+
+	var candidates []replacementCandidate
+	for i, ver := range versions {
+		if excluded.Contains(ver) || ver.Sort(current) == 0 {
+			continue
+		}
+		distance := pos - i
+		if i >= pos {
+			distance = i - pos
+			if !published {
+				distance++
+			}
+		}
+		candidates = append(candidates, replacementCandidate{
+			version:  ver,
+			distance: distance,
+			accepted: acceptedByAll(ver, requirements),
+		})
+	}
+
+	slices.SortStableFunc(candidates, func(a, b replacementCandidate) int {
+		if a.accepted != b.accepted {
+			if a.accepted {
+				return -1
+			}
+			return 1
+		}
+		if a.distance != b.distance {
+			return a.distance - b.distance
+		}
+		return b.version.Sort(a.version)
+	})
+
+	if len(candidates) > maxReplacementCandidates {
+		candidates = candidates[:maxReplacementCandidates]
+	}
+	return candidates
+}
+
+// solveReplacement solves with pkg pinned to target and every other locked
+// package frozen, unfreezing the pins each failure blames until the solve
+// succeeds or fails for a reason other than a frozen pin.
+func solveReplacement(root RootSource, locked Solution, pkg Name, target Version, source Source, opts []SolverOption) (Solution, error) {
+	frozen := make(map[Name]bool)
+	for nv := range locked.All() {
+		if nv.Name != pkg && nv.Name != MakeName("$$root") {
+			frozen[nv.Name] = true
+		}
+	}
+	pin := NewPolicySet("replacement").Constrain(pkg, EmptyVersionSet().Singleton(target),
+		fmt.Sprintf("replacing %s %s", FormatName(pkg), target))
+
+	for {
+		names := make([]Name, 0, len(frozen))
+		for nv := range locked.All() {
+			if frozen[nv.Name] {
+				names = append(names, nv.Name)
+			}
+		}
+		solverOpts := append(slices.Clone(opts),
+			WithIncompatibilityTracking(true),
+			WithLockfile(locked),
+			WithFrozen(names...),
+			WithPolicy(pin),
+		)
+		solution, err := NewSolverWithOptions([]Source{root, source}, solverOpts...).Solve(root.Term())
+
+		var conflict *FrozenConflictError
+		if err == nil || !errors.As(err, &conflict) {
+			return solution, err
+		}
+		unfrozen := false
+		for _, p := range conflict.Pins {
+			if frozen[p.Name] {
+				delete(frozen, p.Name)
+				unfrozen = true
+			}
+		}
+		if !unfrozen {
+			// The pins come from the caller's own WithFrozen.
+			return nil, err
+		}
+	}
+}
+
+// lockChanges lists the packages of solution other than pkg that are new or
+// at a different version than in locked, sorted by name.
+func lockChanges(locked, solution Solution, pkg Name) []NameVersion {
+	var changes []NameVersion
+	for nv := range solution.All() {
+		if nv.Name == pkg || nv.Name == MakeName("$$root") {
+			continue
+		}
+		if prev, ok := locked.GetVersion(nv.Name); ok && prev.Sort(nv.Version) == 0 {
+			continue
+		}
+		changes = append(changes, NameVersion{Name: nv.Name, Version: nv.Version})
+	}
+	slices.SortFunc(changes, func(a, b NameVersion) int { return strings.Compare(a.Name.Value(), b.Name.Value()) })
+	return changes
+}
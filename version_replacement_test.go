@@ -0,0 +1,85 @@
+package pubgrub
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSuggestReplacementSwapsNearestAcceptedVersion(t *testing.T) {
+	source := &InMemorySource{}
+	for _, v := range []string{"2.2.2", "2.2.3", "2.2.8", "3.0.0"} {
+		source.AddPackage(MakeName("rack"), mustSemver(t, v), nil)
+	}
+	source.AddPackage(MakeName("rails"), mustSemver(t, "7.0.0"), []Term{
+		NewTerm(MakeName("rack"), NewVersionSetCondition(mustParseVersionRange(t, ">=2.2.0, <3.0.0"))),
+	})
+	root := NewRootSource()
+	root.AddPackage(MakeName("rails"), NewVersionSetCondition(FullVersionSet()))
+	locked := Solution{
+		{Name: MakeName("rails"), Version: mustSemver(t, "7.0.0")},
+		{Name: MakeName("rack"), Version: mustSemver(t, "2.2.3")},
+	}
+
+	r, err := SuggestReplacement(*root, locked, MakeName("rack"), mustParseVersionRange(t, ">=2.2.0, <2.2.8"), source)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Suggested.String() != "2.2.8" || r.Distance != 1 || len(r.Changes) != 0 {
+		t.Fatalf("unexpected replacement: %+v", r)
+	}
+	if want := "replace rack 2.2.3 with 2.2.8"; r.String() != want {
+		t.Fatalf("unexpected description %q, want %q", r.String(), want)
+	}
+	if ver, ok := r.Solution.GetVersion(MakeName("rails")); !ok || ver.String() != "7.0.0" {
+		t.Fatalf("expected rails to stay locked, got %v", ver)
+	}
+}
+
+func TestSuggestReplacementUpdatesBlockingDependents(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("rack"), mustSemver(t, "2.2.3"), nil)
+	source.AddPackage(MakeName("rack"), mustSemver(t, "3.0.0"), nil)
+	source.AddPackage(MakeName("puma"), mustSemver(t, "6.0.0"), nil)
+	source.AddPackage(MakeName("puma"), mustSemver(t, "6.4.0"), nil)
+	source.AddPackage(MakeName("sinatra"), mustSemver(t, "2.0.0"), []Term{
+		NewTerm(MakeName("rack"), NewVersionSetCondition(mustParseVersionRange(t, "<3.0.0"))),
+	})
+	source.AddPackage(MakeName("sinatra"), mustSemver(t, "3.0.0"), []Term{
+		NewTerm(MakeName("rack"), NewVersionSetCondition(mustParseVersionRange(t, ">=3.0.0"))),
+	})
+	root := NewRootSource()
+	root.AddPackage(MakeName("sinatra"), NewVersionSetCondition(FullVersionSet()))
+	root.AddPackage(MakeName("puma"), NewVersionSetCondition(FullVersionSet()))
+	locked := Solution{
+		{Name: MakeName("sinatra"), Version: mustSemver(t, "2.0.0")},
+		{Name: MakeName("puma"), Version: mustSemver(t, "6.0.0")},
+		{Name: MakeName("rack"), Version: mustSemver(t, "2.2.3")},
+	}
+
+	r, err := SuggestReplacement(*root, locked, MakeName("rack"), mustParseVersionRange(t, "<3.0.0"), source)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "replace rack 2.2.3 with 3.0.0, also updating sinatra to 3.0.0"; r.String() != want {
+		t.Fatalf("unexpected replacement %q, want %q", r.String(), want)
+	}
+	if ver, _ := r.Solution.GetVersion(MakeName("puma")); ver.String() != "6.0.0" {
+		t.Fatalf("expected puma to stay at its locked version, got %v", ver)
+	}
+}
+
+func TestSuggestReplacementWithoutCandidates(t *testing.T) {
+	source := &InMemorySource{}
+	source.AddPackage(MakeName("rack"), mustSemver(t, "2.2.3"), nil)
+	root := NewRootSource()
+	root.AddPackage(MakeName("rack"), NewVersionSetCondition(FullVersionSet()))
+	locked := Solution{{Name: MakeName("rack"), Version: mustSemver(t, "2.2.3")}}
+
+	_, err := SuggestReplacement(*root, locked, MakeName("rack"), FullVersionSet(), source)
+	if !errors.Is(err, ErrNoSolution) {
+		t.Fatalf("expected ErrNoSolution, got %v", err)
+	}
+	if _, err := SuggestReplacement(*root, locked, MakeName("rails"), FullVersionSet(), source); err == nil {
+		t.Fatal("expected an error for a package missing from the lockfile")
+	}
+}
@@ -77,6 +77,10 @@ func FullVersionSet() VersionSet {
 	}
 }
 
+// fullVersionSet is a shared full set for hot paths. Set operations never
+// mutate their receiver, so it is safe to hand out.
+var fullVersionSet = FullVersionSet()
+
 // NewVersionRangeSet creates a VersionSet from lower and upper bounds.
 // This helper allows custom Version implementations to create intervals
 // without relying on ParseVersionRange which uses SemanticVersion.
@@ -142,6 +142,47 @@ func TestParseVersionRangeErrors(t *testing.T) {
 	}
 }
 
+func TestParseVersionRangeStrictRejectsAmbiguousInput(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{"1.2", "", "> =1.0.0", ">==1.0.0", ">= 1.0.0 beta", "~>1.0", ">=1.0.0, 2.0.0"} {
+		t.Run(input, func(t *testing.T) {
+			if _, err := ParseVersionRangeStrict(input); err == nil {
+				t.Fatalf("expected strict parsing to reject %q", input)
+			}
+			if input == "> =1.0.0" || input == "1.2" || input == "" {
+				if _, err := ParseVersionRange(input); err != nil {
+					t.Fatalf("expected lenient parsing to accept %q, got %v", input, err)
+				}
+			}
+		})
+	}
+
+	for _, input := range []string{">=1.0.0, <2.0.0", ">= 1.0.0", "==1.2", "*", "!=1.5.0 || >=3.0.0"} {
+		strict, err := ParseVersionRangeStrict(input)
+		if err != nil {
+			t.Fatalf("expected strict parsing to accept %q, got %v", input, err)
+		}
+		if lenient := mustParseVersionRange(t, input); strict.String() != lenient.String() {
+			t.Fatalf("strict %s and lenient %s parses of %q differ", strict, lenient, input)
+		}
+	}
+}
+
+func TestRangeParserUsesVersionParser(t *testing.T) {
+	t.Parallel()
+
+	parser := RangeParser{Strict: true, Version: func(s string) (Version, error) {
+		return ParseSemanticVersion(s)
+	}}
+	if _, err := parser.Parse(">=1.0.0, <2.0.0"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := parser.Parse(">=banana"); err == nil {
+		t.Fatal("expected the semantic version parser to reject banana")
+	}
+}
+
 func TestVersionSetIsSubset(t *testing.T) {
 	t.Parallel()
 
@@ -247,3 +288,99 @@ func TestVersionSetConditionNil(t *testing.T) {
 		t.Fatal("nil condition should satisfy any version")
 	}
 }
+
+func TestVersionSetEqualAcrossConstructions(t *testing.T) {
+	t.Parallel()
+
+	a := mustParseVersionRange(t, ">=1.0.0, <2.0.0")
+	b := mustParseVersionRange(t, ">=1.5.0, <3.0.0")
+	c := mustParseVersionRange(t, ">=4.0.0")
+
+	tests := []struct {
+		name string
+		x, y VersionSet
+	}{
+		{"complement of complement", a.Complement().Complement(), a},
+		{"union order", a.Union(b).Union(c), c.Union(b.Union(a))},
+		{"union of pieces", mustParseVersionRange(t, ">=1.0.0, <1.5.0").Union(mustParseVersionRange(t, ">=1.5.0, <3.0.0")), a.Union(b)},
+		{"intersection via complements", a.Intersection(b), a.Complement().Union(b.Complement()).Complement()},
+		{"empty", a.Intersection(c), EmptyVersionSet()},
+		{"full", a.Union(a.Complement()), FullVersionSet()},
+	}
+	for _, tc := range tests {
+		if !setsEqual(tc.x, tc.y) || !setsEqual(tc.y, tc.x) {
+			t.Fatalf("%s: expected %s and %s to be equal", tc.name, tc.x, tc.y)
+		}
+		if !CanonicalVersionSet(tc.x).(*VersionIntervalSet).Equal(CanonicalVersionSet(tc.y)) {
+			t.Fatalf("%s: canonical forms differ: %s vs %s", tc.name, tc.x, tc.y)
+		}
+	}
+
+	if setsEqual(a, b) || setsEqual(a, a.Union(c)) {
+		t.Fatalf("expected different sets to compare unequal")
+	}
+	if !setsEqual(mustParseVersionRange(t, "==1.0.0"), (&VersionIntervalSet{}).Singleton(mustSemver(t, "1.0.0"))) {
+		t.Fatalf("expected singleton forms to compare equal")
+	}
+}
+
+func TestAddDerivationUnchangedForEquivalentSet(t *testing.T) {
+	root := MakeName("root")
+	ps := newPartialSolution(root)
+	ps.seedRoot(root, SimpleVersion("1"))
+
+	lib := MakeName("lib")
+	narrow := mustParseVersionRange(t, ">=1.0.0, <2.0.0")
+	if _, changed, err := ps.addDerivation(NewTerm(lib, NewVersionSetCondition(narrow)), nil); err != nil || !changed {
+		t.Fatalf("expected the first derivation to change lib, changed=%v err=%v", changed, err)
+	}
+
+	same := narrow.Complement().Complement().Union(mustParseVersionRange(t, ">=1.2.0, <1.4.0"))
+	if _, changed, err := ps.addDerivation(NewTerm(lib, NewVersionSetCondition(same)), nil); err != nil || changed {
+		t.Fatalf("expected an equivalent derivation to leave lib unchanged, changed=%v err=%v", changed, err)
+	}
+}
+
+func TestRangeBuilderMatchesParsedRanges(t *testing.T) {
+	t.Parallel()
+
+	v1, v2, v3 := mustSemver(t, "1.0.0"), mustSemver(t, "2.0.0"), mustSemver(t, "3.0.0")
+	prefix := Range().GTE(v1)
+
+	tests := []struct {
+		built VersionSet
+		want  string
+	}{
+		{Range().GTE(v1).LT(v2).Or().Exact(v3).Build(), ">=1.0.0, <2.0.0 || ==3.0.0"},
+		{Range().GT(v1).LTE(v2).Build(), ">1.0.0, <=2.0.0"},
+		{Range().Build(), "*"},
+		{RangeBuilder{}.LT(v1).Build(), "<1.0.0"},
+		{prefix.LT(v2).Build(), ">=1.0.0, <2.0.0"},
+		{prefix.Except(v2).Build(), ">=1.0.0, <2.0.0 || >2.0.0"},
+		{Range().GTE(v2).LT(v1).Build(), ">=2.0.0, <1.0.0"},
+	}
+	for _, tc := range tests {
+		want := mustParseVersionRange(t, tc.want)
+		if !setsEqual(tc.built, want) {
+			t.Fatalf("expected %s, built %s", want, tc.built)
+		}
+	}
+}
+
+func TestExactPinSetsAreShared(t *testing.T) {
+	t.Parallel()
+
+	for _, pair := range [][2]Version{
+		{SimpleVersion("1.0.0"), SimpleVersion("1.0.0")},
+		{mustSemver(t, "2.1.0"), mustSemver(t, "2.1.0")},
+	} {
+		a, _ := termAllowedSet(NewTerm(MakeName("lib"), EqualsCondition{Version: pair[0]}))
+		b, _ := termForbiddenSet(NewNegativeTerm(MakeName("other"), &EqualsCondition{Version: pair[1]}))
+		if a != b {
+			t.Fatalf("expected one shared set for %s, got distinct instances", pair[0])
+		}
+		if !a.Contains(pair[0]) || a.Contains(SimpleVersion("9.9.9")) {
+			t.Fatalf("unexpected singleton set %s", a)
+		}
+	}
+}
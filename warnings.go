@@ -0,0 +1,114 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubgrub
+
+import "fmt"
+
+// Warning is a non-fatal problem noticed while solving. Concrete warning
+// types carry the details; use a type switch to handle specific ones.
+//
+// Example:
+//
+//	solver := NewSolverWithOptions([]Source{root, source}, WithWarningHandler(func(w Warning) {
+//	    log.Println("warning:", w.Warning())
+//	}))
+type Warning interface {
+	// Warning returns a human-readable description.
+	Warning() string
+}
+
+// DuplicateVersionWarning reports a version published by more than one
+// source of a CombinedSource. A package unexpectedly appearing in a second
+// source is the signature of a dependency-confusion attack; enable
+// WithStrictSources to fail instead of warning.
+type DuplicateVersionWarning struct {
+	Package Name
+	Version Version
+	// Sources names the source that wins and the one it shadows.
+	Sources [2]string
+}
+
+// Warning implements the Warning interface.
+func (w DuplicateVersionWarning) Warning() string {
+	return fmt.Sprintf("%s %s is published by both %s and %s", FormatName(w.Package), w.Version, w.Sources[0], w.Sources[1])
+}
+
+// ReplacementWarning reports a requirement on a renamed package that was
+// redirected to its successor, see WithReplacement.
+type ReplacementWarning struct {
+	Dependent Name
+	Version   Version
+	Old       Name
+	New       Name
+}
+
+// Warning implements the Warning interface.
+func (w ReplacementWarning) Warning() string {
+	return fmt.Sprintf("%s %s requires %s, which is replaced by %s", FormatName(w.Dependent), w.Version, FormatName(w.Old), FormatName(w.New))
+}
+
+// BestEffortWarning reports a best-effort dependency that was dropped
+// because no published version satisfies it, see BestEffortCondition.
+type BestEffortWarning struct {
+	Dependent Name
+	Version   Version
+	// Dependency is the dropped requirement, without the best-effort marker.
+	Dependency Term
+}
+
+// Warning implements the Warning interface.
+func (w BestEffortWarning) Warning() string {
+	return fmt.Sprintf("%s %s: dropped best-effort dependency %s, no published version satisfies it", FormatName(w.Dependent), w.Version, w.Dependency)
+}
+
+// OptionalWarning reports an optional dependency that was skipped because
+// it conflicts with other requirements, see OptionalCondition.
+type OptionalWarning struct {
+	Dependent Name
+	Version   Version
+	// Dependency is the skipped requirement, without the optional marker.
+	Dependency Term
+}
+
+// Warning implements the Warning interface.
+func (w OptionalWarning) Warning() string {
+	return fmt.Sprintf("%s %s: skipped optional dependency %s, it conflicts with other requirements", FormatName(w.Dependent), w.Version, w.Dependency)
+}
+
+// ErrDuplicateVersion is returned instead of a DuplicateVersionWarning when
+// strict sources are enabled.
+type ErrDuplicateVersion struct {
+	DuplicateVersionWarning
+}
+
+// Error implements the error interface.
+func (e ErrDuplicateVersion) Error() string {
+	return e.Warning()
+}
+
+// Warnings returns the warnings raised by the most recent Solve call.
+func (s *Solver) Warnings() []Warning {
+	return s.warnings
+}
+
+// warn records a warning and forwards it to the configured handler.
+func (st *solverState) warn(w Warning) {
+	st.warnings = append(st.warnings, w)
+	if st.options.OnWarning != nil {
+		st.options.OnWarning(w)
+	}
+}
+
+var _ error = ErrDuplicateVersion{}
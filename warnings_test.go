@@ -0,0 +1,66 @@
+package pubgrub
+
+import (
+	"errors"
+	"testing"
+)
+
+type namedTestSource struct {
+	*InMemorySource
+	name string
+}
+
+func (s namedTestSource) SourceName() string { return s.name }
+
+func duplicateSources() (*RootSource, Source, Source) {
+	internal := &InMemorySource{}
+	internal.AddPackage(MakeName("secret"), SimpleVersion("1.0.0"), nil)
+	public := &InMemorySource{}
+	public.AddPackage(MakeName("secret"), SimpleVersion("1.0.0"), nil)
+	public.AddPackage(MakeName("secret"), SimpleVersion("9.9.9"), nil)
+
+	root := NewRootSource()
+	root.AddPackage(MakeName("secret"), EqualsCondition{Version: SimpleVersion("1.0.0")})
+	return root, namedTestSource{internal, "internal"}, namedTestSource{public, "public"}
+}
+
+func TestDuplicateVersionWarning(t *testing.T) {
+	root, internal, public := duplicateSources()
+
+	var handled []Warning
+	solver := NewSolverWithOptions([]Source{root, internal, public},
+		WithWarningHandler(func(w Warning) { handled = append(handled, w) }),
+	)
+	if _, err := solver.Solve(root.Term()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	warnings := solver.Warnings()
+	if len(warnings) != 1 || len(handled) != 1 {
+		t.Fatalf("expected one warning, got %v (handled %v)", warnings, handled)
+	}
+	dup, ok := warnings[0].(DuplicateVersionWarning)
+	if !ok {
+		t.Fatalf("expected DuplicateVersionWarning, got %T", warnings[0])
+	}
+	if dup.Sources != [2]string{"internal", "public"} {
+		t.Fatalf("expected both sources to be named, got %v", dup.Sources)
+	}
+	if got := dup.Warning(); got != "secret 1.0.0 is published by both internal and public" {
+		t.Fatalf("unexpected message: %s", got)
+	}
+}
+
+func TestStrictSourcesFailOnDuplicate(t *testing.T) {
+	root, internal, public := duplicateSources()
+
+	solver := NewSolverWithOptions([]Source{root, internal, public}, WithStrictSources(true))
+	_, err := solver.Solve(root.Term())
+	var dupErr ErrDuplicateVersion
+	if !errors.As(err, &dupErr) {
+		t.Fatalf("expected ErrDuplicateVersion, got %v", err)
+	}
+	if dupErr.Package != MakeName("secret") {
+		t.Fatalf("unexpected package %s", dupErr.Package.Value())
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright 2025 Contriboss
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tuning collects per-solve metrics across many resolutions so that
+// solver heuristics can be compared on real workloads.
+//
+// A Harness runs every problem in a corpus under every configuration and
+// feeds the results into a Collector. Problem identities are anonymized before
+// they are recorded, so collected samples can be shared without exposing
+// private package names.
+//
+// The package is experimental: its API may change in any minor release. See
+// x/README.md.
+//
+// Example:
+//
+//	h := tuning.Harness{
+//	    Configs: []tuning.Config{
+//	        {Name: "default"},
+//	        {Name: "small-budget", Options: []pubgrub.SolverOption{pubgrub.WithMaxSteps(500)}},
+//	    },
+//	    Corpus: problems,
+//	}
+//	report := h.Run()
+//	report.WriteTo(os.Stdout)
+package tuning
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/contriboss/pubgrub-go"
+)
+
+// Problem is a single resolution request in a tuning corpus.
+type Problem struct {
+	// Name identifies the problem. It is anonymized before being recorded.
+	Name string
+	// Root is the term passed to Solve.
+	Root pubgrub.Term
+	// Sources provide the root requirements and package data.
+	Sources []pubgrub.Source
+}
+
+// Config is a named solver configuration under evaluation.
+type Config struct {
+	Name    string
+	Options []pubgrub.SolverOption
+}
+
+// Sample holds the metrics recorded for one solve.
+type Sample struct {
+	Config   string
+	Problem  string // anonymized problem identity
+	Solved   bool
+	Stats    pubgrub.SolveStats
+	Duration time.Duration
+}
+
+// Aggregate summarizes all samples recorded for one configuration.
+type Aggregate struct {
+	Config   string
+	Solves   int
+	Failures int
+
+	TotalSteps     int
+	TotalConflicts int
+	TotalDuration  time.Duration
+
+	MeanSteps     float64
+	MeanDecisions float64
+	MeanConflicts float64
+	MeanDuration  time.Duration
+}
+
+// Collector accumulates samples. It is safe for concurrent use.
+type Collector struct {
+	mu      sync.Mutex
+	samples []Sample
+}
+
+// Record adds a sample to the collector.
+func (c *Collector) Record(sample Sample) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.samples = append(c.samples, sample)
+}
+
+// Samples returns a copy of the recorded samples.
+func (c *Collector) Samples() []Sample {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.samples)
+}
+
+// Aggregates summarizes the recorded samples per configuration, in the order
+// configurations were first seen.
+func (c *Collector) Aggregates() []Aggregate {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	index := make(map[string]int)
+	var result []Aggregate
+	decisions := make(map[string]int)
+
+	for _, s := range c.samples {
+		i, ok := index[s.Config]
+		if !ok {
+			i = len(result)
+			index[s.Config] = i
+			result = append(result, Aggregate{Config: s.Config})
+		}
+		agg := &result[i]
+		agg.Solves++
+		if !s.Solved {
+			agg.Failures++
+		}
+		agg.TotalSteps += s.Stats.Steps
+		agg.TotalConflicts += s.Stats.Conflicts
+		agg.TotalDuration += s.Duration
+		decisions[s.Config] += s.Stats.Decisions
+	}
+
+	for i := range result {
+		agg := &result[i]
+		n := float64(agg.Solves)
+		agg.MeanSteps = float64(agg.TotalSteps) / n
+		agg.MeanDecisions = float64(decisions[agg.Config]) / n
+		agg.MeanConflicts = float64(agg.TotalConflicts) / n
+		agg.MeanDuration = agg.TotalDuration / time.Duration(agg.Solves)
+	}
+
+	return result
+}
+
+// Harness compares solver configurations over a corpus of problems.
+type Harness struct {
+	Configs []Config
+	Corpus  []Problem
+
+	// Collector receives every sample. When nil, Run uses a fresh collector.
+	Collector *Collector
+}
+
+// Run solves every problem under every configuration and returns a report.
+// Resolution failures are recorded as unsolved samples rather than aborting
+// the run, since a configuration that fails more often is itself a result.
+func (h *Harness) Run() Report {
+	collector := h.Collector
+	if collector == nil {
+		collector = &Collector{}
+	}
+
+	for _, problem := range h.Corpus {
+		id := Anonymize(problem.Name)
+		for _, cfg := range h.Configs {
+			solver := pubgrub.NewSolverWithOptions(problem.Sources, cfg.Options...)
+
+			start := time.Now()
+			_, err := solver.Solve(problem.Root)
+			elapsed := time.Since(start)
+
+			collector.Record(Sample{
+				Config:   cfg.Name,
+				Problem:  id,
+				Solved:   err == nil,
+				Stats:    solver.Stats(),
+				Duration: elapsed,
+			})
+		}
+	}
+
+	return Report{Aggregates: collector.Aggregates()}
+}
+
+// Anonymize returns a stable, non-reversible identifier for a problem name.
+func Anonymize(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(sum[:6])
+}
+
+// Report is the outcome of a harness run.
+type Report struct {
+	Aggregates []Aggregate
+}
+
+// Best returns the configuration that solved the most problems, breaking ties
+// by the fewest mean conflicts.
+func (r Report) Best() (Aggregate, bool) {
+	if len(r.Aggregates) == 0 {
+		return Aggregate{}, false
+	}
+
+	best := r.Aggregates[0]
+	for _, agg := range r.Aggregates[1:] {
+		solved := agg.Solves - agg.Failures
+		bestSolved := best.Solves - best.Failures
+		switch {
+		case solved > bestSolved:
+			best = agg
+		case solved == bestSolved && agg.MeanConflicts < best.MeanConflicts:
+			best = agg
+		}
+	}
+	return best, true
+}
+
+// WriteTo renders the report as an aligned text table.
+func (r Report) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	tw := tabwriter.NewWriter(cw, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "config\tsolves\tfailures\tmean steps\tmean decisions\tmean conflicts\tmean time")
+	for _, agg := range r.Aggregates {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f\t%.1f\t%.1f\t%s\n",
+			agg.Config, agg.Solves, agg.Failures,
+			agg.MeanSteps, agg.MeanDecisions, agg.MeanConflicts, agg.MeanDuration)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return cw.n, err
+	}
+	return cw.n, cw.err
+}
+
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	if err != nil && c.err == nil {
+		c.err = err
+	}
+	return n, err
+}
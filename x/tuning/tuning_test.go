@@ -0,0 +1,78 @@
+package tuning
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/contriboss/pubgrub-go"
+)
+
+func testCorpus() []Problem {
+	source := &pubgrub.InMemorySource{}
+	source.AddPackage(pubgrub.MakeName("a"), pubgrub.SimpleVersion("1.0.0"), []pubgrub.Term{
+		pubgrub.NewTerm(pubgrub.MakeName("b"), pubgrub.EqualsCondition{Version: pubgrub.SimpleVersion("1.0.0")}),
+	})
+	source.AddPackage(pubgrub.MakeName("b"), pubgrub.SimpleVersion("1.0.0"), nil)
+
+	ok := pubgrub.NewRootSource()
+	ok.AddPackage(pubgrub.MakeName("a"), pubgrub.EqualsCondition{Version: pubgrub.SimpleVersion("1.0.0")})
+
+	broken := pubgrub.NewRootSource()
+	broken.AddPackage(pubgrub.MakeName("b"), pubgrub.EqualsCondition{Version: pubgrub.SimpleVersion("2.0.0")})
+
+	return []Problem{
+		{Name: "private/app", Root: ok.Term(), Sources: []pubgrub.Source{ok, source}},
+		{Name: "private/broken", Root: broken.Term(), Sources: []pubgrub.Source{broken, source}},
+	}
+}
+
+func TestHarnessAggregatesPerConfig(t *testing.T) {
+	h := Harness{
+		Configs: []Config{
+			{Name: "default"},
+			{Name: "tracking", Options: []pubgrub.SolverOption{pubgrub.WithIncompatibilityTracking(true)}},
+		},
+		Corpus: testCorpus(),
+	}
+
+	report := h.Run()
+	if len(report.Aggregates) != 2 {
+		t.Fatalf("expected 2 aggregates, got %d", len(report.Aggregates))
+	}
+	for _, agg := range report.Aggregates {
+		if agg.Solves != 2 || agg.Failures != 1 {
+			t.Fatalf("%s: expected 2 solves with 1 failure, got %d/%d", agg.Config, agg.Solves, agg.Failures)
+		}
+		if agg.MeanDecisions <= 0 {
+			t.Fatalf("%s: expected decisions to be recorded", agg.Config)
+		}
+	}
+
+	if _, ok := report.Best(); !ok {
+		t.Fatal("expected a best configuration")
+	}
+
+	var buf bytes.Buffer
+	if _, err := report.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	if !strings.Contains(buf.String(), "tracking") {
+		t.Fatalf("report missing config row:\n%s", buf.String())
+	}
+}
+
+func TestSamplesAreAnonymized(t *testing.T) {
+	collector := &Collector{}
+	h := Harness{Configs: []Config{{Name: "default"}}, Corpus: testCorpus(), Collector: collector}
+	h.Run()
+
+	for _, s := range collector.Samples() {
+		if strings.Contains(s.Problem, "private") {
+			t.Fatalf("problem name leaked into sample: %q", s.Problem)
+		}
+		if s.Problem != Anonymize("private/app") && s.Problem != Anonymize("private/broken") {
+			t.Fatalf("unexpected problem id %q", s.Problem)
+		}
+	}
+}